@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+)
+
+// clustersValue is a flag.Value for the named clusters of a multi-cluster
+// setup, written as a comma-separated list of name=kubeconfig#context entries.
+// The kubeconfig and the context may be omitted, e.g. "staging=#staging" uses
+// the staging context of the default kubeconfig.
+type clustersValue []cluster_client.NamedCluster
+
+func clustersFlag(name, usage string) *[]cluster_client.NamedCluster {
+	var clusters []cluster_client.NamedCluster
+	flag.Var((*clustersValue)(&clusters), name, usage)
+	return &clusters
+}
+
+func (v *clustersValue) String() string {
+	if v == nil {
+		return ""
+	}
+	entries := make([]string, 0, len(*v))
+	for _, c := range *v {
+		entry := c.Name + "=" + c.Kubeconfig
+		if c.Context != "" {
+			entry += "#" + c.Context
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, ",")
+}
+
+func (v *clustersValue) Set(s string) error {
+	var clusters []cluster_client.NamedCluster
+	for _, entry := range splitList(s) {
+		name, target, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return fmt.Errorf("invalid cluster %q: use name=kubeconfig#context", entry)
+		}
+		kubeconfig, kubeContext, _ := strings.Cut(target, "#")
+		clusters = append(clusters, cluster_client.NamedCluster{
+			Name:       name,
+			Kubeconfig: strings.TrimSpace(kubeconfig),
+			Context:    strings.TrimSpace(kubeContext),
+		})
+	}
+	*v = clusters
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+)
+
+func TestClustersValue(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []cluster_client.NamedCluster
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"prod=/etc/kube/prod", []cluster_client.NamedCluster{{Name: "prod", Kubeconfig: "/etc/kube/prod"}}, false},
+		{"prod=/etc/kube/prod#admin@prod, staging=#staging", []cluster_client.NamedCluster{
+			{Name: "prod", Kubeconfig: "/etc/kube/prod", Context: "admin@prod"},
+			{Name: "staging", Context: "staging"},
+		}, false},
+		{"prod", nil, true},
+		{"=/etc/kube/prod", nil, true},
+	}
+	for _, tt := range tests {
+		var v clustersValue
+		err := v.Set(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if !reflect.DeepEqual([]cluster_client.NamedCluster(v), tt.want) {
+			t.Errorf("Set(%q) = %+v, want %+v", tt.in, v, tt.want)
+		}
+
+		// The flag value written by the config file must parse back.
+		var again clustersValue
+		if err := again.Set(v.String()); err != nil || !reflect.DeepEqual(again, v) {
+			t.Errorf("Set(%q) = %+v, %v, want %+v", v.String(), again, err, v)
+		}
+	}
+}
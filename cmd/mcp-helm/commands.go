@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+
+	"github.com/zekker6/mcp-helm/internal/tools"
+	"github.com/zekker6/mcp-helm/lib/logger"
+)
+
+func main() {
+	flag.Usage = usage
+
+	// Flags without a command run the server, as before commands existed.
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	switch cmd {
+	case "serve":
+		parseFlags(args)
+		serve()
+	case "version":
+		parseFlags(args)
+		printVersion(os.Stdout)
+	case "tools":
+		if len(args) == 0 || args[0] != "list" {
+			fmt.Fprintln(os.Stderr, "Usage: mcp-helm tools list [flags]")
+			os.Exit(2)
+		}
+		parseFlags(args[1:])
+		if err := listTools(os.Stdout); err != nil {
+			logger.Error("Failed to list tools", zap.Error(err))
+			os.Exit(1)
+		}
+	case "call":
+		if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+			fmt.Fprintln(os.Stderr, "Usage: mcp-helm call <tool> [--arg key=value ...] [flags]")
+			os.Exit(2)
+		}
+		var toolArgs argList
+		flag.Var(&toolArgs, "arg", "Tool argument as key=value, for the call command. Can be repeated")
+		parseFlags(args[1:])
+		os.Exit(callTool(args[0], toolArgs))
+	case "help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", cmd)
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	_, _ = fmt.Fprint(out, `Usage: mcp-helm [command] [flags]
+
+Commands:
+  serve       Run the MCP server (default)
+  version     Print version information
+  tools list  Print the tools exposed with the given flags and their input schemas as JSON
+  call        Run a single tool and print its result, e.g.
+              mcp-helm call list_chart_versions --arg repository_url=https://charts.example.com --arg chart_name=app
+  help        Print this help
+
+Flags:
+`)
+	flag.PrintDefaults()
+}
+
+// parseFlags parses the command-line flags of a command and completes them
+// from the config file and the environment. Every flag can also be set with
+// an environment variable named after it, e.g. MCP_HELM_HTTP_LISTEN_ADDR for
+// -httpListenAddr. Values are applied in order of precedence: command line,
+// config file, environment.
+func parseFlags(args []string) {
+	// CommandLine exits on parse errors.
+	_ = flag.CommandLine.Parse(args)
+	if flag.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments: %s\n\n", strings.Join(flag.Args(), " "))
+		usage()
+		os.Exit(2)
+	}
+
+	if *configFile != "" {
+		if err := applyConfigFile(flag.CommandLine, *configFile); err != nil {
+			logger.Error("Failed to load configuration", zap.Error(err))
+			os.Exit(1)
+		}
+	}
+	if err := applyEnv(flag.CommandLine); err != nil {
+		logger.Error("Failed to load configuration from environment", zap.Error(err))
+		os.Exit(1)
+	}
+}
+
+func printVersion(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "mcp-helm v%s (commit: %s, date: %s)\n", version, commit, date)
+}
+
+// listTools writes the definitions of the tools selected by -enableTools and
+// -disableTools as JSON, in the form clients receive them from tools/list.
+func listTools(w io.Writer) error {
+	selected, err := selectTools(allTools(nil, nil, nil), splitList(*enableTools), splitList(*disableTools))
+	if err != nil {
+		return err
+	}
+
+	defs := make([]mcp.Tool, 0, len(selected))
+	for _, t := range selected {
+		defs = append(defs, t.Tool)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(defs)
+}
+
+// argList collects the repeated --arg flags of the call command.
+type argList []string
+
+func (a *argList) String() string {
+	return strings.Join(*a, ",")
+}
+
+func (a *argList) Set(v string) error {
+	if !strings.Contains(v, "=") {
+		return fmt.Errorf("expected key=value, got %q", v)
+	}
+	*a = append(*a, v)
+	return nil
+}
+
+// callTool runs the named tool once with the given key=value arguments and
+// prints its result. Errors are printed to stderr. It returns the exit code.
+func callTool(name string, args argList) int {
+	logger.Init()
+	defer logger.Stop()
+
+	helmClient := getHelmClient()
+	defer func() { _ = helmClient.Close() }()
+
+	selected, err := selectTools(allTools(helmClient, getClusterClient(), tools.NewResultLimiter(*maxResultBytes)), []string{name}, splitList(*disableTools))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if len(selected) == 0 {
+		fmt.Fprintf(os.Stderr, "tool %q is disabled\n", name)
+		return 2
+	}
+	tool := selected[0]
+
+	arguments, err := toolArguments(tool.Tool, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var request mcp.CallToolRequest
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+	result, err := tool.Handler(ctx, request)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	out := os.Stdout
+	if result.IsError {
+		out = os.Stderr
+	}
+	for _, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok {
+			_, _ = fmt.Fprintln(out, text.Text)
+		}
+	}
+	if result.IsError {
+		return 1
+	}
+	return 0
+}
+
+// toolArguments converts key=value pairs into tool call arguments, using the
+// input schema of the tool to decode booleans, numbers, arrays and objects.
+// Array arguments are given as a JSON array or by repeating the key, object
+// arguments as a JSON object.
+func toolArguments(tool mcp.Tool, args argList) (map[string]any, error) {
+	arguments := make(map[string]any, len(args))
+	for _, arg := range args {
+		key, value, _ := strings.Cut(arg, "=")
+		prop, ok := tool.InputSchema.Properties[key].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("tool %q has no argument %q", tool.Name, key)
+		}
+
+		switch prop["type"] {
+		case "boolean":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("argument %q must be a boolean: %v", key, err)
+			}
+			arguments[key] = b
+		case "number":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("argument %q must be a number: %v", key, err)
+			}
+			arguments[key] = n
+		case "array":
+			items := []any{value}
+			if strings.HasPrefix(strings.TrimSpace(value), "[") {
+				if err := json.Unmarshal([]byte(value), &items); err != nil {
+					return nil, fmt.Errorf("argument %q must be a JSON array: %v", key, err)
+				}
+			}
+			prev, _ := arguments[key].([]any)
+			arguments[key] = append(prev, items...)
+		case "object":
+			var obj map[string]any
+			if err := json.Unmarshal([]byte(value), &obj); err != nil {
+				return nil, fmt.Errorf("argument %q must be a JSON object: %v", key, err)
+			}
+			arguments[key] = obj
+		default:
+			arguments[key] = value
+		}
+	}
+	return arguments, nil
+}
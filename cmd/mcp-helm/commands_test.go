@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestListTools(t *testing.T) {
+	defer func(enable, disable string) { *enableTools, *disableTools = enable, disable }(*enableTools, *disableTools)
+
+	*enableTools, *disableTools = "", "get_chart_contents"
+	var buf bytes.Buffer
+	if err := listTools(&buf); err != nil {
+		t.Fatalf("listTools: %v", err)
+	}
+
+	var defs []mcp.Tool
+	if err := json.Unmarshal(buf.Bytes(), &defs); err != nil {
+		t.Fatalf("output is not a JSON tool list: %v", err)
+	}
+	if len(defs) != len(allTools(nil, nil, nil))-1 {
+		t.Fatalf("got %d tools, want %d", len(defs), len(allTools(nil, nil, nil))-1)
+	}
+	for _, d := range defs {
+		if d.Name == "get_chart_contents" {
+			t.Fatalf("disabled tool %q listed", d.Name)
+		}
+		if d.InputSchema.Type != "object" {
+			t.Errorf("tool %q has no input schema", d.Name)
+		}
+	}
+
+	defer func(n int) { *maxResultBytes = n }(*maxResultBytes)
+	*maxResultBytes = 1000
+	for _, tool := range allTools(nil, nil, nil) {
+		if tool.Tool.OutputSchema.Type != "" || tool.Tool.RawOutputSchema != nil {
+			t.Errorf("tool %q advertises an output schema although results are truncated", tool.Tool.Name)
+		}
+	}
+
+	*disableTools = "no_such_tool"
+	if err := listTools(&buf); err == nil {
+		t.Fatal("expected an error for an unknown tool name")
+	}
+}
+
+func TestPrintVersion(t *testing.T) {
+	var buf bytes.Buffer
+	printVersion(&buf)
+	if !strings.HasPrefix(buf.String(), "mcp-helm v"+version) {
+		t.Fatalf("unexpected version output %q", buf.String())
+	}
+}
+
+func TestToolArguments(t *testing.T) {
+	tool := mcp.NewTool("test",
+		mcp.WithString("name"),
+		mcp.WithBoolean("recursive"),
+		mcp.WithNumber("limit"),
+		mcp.WithArray("set", mcp.WithStringItems()),
+		mcp.WithObject("labels"),
+	)
+
+	got, err := toolArguments(tool, argList{"name=a=b", "recursive=true", "limit=5"})
+	if err != nil {
+		t.Fatalf("toolArguments: %v", err)
+	}
+	if got["name"] != "a=b" || got["recursive"] != true || got["limit"] != float64(5) {
+		t.Fatalf("unexpected arguments %#v", got)
+	}
+
+	got, err = toolArguments(tool, argList{"set=image.tag=2.0", `set=["a=1","b=2"]`, `labels={"app":"web"}`})
+	if err != nil {
+		t.Fatalf("toolArguments: %v", err)
+	}
+	var request mcp.CallToolRequest
+	request.Params.Arguments = got
+	if set := request.GetStringSlice("set", nil); !slices.Equal(set, []string{"image.tag=2.0", "a=1", "b=2"}) {
+		t.Fatalf("unexpected set argument %q", set)
+	}
+	if labels, ok := got["labels"].(map[string]any); !ok || labels["app"] != "web" {
+		t.Fatalf("unexpected labels argument %#v", got["labels"])
+	}
+
+	for _, args := range []argList{{"unknown=1"}, {"recursive=maybe"}, {"limit=many"}, {"set=[a"}, {"labels=a=b"}} {
+		if _, err := toolArguments(tool, args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+
+	var a argList
+	if err := a.Set("novalue"); err == nil {
+		t.Error("expected an error for an argument without '='")
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+)
+
+// fileConfig is the layout of the file passed with -config. Every field maps
+// to a command-line flag; durations are written as in flags, e.g. "30s".
+type fileConfig struct {
+	Server struct {
+		Mode                  *string  `yaml:"mode"`
+		LogLevel              *string  `yaml:"logLevel"`
+		HTTPListenAddr        *string  `yaml:"httpListenAddr"`
+		SocketPath            *string  `yaml:"socketPath"`
+		HTTPHeartbeatInterval *string  `yaml:"httpHeartbeatInterval"`
+		SSEKeepAliveInterval  *string  `yaml:"sseKeepAliveInterval"`
+		ShutdownTimeout       *string  `yaml:"shutdownTimeout"`
+		TLSCert               *string  `yaml:"tlsCert"`
+		TLSKey                *string  `yaml:"tlsKey"`
+		APIKey                *string  `yaml:"apiKey"`
+		EnableTools           []string `yaml:"enableTools"`
+		DisableTools          []string `yaml:"disableTools"`
+		PullChartDir          *string  `yaml:"pullChartDir"`
+	} `yaml:"server"`
+
+	Pricing struct {
+		CPUHour      *float64 `yaml:"cpuHour"`
+		MemoryGBHour *float64 `yaml:"memoryGBHour"`
+	} `yaml:"pricing"`
+
+	Repositories struct {
+		Allowed    []string          `yaml:"allowed"`
+		Denied     []string          `yaml:"denied"`
+		Local      *bool             `yaml:"local"`
+		PluginsDir *string           `yaml:"pluginsDir"`
+		Prewarm    []string          `yaml:"prewarm"`
+		Aliases    map[string]string `yaml:"aliases"`
+	} `yaml:"repositories"`
+
+	Credentials struct {
+		Username              *string `yaml:"username"`
+		PasswordFile          *string `yaml:"passwordFile"`
+		BearerTokenFile       *string `yaml:"bearerTokenFile"`
+		RegistryCredentials   *string `yaml:"registryCredentials"`
+		RegistryPlainHTTP     *bool   `yaml:"registryPlainHTTP"`
+		TLSCert               *string `yaml:"tlsCert"`
+		TLSKey                *string `yaml:"tlsKey"`
+		TLSCA                 *string `yaml:"tlsCA"`
+		TLSInsecureSkipVerify *bool   `yaml:"tlsInsecureSkipVerify"`
+		PassCredentialsAll    *bool   `yaml:"passCredentialsAll"`
+	} `yaml:"credentials"`
+
+	Cache struct {
+		Dir            *string `yaml:"dir"`
+		IndexTTL       *string `yaml:"indexTTL"`
+		ChartCacheSize *int    `yaml:"chartCacheSize"`
+	} `yaml:"cache"`
+
+	Limits struct {
+		RepoTimeout                *string `yaml:"repoTimeout"`
+		DownloadTimeout            *string `yaml:"downloadTimeout"`
+		RetryAttempts              *int    `yaml:"retryAttempts"`
+		RetryBackoff               *string `yaml:"retryBackoff"`
+		MaxChartSizeMB             *int64  `yaml:"maxChartSizeMB"`
+		MaxDecompressedChartSizeMB *int64  `yaml:"maxDecompressedChartSizeMB"`
+		RateLimit                  *int    `yaml:"rateLimit"`
+		MaxResultBytes             *int    `yaml:"maxResultBytes"`
+	} `yaml:"limits"`
+
+	Cluster struct {
+		Kubeconfig       *string `yaml:"kubeconfig"`
+		Context          *string `yaml:"context"`
+		InCluster        *bool   `yaml:"inCluster"`
+		EnableWriteTools *bool   `yaml:"enableWriteTools"`
+		// Clusters adds named clusters that cluster tools can select.
+		Clusters []clusterConfig `yaml:"clusters"`
+	} `yaml:"cluster"`
+}
+
+// clusterConfig is an entry of cluster.clusters in the config file.
+type clusterConfig struct {
+	Name       string `yaml:"name"`
+	Kubeconfig string `yaml:"kubeconfig"`
+	Context    string `yaml:"context"`
+}
+
+// flagValues returns the values set in the file, keyed by flag name.
+func (fc *fileConfig) flagValues() map[string]string {
+	values := make(map[string]string)
+	set := func(name string, v any) {
+		switch v := v.(type) {
+		case *string:
+			if v != nil {
+				values[name] = *v
+			}
+		case *bool:
+			if v != nil {
+				values[name] = fmt.Sprint(*v)
+			}
+		case *int:
+			if v != nil {
+				values[name] = fmt.Sprint(*v)
+			}
+		case *int64:
+			if v != nil {
+				values[name] = fmt.Sprint(*v)
+			}
+		case *float64:
+			if v != nil {
+				values[name] = fmt.Sprint(*v)
+			}
+		case []string:
+			if v != nil {
+				values[name] = strings.Join(v, ",")
+			}
+		case map[string]string:
+			if v != nil {
+				values[name] = (*repoAliasesValue)(&v).String()
+			}
+		case []clusterConfig:
+			if v != nil {
+				clusters := make(clustersValue, 0, len(v))
+				for _, c := range v {
+					clusters = append(clusters, cluster_client.NamedCluster(c))
+				}
+				values[name] = clusters.String()
+			}
+		}
+	}
+
+	set("mode", fc.Server.Mode)
+	set("logLevel", fc.Server.LogLevel)
+	set("httpListenAddr", fc.Server.HTTPListenAddr)
+	set("socketPath", fc.Server.SocketPath)
+	set("httpHeartbeatInterval", fc.Server.HTTPHeartbeatInterval)
+	set("sseKeepAliveInterval", fc.Server.SSEKeepAliveInterval)
+	set("shutdownTimeout", fc.Server.ShutdownTimeout)
+	set("tlsCert", fc.Server.TLSCert)
+	set("tlsKey", fc.Server.TLSKey)
+	set("apiKey", fc.Server.APIKey)
+	set("enableTools", fc.Server.EnableTools)
+	set("disableTools", fc.Server.DisableTools)
+	set("pullChartDir", fc.Server.PullChartDir)
+
+	set("cpuHourPrice", fc.Pricing.CPUHour)
+	set("memoryGBHourPrice", fc.Pricing.MemoryGBHour)
+
+	set("allowedRepos", fc.Repositories.Allowed)
+	set("deniedRepos", fc.Repositories.Denied)
+	set("enableLocalCharts", fc.Repositories.Local)
+	set("helmPluginsDir", fc.Repositories.PluginsDir)
+	set("prewarmRepos", fc.Repositories.Prewarm)
+	set("repoAliases", fc.Repositories.Aliases)
+
+	set("username", fc.Credentials.Username)
+	set("password-file", fc.Credentials.PasswordFile)
+	set("bearer-token-file", fc.Credentials.BearerTokenFile)
+	set("registry-credentials", fc.Credentials.RegistryCredentials)
+	set("registry-plain-http", fc.Credentials.RegistryPlainHTTP)
+	set("tls-cert", fc.Credentials.TLSCert)
+	set("tls-key", fc.Credentials.TLSKey)
+	set("tls-ca", fc.Credentials.TLSCA)
+	set("tls-insecure-skip-verify", fc.Credentials.TLSInsecureSkipVerify)
+	set("pass-credentials-all", fc.Credentials.PassCredentialsAll)
+
+	set("cacheDir", fc.Cache.Dir)
+	set("indexTTL", fc.Cache.IndexTTL)
+	set("chartCacheSize", fc.Cache.ChartCacheSize)
+
+	set("repoTimeout", fc.Limits.RepoTimeout)
+	set("downloadTimeout", fc.Limits.DownloadTimeout)
+	set("retryAttempts", fc.Limits.RetryAttempts)
+	set("retryBackoff", fc.Limits.RetryBackoff)
+	set("maxChartSizeMB", fc.Limits.MaxChartSizeMB)
+	set("maxDecompressedChartSizeMB", fc.Limits.MaxDecompressedChartSizeMB)
+	set("rateLimit", fc.Limits.RateLimit)
+	set("maxResultBytes", fc.Limits.MaxResultBytes)
+
+	set("kubeconfig", fc.Cluster.Kubeconfig)
+	set("kubeContext", fc.Cluster.Context)
+	set("inCluster", fc.Cluster.InCluster)
+	set("enableWriteTools", fc.Cluster.EnableWriteTools)
+	set("clusters", fc.Cluster.Clusters)
+
+	return values
+}
+
+// applyConfigFile sets the flags of fs that were not given on the command line
+// to the values from the YAML file at path, so flags override the file.
+func applyConfigFile(fs *flag.FlagSet, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var fc fileConfig
+	if err := yaml.UnmarshalStrict(data, &fc); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	for name, value := range fc.flagValues() {
+		if explicit[name] {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("invalid value %q for %s in config file %s: %w", value, name, path, err)
+		}
+	}
+	return nil
+}
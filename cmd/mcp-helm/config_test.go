@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestApplyConfigFile(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	mode := fs.String("mode", "stdio", "")
+	addr := fs.String("httpListenAddr", ":8012", "")
+	timeout := fs.Duration("repoTimeout", 30*time.Second, "")
+	plainHTTP := fs.Bool("registry-plain-http", false, "")
+	allowed := fs.String("allowedRepos", "", "")
+
+	path := writeConfig(t, `
+server:
+  mode: http
+  httpListenAddr: ":9000"
+repositories:
+  allowed:
+    - oci://registry.internal/*
+    - https://charts.internal/*
+credentials:
+  registryPlainHTTP: true
+limits:
+  repoTimeout: 5s
+`)
+	if err := fs.Parse([]string{"-httpListenAddr=:7000"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := applyConfigFile(fs, path); err != nil {
+		t.Fatalf("applyConfigFile() error = %v", err)
+	}
+
+	if *mode != "http" {
+		t.Errorf("mode = %q, want value from file", *mode)
+	}
+	if *addr != ":7000" {
+		t.Errorf("httpListenAddr = %q, want command-line value to override the file", *addr)
+	}
+	if *timeout != 5*time.Second {
+		t.Errorf("repoTimeout = %v, want 5s", *timeout)
+	}
+	if !*plainHTTP {
+		t.Error("registry-plain-http = false, want true")
+	}
+	if *allowed != "oci://registry.internal/*,https://charts.internal/*" {
+		t.Errorf("allowedRepos = %q", *allowed)
+	}
+}
+
+func TestApplyConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown key", "server:\n  listen: \":80\"\n"},
+		{"invalid duration", "limits:\n  repoTimeout: soon\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.Duration("repoTimeout", 0, "")
+			if err := applyConfigFile(fs, writeConfig(t, tt.content)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// TestConfigFlagsExist guards against config fields that refer to flags that
+// do not exist, e.g. after a flag is renamed.
+func TestConfigFlagsExist(t *testing.T) {
+	var fc fileConfig
+	err := yaml.UnmarshalStrict([]byte(`
+server: {mode: a, logLevel: a, httpListenAddr: a, socketPath: a, httpHeartbeatInterval: a, sseKeepAliveInterval: a, shutdownTimeout: a, tlsCert: a, tlsKey: a, apiKey: a, enableTools: [a], disableTools: [a], pullChartDir: a}
+pricing: {cpuHour: 0.05, memoryGBHour: 0.005}
+repositories: {allowed: [a], denied: [a], local: true, pluginsDir: a, prewarm: [a], aliases: {a: b}}
+credentials: {username: a, passwordFile: a, bearerTokenFile: a, registryCredentials: a, registryPlainHTTP: true, tlsCert: a, tlsKey: a, tlsCA: a, tlsInsecureSkipVerify: true, passCredentialsAll: true}
+cache: {dir: a, indexTTL: a, chartCacheSize: 1}
+limits: {repoTimeout: a, downloadTimeout: a, retryAttempts: 1, retryBackoff: a, maxChartSizeMB: 1, maxDecompressedChartSizeMB: 1, rateLimit: 1, maxResultBytes: 1}
+cluster: {kubeconfig: a, context: a, inCluster: true, enableWriteTools: true, clusters: [{name: a, kubeconfig: a, context: a}]}
+`), &fc)
+	if err != nil {
+		t.Fatalf("UnmarshalStrict() error = %v", err)
+	}
+
+	values := fc.flagValues()
+	if len(values) != 47 {
+		t.Errorf("expected 47 values, got %d", len(values))
+	}
+	for name := range values {
+		if flag.Lookup(name) == nil {
+			t.Errorf("config refers to unknown flag -%s", name)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+const envPrefix = "MCP_HELM_"
+
+// envOverrides names the environment variables of flags whose derived name
+// would clash with another flag: -tlsCert and -tls-cert would both map to
+// MCP_HELM_TLS_CERT.
+var envOverrides = map[string]string{
+	"tlsCert": envPrefix + "SERVER_TLS_CERT",
+	"tlsKey":  envPrefix + "SERVER_TLS_KEY",
+}
+
+// envName returns the environment variable for a flag, e.g.
+// "httpListenAddr" -> "MCP_HELM_HTTP_LISTEN_ADDR",
+// "password-file" -> "MCP_HELM_PASSWORD_FILE" and
+// "maxChartSizeMB" -> "MCP_HELM_MAX_CHART_SIZE_MB" and
+// "memoryGBHourPrice" -> "MCP_HELM_MEMORY_GB_HOUR_PRICE".
+func envName(flagName string) string {
+	if name, ok := envOverrides[flagName]; ok {
+		return name
+	}
+
+	var b strings.Builder
+	b.WriteString(envPrefix)
+	runes := []rune(flagName)
+	for i, r := range runes {
+		switch {
+		case r == '-':
+			b.WriteRune('_')
+			continue
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			b.WriteRune('_')
+		case unicode.IsUpper(r) && i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// The first letter of a word after an acronym, as in GBHour.
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// applyEnv sets the flags of fs that were not set yet, either on the command
+// line or from the config file, to the value of their environment variable.
+func applyEnv(fs *flag.FlagSet) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || set[f.Name] {
+			return
+		}
+		name := envName(f.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q for %s: %w", value, name, setErr)
+		}
+	})
+	return err
+}
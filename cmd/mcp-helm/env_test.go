@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"testing"
+	"time"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"mode":                       "MCP_HELM_MODE",
+		"httpListenAddr":             "MCP_HELM_HTTP_LISTEN_ADDR",
+		"password-file":              "MCP_HELM_PASSWORD_FILE",
+		"maxChartSizeMB":             "MCP_HELM_MAX_CHART_SIZE_MB",
+		"maxDecompressedChartSizeMB": "MCP_HELM_MAX_DECOMPRESSED_CHART_SIZE_MB",
+		"memoryGBHourPrice":          "MCP_HELM_MEMORY_GB_HOUR_PRICE",
+		"cacheDir":                   "MCP_HELM_CACHE_DIR",
+		"apiKey":                     "MCP_HELM_API_KEY",
+		"tls-cert":                   "MCP_HELM_TLS_CERT",
+		"tlsCert":                    "MCP_HELM_SERVER_TLS_CERT",
+	}
+	for flagName, want := range tests {
+		if got := envName(flagName); got != want {
+			t.Errorf("envName(%q) = %q, want %q", flagName, got, want)
+		}
+	}
+}
+
+func TestEnvNamesAreUnique(t *testing.T) {
+	seen := make(map[string]string)
+	flag.VisitAll(func(f *flag.Flag) {
+		name := envName(f.Name)
+		if other, ok := seen[name]; ok {
+			t.Errorf("flags -%s and -%s both map to %s", other, f.Name, name)
+		}
+		seen[name] = f.Name
+	})
+}
+
+func TestApplyEnv(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	mode := fs.String("mode", "stdio", "")
+	addr := fs.String("httpListenAddr", ":8012", "")
+	timeout := fs.Duration("repoTimeout", 30*time.Second, "")
+
+	t.Setenv("MCP_HELM_MODE", "http")
+	t.Setenv("MCP_HELM_HTTP_LISTEN_ADDR", ":9000")
+	t.Setenv("MCP_HELM_REPO_TIMEOUT", "5s")
+
+	if err := fs.Parse([]string{"-httpListenAddr=:7000"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := applyEnv(fs); err != nil {
+		t.Fatalf("applyEnv() error = %v", err)
+	}
+
+	if *mode != "http" {
+		t.Errorf("mode = %q, want value from environment", *mode)
+	}
+	if *addr != ":7000" {
+		t.Errorf("httpListenAddr = %q, want command-line value to override the environment", *addr)
+	}
+	if *timeout != 5*time.Second {
+		t.Errorf("repoTimeout = %v, want 5s", *timeout)
+	}
+
+	t.Setenv("MCP_HELM_REPO_TIMEOUT", "soon")
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Duration("repoTimeout", 0, "")
+	if err := applyEnv(fs); err == nil {
+		t.Error("expected an error for an invalid duration")
+	}
+}
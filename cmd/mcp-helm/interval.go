@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// minInterval is the shortest accepted heartbeat or keep-alive interval;
+// shorter intervals only add traffic.
+const minInterval = time.Second
+
+// intervalValue is a flag.Value for heartbeat and keep-alive intervals. It
+// accepts durations like "30s" or "1m", and plain numbers as seconds, which
+// the flags were documented to take before.
+type intervalValue time.Duration
+
+func intervalFlag(name string, value time.Duration, usage string) *time.Duration {
+	d := value
+	flag.Var((*intervalValue)(&d), name, usage)
+	return &d
+}
+
+func (v *intervalValue) String() string {
+	return time.Duration(*v).String()
+}
+
+func (v *intervalValue) Set(s string) error {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		*v = intervalValue(time.Duration(secs * float64(time.Second)))
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid interval %q: use a duration such as 30s or 1m", s)
+	}
+	*v = intervalValue(d)
+	return nil
+}
+
+// validateInterval checks that an interval flag is either 0 (disabled) or at
+// least minInterval.
+func validateInterval(name string, d time.Duration) error {
+	if d < 0 || (d > 0 && d < minInterval) {
+		return fmt.Errorf("-%s must be 0 to disable it or at least %s, got %s", name, minInterval, d)
+	}
+	return nil
+}
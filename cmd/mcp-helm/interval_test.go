@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntervalValue(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30s", 30 * time.Second, false},
+		{"1m", time.Minute, false},
+		{"30", 30 * time.Second, false},
+		{"0", 0, false},
+		{"1.5", 1500 * time.Millisecond, false},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		var v intervalValue
+		err := v.Set(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && time.Duration(v) != tt.want {
+			t.Errorf("Set(%q) = %v, want %v", tt.in, time.Duration(v), tt.want)
+		}
+	}
+}
+
+func TestValidateInterval(t *testing.T) {
+	for d, valid := range map[time.Duration]bool{
+		0:                      true,
+		time.Second:            true,
+		30 * time.Second:       true,
+		30 * time.Nanosecond:   false,
+		500 * time.Millisecond: false,
+		-time.Second:           false,
+	} {
+		if err := validateInterval("interval", d); (err == nil) != valid {
+			t.Errorf("validateInterval(%v) error = %v, want valid = %v", d, err, valid)
+		}
+	}
+}
@@ -1,18 +1,29 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/mark3labs/mcp-go/server"
 	"go.uber.org/zap"
 
+	"github.com/zekker6/mcp-helm/internal/auth"
+	"github.com/zekker6/mcp-helm/internal/prompts"
+	"github.com/zekker6/mcp-helm/internal/ratelimit"
 	"github.com/zekker6/mcp-helm/internal/tools"
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
 	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
 	"github.com/zekker6/mcp-helm/lib/logger"
+	"github.com/zekker6/mcp-helm/lib/metrics"
 )
 
 func readPasswordFile(path string) (string, error) {
@@ -23,6 +34,14 @@ func readPasswordFile(path string) (string, error) {
 	return strings.TrimSpace(string(data)), nil
 }
 
+func readTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
 var (
 	version = "dev"
 	commit  = "none"
@@ -30,34 +49,69 @@ var (
 )
 
 var (
-	mode                 = flag.String("mode", "stdio", "Mode to run the MCP server in (stdio, sse, http)")
+	configFile           = flag.String("config", os.Getenv(envName("config")), "Path to a YAML configuration file. Flags given on the command line override values from the file")
+	mode                 = flag.String("mode", "stdio", "Mode to run the MCP server in (stdio, sse, http, unix)")
+	socketPath           = flag.String("socketPath", "", "Path of the Unix domain socket to serve the Streamable HTTP transport on. Only used when -mode=unix")
 	httpListenAddr       = flag.String("httpListenAddr", ":8012", "Address to listen for http connections in sse mode")
-	heartbeatInterval    = flag.Duration("httpHeartbeatInterval", 30*time.Second, "Interval for sending heartbeat messages in seconds. Only used when -mode=http")
-	sseKeepAliveInterval = flag.Duration("sseKeepAliveInterval", 30*time.Second, "Interval for sending keep-alive messages in seconds. Only used when -mode=sse")
+	heartbeatInterval    = intervalFlag("httpHeartbeatInterval", 30*time.Second, "Interval between heartbeat messages on idle Streamable HTTP streams, e.g. 30s or 1m. Set to 0 to disable. Only used when -mode=http or -mode=unix")
+	shutdownTimeout      = flag.Duration("shutdownTimeout", 30*time.Second, "Time to wait for in-flight requests to complete on SIGTERM or SIGINT in sse and http modes")
+	sseKeepAliveInterval = intervalFlag("sseKeepAliveInterval", 30*time.Second, "Interval between keep-alive messages on idle SSE streams, e.g. 30s or 1m. Set to 0 to disable. Only used when -mode=sse")
+	serverTLSCertFile    = flag.String("tlsCert", "", "Path to TLS certificate file for serving HTTPS in sse and http modes. Must be set together with -tlsKey")
+	serverTLSKeyFile     = flag.String("tlsKey", "", "Path to TLS private key file for serving HTTPS in sse and http modes. Must be set together with -tlsCert")
+	enableTools          = flag.String("enableTools", "", "Comma-separated list of tools to expose. All tools are exposed if empty")
+	disableTools         = flag.String("disableTools", "", "Comma-separated list of tools to hide, e.g. get_chart_contents. Applied after -enableTools")
+	rateLimit            = flag.Int("rateLimit", 0, "Maximum number of tool calls per minute from a single client IP address in sse and http modes. Set to 0 to disable")
+	maxResultBytes       = flag.Int("maxResultBytes", 0, "Maximum size of a tool result in bytes. Larger results are truncated and the rest is returned by the get_result_continuation tool. Set to 0 to disable")
+	apiKey               = flag.String("apiKey", "", "API key required from clients in sse and http modes, sent as \"Authorization: Bearer <key>\" or in the X-API-Key header. Prefer setting it with the MCP_HELM_API_KEY environment variable. Authentication is disabled if empty")
+	pullChartDir         = flag.String("pullChartDir", "", "Directory the pull_chart and package_chart tools may save charts to, e.g. the workspace of an agent. The tools are only exposed if set")
+	cpuHourPrice         = flag.Float64("cpuHourPrice", 0.04048, "Price of one vCPU for an hour used by estimate_chart_cost unless a call sets its own. Defaults to the AWS Fargate on-demand price in us-east-1 in USD")
+	memoryGBHourPrice    = flag.Float64("memoryGBHourPrice", 0.004445, "Price of one GiB of memory for an hour used by estimate_chart_cost unless a call sets its own. Defaults to the AWS Fargate on-demand price in us-east-1 in USD")
 
 	repoUsername     = flag.String("username", "", "Username for authentication (OCI registries and HTTP repositories)")
 	repoPasswordFile = flag.String("password-file", "", "Path to file containing password for authentication (OCI registries and HTTP repositories)")
+	bearerTokenFile  = flag.String("bearer-token-file", "", "Path to file containing bearer token for authentication with HTTP repositories")
 
 	registryCredentials = flag.String("registry-credentials", "", "Path to registry credentials file (e.g., Docker config.json)")
 	registryPlainHTTP   = flag.Bool("registry-plain-http", false, "Use plain HTTP for OCI registry connections (insecure)")
 
-	tlsCertFile           = flag.String("tls-cert", "", "Path to TLS client certificate file for HTTP repositories")
-	tlsKeyFile            = flag.String("tls-key", "", "Path to TLS client key file for HTTP repositories")
-	tlsCAFile             = flag.String("tls-ca", "", "Path to CA certificate file for verifying HTTP repository servers")
+	tlsCertFile           = flag.String("tls-cert", "", "Path to TLS client certificate file for HTTP repositories and OCI registries")
+	tlsKeyFile            = flag.String("tls-key", "", "Path to TLS client key file for HTTP repositories and OCI registries")
+	tlsCAFile             = flag.String("tls-ca", "", "Path to CA certificate file for verifying HTTP repository and OCI registry servers")
 	tlsInsecureSkipVerify = flag.Bool("tls-insecure-skip-verify", false, "Skip TLS certificate verification for HTTP repositories (insecure)")
 	passCredentialsAll    = flag.Bool("pass-credentials-all", false, "Pass credentials to all domains when following redirects")
-)
 
-func main() {
-	flag.Parse()
+	cacheDir                   = flag.String("cacheDir", "", "Directory for repository indexes, downloaded chart archives and temporary files, kept across restarts. Defaults to mcp-helm inside the user cache directory ($XDG_CACHE_HOME or ~/.cache)")
+	indexTTL                   = flag.Duration("indexTTL", 10*time.Minute, "Time after which a cached repository index is downloaded again. Set to 0 to keep indexes until restart")
+	repoTimeout                = flag.Duration("repoTimeout", 30*time.Second, "Timeout for fetching a repository index or listing chart versions. Set to 0 to disable")
+	downloadTimeout            = flag.Duration("downloadTimeout", time.Minute, "Timeout for downloading a single chart archive. Set to 0 to disable")
+	retryAttempts              = flag.Int("retryAttempts", 3, "Number of retries of index fetches and chart downloads after a transient failure (5xx response or reset connection). Set to 0 to disable retries")
+	retryBackoff               = flag.Duration("retryBackoff", 500*time.Millisecond, "Delay before the first retry of a failed download, doubled after every attempt")
+	maxChartSizeMB             = flag.Int64("maxChartSizeMB", 20, "Maximum size of a downloaded chart archive in MiB. Set to 0 to disable the limit")
+	maxDecompressedChartSizeMB = flag.Int64("maxDecompressedChartSizeMB", 100, "Maximum total size of the decompressed content of a chart in MiB. Set to 0 to disable the limit")
+	allowedRepos               = flag.String("allowedRepos", "", "Comma-separated list of repository URL patterns the tools may access, e.g. oci://registry.internal/*. \"*\" matches any characters. All repositories are allowed if empty")
+	deniedRepos                = flag.String("deniedRepos", "", "Comma-separated list of repository URL patterns the tools must not access. Takes precedence over -allowedRepos")
+	repoAliases                = repoAliasesFlag("repoAliases", "Comma-separated list of repository aliases tools accept as repository_url, as name=url, e.g. internal=https://charts.internal.example.com. Adds to and overrides the built-in aliases bitnami, prometheus-community and grafana")
+	prewarmRepos               = flag.String("prewarmRepos", "", "Comma-separated list of HTTP repository URLs or aliases whose indexes are downloaded at startup and refreshed before they expire, so tool calls do not wait for large indexes")
+	chartCacheSize             = flag.Int("chartCacheSize", 32, "Maximum number of loaded charts kept in memory. Set to 0 to disable the cache")
+	enableLocalCharts          = flag.Bool("enableLocalCharts", false, "Allow the tools to read charts from the local filesystem of the server, given as file:// URLs or paths to chart directories. Only enable if clients may read the filesystem, e.g. in stdio mode")
+	helmPluginsDir             = flag.String("helmPluginsDir", "", "Path to Helm plugins directory used to discover downloader plugins (e.g., for s3:// or gs:// repositories). Defaults to $HELM_PLUGINS or Helm's default location")
 
+	kubeconfig       = flag.String("kubeconfig", "", "Path to a kubeconfig file. Enables cluster mode, which adds tools inspecting the Helm releases installed in the cluster")
+	kubeContext      = flag.String("kubeContext", "", "Kubeconfig context to use in cluster mode. Defaults to the current context. Cluster tools can select another context per call")
+	inCluster        = flag.Bool("inCluster", false, "Enable cluster mode for the cluster mcp-helm runs in, using the service account of its pod instead of a kubeconfig")
+	clusters         = clustersFlag("clusters", "Comma-separated list of named clusters cluster tools can select, as name=kubeconfig#context, e.g. prod=/etc/kube/prod,staging=#staging. Enables cluster mode. The first cluster is the default unless -kubeconfig or -inCluster is set")
+	enableWriteTools = flag.Bool("enableWriteTools", false, "Expose tools that push charts to OCI registries and, in cluster mode, install, upgrade, roll back and uninstall releases. Only enable in trusted environments")
+)
+
+// serve runs the MCP server in the configured mode until it is stopped.
+func serve() {
 	logger.Init()
 	defer logger.Stop()
 
 	switch *mode {
-	case "stdio", "sse", "http":
+	case "stdio", "sse", "http", "unix":
 	default:
-		logger.Error("Invalid mode specified: %s. Supported modes are 'stdio', 'sse', and 'http'", zap.String("mode", *mode))
+		logger.Error("Invalid mode specified: %s. Supported modes are 'stdio', 'sse', 'http' and 'unix'", zap.String("mode", *mode))
 		os.Exit(1)
 	}
 
@@ -67,23 +121,64 @@ func main() {
 			logger.Error("HTTP listen address must be specified in sse mode. Use -httpListenAddr to set it", zap.String("httpListenAddr", *httpListenAddr))
 			os.Exit(1)
 		}
+		if (*serverTLSCertFile != "") != (*serverTLSKeyFile != "") {
+			logger.Error("Both -tlsCert and -tlsKey must be provided together")
+			os.Exit(1)
+		}
+	case "unix":
+		if *socketPath == "" {
+			logger.Error("Socket path must be specified in unix mode. Use -socketPath to set it")
+			os.Exit(1)
+		}
+	}
+	if *inCluster && (*kubeconfig != "" || *kubeContext != "") {
+		logger.Error("-inCluster cannot be combined with -kubeconfig and -kubeContext")
+		os.Exit(1)
+	}
+	for name, interval := range map[string]time.Duration{
+		"httpHeartbeatInterval": *heartbeatInterval,
+		"sseKeepAliveInterval":  *sseKeepAliveInterval,
+	} {
+		if err := validateInterval(name, interval); err != nil {
+			logger.Error("Invalid interval", zap.Error(err))
+			os.Exit(1)
+		}
 	}
 
+	serverOpts := []server.ServerOption{
+		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(false),
+		server.WithResourceCapabilities(false, false),
+		server.WithRecovery(),
+		server.WithToolHandlerMiddleware(metrics.ToolMiddleware),
+	}
+	if *rateLimit > 0 {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(ratelimit.New(*rateLimit).Middleware))
+	}
+	var resultLimiter *tools.ResultLimiter
+	if *maxResultBytes > 0 {
+		resultLimiter = tools.NewResultLimiter(*maxResultBytes)
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(resultLimiter.Middleware))
+	}
 	s := server.NewMCPServer(
 		"Helm MCP Server",
 		fmt.Sprintf("v%s (commit: %s, date: %s)", version, commit, date),
-		server.WithToolCapabilities(false),
-		server.WithRecovery(),
+		serverOpts...,
 	)
 
 	helmClient := getHelmClient()
-	s.AddTool(tools.NewListChartsTool(), tools.GetListChartsHandler(helmClient))
-	s.AddTool(tools.NewListChartVersionsTool(), tools.GetListChartVersionsHandler(helmClient))
-	s.AddTool(tools.NewGetLatestVersionOfChartTool(), tools.GetLatestVersionOfCharHandler(helmClient))
-	s.AddTool(tools.NewGetChartValuesTool(), tools.GetChartValuesHandler(helmClient))
-	s.AddTool(tools.NewGetChartContentsTool(), tools.GetChartContentsHandler(helmClient))
-	s.AddTool(tools.NewGetChartDependenciesTool(), tools.GetChartDependenciesHandler(helmClient))
-	s.AddTool(tools.NewGetChartImagesTool(), tools.GetChartImagesHandler(helmClient))
+	defer func() { _ = helmClient.Close() }()
+	serverTools, err := selectTools(allTools(helmClient, getClusterClient(), resultLimiter), splitList(*enableTools), splitList(*disableTools))
+	if err != nil {
+		logger.Error("Invalid tool selection", zap.Error(err))
+		_ = helmClient.Close()
+		os.Exit(1)
+	}
+	s.AddTools(serverTools...)
+	s.AddResourceTemplate(tools.NewChartFileResourceTemplate(), tools.GetChartFileResourceHandler(helmClient))
+	s.AddPrompt(prompts.NewReviewChartUpgradePrompt(), prompts.GetReviewChartUpgradeHandler())
+	s.AddPrompt(prompts.NewGenerateChartValuesPrompt(), prompts.GetGenerateChartValuesHandler())
+	s.AddPrompt(prompts.NewAuditChartSecurityPrompt(), prompts.GetAuditChartSecurityHandler())
 
 	logger.Info("Starting MCP Helm server",
 		zap.String("version", version),
@@ -91,30 +186,54 @@ func main() {
 		zap.String("date", date),
 		zap.String("mode", *mode),
 		zap.String("httpListenAddr", *httpListenAddr),
+		zap.Bool("localCharts", *enableLocalCharts),
+		zap.String("socketPath", *socketPath),
+		zap.Bool("cluster", clusterMode()),
+		zap.Bool("writeTools", *enableWriteTools),
+		zap.Bool("tls", *serverTLSCertFile != ""),
+		zap.Bool("auth", *apiKey != ""),
 	)
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if repos := splitList(*prewarmRepos); len(repos) > 0 {
+		for i, repo := range repos {
+			repos[i] = helmClient.ResolveRepositoryURL(repo)
+		}
+		helmClient.KeepIndexesWarm(ctx, repos)
+	}
+
 	switch *mode {
 	case "stdio":
 		if err := server.ServeStdio(s); err != nil {
 			logger.Error("Failed to start MCP server in stdio mode", zap.Error(err))
 		}
 	case "sse":
-		var opts []server.SSEOption
+		opts := []server.SSEOption{server.WithSSEContextFunc(ratelimit.WithClient)}
 		if *sseKeepAliveInterval > 0 {
 			opts = append(opts, server.WithKeepAliveInterval(*sseKeepAliveInterval))
 		}
 
-		srv := server.NewSSEServer(s, opts...)
-		if err := srv.Start(*httpListenAddr); err != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		sseSrv := server.NewSSEServer(s, opts...)
+		mux.Handle("/", sseSrv)
+		// Open SSE streams never become idle, so close them to let the
+		// shutdown complete once in-flight tool calls are done.
+		if err := listenAndServe(ctx, mux, sseSrv.CloseSessions); err != nil {
 			logger.Error("Failed to start SSE server", zap.Error(err))
 		}
-	case "http":
-		var opts []server.StreamableHTTPOption
+	case "http", "unix":
+		opts := []server.StreamableHTTPOption{server.WithHTTPContextFunc(ratelimit.WithClient)}
 		if *heartbeatInterval > 0 {
 			opts = append(opts, server.WithHeartbeatInterval(*heartbeatInterval))
 		}
-		srv := server.NewStreamableHTTPServer(s, opts...)
-		if err := srv.Start(*httpListenAddr); err != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		streamSrv := server.NewStreamableHTTPServer(s, opts...)
+		mux.Handle("/mcp", streamSrv)
+		if err := listenAndServe(ctx, mux, func() { _ = streamSrv.Shutdown(context.Background()) }); err != nil {
 			logger.Error("Failed to start HTTP server", zap.Error(err))
 		}
 	default:
@@ -122,6 +241,207 @@ func main() {
 	}
 }
 
+// listenAndServe serves handler on -httpListenAddr, or on -socketPath in unix
+// mode, over HTTPS if a server certificate is configured and behind API key
+// authentication if a key is set.
+// When ctx is cancelled the server stops accepting connections, calls
+// onShutdown and waits up to -shutdownTimeout for in-flight requests.
+func listenAndServe(ctx context.Context, handler http.Handler, onShutdown func()) error {
+	if *apiKey != "" {
+		handler = auth.Middleware(*apiKey, handler)
+	}
+	srv := &http.Server{Addr: *httpListenAddr, Handler: handler}
+	srv.RegisterOnShutdown(onShutdown)
+
+	ln, err := listen()
+	if err != nil {
+		return err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if *serverTLSCertFile != "" && *mode != "unix" {
+			errCh <- srv.ServeTLS(ln, *serverTLSCertFile, *serverTLSKeyFile)
+		} else {
+			errCh <- srv.Serve(ln)
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutting down server, waiting for in-flight requests", zap.Duration("timeout", *shutdownTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+	return nil
+}
+
+// listen opens the listener for the configured mode. In unix mode a stale
+// socket left by a previous run is removed first, and the socket is only
+// accessible by the current user; it is removed again when the listener is
+// closed.
+func listen() (net.Listener, error) {
+	if *mode != "unix" {
+		return net.Listen("tcp", *httpListenAddr)
+	}
+
+	if info, err := os.Lstat(*socketPath); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(*socketPath); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", *socketPath, err)
+		}
+	}
+	ln, err := net.Listen("unix", *socketPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(*socketPath, 0o600); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("failed to set permissions of socket %s: %w", *socketPath, err)
+	}
+	return ln, nil
+}
+
+// allTools returns every tool the server provides, with handlers using c, cc
+// and rl. Cluster tools are only provided in cluster mode, pull_chart and
+// package_chart only if -pullChartDir is set, push_chart only if
+// -enableWriteTools is set, and get_result_continuation only if
+// -maxResultBytes is set, which also removes the output schemas.
+func allTools(c *helm_client.HelmClient, cc *cluster_client.ClusterClient, rl *tools.ResultLimiter) []server.ServerTool {
+	all := []server.ServerTool{
+		{Tool: tools.NewListChartsTool(), Handler: tools.GetListChartsHandler(c)},
+		{Tool: tools.NewListChartVersionsTool(), Handler: tools.GetListChartVersionsHandler(c)},
+		{Tool: tools.NewGetLatestVersionOfChartTool(), Handler: tools.GetLatestVersionOfCharHandler(c)},
+		{Tool: tools.NewGetChartAppVersionTool(), Handler: tools.GetChartAppVersionHandler(c)},
+		{Tool: tools.NewGetChartDigestTool(), Handler: tools.GetChartDigestHandler(c)},
+		{Tool: tools.NewGetChartDownloadURLTool(), Handler: tools.GetChartDownloadURLHandler(c)},
+		{Tool: tools.NewFindChartVersionByAppVersionTool(), Handler: tools.GetFindChartVersionByAppVersionHandler(c)},
+		{Tool: tools.NewFindChartTool(), Handler: tools.GetFindChartHandler(c)},
+		{Tool: tools.NewGetChartValuesTool(), Handler: tools.GetChartValuesHandler(c)},
+		{Tool: tools.NewGetChartContentsTool(), Handler: tools.GetChartContentsHandler(c)},
+		{Tool: tools.NewListChartFilesTool(), Handler: tools.GetListChartFilesHandler(c)},
+		{Tool: tools.NewGetChartFileTool(), Handler: tools.GetChartFileHandler(c)},
+		{Tool: tools.NewGetChartDependenciesTool(), Handler: tools.GetChartDependenciesHandler(c)},
+		{Tool: tools.NewGetChartDependencyTreeTool(), Handler: tools.GetChartDependencyTreeHandler(c)},
+		{Tool: tools.NewAnalyzeTemplateFeaturesTool(), Handler: tools.AnalyzeTemplateFeaturesHandler(c)},
+		{Tool: tools.NewGetEffectiveValuesTool(), Handler: tools.GetEffectiveValuesHandler(c)},
+		{Tool: tools.NewValidateValuesTool(), Handler: tools.GetValidateValuesHandler(c)},
+		{Tool: tools.NewRunChartUnitTestsTool(), Handler: tools.GetRunChartUnitTestsHandler(c)},
+		{Tool: tools.NewScoreChartTool(), Handler: tools.GetScoreChartHandler(c)},
+		{Tool: tools.NewCheckPodSecurityTool(), Handler: tools.GetCheckPodSecurityHandler(c)},
+		{Tool: tools.NewGetChartAvailabilityTool(), Handler: tools.GetChartAvailabilityHandler(c)},
+		{Tool: tools.NewGetChartIngressesTool(), Handler: tools.GetChartIngressesHandler(c)},
+		{Tool: tools.NewGetChartServicesTool(), Handler: tools.GetChartServicesHandler(c)},
+		{Tool: tools.NewGetChartConfigUsageTool(), Handler: tools.GetChartConfigUsageHandler(c)},
+		{Tool: tools.NewGetChartStorageTool(), Handler: tools.GetChartStorageHandler(c)},
+		{Tool: tools.NewGetChartSchedulingTool(), Handler: tools.GetChartSchedulingHandler(c)},
+		{Tool: tools.NewCheckChartHATool(), Handler: tools.CheckChartHAHandler(c)},
+		{Tool: tools.NewCheckChartLabelsTool(), Handler: tools.CheckChartLabelsHandler(c)},
+		{Tool: tools.NewFindExternalDependenciesTool(), Handler: tools.FindExternalDependenciesHandler(c)},
+		{Tool: tools.NewEstimateChartCostTool(), Handler: tools.GetEstimateChartCostHandler(c, helm_parser.PriceTable{CPUHour: *cpuHourPrice, MemoryGBHour: *memoryGBHourPrice})},
+		{Tool: tools.NewCheckOutdatedDependenciesTool(), Handler: tools.GetCheckOutdatedDependenciesHandler(c)},
+		{Tool: tools.NewCompareChartsTool(), Handler: tools.GetCompareChartsHandler(c)},
+		{Tool: tools.NewCompareManifestSnapshotTool(), Handler: tools.GetCompareManifestSnapshotHandler(c)},
+		{Tool: tools.NewGetChartImagesTool(), Handler: tools.GetChartImagesHandler(c)},
+		{Tool: tools.NewRelocateChartImagesTool(), Handler: tools.GetRelocateChartImagesHandler(c)},
+		{Tool: tools.NewPlanOfflineBundleTool(), Handler: tools.GetPlanOfflineBundleHandler(c)},
+		{Tool: tools.NewListChartReferrersTool(), Handler: tools.GetListChartReferrersHandler(c)},
+		{Tool: tools.NewGetCacheInfoTool(), Handler: tools.GetCacheInfoHandler(c)},
+		{Tool: tools.NewInvalidateCacheTool(), Handler: tools.GetInvalidateCacheHandler(c)},
+	}
+	if *pullChartDir != "" {
+		all = append(all,
+			server.ServerTool{Tool: tools.NewPullChartTool(), Handler: tools.GetPullChartHandler(c, *pullChartDir)},
+			server.ServerTool{Tool: tools.NewPackageChartTool(), Handler: tools.GetPackageChartHandler(c, *pullChartDir)},
+		)
+	}
+	if *enableWriteTools {
+		all = append(all, server.ServerTool{Tool: tools.NewPushChartTool(), Handler: tools.GetPushChartHandler(c)})
+	}
+	if *maxResultBytes > 0 {
+		all = append(all, server.ServerTool{Tool: tools.NewGetResultContinuationTool(), Handler: tools.GetResultContinuationHandler(rl)})
+	}
+	if clusterMode() {
+		all = append(all,
+			server.ServerTool{Tool: tools.NewListClustersTool(), Handler: tools.GetListClustersHandler(cc)},
+			server.ServerTool{Tool: tools.NewListReleasesTool(), Handler: tools.GetListReleasesHandler(cc)},
+			server.ServerTool{Tool: tools.NewGetReleaseValuesTool(), Handler: tools.GetReleaseValuesHandler(cc)},
+			server.ServerTool{Tool: tools.NewGetReleaseManifestTool(), Handler: tools.GetReleaseManifestHandler(cc)},
+			server.ServerTool{Tool: tools.NewGetReleaseStatusTool(), Handler: tools.GetReleaseStatusHandler(cc)},
+			server.ServerTool{Tool: tools.NewScanDeprecatedAPIsTool(), Handler: tools.GetScanDeprecatedAPIsHandler(cc)},
+			server.ServerTool{Tool: tools.NewPreviewReleaseUpgradeTool(), Handler: tools.GetPreviewReleaseUpgradeHandler(c, cc)},
+			server.ServerTool{Tool: tools.NewCompareReleaseValuesTool(), Handler: tools.GetCompareReleaseValuesHandler(c, cc)},
+			server.ServerTool{Tool: tools.NewDryRunInstallTool(), Handler: tools.GetDryRunInstallHandler(c, cc)},
+		)
+		if *enableWriteTools {
+			all = append(all,
+				server.ServerTool{Tool: tools.NewInstallChartTool(), Handler: tools.GetInstallChartHandler(c, cc)},
+				server.ServerTool{Tool: tools.NewUpgradeReleaseTool(), Handler: tools.GetUpgradeReleaseHandler(c, cc)},
+				server.ServerTool{Tool: tools.NewRollbackReleaseTool(), Handler: tools.GetRollbackReleaseHandler(cc)},
+				server.ServerTool{Tool: tools.NewUninstallReleaseTool(), Handler: tools.GetUninstallReleaseHandler(cc)},
+			)
+		}
+	}
+	if *maxResultBytes > 0 {
+		for i := range all {
+			all[i].Tool = tools.WithoutOutputSchema(all[i].Tool)
+		}
+	}
+	return all
+}
+
+// selectTools returns the tools named in enable, or all tools if enable is
+// empty, without the tools named in disable. Unknown tool names are rejected
+// so that a typo does not silently expose a tool that was meant to be hidden.
+func selectTools(all []server.ServerTool, enable, disable []string) ([]server.ServerTool, error) {
+	known := make(map[string]bool, len(all))
+	for _, t := range all {
+		known[t.Tool.Name] = true
+	}
+	toSet := func(names []string) (map[string]bool, error) {
+		set := make(map[string]bool, len(names))
+		for _, name := range names {
+			if !known[name] {
+				return nil, fmt.Errorf("unknown tool %q", name)
+			}
+			set[name] = true
+		}
+		return set, nil
+	}
+	enabled, err := toSet(enable)
+	if err != nil {
+		return nil, err
+	}
+	disabled, err := toSet(disable)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []server.ServerTool
+	for _, t := range all {
+		if (len(enabled) == 0 || enabled[t.Tool.Name]) && !disabled[t.Tool.Name] {
+			selected = append(selected, t)
+		}
+	}
+	return selected, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getHelmClient() *helm_client.HelmClient {
 	var clientOpts []helm_client.ClientOption
 
@@ -135,6 +455,11 @@ func getHelmClient() *helm_client.HelmClient {
 		os.Exit(1)
 	}
 
+	if *bearerTokenFile != "" && *repoUsername != "" {
+		logger.Error("-bearer-token-file cannot be combined with -username and -password-file")
+		os.Exit(1)
+	}
+
 	// Validate TLS client cert flags - both must be provided together
 	if (*tlsCertFile != "") != (*tlsKeyFile != "") {
 		if *tlsCertFile != "" {
@@ -154,6 +479,15 @@ func getHelmClient() *helm_client.HelmClient {
 		clientOpts = append(clientOpts, helm_client.WithBasicAuth(*repoUsername, password))
 	}
 
+	if *bearerTokenFile != "" {
+		token, err := readTokenFile(*bearerTokenFile)
+		if err != nil {
+			logger.Error("Failed to read bearer token file", zap.Error(err))
+			os.Exit(1)
+		}
+		clientOpts = append(clientOpts, helm_client.WithBearerToken(token))
+	}
+
 	if *registryCredentials != "" {
 		clientOpts = append(clientOpts, helm_client.WithCredentialsFile(*registryCredentials))
 	}
@@ -162,7 +496,7 @@ func getHelmClient() *helm_client.HelmClient {
 	}
 
 	if *tlsCertFile != "" && *tlsKeyFile != "" {
-		clientOpts = append(clientOpts, helm_client.WithTLSClientConfig(*tlsCertFile, *tlsKeyFile))
+		clientOpts = append(clientOpts, helm_client.WithClientTLS(*tlsCertFile, *tlsKeyFile))
 	}
 	if *tlsCAFile != "" {
 		clientOpts = append(clientOpts, helm_client.WithCAFile(*tlsCAFile))
@@ -173,6 +507,28 @@ func getHelmClient() *helm_client.HelmClient {
 	if *passCredentialsAll {
 		clientOpts = append(clientOpts, helm_client.WithPassCredentialsAll(true))
 	}
+	clientOpts = append(clientOpts,
+		helm_client.WithChartCacheSize(*chartCacheSize),
+		helm_client.WithIndexTTL(*indexTTL),
+		helm_client.WithRepoTimeout(*repoTimeout),
+		helm_client.WithDownloadTimeout(*downloadTimeout),
+		helm_client.WithRetry(*retryAttempts, *retryBackoff),
+		helm_client.WithMaxChartSize(*maxChartSizeMB<<20, *maxDecompressedChartSizeMB<<20),
+	)
+	if *cacheDir != "" {
+		clientOpts = append(clientOpts, helm_client.WithCacheDir(*cacheDir))
+	}
+	if len(*repoAliases) > 0 {
+		clientOpts = append(clientOpts, helm_client.WithRepoAliases(*repoAliases))
+	}
+	if *helmPluginsDir != "" {
+		clientOpts = append(clientOpts, helm_client.WithPluginsDirectory(*helmPluginsDir))
+	}
+	clientOpts = append(clientOpts,
+		helm_client.WithAllowedRepos(splitList(*allowedRepos)...),
+		helm_client.WithDeniedRepos(splitList(*deniedRepos)...),
+	)
+	clientOpts = append(clientOpts, helm_client.WithLocalCharts(*enableLocalCharts))
 
 	helmClient, err := helm_client.NewClient(clientOpts...)
 	if err != nil {
@@ -181,3 +537,45 @@ func getHelmClient() *helm_client.HelmClient {
 	}
 	return helmClient
 }
+
+// clusterMode reports whether the cluster tools are enabled, by -kubeconfig,
+// -inCluster or -clusters.
+func clusterMode() bool {
+	return *kubeconfig != "" || *inCluster || len(*clusters) > 0
+}
+
+// getClusterClient returns the client for cluster mode, or nil if cluster mode
+// is not enabled.
+func getClusterClient() *cluster_client.ClusterClient {
+	if !clusterMode() {
+		return nil
+	}
+
+	named := *clusters
+	var opts []cluster_client.ClientOption
+	switch {
+	case *inCluster:
+		opts = append(opts, cluster_client.WithInCluster())
+	case *kubeconfig != "":
+		opts = append(opts,
+			cluster_client.WithKubeconfig(*kubeconfig),
+			cluster_client.WithKubeContext(*kubeContext),
+		)
+	default:
+		// Without -kubeconfig and -inCluster the first named cluster is
+		// the default one.
+		opts = append(opts,
+			cluster_client.WithName(named[0].Name),
+			cluster_client.WithKubeconfig(named[0].Kubeconfig),
+			cluster_client.WithKubeContext(named[0].Context),
+		)
+		named = named[1:]
+	}
+	opts = append(opts, cluster_client.WithClusters(named...))
+	clusterClient, err := cluster_client.NewClient(opts...)
+	if err != nil {
+		logger.Error("Failed to create cluster client", zap.Error(err))
+		os.Exit(1)
+	}
+	return clusterClient
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// repoAliasesValue is a flag.Value for repository aliases, written as a
+// comma-separated list of name=url entries.
+type repoAliasesValue map[string]string
+
+func repoAliasesFlag(name, usage string) *map[string]string {
+	var aliases map[string]string
+	flag.Var((*repoAliasesValue)(&aliases), name, usage)
+	return &aliases
+}
+
+func (v *repoAliasesValue) String() string {
+	if v == nil {
+		return ""
+	}
+	entries := make([]string, 0, len(*v))
+	for name, url := range *v {
+		entries = append(entries, name+"="+url)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+func (v *repoAliasesValue) Set(s string) error {
+	aliases := make(map[string]string)
+	for _, entry := range splitList(s) {
+		name, url, ok := strings.Cut(entry, "=")
+		name, url = strings.TrimSpace(name), strings.TrimSpace(url)
+		if !ok || name == "" || url == "" {
+			return fmt.Errorf("invalid repository alias %q: use name=url", entry)
+		}
+		if strings.Contains(name, "/") {
+			return fmt.Errorf("invalid repository alias %q: the name must not contain \"/\"", entry)
+		}
+		aliases[name] = url
+	}
+	*v = aliases
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRepoAliasesValue(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"", map[string]string{}, false},
+		{"internal=https://charts.internal.example.com", map[string]string{"internal": "https://charts.internal.example.com"}, false},
+		{"internal = https://charts.internal.example.com, mirror=oci://registry.internal/charts", map[string]string{
+			"internal": "https://charts.internal.example.com",
+			"mirror":   "oci://registry.internal/charts",
+		}, false},
+		{"internal", nil, true},
+		{"=https://charts.internal.example.com", nil, true},
+		{"internal=", nil, true},
+		{"charts/internal=https://charts.internal.example.com", nil, true},
+	}
+	for _, tt := range tests {
+		var v repoAliasesValue
+		err := v.Set(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if !reflect.DeepEqual(map[string]string(v), tt.want) {
+			t.Errorf("Set(%q) = %v, want %v", tt.in, v, tt.want)
+		}
+
+		// The flag value written by the config file must parse back.
+		var again repoAliasesValue
+		if err := again.Set(v.String()); err != nil || !reflect.DeepEqual(again, v) {
+			t.Errorf("Set(%q) = %v, %v, want %v", v.String(), again, err, v)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Middleware rejects requests that do not carry token, either as
+// "Authorization: Bearer <token>" or in the X-API-Key header.
+func Middleware(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !valid(r, token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp-helm"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func valid(r *http.Request, token string) bool {
+	provided := r.Header.Get("X-API-Key")
+	if provided == "" {
+		scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return false
+		}
+		provided = strings.TrimSpace(value)
+	}
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
@@ -0,0 +1,44 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func NewAuditChartSecurityPrompt() mcp.Prompt {
+	opts := []mcp.PromptOption{
+		mcp.WithPromptDescription("Audits the security of a Helm chart: images, pod security settings, RBAC and exposed services."),
+	}
+	opts = append(opts, chartArguments(true)...)
+	return mcp.NewPrompt("audit_chart_security", opts...)
+}
+
+func GetAuditChartSecurityHandler() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ref, err := extractChartRef(request)
+		if err != nil {
+			return nil, err
+		}
+
+		text := fmt.Sprintf(`Audit the security of %s with its default values.
+
+Use the Helm tools with %s:
+1. Call get_chart_images with recursive=true and flag images using the latest tag, no tag or no digest, and images from untrusted registries.
+2. Call get_chart_contents with content_filter="templates" and recursive=true, paging with offset if the result is truncated, and check for:
+   - privileged containers, allowPrivilegeEscalation, added capabilities, running as root and missing readOnlyRootFilesystem;
+   - hostNetwork, hostPID, hostIPC, hostPath volumes and host ports;
+   - RBAC rules with wildcards, cluster-wide permissions, access to secrets and automounted service account tokens;
+   - Services of type LoadBalancer or NodePort, Ingresses without TLS and missing NetworkPolicies;
+   - missing resource requests and limits.
+3. Call get_chart_values and check whether insecure settings are enabled by default and whether secure alternatives can be configured.
+4. Call get_chart_dependencies and note subcharts that need their own review.
+
+Report every finding with a severity (critical, high, medium or low), the file or value it comes from and a recommended values override to fix it.`,
+			ref, ref.toolArgs())
+
+		return newResult("Security audit of a Helm chart", text), nil
+	}
+}
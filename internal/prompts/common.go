@@ -0,0 +1,79 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// chartArguments returns the arguments identifying a chart, shared by all
+// prompts. chart_version is only added if withVersion is true.
+func chartArguments(withVersion bool) []mcp.PromptOption {
+	opts := []mcp.PromptOption{
+		mcp.WithArgument("repository_url",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("Helm repository URL, OCI registry (e.g., oci://ghcr.io/org/charts/mychart) or local chart directory"),
+		),
+		mcp.WithArgument("chart_name",
+			mcp.ArgumentDescription("Chart name. Can be omitted for OCI URLs that include the chart name and for local charts"),
+		),
+	}
+	if withVersion {
+		opts = append(opts, mcp.WithArgument("chart_version",
+			mcp.ArgumentDescription("Chart version. If omitted the latest version is used"),
+		))
+	}
+	return opts
+}
+
+// chartRef is the chart selected by the prompt arguments.
+type chartRef struct {
+	repositoryURL string
+	chartName     string
+	chartVersion  string
+}
+
+func extractChartRef(request mcp.GetPromptRequest) (chartRef, error) {
+	args := request.Params.Arguments
+	ref := chartRef{
+		repositoryURL: strings.TrimSpace(args["repository_url"]),
+		chartName:     strings.TrimSpace(args["chart_name"]),
+		chartVersion:  strings.TrimSpace(args["chart_version"]),
+	}
+	if ref.repositoryURL == "" {
+		return ref, fmt.Errorf("repository_url is required")
+	}
+	return ref, nil
+}
+
+// String describes the chart for the prompt text, e.g.
+// `chart "nginx" from repository "https://charts.example.com", version "1.2.3"`.
+func (r chartRef) String() string {
+	s := "the chart"
+	if r.chartName != "" {
+		s = fmt.Sprintf("chart %q", r.chartName)
+	}
+	s += fmt.Sprintf(" from repository %q", r.repositoryURL)
+	if r.chartVersion != "" {
+		s += fmt.Sprintf(", version %q", r.chartVersion)
+	} else {
+		s += ", latest version (resolve it with get_latest_version_of_chart and use it for every tool call)"
+	}
+	return s
+}
+
+// toolArgs describes the arguments to pass to chart tools for r.
+func (r chartRef) toolArgs() string {
+	s := fmt.Sprintf("repository_url=%q", r.repositoryURL)
+	if r.chartName != "" {
+		s += fmt.Sprintf(", chart_name=%q", r.chartName)
+	}
+	return s
+}
+
+func newResult(description, text string) *mcp.GetPromptResult {
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	})
+}
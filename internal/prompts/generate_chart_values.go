@@ -0,0 +1,52 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func NewGenerateChartValuesPrompt() mcp.Prompt {
+	opts := []mcp.PromptOption{
+		mcp.WithPromptDescription("Generates a values file for a Helm chart from a description of the desired deployment."),
+	}
+	opts = append(opts, chartArguments(true)...)
+	opts = append(opts,
+		mcp.WithArgument("requirements",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("Description of the desired deployment, e.g. replicas, ingress host, resources or persistence"),
+		),
+	)
+	return mcp.NewPrompt("generate_chart_values", opts...)
+}
+
+func GetGenerateChartValuesHandler() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ref, err := extractChartRef(request)
+		if err != nil {
+			return nil, err
+		}
+		requirements := strings.TrimSpace(request.Params.Arguments["requirements"])
+		if requirements == "" {
+			return nil, fmt.Errorf("requirements is required")
+		}
+
+		text := fmt.Sprintf(`Write a Helm values file for %s that meets these requirements:
+
+%s
+
+Use the Helm tools with %s:
+1. Call get_chart_values to learn the available options and their defaults.
+2. Call list_chart_files and, if present, read README.md and values.schema.json with get_chart_file to understand how the options are used and validated.
+3. Check templates with get_chart_file where the meaning of an option is unclear.
+4. Call get_chart_images with the generated values as custom_values to check that the chart renders and uses the expected images.
+
+Only include values that differ from the defaults, keep the structure of the chart's values, and add a short comment to every non-obvious setting. If a requirement cannot be met by the chart, say so instead of inventing options.`,
+			ref, requirements, ref.toolArgs())
+
+		return newResult("Values file for a Helm chart", text), nil
+	}
+}
@@ -0,0 +1,90 @@
+package prompts
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func getPrompt(t *testing.T, handler server.PromptHandlerFunc, args map[string]string) (string, error) {
+	t.Helper()
+	var request mcp.GetPromptRequest
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request)
+	if err != nil {
+		return "", err
+	}
+	if len(result.Messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(result.Messages))
+	}
+	text, ok := result.Messages[0].Content.(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Messages[0].Content)
+	}
+	return text.Text, nil
+}
+
+func TestPrompts(t *testing.T) {
+	tests := []struct {
+		name     string
+		prompt   mcp.Prompt
+		handler  server.PromptHandlerFunc
+		args     map[string]string
+		contains []string
+	}{
+		{
+			name:     "review chart upgrade",
+			prompt:   NewReviewChartUpgradePrompt(),
+			handler:  GetReviewChartUpgradeHandler(),
+			args:     map[string]string{"repository_url": "https://charts.example.com", "chart_name": "nginx", "current_version": "1.0.0", "target_version": "2.0.0"},
+			contains: []string{`"1.0.0"`, `version "2.0.0"`, `chart_name="nginx"`, "get_chart_values"},
+		},
+		{
+			name:     "generate chart values",
+			prompt:   NewGenerateChartValuesPrompt(),
+			handler:  GetGenerateChartValuesHandler(),
+			args:     map[string]string{"repository_url": "oci://ghcr.io/org/charts/app", "requirements": "3 replicas with ingress"},
+			contains: []string{"3 replicas with ingress", "latest version", `repository_url="oci://ghcr.io/org/charts/app"`},
+		},
+		{
+			name:     "audit chart security",
+			prompt:   NewAuditChartSecurityPrompt(),
+			handler:  GetAuditChartSecurityHandler(),
+			args:     map[string]string{"repository_url": "https://charts.example.com", "chart_name": "nginx", "chart_version": "1.0.0"},
+			contains: []string{`version "1.0.0"`, "get_chart_images", "severity"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := getPrompt(t, tt.handler, tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(text, s) {
+					t.Errorf("expected prompt to contain %q, got:\n%s", s, text)
+				}
+			}
+
+			// Every required argument must be enforced by the handler.
+			for _, arg := range tt.prompt.Arguments {
+				if !arg.Required {
+					continue
+				}
+				args := make(map[string]string)
+				for k, v := range tt.args {
+					if k != arg.Name {
+						args[k] = v
+					}
+				}
+				if _, err := getPrompt(t, tt.handler, args); err == nil {
+					t.Errorf("expected an error without %s", arg.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func NewReviewChartUpgradePrompt() mcp.Prompt {
+	opts := []mcp.PromptOption{
+		mcp.WithPromptDescription("Reviews an upgrade of a Helm chart between two versions: changed default values, dependencies, images and breaking changes."),
+	}
+	opts = append(opts, chartArguments(false)...)
+	opts = append(opts,
+		mcp.WithArgument("current_version",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("Currently deployed chart version"),
+		),
+		mcp.WithArgument("target_version",
+			mcp.ArgumentDescription("Chart version to upgrade to. If omitted the latest version is used"),
+		),
+	)
+	return mcp.NewPrompt("review_chart_upgrade", opts...)
+}
+
+func GetReviewChartUpgradeHandler() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ref, err := extractChartRef(request)
+		if err != nil {
+			return nil, err
+		}
+		current := strings.TrimSpace(request.Params.Arguments["current_version"])
+		if current == "" {
+			return nil, fmt.Errorf("current_version is required")
+		}
+		target := strings.TrimSpace(request.Params.Arguments["target_version"])
+		if target == "" {
+			target = "the latest version (resolve it with get_latest_version_of_chart)"
+		} else {
+			target = fmt.Sprintf("version %q", target)
+		}
+
+		text := fmt.Sprintf(`Review the upgrade of %s from version %q to %s.
+
+Use the Helm tools with %s:
+1. Call list_chart_versions to confirm both versions exist and to list the releases in between.
+2. Call get_chart_values for both versions and compare the default values: list added, removed and renamed keys and changed defaults.
+3. Call get_chart_dependencies for both versions and report added, removed or upgraded subcharts.
+4. Call get_chart_images for both versions and report changed images and tags.
+5. Call list_chart_files for the target version and read CHANGELOG, UPGRADING or README files with get_chart_file to find documented breaking changes and migration steps.
+
+Finish with a summary that lists breaking changes first, then the values an existing deployment has to change, then other notable changes, and rate the risk of the upgrade as low, medium or high.`,
+			ref, current, target, ref.toolArgs())
+
+		return newResult("Review of a Helm chart upgrade", text), nil
+	}
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/time/rate"
+)
+
+// idleTimeout is the time after which the state of a client that made no
+// requests is dropped.
+const idleTimeout = 10 * time.Minute
+
+type clientKey struct{}
+
+// WithClient stores the address of the client that sent r in ctx. It is meant
+// to be used as the context function of the SSE and Streamable HTTP servers.
+func WithClient(ctx context.Context, r *http.Request) context.Context {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return context.WithValue(ctx, clientKey{}, host)
+}
+
+type client struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter limits the number of tool invocations per client.
+type Limiter struct {
+	perMinute int
+
+	mu        sync.Mutex
+	clients   map[string]*client
+	lastSweep time.Time
+}
+
+// New returns a Limiter allowing perMinute tool invocations per minute for
+// every client, with bursts of up to perMinute invocations.
+func New(perMinute int) *Limiter {
+	return &Limiter{
+		perMinute: perMinute,
+		clients:   make(map[string]*client),
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *Limiter) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > idleTimeout {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) > idleTimeout {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{limiter: rate.NewLimiter(rate.Every(time.Minute/time.Duration(l.perMinute)), l.perMinute)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	return c.limiter.AllowN(now, 1)
+}
+
+// Middleware rejects tool invocations of clients that exceeded their limit.
+// Calls without a client address, e.g. in stdio mode, are not limited.
+func (l *Limiter) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		key, ok := ctx.Value(clientKey{}).(string)
+		if ok && !l.allow(key) {
+			return mcp.NewToolResultError(fmt.Sprintf("rate limit of %d tool calls per minute exceeded, retry later", l.perMinute)), nil
+		}
+		return next(ctx, request)
+	}
+}
@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMiddleware(t *testing.T) {
+	calls := 0
+	handler := New(2).Middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	clientCtx := func(addr string) context.Context {
+		r := httptest.NewRequest("POST", "/mcp", nil)
+		r.RemoteAddr = addr
+		return WithClient(context.Background(), r)
+	}
+
+	call := func(ctx context.Context) bool {
+		result, err := handler(ctx, mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return !result.IsError
+	}
+
+	first := clientCtx("10.0.0.1:1234")
+	for i := 0; i < 2; i++ {
+		if !call(first) {
+			t.Fatalf("call %d was rate limited", i+1)
+		}
+	}
+	if call(clientCtx("10.0.0.1:5678")) {
+		t.Error("expected third call from the same host to be rate limited")
+	}
+	if !call(clientCtx("10.0.0.2:1234")) {
+		t.Error("expected call from another host not to be rate limited")
+	}
+	for i := 0; i < 5; i++ {
+		if !call(context.Background()) {
+			t.Fatal("expected calls without a client to be unlimited")
+		}
+	}
+	if calls != 8 {
+		t.Errorf("expected 8 calls to reach the handler, got %d", calls)
+	}
+}
@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewAnalyzeTemplateFeaturesTool() mcp.Tool {
+	return mcp.NewTool("analyze_template_features",
+		mcp.WithDescription("Scans the chart templates for lookup calls, .Capabilities.APIVersions and .Capabilities.KubeVersion checks and required values. Reports where they are used and how these parts of the chart behave when rendered offline, as the other tools of this server do, compared to installing into a live cluster. Supports both HTTP repositories and OCI registries."),
+		readOnlyAnnotation("Analyze template features"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used")),
+		mcp.WithBoolean("recursive",
+			mcp.Description("If true, scans the templates of subcharts as well. Defaults to false"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[helm_parser.ClusterFeatureReport](),
+	)
+}
+
+func AnalyzeTemplateFeaturesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+		recursive := request.GetBool("recursive", false)
+
+		report, err := c.FindClusterFeatures(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, recursive)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to analyze templates: %v", err)), nil
+		}
+		return formatOutput(format, report, nil), nil
+	}
+}
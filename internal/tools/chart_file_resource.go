@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/yosida95/uritemplate/v3"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// chartFileURITemplate is the URI template of chart file resources. All
+// variables are query parameters, as repository URLs and paths contain
+// slashes.
+const chartFileURITemplate = "helm-chart://file{?repository_url,chart_name,chart_version,path}"
+
+var chartFileURI = uritemplate.MustNew(chartFileURITemplate)
+
+func NewChartFileResourceTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate(chartFileURITemplate, "chart_file",
+		mcp.WithTemplateTitle("Chart file"),
+		mcp.WithTemplateDescription("A single file of a chart version, as listed by list_chart_files. Tools listing chart files link to these resources, so clients can read only the files they need."),
+	)
+}
+
+// GetChartFileResourceHandler reads chart file resources. The repository,
+// chart and version are resolved like the parameters of get_chart_file.
+func GetChartFileResourceHandler(c *helm_client.HelmClient) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		u, err := url.Parse(request.Params.URI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chart file URI %q: %v", request.Params.URI, err)
+		}
+		query := u.Query()
+
+		var toolRequest mcp.CallToolRequest
+		toolRequest.Params.Arguments = map[string]any{
+			"repository_url": query.Get("repository_url"),
+			"chart_name":     query.Get("chart_name"),
+			"chart_version":  query.Get("chart_version"),
+		}
+		params, errResult := ExtractCommonParams(ctx, toolRequest, c, true)
+		if errResult != nil {
+			return nil, resultError(errResult)
+		}
+
+		filePath := query.Get("path")
+		if filePath == "" {
+			return nil, fmt.Errorf("chart file URI %q has no path", request.Params.URI)
+		}
+		content, err := c.GetChartFile(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chart file: %v", err)
+		}
+
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: chartFileMIMEType(filePath),
+			Text:     content,
+		}}, nil
+	}
+}
+
+// chartFileLinks returns a resource link to every file of files, in the chart
+// version of params.
+func chartFileLinks(params *CommonParams, files []helm_parser.ChartFile) ([]mcp.Content, error) {
+	links := make([]mcp.Content, 0, len(files))
+	for _, f := range files {
+		uri, err := chartFileURI.Expand(uritemplate.Values{
+			"repository_url": uritemplate.String(params.RepositoryURL),
+			"chart_name":     uritemplate.String(params.ChartName),
+			"chart_version":  uritemplate.String(params.ChartVersion),
+			"path":           uritemplate.String(f.Path),
+		})
+		if err != nil {
+			return nil, err
+		}
+		link := mcp.NewResourceLink(uri, f.Path, fmt.Sprintf("%s of chart %s version %s", f.Path, params.ChartName, params.ChartVersion), chartFileMIMEType(f.Path))
+		size := int64(f.Size)
+		link.Size = &size
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// chartFileMIMEType guesses the MIME type of a chart file from its extension.
+// Templates, values and Chart.yaml are YAML.
+func chartFileMIMEType(filePath string) string {
+	switch ext := path.Ext(filePath); ext {
+	case ".yaml", ".yml", ".tpl":
+		return "application/yaml"
+	case ".txt", "":
+		return "text/plain"
+	default:
+		if t := mime.TypeByExtension(ext); t != "" {
+			return t
+		}
+		return "text/plain"
+	}
+}
+
+// resultError returns the message of a tool error result as an error.
+func resultError(result *mcp.CallToolResult) error {
+	for _, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok {
+			return errors.New(text.Text)
+		}
+	}
+	return errors.New("unknown error")
+}
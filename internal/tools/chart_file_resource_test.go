@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func TestChartFileResources(t *testing.T) {
+	client, err := helm_client.NewClient(helm_client.WithLocalCharts(true))
+	if err != nil {
+		t.Fatalf("failed to create helm client: %v", err)
+	}
+
+	chartDir := t.TempDir()
+	files := map[string]string{
+		"Chart.yaml":                "apiVersion: v2\nname: local-chart\nversion: 0.1.0\n",
+		"values.yaml":               "replicaCount: 1\n",
+		"templates/deployment.yaml": "kind: Deployment\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(chartDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"repository_url": "file://" + chartDir}
+	result, err := GetListChartFilesHandler(client)(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("list_chart_files failed: %v %v", err, result.Content)
+	}
+
+	template := NewChartFileResourceTemplate()
+	read := GetChartFileResourceHandler(client)
+	links := 0
+	for _, c := range result.Content {
+		link, ok := c.(mcp.ResourceLink)
+		if !ok {
+			continue
+		}
+		links++
+		if !template.URITemplate.Regexp().MatchString(link.URI) {
+			t.Errorf("link %s does not match the resource template", link.URI)
+		}
+
+		var readRequest mcp.ReadResourceRequest
+		readRequest.Params.URI = link.URI
+		contents, err := read(context.Background(), readRequest)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", link.URI, err)
+		}
+		text := contents[0].(mcp.TextResourceContents)
+		if text.Text != files[link.Name] {
+			t.Errorf("%s: content = %q, want %q", link.Name, text.Text, files[link.Name])
+		}
+		if text.MIMEType != "application/yaml" {
+			t.Errorf("%s: MIME type = %q, want application/yaml", link.Name, text.MIMEType)
+		}
+	}
+	if links != len(files) {
+		t.Errorf("got %d resource links, want %d", links, len(files))
+	}
+
+	var readRequest mcp.ReadResourceRequest
+	readRequest.Params.URI = "helm-chart://file?repository_url=" + "file://" + chartDir + "&path=missing.yaml"
+	if _, err := read(context.Background(), readRequest); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewCheckChartHATool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart, with its default values unless overridden, and audits whether its Deployments, StatefulSets and ReplicaSets are highly available: single-replica components, replicas without anti-affinity or topology spread, missing or blocking PodDisruptionBudgets, update strategies causing downtime, and leader election detected from flags, environment variables or permissions on Leases. Returns an HA readiness summary with recommendations for production reviews."),
+		readOnlyAnnotation("Check chart HA"),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.HAReport](),
+	}
+	return mcp.NewTool("check_chart_ha", append(opts, chartRenderParams(`{"replicaCount": 3}`)...)...)
+}
+
+func CheckChartHAHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		render, errResult := extractChartRender(ctx, request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.CheckChartHA(ctx, render.RepositoryURL, render.ChartName, render.ChartVersion, render.Values, render.Options)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to check chart HA: %v", err)), nil
+		}
+
+		return formatOutput(format, report, func() string { return formatHAReport(report) }), nil
+	}
+}
+
+func formatHAReport(report *helm_parser.HAReport) string {
+	if len(report.Workloads) == 0 {
+		return "The chart renders no Deployments, StatefulSets or ReplicaSets to audit"
+	}
+
+	var sb strings.Builder
+	verdict := "ready for production"
+	if !report.Ready {
+		verdict = "not ready for production"
+	}
+	fmt.Fprintf(&sb, "HA %s: %d critical, %d warnings over %d workloads\n", verdict, report.Critical, report.Warnings, len(report.Workloads))
+	for _, w := range report.Workloads {
+		fmt.Fprintf(&sb, "\n%s/%s: %d replicas", w.Kind, w.Name, w.Replicas)
+		if w.LeaderElection != "" {
+			fmt.Fprintf(&sb, ", leader election %s", w.LeaderElection)
+		}
+		sb.WriteString("\n")
+		for _, check := range w.Checks {
+			if check.Grade == helm_parser.GradeSkipped {
+				continue
+			}
+			fmt.Fprintf(&sb, "  [%s] %s", check.Grade, check.ID)
+			if check.Message != "" {
+				fmt.Fprintf(&sb, ": %s", check.Message)
+			}
+			if check.Recommendation != "" {
+				fmt.Fprintf(&sb, "\n    %s", check.Recommendation)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
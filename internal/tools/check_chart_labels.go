@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewCheckChartLabelsTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart with the given values, aggregates the labels and annotations of its resources, and checks that every resource and workload pod template carries the required labels, by default the app.kubernetes.io/* labels recommended by Kubernetes, with values Kubernetes accepts. Use it to enforce labeling policies."),
+		readOnlyAnnotation("Check chart labels"),
+		mcp.WithArray("required_labels",
+			mcp.WithStringItems(),
+			mcp.Description("Label keys every resource must have (e.g., [\"app.kubernetes.io/name\", \"team\"]). Defaults to the recommended app.kubernetes.io/name, instance, version, component, part-of and managed-by labels"),
+		),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.LabelReport](),
+	}
+	return mcp.NewTool("check_chart_labels", append(opts, chartRenderParams(`{"commonLabels": {"team": "payments"}}`)...)...)
+}
+
+func CheckChartLabelsHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		render, errResult := extractChartRender(ctx, request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.CheckChartLabels(ctx, render.RepositoryURL, render.ChartName, render.ChartVersion, render.Values, render.Options, request.GetStringSlice("required_labels", nil))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to check chart labels: %v", err)), nil
+		}
+
+		return formatOutput(format, report, func() string { return formatLabelReport(report) }), nil
+	}
+}
+
+func formatLabelReport(report *helm_parser.LabelReport) string {
+	if len(report.Resources) == 0 {
+		return "The chart renders no resources"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d%% of %d resources conform, requiring %s\n", report.Conformance, len(report.Resources), strings.Join(report.Required, ", "))
+	for _, r := range report.Resources {
+		if len(r.Missing) == 0 && len(r.PodTemplateMissing) == 0 && len(r.Invalid) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n%s/%s\n", r.Kind, r.Name)
+		if len(r.Missing) > 0 {
+			fmt.Fprintf(&sb, "  missing: %s\n", strings.Join(r.Missing, ", "))
+		}
+		if len(r.PodTemplateMissing) > 0 {
+			fmt.Fprintf(&sb, "  missing on pods: %s\n", strings.Join(r.PodTemplateMissing, ", "))
+		}
+		for _, invalid := range r.Invalid {
+			fmt.Fprintf(&sb, "  invalid: %s\n", invalid)
+		}
+	}
+
+	sb.WriteString("\nLabels:\n")
+	for _, l := range report.Labels {
+		fmt.Fprintf(&sb, "  %s on %d resources: %s\n", l.Key, l.Count, strings.Join(l.Values, ", "))
+	}
+	if len(report.Annotations) > 0 {
+		sb.WriteString("\nAnnotations:\n")
+		for _, a := range report.Annotations {
+			fmt.Fprintf(&sb, "  %s on %d resources\n", a.Key, a.Count)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewCheckOutdatedDependenciesTool() mcp.Tool {
+	return mcp.NewTool("check_outdated_dependencies",
+		mcp.WithDescription("Checks every dependency declared in the chart's Chart.yaml against the latest version in the dependency's repository and reports which dependencies are behind, by a major, minor or patch version, and whether their version constraint admits the latest version."),
+		readOnlyAnnotation("Check outdated dependencies"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[outdatedDependenciesResult](),
+	)
+}
+
+type outdatedDependenciesResult struct {
+	Chart        string                         `json:"chart"`
+	Version      string                         `json:"version"`
+	Outdated     int                            `json:"outdated"`
+	Dependencies []helm_client.DependencyUpdate `json:"dependencies"`
+}
+
+func GetCheckOutdatedDependenciesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		updates, err := c.CheckDependencyUpdates(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to check dependencies: %v", err)), nil
+		}
+
+		result := outdatedDependenciesResult{
+			Chart:        params.ChartName,
+			Version:      params.ChartVersion,
+			Dependencies: updates,
+		}
+		for _, u := range updates {
+			if u.Outdated {
+				result.Outdated++
+			}
+		}
+
+		return formatOutput(format, result, nil), nil
+	}
+}
@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewCheckPodSecurityTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart with the given values and evaluates its workloads against the Kubernetes Pod Security Standards, like the PodSecurity admission controller. Reports the most restrictive level each workload satisfies (privileged, baseline or restricted) and the fields violating the stricter levels, and the strictest pod-security.kubernetes.io/enforce level a namespace running the chart may have. Use it to check whether a chart can be installed into a namespace enforcing a level, or which values make it comply."),
+		readOnlyAnnotation("Check Pod Security Standards"),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.PodSecurityReport](),
+	}
+	return mcp.NewTool("check_pod_security", append(opts, chartRenderParams(`{"podSecurityContext": {"runAsNonRoot": true}}`)...)...)
+}
+
+func GetCheckPodSecurityHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		render, errResult := extractChartRender(ctx, request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.CheckPodSecurity(ctx, render.RepositoryURL, render.ChartName, render.ChartVersion, render.Values, render.Options)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to check pod security: %v", err)), nil
+		}
+
+		return formatOutput(format, report, func() string { return formatPodSecurityReport(report) }), nil
+	}
+}
+
+func formatPodSecurityReport(report *helm_parser.PodSecurityReport) string {
+	if len(report.Workloads) == 0 {
+		return "The chart renders no workloads"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "All %d workloads satisfy the %s level\n", len(report.Workloads), report.Level)
+	for _, w := range report.Workloads {
+		fmt.Fprintf(&sb, "\n%s/%s: %s\n", w.Kind, w.Name, w.Level)
+		for _, v := range w.Violations {
+			fmt.Fprintf(&sb, "  [%s] %s: %s (%s)\n", v.Level, v.Policy, v.Message, v.Field)
+		}
+	}
+	return sb.String()
+}
@@ -1,11 +1,20 @@
 package tools
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/strvals"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
 	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
 )
 
 // CommonParams holds the common request parameters used across chart tools.
@@ -20,19 +29,28 @@ type CommonParams struct {
 // it will fetch the latest version from the repository.
 //
 // For OCI URLs, chart_name is optional - if not provided, it will be extracted from the URL.
+// For local charts, chart_name is optional - if not provided, it is read from Chart.yaml.
 // For HTTP repositories, chart_name is required.
-func ExtractCommonParams(request mcp.CallToolRequest, c *helm_client.HelmClient, resolveLatestVersion bool) (*CommonParams, *mcp.CallToolResult) {
-	repositoryURL, err := request.RequireString("repository_url")
-	if err != nil {
-		return nil, mcp.NewToolResultError(err.Error())
+func ExtractCommonParams(ctx context.Context, request mcp.CallToolRequest, c *helm_client.HelmClient, resolveLatestVersion bool) (*CommonParams, *mcp.CallToolResult) {
+	repositoryURL, errResult := ExtractRepositoryURL(request, c)
+	if errResult != nil {
+		return nil, errResult
 	}
-	repositoryURL = strings.TrimSpace(repositoryURL)
 
 	// chart_name is optional for OCI URLs (can be extracted from URL)
 	chartName := strings.TrimSpace(request.GetString("chart_name", ""))
+	var err error
 
-	// For OCI URLs, extract chart name from URL if not provided
-	if helm_client.IsOCI(repositoryURL) {
+	// For local charts, read the chart name from Chart.yaml if not provided
+	if c.IsLocal(repositoryURL) {
+		if chartName == "" {
+			chartName, err = c.LocalChartName(repositoryURL)
+			if err != nil {
+				return nil, mcp.NewToolResultError(fmt.Sprintf("chart_name is required: %v", err))
+			}
+		}
+	} else if helm_client.IsOCI(repositoryURL) {
+		// For OCI URLs, extract chart name from URL if not provided
 		if chartName == "" {
 			chartName = helm_client.ExtractChartNameFromOCI(repositoryURL)
 			if chartName == "" {
@@ -48,7 +66,7 @@ func ExtractCommonParams(request mcp.CallToolRequest, c *helm_client.HelmClient,
 
 	chartVersion := request.GetString("chart_version", "")
 	if chartVersion == "" && resolveLatestVersion {
-		chartVersion, err = c.GetChartLatestVersion(repositoryURL, chartName)
+		chartVersion, err = c.GetChartLatestVersion(ctx, repositoryURL, chartName)
 		if err != nil {
 			return nil, mcp.NewToolResultError(fmt.Sprintf("failed to get the latest chart version: %v", err))
 		}
@@ -61,11 +79,267 @@ func ExtractCommonParams(request mcp.CallToolRequest, c *helm_client.HelmClient,
 	}, nil
 }
 
-// ExtractRepositoryURL extracts and trims the repository_url parameter from the request.
-func ExtractRepositoryURL(request mcp.CallToolRequest) (string, *mcp.CallToolResult) {
+// ExtractRepositoryURL extracts and trims the repository_url parameter from the
+// request, resolving repository aliases like "bitnami" to their URL.
+func ExtractRepositoryURL(request mcp.CallToolRequest, c *helm_client.HelmClient) (string, *mcp.CallToolResult) {
 	repositoryURL, err := request.RequireString("repository_url")
 	if err != nil {
 		return "", mcp.NewToolResultError(err.Error())
 	}
-	return strings.TrimSpace(repositoryURL), nil
+	return c.ResolveRepositoryURL(strings.TrimSpace(repositoryURL)), nil
+}
+
+// readOnlyAnnotation marks a tool as read-only: it only reads from chart
+// repositories, which are external (open world), and never changes them, so
+// clients can safely auto-approve and repeat calls.
+func readOnlyAnnotation(title string) mcp.ToolOption {
+	return mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		Title:           title,
+		ReadOnlyHint:    mcp.ToBoolPtr(true),
+		DestructiveHint: mcp.ToBoolPtr(false),
+		IdempotentHint:  mcp.ToBoolPtr(true),
+		OpenWorldHint:   mcp.ToBoolPtr(true),
+	})
+}
+
+// writeAnnotation marks a tool as changing the Kubernetes cluster, so clients
+// ask for confirmation. destructive tools may replace or remove existing
+// resources.
+func writeAnnotation(title string, destructive bool) mcp.ToolOption {
+	return mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		Title:           title,
+		ReadOnlyHint:    mcp.ToBoolPtr(false),
+		DestructiveHint: mcp.ToBoolPtr(destructive),
+		IdempotentHint:  mcp.ToBoolPtr(false),
+		OpenWorldHint:   mcp.ToBoolPtr(true),
+	})
+}
+
+// contextParam is the kubeconfig context parameter of cluster tools.
+var contextParam = mcp.WithString("context",
+	mcp.Description("Kubeconfig context of the cluster to use. Defaults to the context the server was started with"),
+)
+
+// clusterParam is the named cluster parameter of cluster tools.
+var clusterParam = mcp.WithString("cluster",
+	mcp.Description("Name of the cluster to use, as returned by list_clusters. Defaults to the default cluster"),
+)
+
+// clusterClientFor returns the client for the cluster and context parameters
+// of the request.
+func clusterClientFor(c *cluster_client.ClusterClient, request mcp.CallToolRequest) (*cluster_client.ClusterClient, *mcp.CallToolResult) {
+	client, err := c.ForCluster(request.GetString("cluster", ""))
+	if err != nil {
+		return nil, mcp.NewToolResultError(err.Error())
+	}
+	client, err = client.ForContext(request.GetString("context", ""))
+	if err != nil {
+		return nil, mcp.NewToolResultError(err.Error())
+	}
+	return client, nil
+}
+
+// timeoutParam is the timeout parameter of tools changing releases.
+var timeoutParam = mcp.WithString("timeout",
+	mcp.Description("Time to wait for Kubernetes operations, e.g. 5m or 30s. Defaults to 5m"),
+)
+
+// writeOptionsParams are the parameters shared by tools installing and
+// upgrading releases.
+var writeOptionsParams = []mcp.ToolOption{
+	timeoutParam,
+	mcp.WithBoolean("atomic",
+		mcp.Description("If true, waits for the resources to become ready and rolls the release back if they do not. Defaults to false"),
+	),
+}
+
+// extractWriteOptions reads the timeout and atomic parameters.
+func extractWriteOptions(request mcp.CallToolRequest) (cluster_client.WriteOptions, *mcp.CallToolResult) {
+	opts := cluster_client.WriteOptions{Atomic: request.GetBool("atomic", false)}
+	if s := request.GetString("timeout", ""); s != "" {
+		timeout, err := time.ParseDuration(s)
+		if err != nil || timeout <= 0 {
+			return opts, mcp.NewToolResultError(fmt.Sprintf("invalid timeout %q: expected a positive duration such as 5m", s))
+		}
+		opts.Timeout = timeout
+	}
+	return opts, nil
+}
+
+// renderOptionsParams are the parameters shared by tools rendering chart
+// templates offline.
+var renderOptionsParams = []mcp.ToolOption{
+	mcp.WithString("release_name",
+		mcp.Description("Release name to render the templates for, as reported by .Release.Name. Resource names usually derive from it. Defaults to "+helm_parser.DefaultReleaseName),
+	),
+	mcp.WithString("namespace",
+		mcp.Description("Namespace to render the templates for, as reported by .Release.Namespace. Defaults to "+helm_parser.DefaultNamespace),
+	),
+	mcp.WithString("kube_version",
+		mcp.Description("Kubernetes version to render the templates for (e.g., 1.29.0), as reported by .Capabilities.KubeVersion. Defaults to Helm's built-in version"),
+	),
+	mcp.WithArray("api_versions",
+		mcp.WithStringItems(),
+		mcp.Description("Additional API versions available in the cluster, as reported by .Capabilities.APIVersions (e.g., [\"monitoring.coreos.com/v1\", \"monitoring.coreos.com/v1/ServiceMonitor\"]), to render resources that depend on CRDs"),
+	),
+	mcp.WithArray("patches",
+		mcp.Description("Kustomize patches applied to the rendered manifests before they are analyzed, like a kustomize post-renderer. Each patch is a strategic merge patch in YAML, matching the resource by apiVersion, kind and name, or a JSON 6902 patch (e.g., [{\"op\": \"replace\", \"path\": \"/spec/replicas\", \"value\": 2}]) with a target selecting the resources"),
+		mcp.Items(map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"patch": map[string]any{"type": "string", "description": "Strategic merge patch or JSON 6902 patch"},
+				"target": map[string]any{
+					"type":        "object",
+					"description": "Resources to patch; empty fields match any resource",
+					"properties": map[string]any{
+						"group":              map[string]any{"type": "string"},
+						"version":            map[string]any{"type": "string"},
+						"kind":               map[string]any{"type": "string"},
+						"name":               map[string]any{"type": "string"},
+						"namespace":          map[string]any{"type": "string"},
+						"labelSelector":      map[string]any{"type": "string"},
+						"annotationSelector": map[string]any{"type": "string"},
+					},
+				},
+			},
+			"required": []string{"patch"},
+		}),
+	),
+}
+
+// extractRenderOptions reads the release_name, namespace, kube_version,
+// api_versions and patches parameters.
+func extractRenderOptions(request mcp.CallToolRequest) (helm_parser.RenderOptions, *mcp.CallToolResult) {
+	opts := helm_parser.RenderOptions{
+		ReleaseName: request.GetString("release_name", ""),
+		Namespace:   request.GetString("namespace", ""),
+	}
+	if opts.ReleaseName != "" {
+		if err := chartutil.ValidateReleaseName(opts.ReleaseName); err != nil {
+			return opts, mcp.NewToolResultError(fmt.Sprintf("invalid release_name %q: %v", opts.ReleaseName, err))
+		}
+	}
+	if opts.Namespace != "" {
+		if errs := validation.IsDNS1123Label(opts.Namespace); len(errs) > 0 {
+			return opts, mcp.NewToolResultError(fmt.Sprintf("invalid namespace %q: %s", opts.Namespace, strings.Join(errs, "; ")))
+		}
+	}
+
+	caps, err := helm_parser.NewCapabilities(request.GetString("kube_version", ""), request.GetStringSlice("api_versions", nil))
+	if err != nil {
+		return opts, mcp.NewToolResultError(err.Error())
+	}
+	opts.Capabilities = caps
+
+	if patches, ok := request.GetArguments()["patches"]; ok && patches != nil {
+		// Round-trip through JSON to decode the patch objects.
+		encoded, err := json.Marshal(patches)
+		if err == nil {
+			err = json.Unmarshal(encoded, &opts.Patches)
+		}
+		if err != nil {
+			return opts, mcp.NewToolResultError(fmt.Sprintf("invalid patches: %v", err))
+		}
+		for i, p := range opts.Patches {
+			if strings.TrimSpace(p.Patch) == "" {
+				return opts, mcp.NewToolResultError(fmt.Sprintf("invalid patches: patch %d is empty", i))
+			}
+		}
+	}
+	return opts, nil
+}
+
+// setParam is the parameter of tools taking values for --set style
+// overrides, applied after custom_values.
+var setParam = mcp.WithArray("set",
+	mcp.WithStringItems(),
+	mcp.Description("Values to set in helm --set syntax, applied after custom_values (e.g., [\"image.tag=v2\", \"ingress.hosts[0]=example.com\"])"),
+)
+
+// valuesURLParam is the parameter of tools taking values for a values file
+// to download, merged below custom_values and set.
+var valuesURLParam = mcp.WithString("values_url",
+	mcp.Description("HTTP(S) URL of a YAML values file to use as base values, like helm --values with a URL. custom_values and set are merged over it"),
+)
+
+// extractValues merges the values of the values_url, custom_values and set
+// parameters in this order, like helm --values followed by --set. Nested
+// maps are merged, set paths are applied to the merged values.
+func extractValues(ctx context.Context, c *helm_client.HelmClient, request mcp.CallToolRequest) (map[string]any, *mcp.CallToolResult) {
+	var base map[string]any
+	if u := request.GetString("values_url", ""); u != "" {
+		var err error
+		if base, err = c.FetchValues(ctx, u); err != nil {
+			return nil, mcp.NewToolResultError(err.Error())
+		}
+	}
+
+	var custom map[string]any
+	if s := request.GetString("custom_values", ""); s != "" {
+		if err := json.Unmarshal([]byte(s), &custom); err != nil {
+			return nil, mcp.NewToolResultError(fmt.Sprintf("failed to parse custom_values JSON: %v", err))
+		}
+	}
+
+	values := helm_client.MergeValues(base, custom)
+	for _, s := range request.GetStringSlice("set", nil) {
+		if values == nil {
+			values = make(map[string]any)
+		}
+		if err := strvals.ParseInto(s, values); err != nil {
+			return nil, mcp.NewToolResultError(fmt.Sprintf("failed to parse set value %q: %v", s, err))
+		}
+	}
+	return values, nil
+}
+
+// chartRenderParams returns the parameters of tools rendering a chart version
+// with values: repository_url, chart_name, chart_version, custom_values with
+// valuesExample as example, set, values_url and the render options. See
+// extractChartRender.
+func chartRenderParams(valuesExample string) []mcp.ToolOption {
+	return append([]mcp.ToolOption{
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("custom_values",
+			mcp.Description("JSON object of custom values to override chart defaults (e.g., "+valuesExample+")"),
+		),
+		setParam,
+		valuesURLParam,
+	}, renderOptionsParams...)
+}
+
+// chartRender is a chart version to render with values, read from the
+// parameters of chartRenderParams.
+type chartRender struct {
+	CommonParams
+	Values  map[string]any
+	Options helm_parser.RenderOptions
+}
+
+// extractChartRender reads the parameters of chartRenderParams, resolving
+// the latest version if chart_version is empty.
+func extractChartRender(ctx context.Context, request mcp.CallToolRequest, c *helm_client.HelmClient) (*chartRender, *mcp.CallToolResult) {
+	params, errResult := ExtractCommonParams(ctx, request, c, true)
+	if errResult != nil {
+		return nil, errResult
+	}
+	values, errResult := extractValues(ctx, c, request)
+	if errResult != nil {
+		return nil, errResult
+	}
+	opts, errResult := extractRenderOptions(request)
+	if errResult != nil {
+		return nil, errResult
+	}
+	return &chartRender{CommonParams: *params, Values: values, Options: opts}, nil
 }
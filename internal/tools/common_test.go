@@ -1,7 +1,16 @@
 package tools
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
@@ -120,7 +129,7 @@ func TestExtractCommonParams(t *testing.T) {
 				},
 			}
 
-			params, errResult := ExtractCommonParams(request, client, tt.resolveLatestVersion)
+			params, errResult := ExtractCommonParams(context.Background(), request, client, tt.resolveLatestVersion)
 
 			if tt.wantError {
 				if errResult == nil {
@@ -157,7 +166,55 @@ func TestExtractCommonParams(t *testing.T) {
 	}
 }
 
+func TestExtractCommonParamsLocal(t *testing.T) {
+	client, err := helm_client.NewClient(helm_client.WithLocalCharts(true))
+	if err != nil {
+		t.Fatalf("failed to create helm client: %v", err)
+	}
+
+	chartDir := t.TempDir()
+	chartYAML := "apiVersion: v2\nname: local-chart\nversion: 0.1.0\n"
+	if err := os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(chartYAML), 0o644); err != nil {
+		t.Fatalf("write Chart.yaml: %v", err)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "test_tool",
+			Arguments: map[string]any{
+				"repository_url": "file://" + chartDir,
+			},
+		},
+	}
+
+	params, errResult := ExtractCommonParams(context.Background(), request, client, true)
+	if errResult != nil {
+		t.Fatalf("unexpected error: %v", errResult)
+	}
+	if params.ChartName != "local-chart" {
+		t.Errorf("ChartName = %q, want %q", params.ChartName, "local-chart")
+	}
+	if params.ChartVersion != "0.1.0" {
+		t.Errorf("ChartVersion = %q, want %q", params.ChartVersion, "0.1.0")
+	}
+
+	noLocal, err := helm_client.NewClient()
+	if err != nil {
+		t.Fatalf("failed to create helm client: %v", err)
+	}
+	if _, errResult := ExtractCommonParams(context.Background(), request, noLocal, true); errResult == nil {
+		t.Error("expected local charts to be rejected unless enabled")
+	}
+}
+
 func TestExtractRepositoryURL(t *testing.T) {
+	client, err := helm_client.NewClient(helm_client.WithCacheDir(t.TempDir()), helm_client.WithRepoAliases(map[string]string{
+		"internal": "https://charts.internal.example.com",
+	}))
+	if err != nil {
+		t.Fatalf("failed to create helm client: %v", err)
+	}
+
 	tests := []struct {
 		name              string
 		arguments         map[string]any
@@ -195,6 +252,20 @@ func TestExtractRepositoryURL(t *testing.T) {
 			wantError: false,
 			wantURL:   "oci://ghcr.io/org/charts/mychart",
 		},
+		{
+			name: "built-in alias",
+			arguments: map[string]any{
+				"repository_url": "bitnami",
+			},
+			wantURL: "https://charts.bitnami.com/bitnami",
+		},
+		{
+			name: "configured alias",
+			arguments: map[string]any{
+				"repository_url": " internal ",
+			},
+			wantURL: "https://charts.internal.example.com",
+		},
 	}
 
 	for _, tt := range tests {
@@ -206,7 +277,7 @@ func TestExtractRepositoryURL(t *testing.T) {
 				},
 			}
 
-			url, errResult := ExtractRepositoryURL(request)
+			url, errResult := ExtractRepositoryURL(request, client)
 
 			if tt.wantError {
 				if errResult == nil {
@@ -254,3 +325,370 @@ func containsString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestToolAnnotations(t *testing.T) {
+	for _, tool := range []mcp.Tool{
+		NewListChartsTool(),
+		NewListChartVersionsTool(),
+		NewGetLatestVersionOfChartTool(),
+		NewGetChartAppVersionTool(),
+		NewGetChartDigestTool(),
+		NewGetChartDownloadURLTool(),
+		NewFindChartVersionByAppVersionTool(),
+		NewFindChartTool(),
+		NewGetChartValuesTool(),
+		NewGetChartContentsTool(),
+		NewListChartFilesTool(),
+		NewGetChartFileTool(),
+		NewGetChartDependenciesTool(),
+		NewGetChartDependencyTreeTool(),
+		NewAnalyzeTemplateFeaturesTool(),
+		NewGetEffectiveValuesTool(),
+		NewValidateValuesTool(),
+		NewRunChartUnitTestsTool(),
+		NewScoreChartTool(),
+		NewCheckPodSecurityTool(),
+		NewGetChartAvailabilityTool(),
+		NewGetChartIngressesTool(),
+		NewGetChartServicesTool(),
+		NewGetChartConfigUsageTool(),
+		NewGetChartStorageTool(),
+		NewGetChartSchedulingTool(),
+		NewCheckChartHATool(),
+		NewCheckChartLabelsTool(),
+		NewFindExternalDependenciesTool(),
+		NewEstimateChartCostTool(),
+		NewCheckOutdatedDependenciesTool(),
+		NewCompareChartsTool(),
+		NewGetChartImagesTool(),
+		NewRelocateChartImagesTool(),
+		NewPlanOfflineBundleTool(),
+		NewListChartReferrersTool(),
+		NewGetCacheInfoTool(),
+		NewListClustersTool(),
+		NewListReleasesTool(),
+		NewGetReleaseValuesTool(),
+		NewGetReleaseManifestTool(),
+		NewGetReleaseStatusTool(),
+		NewScanDeprecatedAPIsTool(),
+		NewPreviewReleaseUpgradeTool(),
+		NewCompareReleaseValuesTool(),
+		NewDryRunInstallTool(),
+		NewGetResultContinuationTool(),
+	} {
+		a := tool.Annotations
+		if a.Title == "" {
+			t.Errorf("%s: missing title", tool.Name)
+		}
+		if a.ReadOnlyHint == nil || !*a.ReadOnlyHint {
+			t.Errorf("%s: expected readOnlyHint", tool.Name)
+		}
+		if a.DestructiveHint == nil || *a.DestructiveHint {
+			t.Errorf("%s: expected destructiveHint to be false", tool.Name)
+		}
+		if a.IdempotentHint == nil || !*a.IdempotentHint {
+			t.Errorf("%s: expected idempotentHint", tool.Name)
+		}
+	}
+
+	for _, tool := range []mcp.Tool{
+		NewInstallChartTool(),
+		NewUpgradeReleaseTool(),
+		NewRollbackReleaseTool(),
+		NewUninstallReleaseTool(),
+	} {
+		a := tool.Annotations
+		if a.ReadOnlyHint == nil || *a.ReadOnlyHint {
+			t.Errorf("%s: expected readOnlyHint to be false", tool.Name)
+		}
+		if a.IdempotentHint == nil || *a.IdempotentHint {
+			t.Errorf("%s: expected idempotentHint to be false", tool.Name)
+		}
+		if _, ok := tool.InputSchema.Properties["timeout"]; !ok {
+			t.Errorf("%s: missing timeout parameter", tool.Name)
+		}
+	}
+
+	// invalidate_cache only changes the state of the server.
+	a := NewInvalidateCacheTool().Annotations
+	if a.ReadOnlyHint == nil || *a.ReadOnlyHint {
+		t.Errorf("invalidate_cache: expected readOnlyHint to be false")
+	}
+	if a.DestructiveHint == nil || *a.DestructiveHint {
+		t.Errorf("invalidate_cache: expected destructiveHint to be false")
+	}
+	if a.OpenWorldHint == nil || *a.OpenWorldHint {
+		t.Errorf("invalidate_cache: expected openWorldHint to be false")
+	}
+
+	// pull_chart only adds files, replacing them only with overwrite.
+	a = NewPullChartTool().Annotations
+	if a.ReadOnlyHint == nil || *a.ReadOnlyHint {
+		t.Errorf("pull_chart: expected readOnlyHint to be false")
+	}
+	if a.DestructiveHint == nil || *a.DestructiveHint {
+		t.Errorf("pull_chart: expected destructiveHint to be false")
+	}
+
+	// compare_manifest_snapshot replaces the stored snapshot with update.
+	a = NewCompareManifestSnapshotTool().Annotations
+	if a.ReadOnlyHint == nil || *a.ReadOnlyHint {
+		t.Errorf("compare_manifest_snapshot: expected readOnlyHint to be false")
+	}
+	if a.DestructiveHint == nil || !*a.DestructiveHint {
+		t.Errorf("compare_manifest_snapshot: expected destructiveHint")
+	}
+
+	// package_chart may replace an archive with overwrite.
+	a = NewPackageChartTool().Annotations
+	if a.ReadOnlyHint == nil || *a.ReadOnlyHint {
+		t.Errorf("package_chart: expected readOnlyHint to be false")
+	}
+	if a.DestructiveHint == nil || !*a.DestructiveHint {
+		t.Errorf("package_chart: expected destructiveHint")
+	}
+
+	// push_chart may replace an existing tag in the registry.
+	a = NewPushChartTool().Annotations
+	if a.ReadOnlyHint == nil || *a.ReadOnlyHint {
+		t.Errorf("push_chart: expected readOnlyHint to be false")
+	}
+	if a.DestructiveHint == nil || !*a.DestructiveHint {
+		t.Errorf("push_chart: expected destructiveHint")
+	}
+}
+
+func TestToolOutputSchemas(t *testing.T) {
+	for _, tool := range []mcp.Tool{
+		NewListChartsTool(),
+		NewListChartVersionsTool(),
+		NewGetLatestVersionOfChartTool(),
+		NewGetChartAppVersionTool(),
+		NewGetChartDigestTool(),
+		NewGetChartDownloadURLTool(),
+		NewFindChartVersionByAppVersionTool(),
+		NewFindChartTool(),
+		NewListChartFilesTool(),
+		NewGetChartDependenciesTool(),
+		NewGetChartDependencyTreeTool(),
+		NewAnalyzeTemplateFeaturesTool(),
+		NewGetEffectiveValuesTool(),
+		NewValidateValuesTool(),
+		NewRunChartUnitTestsTool(),
+		NewScoreChartTool(),
+		NewCheckPodSecurityTool(),
+		NewGetChartAvailabilityTool(),
+		NewGetChartIngressesTool(),
+		NewGetChartServicesTool(),
+		NewGetChartConfigUsageTool(),
+		NewGetChartStorageTool(),
+		NewGetChartSchedulingTool(),
+		NewCheckChartHATool(),
+		NewCheckChartLabelsTool(),
+		NewFindExternalDependenciesTool(),
+		NewEstimateChartCostTool(),
+		NewCheckOutdatedDependenciesTool(),
+		NewCompareChartsTool(),
+		NewCompareManifestSnapshotTool(),
+		NewGetChartImagesTool(),
+		NewRelocateChartImagesTool(),
+		NewPlanOfflineBundleTool(),
+		NewListChartReferrersTool(),
+		NewGetCacheInfoTool(),
+		NewInvalidateCacheTool(),
+		NewPullChartTool(),
+		NewPackageChartTool(),
+		NewPushChartTool(),
+		NewListClustersTool(),
+		NewListReleasesTool(),
+		NewGetReleaseValuesTool(),
+		NewGetReleaseStatusTool(),
+		NewScanDeprecatedAPIsTool(),
+		NewPreviewReleaseUpgradeTool(),
+		NewCompareReleaseValuesTool(),
+		NewDryRunInstallTool(),
+		NewInstallChartTool(),
+		NewUpgradeReleaseTool(),
+		NewRollbackReleaseTool(),
+		NewUninstallReleaseTool(),
+	} {
+		encoded, err := json.Marshal(tool)
+		if err != nil {
+			t.Fatalf("%s: failed to marshal tool: %v", tool.Name, err)
+		}
+		var decoded struct {
+			OutputSchema struct {
+				Type string `json:"type"`
+			} `json:"outputSchema"`
+		}
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("%s: failed to unmarshal tool: %v", tool.Name, err)
+		}
+		if decoded.OutputSchema.Type != "object" {
+			t.Errorf("%s: expected an object output schema, got type %q", tool.Name, decoded.OutputSchema.Type)
+		}
+	}
+}
+
+func TestClusterToolsContextParam(t *testing.T) {
+	for _, tool := range []mcp.Tool{
+		NewListReleasesTool(),
+		NewGetReleaseValuesTool(),
+		NewGetReleaseManifestTool(),
+		NewGetReleaseStatusTool(),
+		NewScanDeprecatedAPIsTool(),
+		NewPreviewReleaseUpgradeTool(),
+		NewCompareReleaseValuesTool(),
+		NewDryRunInstallTool(),
+		NewInstallChartTool(),
+		NewUpgradeReleaseTool(),
+		NewRollbackReleaseTool(),
+		NewUninstallReleaseTool(),
+	} {
+		if _, ok := tool.InputSchema.Properties["context"]; !ok {
+			t.Errorf("%s: missing context parameter", tool.Name)
+		}
+		if _, ok := tool.InputSchema.Properties["cluster"]; !ok {
+			t.Errorf("%s: missing cluster parameter", tool.Name)
+		}
+	}
+}
+
+func TestChartRenderToolsParams(t *testing.T) {
+	for _, tool := range []mcp.Tool{
+		NewGetChartAvailabilityTool(),
+		NewEstimateChartCostTool(),
+		NewCheckPodSecurityTool(),
+		NewGetChartIngressesTool(),
+		NewGetChartServicesTool(),
+		NewGetChartConfigUsageTool(),
+		NewGetChartStorageTool(),
+		NewGetChartSchedulingTool(),
+		NewCheckChartHATool(),
+		NewCheckChartLabelsTool(),
+		NewScoreChartTool(),
+	} {
+		for _, name := range []string{"repository_url", "chart_name", "chart_version", "custom_values", "set", "values_url", "release_name", "namespace", "kube_version", "api_versions", "patches"} {
+			if _, ok := tool.InputSchema.Properties[name]; !ok {
+				t.Errorf("%s: missing %s parameter", tool.Name, name)
+			}
+		}
+		if !slices.Equal(tool.InputSchema.Required, []string{"repository_url", "chart_name"}) {
+			t.Errorf("%s: required = %v, want [repository_url chart_name]", tool.Name, tool.InputSchema.Required)
+		}
+	}
+}
+
+func TestExtractWriteOptions(t *testing.T) {
+	request := func(args map[string]any) mcp.CallToolRequest {
+		var r mcp.CallToolRequest
+		r.Params.Arguments = args
+		return r
+	}
+
+	opts, errResult := extractWriteOptions(request(map[string]any{"timeout": "90s", "atomic": true}))
+	if errResult != nil || opts.Timeout != 90*time.Second || !opts.Atomic {
+		t.Fatalf("unexpected options %+v, %v", opts, errResult)
+	}
+	for _, timeout := range []string{"soon", "-1m", "0s"} {
+		if _, errResult := extractWriteOptions(request(map[string]any{"timeout": timeout})); errResult == nil {
+			t.Errorf("expected an error for timeout %q", timeout)
+		}
+	}
+}
+
+func TestExtractRenderOptions(t *testing.T) {
+	request := func(args map[string]any) mcp.CallToolRequest {
+		var r mcp.CallToolRequest
+		r.Params.Arguments = args
+		return r
+	}
+
+	opts, errResult := extractRenderOptions(request(map[string]any{
+		"release_name": "prod",
+		"namespace":    "apps",
+		"kube_version": "1.30.0",
+		"api_versions": []any{"monitoring.coreos.com/v1"},
+		"patches": []any{
+			map[string]any{"patch": "kind: Deployment", "target": map[string]any{"kind": "Deployment", "name": "app"}},
+		},
+	}))
+	if errResult != nil {
+		t.Fatalf("unexpected error %v", errResult)
+	}
+	if len(opts.Patches) != 1 || opts.Patches[0].Patch != "kind: Deployment" || opts.Patches[0].Target.Name != "app" {
+		t.Errorf("unexpected patches %+v", opts.Patches)
+	}
+	if opts.ReleaseName != "prod" || opts.Namespace != "apps" || opts.Capabilities.KubeVersion.Version != "v1.30.0" ||
+		!opts.Capabilities.APIVersions.Has("monitoring.coreos.com/v1") {
+		t.Errorf("unexpected options %+v", opts)
+	}
+
+	for _, args := range []map[string]any{
+		{"release_name": "Prod_Release"},
+		{"namespace": "apps/prod"},
+		{"kube_version": "latest"},
+		{"patches": "spec: {}"},
+		{"patches": []any{map[string]any{"target": map[string]any{"kind": "Deployment"}}}},
+	} {
+		if _, errResult := extractRenderOptions(request(args)); errResult == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestExtractValues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/values.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("image:\n  repository: base\n  tag: v0\n  pullPolicy: Always\nreplicaCount: 1\n"))
+	}))
+	defer srv.Close()
+
+	client, err := helm_client.NewClient()
+	if err != nil {
+		t.Fatalf("failed to create helm client: %v", err)
+	}
+	request := func(args map[string]any) mcp.CallToolRequest {
+		var r mcp.CallToolRequest
+		r.Params.Arguments = args
+		return r
+	}
+
+	values, errResult := extractValues(context.Background(), client, request(map[string]any{
+		"values_url":    srv.URL + "/values.yaml",
+		"custom_values": `{"image": {"repository": "app", "tag": "v1"}, "replicaCount": 2}`,
+		"set":           []any{"image.tag=v2", "ingress.hosts[0]=example.com"},
+	}))
+	if errResult != nil {
+		t.Fatalf("unexpected error %v", errResult)
+	}
+	want := map[string]any{
+		"image":        map[string]any{"repository": "app", "tag": "v2", "pullPolicy": "Always"},
+		"replicaCount": float64(2),
+		"ingress":      map[string]any{"hosts": []any{"example.com"}},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("extractValues() = %v, want %v", values, want)
+	}
+
+	values, errResult = extractValues(context.Background(), client, request(map[string]any{"set": []any{"a.b=1"}}))
+	if errResult != nil || !reflect.DeepEqual(values, map[string]any{"a": map[string]any{"b": int64(1)}}) {
+		t.Errorf("extractValues() = %v, %v", values, errResult)
+	}
+
+	if values, errResult := extractValues(context.Background(), client, request(nil)); errResult != nil || values != nil {
+		t.Errorf("expected no values, got %v, %v", values, errResult)
+	}
+	for _, args := range []map[string]any{
+		{"set": []any{"replicaCount"}},
+		{"values_url": srv.URL + "/missing.yaml"},
+		{"values_url": "file:///etc/passwd"},
+	} {
+		if _, errResult := extractValues(context.Background(), client, request(args)); errResult == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewCompareChartsTool() mcp.Tool {
+	return mcp.NewTool("compare_charts",
+		mcp.WithDescription("Compares the same chart published by two repositories, e.g. the Bitnami and the upstream variant of a chart: their available versions, the default values that differ and the images and registries they deploy with default values. Use it to choose between variants of a chart."),
+		readOnlyAnnotation("Compare charts across repositories"),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name, the same in both repositories"),
+		),
+		mcp.WithString("repository_url_a",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami) of the first variant. Supports HTTP repos, OCI registries (e.g., oci://registry-1.docker.io/bitnamicharts) and local chart directories"),
+		),
+		mcp.WithString("repository_url_b",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias of the second variant"),
+		),
+		mcp.WithString("chart_version_a",
+			mcp.Description("Chart version of the first variant. If omitted the latest version will be used"),
+		),
+		mcp.WithString("chart_version_b",
+			mcp.Description("Chart version of the second variant. If omitted the latest version will be used"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[helm_client.ChartComparison](),
+	)
+}
+
+func GetCompareChartsHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		chartName, err := request.RequireString("chart_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		repos := make([]string, 2)
+		for i, param := range []string{"repository_url_a", "repository_url_b"} {
+			repoURL, err := request.RequireString(param)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repos[i] = c.ResolveRepositoryURL(strings.TrimSpace(repoURL))
+		}
+
+		comparison, err := c.CompareCharts(ctx, strings.TrimSpace(chartName),
+			repos[0], strings.TrimSpace(request.GetString("chart_version_a", "")),
+			repos[1], strings.TrimSpace(request.GetString("chart_version_b", "")))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to compare charts: %v", err)), nil
+		}
+		return formatOutput(format, comparison, nil), nil
+	}
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewCompareManifestSnapshotTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart with the given values and compares the manifest with a named snapshot stored on the server, for regression checks of chart or values changes. The first call with a snapshot name stores the render as the snapshot; later calls return a per-resource diff of the new render against it. With update the snapshot is replaced by the new render after comparing."),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Compare manifest snapshot",
+			ReadOnlyHint:    mcp.ToBoolPtr(false),
+			DestructiveHint: mcp.ToBoolPtr(true),
+			IdempotentHint:  mcp.ToBoolPtr(true),
+			OpenWorldHint:   mcp.ToBoolPtr(true),
+		}),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("snapshot",
+			mcp.Required(),
+			mcp.Description("Name of the snapshot to compare with, created from this render if it does not exist (e.g., my-app-prod). Up to 128 letters, digits, '.', '_' and '-'"),
+		),
+		mcp.WithBoolean("update",
+			mcp.Description("If true, replaces the snapshot with this render after comparing, accepting the changes. Defaults to false"),
+		),
+		mcp.WithString("custom_values",
+			mcp.Description("JSON object of custom values to override chart defaults (e.g., {\"image\": {\"tag\": \"v2\"}})"),
+		),
+		setParam,
+		valuesURLParam,
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_client.SnapshotComparison](),
+	}
+	return mcp.NewTool("compare_manifest_snapshot", append(opts, renderOptionsParams...)...)
+}
+
+func GetCompareManifestSnapshotHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		snapshot, err := request.RequireString("snapshot")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		customValues, errResult := extractValues(ctx, c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		renderOpts, errResult := extractRenderOptions(request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		result, err := c.CompareManifestSnapshot(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, customValues, renderOpts, snapshot, request.GetBool("update", false))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to compare with snapshot: %v", err)), nil
+		}
+
+		return formatOutput(format, result, func() string {
+			if result.Created {
+				return fmt.Sprintf("Created snapshot %s from chart %s version %s", result.Snapshot, result.Chart, result.Version)
+			}
+
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "Snapshot %s of chart %s version %s taken %s: ", result.Snapshot, result.Chart, result.Version, result.CreatedAt.Format(time.RFC3339))
+			if len(result.Diff.Changes) == 0 {
+				fmt.Fprintf(&sb, "no changes, %d resources unchanged\n", result.Diff.Unchanged)
+			} else {
+				fmt.Fprintf(&sb, "%d resources changed, %d unchanged\n", len(result.Diff.Changes), result.Diff.Unchanged)
+			}
+			for _, change := range result.Diff.Changes {
+				fmt.Fprintf(&sb, "\n%s %s\n%s", change.Change, change.Resource, change.Diff)
+			}
+			if result.Updated {
+				sb.WriteString("\nThe snapshot was updated to this render.\n")
+			}
+			return sb.String()
+		}), nil
+	}
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewCompareReleaseValuesTool() mcp.Tool {
+	return mcp.NewTool("compare_release_values",
+		mcp.WithDescription("Compares the values of an installed Helm release with the defaults of its chart. Returns the values the user has overridden and the defaults that change in another chart version, marking the ones the release overrides. Use it to plan upgrades."),
+		readOnlyAnnotation("Compare release values with chart defaults"),
+		mcp.WithString("release_name",
+			mcp.Required(),
+			mcp.Description("Name of the release, as returned by list_releases"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the release. Defaults to the namespace of the kubeconfig context"),
+		),
+		contextParam,
+		clusterParam,
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias of the chart. Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version to compare the defaults with. If omitted the latest version will be used"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[cluster_client.ValuesComparison](),
+	)
+}
+
+func GetCompareReleaseValuesHandler(c *helm_client.HelmClient, cc *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		cc, errResult := clusterClientFor(cc, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		name, err := request.RequireString("release_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		target, err := c.LoadChart(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		comparison, err := cc.CompareValues(ctx, request.GetString("namespace", ""), name, target)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to compare values: %v", err)), nil
+		}
+
+		return formatOutput(format, comparison, nil), nil
+	}
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewEstimateChartCostTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart with the given values, sums the CPU and memory requested by its workloads and estimates the monthly cost of running them from a price per vCPU-hour and GiB-hour. Deployments and StatefulSets run their replica count or the minimum of a HorizontalPodAutoscaler in the chart, also reporting the cost at its maximum; Jobs and CronJobs are listed but not counted. Containers without requests are reported, as the estimate does not include them."),
+		readOnlyAnnotation("Estimate chart cost"),
+		mcp.WithObject("replicas",
+			mcp.Description("Replica counts overriding those of the rendered workloads, keyed by kind/name or name (e.g., {\"Deployment/web\": 5})"),
+			mcp.AdditionalProperties(map[string]any{"type": "integer", "minimum": 0}),
+		),
+		mcp.WithNumber("daemonset_nodes",
+			mcp.Description("Number of nodes DaemonSets run a pod on. Defaults to 1"),
+			mcp.Min(1),
+		),
+		mcp.WithNumber("cpu_hour_price",
+			mcp.Description("Price of one vCPU for an hour. Defaults to the price configured on the server"),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("memory_gb_hour_price",
+			mcp.Description("Price of one GiB of memory for an hour. Defaults to the price configured on the server"),
+			mcp.Min(0),
+		),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.Footprint](),
+	}
+	return mcp.NewTool("estimate_chart_cost", append(opts, chartRenderParams(`{"replicaCount": 3}`)...)...)
+}
+
+// GetEstimateChartCostHandler returns the handler of estimate_chart_cost,
+// charging prices unless a call sets its own.
+func GetEstimateChartCostHandler(c *helm_client.HelmClient, prices helm_parser.PriceTable) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		render, errResult := extractChartRender(ctx, request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		opts := helm_parser.FootprintOptions{
+			Prices: helm_parser.PriceTable{
+				CPUHour:      request.GetFloat("cpu_hour_price", prices.CPUHour),
+				MemoryGBHour: request.GetFloat("memory_gb_hour_price", prices.MemoryGBHour),
+			},
+			DaemonSetNodes: request.GetInt("daemonset_nodes", 1),
+		}
+		if opts.Prices.CPUHour < 0 || opts.Prices.MemoryGBHour < 0 {
+			return mcp.NewToolResultError("prices must not be negative"), nil
+		}
+		if replicas, ok := request.GetArguments()["replicas"]; ok && replicas != nil {
+			// Round-trip through JSON to decode the replica counts.
+			encoded, err := json.Marshal(replicas)
+			if err == nil {
+				err = json.Unmarshal(encoded, &opts.Replicas)
+			}
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid replicas: %v", err)), nil
+			}
+			for name, n := range opts.Replicas {
+				if n < 0 {
+					return mcp.NewToolResultError(fmt.Sprintf("invalid replicas: negative count for %s", name)), nil
+				}
+			}
+		}
+
+		footprint, err := c.EstimateChartFootprint(ctx, render.RepositoryURL, render.ChartName, render.ChartVersion, render.Values, render.Options, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to estimate chart cost: %v", err)), nil
+		}
+
+		return formatOutput(format, footprint, func() string { return formatFootprint(footprint) }), nil
+	}
+}
+
+func formatFootprint(f *helm_parser.Footprint) string {
+	if len(f.Workloads) == 0 {
+		return "The chart renders no workloads"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Requests %.2f vCPU and %.2f GiB of memory, %.2f per month", f.CPU, f.MemoryGB, f.MonthlyCost)
+	if f.MaxMonthlyCost > 0 {
+		fmt.Fprintf(&sb, " (up to %.2f with autoscaling)", f.MaxMonthlyCost)
+	}
+	fmt.Fprintf(&sb, " at %g per vCPU-hour and %g per GiB-hour\n\n", f.Prices.CPUHour, f.Prices.MemoryGBHour)
+
+	for _, w := range f.Workloads {
+		replicas := fmt.Sprint(w.Replicas)
+		if w.MaxReplicas > w.Replicas {
+			replicas = fmt.Sprintf("%d-%d", w.Replicas, w.MaxReplicas)
+		}
+		fmt.Fprintf(&sb, "%s/%s: %s x (%.3f vCPU, %.3f GiB) = %.2f per month", w.Kind, w.Name, replicas, w.PodCPU, w.PodMemoryGB, w.MonthlyCost)
+		if w.Intermittent {
+			sb.WriteString(", runs intermittently and is not counted")
+		}
+		if len(w.MissingRequests) > 0 {
+			fmt.Fprintf(&sb, ", no requests for %s", strings.Join(w.MissingRequests, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
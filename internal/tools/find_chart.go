@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewFindChartTool() mcp.Tool {
+	return mcp.NewTool("find_chart",
+		mcp.WithDescription("Finds the repositories providing a chart when only its name is known, e.g. \"the cert-manager chart\". Searches the repositories of the repository aliases (e.g., bitnami) and optionally Artifact Hub, and returns the candidate repository URLs with their latest versions. Pass a candidate repository URL and chart name to the other tools."),
+		readOnlyAnnotation("Find chart"),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name to look for, compared case-insensitively"),
+		),
+		mcp.WithBoolean("search_artifact_hub",
+			mcp.Description("Also search Artifact Hub (artifacthub.io) for public repositories providing the chart. Official charts and charts of verified publishers are listed first. Defaults to false"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[helm_client.FindChartResult](),
+	)
+}
+
+func GetFindChartHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		chartName, err := request.RequireString("chart_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		chartName = strings.TrimSpace(chartName)
+		if chartName == "" {
+			return mcp.NewToolResultError("chart_name must not be empty"), nil
+		}
+
+		result := c.FindChart(ctx, chartName, request.GetBool("search_artifact_hub", false))
+		return formatOutput(format, result, func() string {
+			var b strings.Builder
+			for _, candidate := range result.Candidates {
+				fmt.Fprintf(&b, "%s %s %s (%s)\n", candidate.RepositoryURL, candidate.Chart, candidate.LatestVersion, candidate.Source)
+			}
+			for source, err := range result.Errors {
+				fmt.Fprintf(&b, "failed to search %s: %s\n", source, err)
+			}
+			if b.Len() == 0 {
+				return fmt.Sprintf("No repository providing chart %s found", chartName)
+			}
+			return b.String()
+		}), nil
+	}
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewFindChartVersionByAppVersionTool() mcp.Tool {
+	return mcp.NewTool("find_chart_version_by_app_version",
+		mcp.WithDescription("Finds the chart versions shipping a given application version by walking the repository index, e.g. which grafana chart versions deploy Grafana 11.2. Versions are returned newest first. Not supported for OCI registries."),
+		readOnlyAnnotation("Find chart version by app version"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name"),
+		),
+		mcp.WithString("app_version",
+			mcp.Required(),
+			mcp.Description("Application version to look for. A partial version matches every version it is a prefix of, e.g. 11.2 matches 11.2.0 and 11.2.1"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[appVersionSearchResult](),
+	)
+}
+
+type appVersionSearchResult struct {
+	Chart      string                     `json:"chart"`
+	AppVersion string                     `json:"appVersion"`
+	Versions   []helm_client.ChartVersion `json:"versions"`
+}
+
+func GetFindChartVersionByAppVersionHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, false)
+		if errResult != nil {
+			return errResult, nil
+		}
+		appVersion, err := request.RequireString("app_version")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		versions, err := c.FindChartVersionsByAppVersion(ctx, params.RepositoryURL, params.ChartName, appVersion)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to find chart versions: %v", err)), nil
+		}
+
+		return formatOutput(format, appVersionSearchResult{
+			Chart:      params.ChartName,
+			AppVersion: appVersion,
+			Versions:   versions,
+		}, nil), nil
+	}
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewFindExternalDependenciesTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Scans the effective values of a chart and the manifests rendered with them for services outside the cluster the chart expects: database, cache, message queue, object storage and SMTP endpoints in values, environment variables and ConfigMaps, targets of ExternalName Services and host names managed by external-dns. Endpoints inside the cluster are ignored, passwords in URLs are redacted and Secrets are not inspected."),
+		readOnlyAnnotation("Find external dependencies"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("custom_values",
+			mcp.Description("JSON object of custom values to override chart defaults (e.g., {\"externalDatabase\": {\"host\": \"db.example.com\"}})"),
+		),
+		setParam,
+		valuesURLParam,
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.DependencyReport](),
+	}
+	return mcp.NewTool("find_external_dependencies", append(opts, renderOptionsParams...)...)
+}
+
+func FindExternalDependenciesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		customValues, errResult := extractValues(ctx, c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		renderOpts, errResult := extractRenderOptions(request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.FindExternalDependencies(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, customValues, renderOpts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to find external dependencies: %v", err)), nil
+		}
+
+		return formatOutput(format, report, func() string { return formatDependencyReport(report) }), nil
+	}
+}
+
+func formatDependencyReport(report *helm_parser.DependencyReport) string {
+	if len(report.Dependencies) == 0 {
+		return "No external dependencies found"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Found %d external endpoints: %s\n", len(report.Dependencies), strings.Join(report.Categories, ", "))
+	for _, category := range report.Categories {
+		fmt.Fprintf(&sb, "\n%s:\n", category)
+		for _, dep := range report.Dependencies {
+			if dep.Category == category {
+				fmt.Fprintf(&sb, "  %s (%s)\n", dep.Endpoint, dep.Location)
+			}
+		}
+	}
+	return sb.String()
+}
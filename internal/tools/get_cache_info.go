@@ -0,0 +1,35 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewGetCacheInfoTool() mcp.Tool {
+	return mcp.NewTool("get_cache_info",
+		mcp.WithDescription("Reports the caches of the server: the repository indexes held in memory with their age and chart count, the loaded charts cached in memory and the chart archives cached on disk with their size. Use it to understand why results might be stale; force_refresh on the repository tools downloads an index again."),
+		readOnlyAnnotation("Get cache info"),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[helm_client.CacheInfo](),
+	)
+}
+
+func GetCacheInfoHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		info, err := c.GetCacheInfo()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get cache info: %v", err)), nil
+		}
+		return formatOutput(format, info, nil), nil
+	}
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewGetChartAppVersionTool() mcp.Tool {
+	return mcp.NewTool("get_chart_app_version",
+		mcp.WithDescription("Retrieves the appVersion of a chart version, i.e. the version of the application the chart deploys."),
+		readOnlyAnnotation("Get chart app version"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[helm_client.ChartVersion](),
+	)
+}
+
+func GetChartAppVersionHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		appVersion, err := c.GetChartAppVersion(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get app version: %v", err)), nil
+		}
+
+		return formatOutput(format, helm_client.ChartVersion{Version: params.ChartVersion, AppVersion: appVersion}, nil), nil
+	}
+}
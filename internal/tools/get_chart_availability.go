@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewGetChartAvailabilityTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart with the given values and summarizes per workload what keeps it available: replica count, HorizontalPodAutoscaler, PodDisruptionBudget, topology spread constraints, pod anti-affinity and update strategy. Use it to assess whether the defaults of a chart are ready for production, or which values enable high availability."),
+		readOnlyAnnotation("Get chart availability"),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.AvailabilityReport](),
+	}
+	return mcp.NewTool("get_chart_availability", append(opts, chartRenderParams(`{"replicaCount": 3, "pdb": {"create": true}}`)...)...)
+}
+
+func GetChartAvailabilityHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		render, errResult := extractChartRender(ctx, request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.SummarizeChartAvailability(ctx, render.RepositoryURL, render.ChartName, render.ChartVersion, render.Values, render.Options)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to summarize chart availability: %v", err)), nil
+		}
+
+		return formatOutput(format, report, func() string { return formatAvailabilityReport(report) }), nil
+	}
+}
+
+func formatAvailabilityReport(report *helm_parser.AvailabilityReport) string {
+	if len(report.Workloads) == 0 {
+		return "The chart renders no Deployments, StatefulSets, DaemonSets or ReplicaSets"
+	}
+
+	var sb strings.Builder
+	for i, w := range report.Workloads {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "%s/%s\n", w.Kind, w.Name)
+		switch {
+		case w.Replicas == nil:
+			sb.WriteString("  replicas: one per node\n")
+		case w.Autoscaler != nil:
+			fmt.Fprintf(&sb, "  replicas: %d-%d (HorizontalPodAutoscaler %s)\n", w.Autoscaler.MinReplicas, w.Autoscaler.MaxReplicas, w.Autoscaler.Name)
+		default:
+			fmt.Fprintf(&sb, "  replicas: %d\n", *w.Replicas)
+		}
+		switch {
+		case w.DisruptionBudget == nil:
+			sb.WriteString("  disruption budget: none\n")
+		case w.DisruptionBudget.MinAvailable != "":
+			fmt.Fprintf(&sb, "  disruption budget: %s, minAvailable %s\n", w.DisruptionBudget.Name, w.DisruptionBudget.MinAvailable)
+		default:
+			fmt.Fprintf(&sb, "  disruption budget: %s, maxUnavailable %s\n", w.DisruptionBudget.Name, w.DisruptionBudget.MaxUnavailable)
+		}
+		spread := "none"
+		if len(w.TopologySpread) > 0 {
+			keys := make([]string, 0, len(w.TopologySpread))
+			for _, s := range w.TopologySpread {
+				keys = append(keys, fmt.Sprintf("%s (maxSkew %d, %s)", s.TopologyKey, s.MaxSkew, s.WhenUnsatisfiable))
+			}
+			spread = strings.Join(keys, ", ")
+		}
+		fmt.Fprintf(&sb, "  topology spread: %s\n", spread)
+		fmt.Fprintf(&sb, "  pod anti-affinity: %t\n", w.PodAntiAffinity)
+		if w.UpdateStrategy.Type != "" {
+			fmt.Fprintf(&sb, "  update strategy: %s", w.UpdateStrategy.Type)
+			if w.UpdateStrategy.MaxSurge != "" {
+				fmt.Fprintf(&sb, ", maxSurge %s", w.UpdateStrategy.MaxSurge)
+			}
+			if w.UpdateStrategy.MaxUnavailable != "" {
+				fmt.Fprintf(&sb, ", maxUnavailable %s", w.UpdateStrategy.MaxUnavailable)
+			}
+			if w.UpdateStrategy.Partition != nil {
+				fmt.Fprintf(&sb, ", partition %d", *w.UpdateStrategy.Partition)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
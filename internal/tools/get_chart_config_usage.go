@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewGetChartConfigUsageTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart with the given values and maps which workloads use which ConfigMaps and Secrets through environment variables, envFrom, volumes and image pull secrets, and which of them the chart creates itself, including through ExternalSecrets, SealedSecrets and cert-manager Certificates, or expects to exist before installation."),
+		readOnlyAnnotation("Get chart config usage"),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.ConfigUsageReport](),
+	}
+	return mcp.NewTool("get_chart_config_usage", append(opts, chartRenderParams(`{"auth": {"existingSecret": "app-credentials"}}`)...)...)
+}
+
+func GetChartConfigUsageHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		render, errResult := extractChartRender(ctx, request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.GetChartConfigUsage(ctx, render.RepositoryURL, render.ChartName, render.ChartVersion, render.Values, render.Options)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get chart config usage: %v", err)), nil
+		}
+
+		return formatOutput(format, report, func() string { return formatConfigUsageReport(report) }), nil
+	}
+}
+
+func formatConfigUsageReport(report *helm_parser.ConfigUsageReport) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Secrets expected to exist: %s\n", joinOrNone(report.ExpectedSecrets))
+	fmt.Fprintf(&sb, "ConfigMaps expected to exist: %s\n", joinOrNone(report.ExpectedConfigMaps))
+
+	for _, group := range []struct {
+		kind    string
+		objects []helm_parser.ConfigObject
+	}{{"ConfigMaps", report.ConfigMaps}, {"Secrets", report.Secrets}} {
+		if len(group.objects) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n%s:\n", group.kind)
+		for _, o := range group.objects {
+			origin := "expected to exist"
+			if o.CreatedBy != "" {
+				origin = "created by " + o.CreatedBy
+			}
+			if o.Optional {
+				origin += ", optional"
+			}
+			fmt.Fprintf(&sb, "  %s (%s), used by %s\n", o.Name, origin, joinOrNone(o.UsedBy))
+		}
+	}
+
+	for _, w := range report.Workloads {
+		if len(w.References) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n%s/%s\n", w.Kind, w.Name)
+		for _, ref := range w.References {
+			fmt.Fprintf(&sb, "  %s %s via %s", ref.Kind, ref.Name, ref.Usage)
+			if ref.Key != "" {
+				fmt.Fprintf(&sb, " key %s", ref.Key)
+			}
+			if ref.Container != "" {
+				fmt.Fprintf(&sb, " in container %s", ref.Container)
+			}
+			if ref.Optional {
+				sb.WriteString(" (optional)")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
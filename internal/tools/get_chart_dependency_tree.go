@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewGetChartDependencyTreeTool() mcp.Tool {
+	return mcp.NewTool("get_chart_dependency_tree",
+		mcp.WithDescription("Retrieves the transitive dependencies of the chart as a nested tree with name, version constraint, repository, condition, tags, alias and the dependencies of every bundled subchart. Use it to understand umbrella charts at a glance. Supports both HTTP repositories and OCI registries."),
+		readOnlyAnnotation("Get chart dependency tree"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used")),
+		outputFormatParam(OutputJSON),
+		// mcp.WithOutputSchema cannot describe the recursive
+		// helm_parser.DependencyNode.
+		mcp.WithRawOutputSchema(dependencyTreeSchema),
+	)
+}
+
+// dependencyTreeResult is the structured content of get_chart_dependency_tree.
+type dependencyTreeResult struct {
+	Dependencies []helm_parser.DependencyNode `json:"dependencies"`
+}
+
+var dependencyTreeSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"dependencies": {"type": ["null", "array"], "items": {"$ref": "#/$defs/dependency"}}
+	},
+	"required": ["dependencies"],
+	"$defs": {
+		"dependency": {
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"version": {"type": "string"},
+				"repository": {"type": "string"},
+				"condition": {"type": "string"},
+				"tags": {"type": ["null", "array"], "items": {"type": "string"}},
+				"alias": {"type": "string"},
+				"bundled": {"type": "boolean"},
+				"resolvedVersion": {"type": "string"},
+				"children": {"type": ["null", "array"], "items": {"$ref": "#/$defs/dependency"}}
+			},
+			"required": ["name", "version", "bundled"],
+			"additionalProperties": false
+		}
+	}
+}`)
+
+func GetChartDependencyTreeHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		tree, err := c.GetChartDependencyTree(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get dependency tree: %v", err)), nil
+		}
+		return formatListOutput(format, tree, dependencyTreeResult{Dependencies: tree}, nil), nil
+	}
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewGetChartDigestTool() mcp.Tool {
+	return mcp.NewTool("get_chart_digest",
+		mcp.WithDescription("Retrieves the digest of a chart version, to pin it immutably, e.g. in GitOps pipelines: the sha256 digest of the chart archive listed in the index of HTTP repositories, or the manifest digest for OCI registries together with the OCI reference pinned to it. If the index lists no digest, the archive is downloaded and its digest computed."),
+		readOnlyAnnotation("Get chart digest"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com) and OCI registries (e.g., oci://ghcr.io/org/charts/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[helm_client.ChartDigest](),
+	)
+}
+
+func GetChartDigestHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		digest, err := c.GetChartDigest(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get chart digest: %v", err)), nil
+		}
+
+		return formatOutput(format, digest, func() string {
+			if digest.Reference != "" {
+				return digest.Reference
+			}
+			return digest.Digest
+		}), nil
+	}
+}
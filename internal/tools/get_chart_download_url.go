@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewGetChartDownloadURLTool() mcp.Tool {
+	return mcp.NewTool("get_chart_download_url",
+		mcp.WithDescription("Retrieves the absolute URLs the archive of a chart version can be downloaded from, e.g. for mirroring or external tooling: the URLs listed in the index of HTTP repositories, with relative URLs resolved against the repository, or the OCI reference for OCI registries."),
+		readOnlyAnnotation("Get chart download URL"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com) and OCI registries (e.g., oci://ghcr.io/org/charts/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[chartDownloadURLsResult](),
+	)
+}
+
+type chartDownloadURLsResult struct {
+	Chart   string   `json:"chart"`
+	Version string   `json:"version"`
+	URLs    []string `json:"urls"`
+}
+
+func GetChartDownloadURLHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		urls, err := c.GetChartDownloadURLs(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get chart download URLs: %v", err)), nil
+		}
+
+		result := chartDownloadURLsResult{Chart: params.ChartName, Version: params.ChartVersion, URLs: urls}
+		return formatOutput(format, result, func() string { return strings.Join(urls, "\n") }), nil
+	}
+}
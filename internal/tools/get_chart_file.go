@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewGetChartFileTool() mcp.Tool {
+	return mcp.NewTool("get_chart_file",
+		mcp.WithDescription("Retrieves the content of a single chart file. Use list_chart_files to find file paths. Supports both HTTP repositories and OCI registries."),
+		readOnlyAnnotation("Get chart file"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used")),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the file relative to the chart root, as returned by list_chart_files (e.g., templates/deployment.yaml or charts/redis/values.yaml)"),
+		),
+	)
+}
+
+func GetChartFileHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		path, err := request.RequireString("path")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		content, err := c.GetChartFile(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, strings.TrimSpace(path))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get chart file: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(content), nil
+	}
+}
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -13,11 +12,12 @@ import (
 )
 
 func NewGetChartImagesTool() mcp.Tool {
-	return mcp.NewTool("get_chart_images",
-		mcp.WithDescription("Extracts container images used in a Helm chart by rendering templates and parsing Kubernetes manifests. Supports both HTTP repositories and OCI registries."),
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Extracts container images used in a Helm chart by rendering templates and parsing Kubernetes manifests, and optionally by scanning the values for image references. Supports both HTTP repositories and OCI registries."),
+		readOnlyAnnotation("Get chart images"),
 		mcp.WithString("repository_url",
 			mcp.Required(),
-			mcp.Description("Helm repository URL. Supports HTTP repos (e.g., https://charts.example.com) and OCI registries (e.g., oci://ghcr.io/org/charts/mychart)"),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
 		),
 		mcp.WithString("chart_name",
 			mcp.Required(),
@@ -30,37 +30,75 @@ func NewGetChartImagesTool() mcp.Tool {
 			mcp.Description("If true, extracts images from subcharts as well. Defaults to false"),
 		),
 		mcp.WithString("custom_values",
-			mcp.Description("JSON object of custom values to override chart defaults (e.g., {\"image.tag\": \"v2\"})"),
+			mcp.Description("JSON object of custom values to override chart defaults (e.g., {\"image\": {\"tag\": \"v2\"}})"),
 		),
-	)
+		setParam,
+		valuesURLParam,
+		mcp.WithString("source",
+			mcp.Description("Where to look for images: rendered (default, images in the manifests rendered with the values), values (image references in the values, including those of optional features that are disabled by default) or all"),
+			mcp.Enum(string(helm_parser.ImagesRendered), string(helm_parser.ImagesValues), string(helm_parser.ImagesAll)),
+		),
+		mcp.WithBoolean("platforms",
+			mcp.Description("If true, looks up each image in its registry and reports the platforms it is published for (e.g. linux/amd64, linux/arm64), to check compatibility with ARM clusters. Defaults to false"),
+		),
+		mcp.WithBoolean("resolve_digests",
+			mcp.Description("If true, resolves each image tag to the digest it currently points to in its registry, reported as resolvedDigest, for pinning images or detecting tags that moved. Defaults to false"),
+		),
+		mcp.WithBoolean("verify_images",
+			mcp.Description("If true, checks that each image exists in its registry and reports its status: found, missing (the tag or digest was never published), unauthorized (the registry requires credentials) or error. Defaults to false"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[chartImagesResult](),
+	}
+	return mcp.NewTool("get_chart_images", append(opts, renderOptionsParams...)...)
 }
 
 type chartImagesResult struct {
-	Chart      string                       `json:"chart"`
-	Version    string                       `json:"version"`
-	ImageCount int                          `json:"imageCount"`
-	Images     []helm_parser.ImageReference `json:"images"`
+	Chart      string       `json:"chart"`
+	Version    string       `json:"version"`
+	ImageCount int          `json:"imageCount"`
+	Images     []chartImage `json:"images"`
+}
+
+type chartImage struct {
+	helm_parser.ImageReference
+	Platforms []string `json:"platforms,omitempty"`
+	// PlatformsError is set if the platforms could not be looked up.
+	PlatformsError string `json:"platformsError,omitempty"`
+	// Status and StatusError are set if the image was verified.
+	Status      string `json:"status,omitempty"`
+	StatusError string `json:"statusError,omitempty"`
 }
 
 func GetChartImagesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		params, errResult := ExtractCommonParams(request, c, true)
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
 		if errResult != nil {
 			return errResult, nil
 		}
 
 		recursive := request.GetBool("recursive", false)
+		source, err := helm_parser.ParseImageSource(request.GetString("source", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 
-		// Parse custom values if provided
-		var customValues map[string]interface{}
-		customValuesStr := request.GetString("custom_values", "")
-		if customValuesStr != "" {
-			if err := json.Unmarshal([]byte(customValuesStr), &customValues); err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to parse custom_values JSON: %v", err)), nil
-			}
+		customValues, errResult := extractValues(ctx, c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		renderOpts, errResult := extractRenderOptions(request)
+		if errResult != nil {
+			return errResult, nil
 		}
 
-		images, err := c.GetChartImages(params.RepositoryURL, params.ChartName, params.ChartVersion, customValues, recursive)
+		images, err := c.GetChartImages(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, customValues, recursive, source, renderOpts)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to extract images: %v", err)), nil
 		}
@@ -69,14 +107,31 @@ func GetChartImagesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
 			Chart:      params.ChartName,
 			Version:    params.ChartVersion,
 			ImageCount: len(images),
-			Images:     images,
+			Images:     make([]chartImage, len(images)),
 		}
-
-		encoded, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal result: %v", err)), nil
+		for i, image := range images {
+			result.Images[i].ImageReference = image
+		}
+		if request.GetBool("platforms", false) {
+			for i, p := range c.GetImagePlatforms(ctx, images) {
+				result.Images[i].Platforms = p.Platforms
+				if p.Err != nil {
+					result.Images[i].PlatformsError = p.Err.Error()
+				}
+			}
+		}
+		// Both options resolve the manifest of every image, so the registries
+		// are only queried once.
+		if request.GetBool("verify_images", false) || request.GetBool("resolve_digests", false) {
+			for i, status := range c.VerifyImages(ctx, images) {
+				result.Images[i].Status = status.Status
+				result.Images[i].ResolvedDigest = status.Digest
+				if status.Err != nil {
+					result.Images[i].StatusError = status.Err.Error()
+				}
+			}
 		}
 
-		return mcp.NewToolResultText(string(encoded)), nil
+		return formatOutput(format, result, nil), nil
 	}
 }
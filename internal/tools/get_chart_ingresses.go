@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewGetChartIngressesTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart with the given values and returns its Ingresses, Gateway API Gateways and HTTPRoutes with their hosts, paths, backend services, TLS secrets, ingress or gateway class and cert-manager issuer. Use it to plan the DNS records and certificates an installation needs."),
+		readOnlyAnnotation("Get chart ingresses"),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.IngressReport](),
+	}
+	return mcp.NewTool("get_chart_ingresses", append(opts, chartRenderParams(`{"ingress": {"enabled": true, "hostname": "app.example.com"}}`)...)...)
+}
+
+func GetChartIngressesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		render, errResult := extractChartRender(ctx, request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.GetChartIngresses(ctx, render.RepositoryURL, render.ChartName, render.ChartVersion, render.Values, render.Options)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get chart ingresses: %v", err)), nil
+		}
+
+		return formatOutput(format, report, func() string { return formatIngressReport(report) }), nil
+	}
+}
+
+func formatIngressReport(report *helm_parser.IngressReport) string {
+	if len(report.Resources) == 0 {
+		return "The chart renders no Ingresses, Gateways or HTTPRoutes"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Hosts: %s\n", joinOrNone(report.Hosts))
+	fmt.Fprintf(&sb, "TLS secrets: %s\n", joinOrNone(report.TLSSecrets))
+	for _, r := range report.Resources {
+		fmt.Fprintf(&sb, "\n%s/%s", r.Kind, r.Name)
+		if r.Class != "" {
+			fmt.Fprintf(&sb, " (class %s)", r.Class)
+		}
+		sb.WriteString("\n")
+		if len(r.ParentRefs) > 0 {
+			fmt.Fprintf(&sb, "  gateways: %s\n", strings.Join(r.ParentRefs, ", "))
+		}
+		for _, l := range r.Listeners {
+			fmt.Fprintf(&sb, "  listener %s: %s port %d", l.Name, l.Protocol, l.Port)
+			if l.Hostname != "" {
+				fmt.Fprintf(&sb, " for %s", l.Hostname)
+			}
+			if len(l.TLSSecrets) > 0 {
+				fmt.Fprintf(&sb, ", certificates %s", strings.Join(l.TLSSecrets, ", "))
+			}
+			sb.WriteString("\n")
+		}
+		for _, route := range r.Routes {
+			host := route.Host
+			if host == "" {
+				host = "*"
+			}
+			fmt.Fprintf(&sb, "  %s%s -> %s\n", host, route.Path, route.Backend)
+		}
+		for _, tls := range r.TLS {
+			fmt.Fprintf(&sb, "  TLS %s for %s\n", tls.SecretName, joinOrNone(tls.Hosts))
+		}
+		if r.CertIssuer != "" {
+			fmt.Fprintf(&sb, "  cert-manager issuer: %s\n", r.CertIssuer)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewGetChartSchedulingTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart with the given values and summarizes the scheduling constraints of its workloads: node selectors, node and pod affinities, tolerations, priority classes, runtime classes and schedulers, and which workloads only run on nodes with specific labels. Use it to see whether a chart assumes specific node pools."),
+		readOnlyAnnotation("Get chart scheduling"),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.SchedulingReport](),
+	}
+	return mcp.NewTool("get_chart_scheduling", append(opts, chartRenderParams(`{"nodeSelector": {"kubernetes.io/arch": "arm64"}}`)...)...)
+}
+
+func GetChartSchedulingHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		render, errResult := extractChartRender(ctx, request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.GetChartScheduling(ctx, render.RepositoryURL, render.ChartName, render.ChartVersion, render.Values, render.Options)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get chart scheduling: %v", err)), nil
+		}
+
+		return formatOutput(format, report, func() string { return formatSchedulingReport(report) }), nil
+	}
+}
+
+func formatSchedulingReport(report *helm_parser.SchedulingReport) string {
+	if len(report.Workloads) == 0 {
+		return "The chart renders no workloads"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Pinned to specific nodes: %s\n", joinOrNone(report.PinnedWorkloads))
+	fmt.Fprintf(&sb, "Required node labels: %s\n", joinOrNone(report.NodeLabels))
+	fmt.Fprintf(&sb, "Tolerations: %s\n", joinOrNone(report.Tolerations))
+	fmt.Fprintf(&sb, "Priority classes: %s\n", joinOrNone(report.PriorityClasses))
+	fmt.Fprintf(&sb, "Runtime classes: %s\n", joinOrNone(report.RuntimeClasses))
+
+	for _, w := range report.Workloads {
+		fmt.Fprintf(&sb, "\n%s/%s\n", w.Kind, w.Name)
+		if len(w.NodeSelector) > 0 {
+			fmt.Fprintf(&sb, "  node selector: %s\n", formatLabels(w.NodeSelector))
+		}
+		for _, list := range []struct {
+			name  string
+			terms []string
+		}{{"node affinity", w.NodeAffinity}, {"pod affinity", w.PodAffinity}, {"pod anti-affinity", w.PodAntiAffinity}} {
+			for _, term := range list.terms {
+				fmt.Fprintf(&sb, "  %s %s\n", list.name, term)
+			}
+		}
+		if len(w.Tolerations) > 0 {
+			fmt.Fprintf(&sb, "  tolerations: %s\n", strings.Join(w.Tolerations, ", "))
+		}
+		if w.PriorityClassName != "" {
+			fmt.Fprintf(&sb, "  priority class: %s\n", w.PriorityClassName)
+		}
+		if w.RuntimeClassName != "" {
+			fmt.Fprintf(&sb, "  runtime class: %s\n", w.RuntimeClassName)
+		}
+		if w.SchedulerName != "" {
+			fmt.Fprintf(&sb, "  scheduler: %s\n", w.SchedulerName)
+		}
+	}
+	return sb.String()
+}
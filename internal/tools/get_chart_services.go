@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewGetChartServicesTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart with the given values and returns its Services with their type, ports, target ports, selectors and the workloads they select, and the ports reachable from outside the cluster through LoadBalancer and NodePort Services, external IPs and container host ports. Use it for a quick view of the network surface of an installation."),
+		readOnlyAnnotation("Get chart services"),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.ServiceReport](),
+	}
+	return mcp.NewTool("get_chart_services", append(opts, chartRenderParams(`{"service": {"type": "LoadBalancer"}}`)...)...)
+}
+
+func GetChartServicesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		render, errResult := extractChartRender(ctx, request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.GetChartServices(ctx, render.RepositoryURL, render.ChartName, render.ChartVersion, render.Values, render.Options)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get chart services: %v", err)), nil
+		}
+
+		return formatOutput(format, report, func() string { return formatServiceReport(report) }), nil
+	}
+}
+
+func formatServiceReport(report *helm_parser.ServiceReport) string {
+	var sb strings.Builder
+	if len(report.Exposed) == 0 {
+		sb.WriteString("No ports are exposed outside the cluster\n")
+	} else {
+		sb.WriteString("Exposed outside the cluster:\n")
+		for _, e := range report.Exposed {
+			fmt.Fprintf(&sb, "  %s %d/%s via %s", e.Resource, e.Port, e.Protocol, e.Exposure)
+			if e.NodePort != 0 {
+				fmt.Fprintf(&sb, " (node port %d)", e.NodePort)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	if len(report.Services) == 0 {
+		sb.WriteString("\nThe chart renders no Services\n")
+		return sb.String()
+	}
+
+	for _, s := range report.Services {
+		fmt.Fprintf(&sb, "\nService/%s (%s", s.Name, s.Type)
+		if s.Headless {
+			sb.WriteString(", headless")
+		}
+		sb.WriteString(")\n")
+		if s.ExternalName != "" {
+			fmt.Fprintf(&sb, "  external name: %s\n", s.ExternalName)
+		}
+		for _, p := range s.Ports {
+			name := p.Name
+			if name == "" {
+				name = "-"
+			}
+			fmt.Fprintf(&sb, "  port %s: %d/%s -> %s", name, p.Port, p.Protocol, p.TargetPort)
+			if p.NodePort != 0 {
+				fmt.Fprintf(&sb, ", node port %d", p.NodePort)
+			}
+			sb.WriteString("\n")
+		}
+		if len(s.Selector) > 0 {
+			fmt.Fprintf(&sb, "  selector: %s\n", formatLabels(s.Selector))
+			fmt.Fprintf(&sb, "  workloads: %s\n", joinOrNone(s.Workloads))
+		}
+		if len(s.ExternalIPs) > 0 {
+			fmt.Fprintf(&sb, "  external IPs: %s\n", strings.Join(s.ExternalIPs, ", "))
+		}
+		if len(s.LoadBalancerSourceRanges) > 0 {
+			fmt.Fprintf(&sb, "  source ranges: %s\n", strings.Join(s.LoadBalancerSourceRanges, ", "))
+		}
+		if s.ExternalTrafficPolicy != "" {
+			fmt.Fprintf(&sb, "  external traffic policy: %s\n", s.ExternalTrafficPolicy)
+		}
+		if len(s.LoadBalancerAnnotations) > 0 {
+			fmt.Fprintf(&sb, "  load balancer annotations: %s\n", formatLabels(s.LoadBalancerAnnotations))
+		}
+	}
+	return sb.String()
+}
+
+// formatLabels formats labels or annotations as sorted key=value pairs.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ", ")
+}
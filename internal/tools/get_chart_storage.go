@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewGetChartStorageTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart with the given values and returns the storage it requests: its PersistentVolumeClaims, the volumeClaimTemplates of StatefulSets and ephemeral volumes with their size, storage class, access modes and number of claims, the total size, and the claims workloads mount that must exist before installation. Use it to plan storage provisioning."),
+		readOnlyAnnotation("Get chart storage"),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.StorageReport](),
+	}
+	return mcp.NewTool("get_chart_storage", append(opts, chartRenderParams(`{"persistence": {"size": "50Gi", "storageClass": "gp3"}}`)...)...)
+}
+
+func GetChartStorageHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		render, errResult := extractChartRender(ctx, request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.GetChartStorage(ctx, render.RepositoryURL, render.ChartName, render.ChartVersion, render.Values, render.Options)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get chart storage: %v", err)), nil
+		}
+
+		return formatOutput(format, report, func() string { return formatStorageReport(report) }), nil
+	}
+}
+
+func formatStorageReport(report *helm_parser.StorageReport) string {
+	var sb strings.Builder
+	if len(report.Claims) == 0 {
+		sb.WriteString("The chart requests no persistent storage\n")
+	} else {
+		classes := slices.Clone(report.StorageClasses)
+		if report.UsesDefaultClass {
+			classes = append(classes, "(cluster default)")
+		}
+		fmt.Fprintf(&sb, "Total: %g GiB in %d claim definitions\n", report.TotalGB, len(report.Claims))
+		fmt.Fprintf(&sb, "Storage classes: %s\n", joinOrNone(classes))
+	}
+	if len(report.ExpectedClaims) > 0 {
+		fmt.Fprintf(&sb, "Claims expected to exist: %s\n", strings.Join(report.ExpectedClaims, ", "))
+	}
+
+	for _, c := range report.Claims {
+		size := c.Size
+		if size == "" {
+			size = "no size"
+		}
+		class := c.StorageClass
+		switch {
+		case c.Static:
+			class = "pre-provisioned volume"
+		case class == "":
+			class = "default class"
+		}
+		fmt.Fprintf(&sb, "\n%s: %s x %d, %s, %s", c.Name, size, c.Count, class, joinOrNone(c.AccessModes))
+		if c.VolumeMode != "" {
+			fmt.Fprintf(&sb, ", %s mode", c.VolumeMode)
+		}
+		sb.WriteString("\n")
+		switch {
+		case c.Ephemeral:
+			fmt.Fprintf(&sb, "  ephemeral volume of %s\n", c.Owner)
+		case c.Owner != "":
+			fmt.Fprintf(&sb, "  volumeClaimTemplate of %s\n", c.Owner)
+		default:
+			fmt.Fprintf(&sb, "  mounted by %s\n", joinOrNone(c.UsedBy))
+		}
+	}
+	return sb.String()
+}
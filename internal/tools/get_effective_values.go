@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewGetEffectiveValuesTool() mcp.Tool {
+	return mcp.NewTool("get_effective_values",
+		mcp.WithDescription("Returns the values a chart is rendered with for the given overrides: the chart defaults, including those of subcharts, coalesced with the values from values_url, custom_values and set using Helm's rules. A null value removes a default. Supports both HTTP repositories and OCI registries."),
+		readOnlyAnnotation("Get effective values"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("custom_values",
+			mcp.Description("JSON object of custom values to override chart defaults (e.g., {\"image\": {\"tag\": \"v2\"}})"),
+		),
+		setParam,
+		valuesURLParam,
+		outputFormatParam(OutputYAML),
+		mcp.WithOutputSchema[map[string]any](),
+	)
+}
+
+func GetEffectiveValuesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputYAML)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+		customValues, errResult := extractValues(ctx, c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		values, err := c.GetEffectiveValues(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, customValues)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get effective values: %v", err)), nil
+		}
+
+		return formatOutput(format, values, nil), nil
+	}
+}
@@ -12,29 +12,47 @@ import (
 func NewGetLatestVersionOfChartTool() mcp.Tool {
 	return mcp.NewTool("get_latest_version_of_chart",
 		mcp.WithDescription("Retrieves the latest version of the chart. For OCI registries, returns the latest semver tag."),
+		readOnlyAnnotation("Get latest chart version"),
 		mcp.WithString("repository_url",
 			mcp.Required(),
-			mcp.Description("Helm repository URL. Supports HTTP repos (e.g., https://charts.example.com) and OCI registries (e.g., oci://ghcr.io/org/charts/mychart)"),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
 		),
 		mcp.WithString("chart_name",
 			mcp.Required(),
 			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
 		),
+		mcp.WithBoolean("force_refresh",
+			mcp.Description("If true, re-downloads the repository index instead of using the cached copy. Defaults to false"),
+		),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[latestVersionResult](),
 	)
 }
 
+type latestVersionResult struct {
+	Version string `json:"version"`
+}
+
 func GetLatestVersionOfCharHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		params, errResult := ExtractCommonParams(request, c, false)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, false)
 		if errResult != nil {
 			return errResult, nil
 		}
 
-		version, err := c.GetChartLatestVersion(params.RepositoryURL, params.ChartName)
+		if request.GetBool("force_refresh", false) {
+			c.InvalidateRepositoryIndex(params.RepositoryURL)
+		}
+
+		version, err := c.GetChartLatestVersion(ctx, params.RepositoryURL, params.ChartName)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to list charts: %v", err)), nil
 		}
 
-		return mcp.NewToolResultText(version), nil
+		return formatOutput(format, latestVersionResult{Version: version}, func() string { return version }), nil
 	}
 }
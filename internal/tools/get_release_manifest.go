@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+)
+
+func NewGetReleaseManifestTool() mcp.Tool {
+	return mcp.NewTool("get_release_manifest",
+		mcp.WithDescription("Retrieves the rendered Kubernetes manifests deployed by an installed Helm release, like 'helm get manifest'. Use it to compare deployed resources with a chart's templates or to analyze drift."),
+		readOnlyAnnotation("Get release manifest"),
+		mcp.WithString("release_name",
+			mcp.Required(),
+			mcp.Description("Name of the release, as returned by list_releases"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the release. Defaults to the namespace of the kubeconfig context"),
+		),
+		contextParam,
+		clusterParam,
+		mcp.WithNumber("revision",
+			mcp.Description("Release revision to get the manifest of. Defaults to the latest revision"),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Byte offset to start returning the manifest from. Use the offset reported in a truncated response to continue. Defaults to 0"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description("Maximum number of bytes of the manifest to return. Larger manifests are truncated with continuation metadata. Set to 0 to return everything. Defaults to 100000"),
+		),
+	)
+}
+
+func GetReleaseManifestHandler(c *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		c, errResult := clusterClientFor(c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		name, err := request.RequireString("release_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		offset, maxBytes := ExtractPagination(request)
+
+		manifest, err := c.GetReleaseManifest(ctx, request.GetString("namespace", ""), name, request.GetInt("revision", 0))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get release manifest: %v", err)), nil
+		}
+		page, err := Paginate(manifest, offset, maxBytes)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return WithPaginationNote(mcp.NewToolResultText(page.Content), page), nil
+	}
+}
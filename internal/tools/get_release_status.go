@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+)
+
+func NewGetReleaseStatusTool() mcp.Tool {
+	return mcp.NewTool("get_release_status",
+		mcp.WithDescription("Retrieves the status of an installed Helm release, like 'helm status', together with the readiness of its Deployments, StatefulSets and DaemonSets and their pods. Use it to check whether a release is actually healthy, not just deployed."),
+		readOnlyAnnotation("Get release status"),
+		mcp.WithString("release_name",
+			mcp.Required(),
+			mcp.Description("Name of the release, as returned by list_releases"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the release. Defaults to the namespace of the kubeconfig context"),
+		),
+		contextParam,
+		clusterParam,
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[cluster_client.ReleaseStatus](),
+	)
+}
+
+func GetReleaseStatusHandler(c *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		c, errResult := clusterClientFor(c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		name, err := request.RequireString("release_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		status, err := c.GetReleaseStatus(ctx, request.GetString("namespace", ""), name)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get release status: %v", err)), nil
+		}
+
+		return formatOutput(format, status, nil), nil
+	}
+}
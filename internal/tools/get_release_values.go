@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+)
+
+func NewGetReleaseValuesTool() mcp.Tool {
+	return mcp.NewTool("get_release_values",
+		mcp.WithDescription("Retrieves the values of an installed Helm release, like 'helm get values'. Use it to plan upgrades against the values actually in use rather than the chart defaults."),
+		readOnlyAnnotation("Get release values"),
+		mcp.WithString("release_name",
+			mcp.Required(),
+			mcp.Description("Name of the release, as returned by list_releases"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the release. Defaults to the namespace of the kubeconfig context"),
+		),
+		contextParam,
+		clusterParam,
+		mcp.WithNumber("revision",
+			mcp.Description("Release revision to get the values of. Defaults to the latest revision"),
+		),
+		mcp.WithBoolean("all",
+			mcp.Description("If true, returns all computed values including the chart defaults instead of only the user-supplied values. Defaults to false"),
+		),
+		outputFormatParam(OutputYAML),
+		mcp.WithOutputSchema[map[string]any](),
+	)
+}
+
+func GetReleaseValuesHandler(c *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputYAML)
+		if errResult != nil {
+			return errResult, nil
+		}
+		c, errResult := clusterClientFor(c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		name, err := request.RequireString("release_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		values, err := c.GetReleaseValues(ctx,
+			request.GetString("namespace", ""),
+			name,
+			request.GetInt("revision", 0),
+			request.GetBool("all", false),
+		)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get release values: %v", err)), nil
+		}
+
+		return formatOutput(format, values, nil), nil
+	}
+}
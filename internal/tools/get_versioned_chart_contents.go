@@ -8,14 +8,16 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
 )
 
 func NewGetChartContentsTool() mcp.Tool {
 	return mcp.NewTool("get_chart_contents",
-		mcp.WithDescription("Retrieves full chart contents. Supports both HTTP repositories and OCI registries."),
+		mcp.WithDescription("Retrieves full chart contents. The first page also links every returned file as a chart file resource, so clients can read single files instead. Supports both HTTP repositories and OCI registries."),
+		readOnlyAnnotation("Get chart contents"),
 		mcp.WithString("repository_url",
 			mcp.Required(),
-			mcp.Description("Helm repository URL. Supports HTTP repos (e.g., https://charts.example.com) and OCI registries (e.g., oci://ghcr.io/org/charts/mychart)"),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
 		),
 		mcp.WithString("chart_name",
 			mcp.Required(),
@@ -27,27 +29,61 @@ func NewGetChartContentsTool() mcp.Tool {
 		mcp.WithBoolean("recursive",
 			mcp.Description("If true, retrieves all files in the chart recursively. Defaults to false"),
 		),
+		mcp.WithString("content_filter",
+			mcp.Description("Which files to return: all (default), templates (only files in templates/) or non_templates (every file except templates)"),
+			mcp.Enum(string(helm_parser.ContentAll), string(helm_parser.ContentTemplates), string(helm_parser.ContentNonTemplates)),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Byte offset to start returning contents from. Use the offset reported in a truncated response to continue. Defaults to 0"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description("Maximum number of bytes of contents to return. Larger contents are truncated with continuation metadata. Set to 0 to return everything. Defaults to 100000"),
+		),
 	)
 }
 
 func GetChartContentsHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		params, errResult := ExtractCommonParams(request, c, true)
+		ctx = ProgressContext(ctx, request)
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
 		if errResult != nil {
 			return errResult, nil
 		}
 
 		recursive := request.GetBool("recursive", false)
+		filter, err := helm_parser.ParseContentFilter(request.GetString("content_filter", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 
-		charts, err := c.GetChartContents(params.RepositoryURL, params.ChartName, params.ChartVersion, recursive)
+		offset, maxBytes := ExtractPagination(request)
+
+		charts, err := c.GetChartContents(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, recursive, filter)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to list charts: %v", err)), nil
 		}
-		encoded, err := json.MarshalIndent(charts, "", "  ")
+		page, err := Paginate(charts, offset, maxBytes)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		encoded, err := json.MarshalIndent(page.Content, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal charts: %v", err)), nil
 		}
 
-		return mcp.NewToolResultText(string(encoded)), nil
+		result := WithPaginationNote(mcp.NewToolResultText(string(encoded)), page)
+		// The links are only returned with the first page.
+		if offset == 0 {
+			files, err := c.ListChartFiles(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list chart files: %v", err)), nil
+			}
+			links, err := chartFileLinks(params, helm_parser.FilterChartFiles(files, recursive, filter))
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to link chart files: %v", err)), nil
+			}
+			result.Content = append(result.Content, links...)
+		}
+		return result, nil
 	}
 }
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -14,9 +13,10 @@ import (
 func NewGetChartDependenciesTool() mcp.Tool {
 	return mcp.NewTool("get_chart_dependencies",
 		mcp.WithDescription("Retrieves dependencies for the chart. Supports both HTTP repositories and OCI registries."),
+		readOnlyAnnotation("Get chart dependencies"),
 		mcp.WithString("repository_url",
 			mcp.Required(),
-			mcp.Description("Helm repository URL. Supports HTTP repos (e.g., https://charts.example.com) and OCI registries (e.g., oci://ghcr.io/org/charts/mychart)"),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
 		),
 		mcp.WithString("chart_name",
 			mcp.Required(),
@@ -24,25 +24,31 @@ func NewGetChartDependenciesTool() mcp.Tool {
 		),
 		mcp.WithString("chart_version",
 			mcp.Description("Chart version. If omitted the latest version will be used")),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[chartDependenciesResult](),
 	)
 }
 
+// chartDependenciesResult is the structured content of get_chart_dependencies.
+type chartDependenciesResult struct {
+	Dependencies []string `json:"dependencies"`
+}
+
 func GetChartDependenciesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		params, errResult := ExtractCommonParams(request, c, true)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
 		if errResult != nil {
 			return errResult, nil
 		}
 
-		charts, err := c.GetChartDependencies(params.RepositoryURL, params.ChartName, params.ChartVersion)
+		charts, err := c.GetChartDependencies(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to list charts: %v", err)), nil
 		}
-		encoded, err := json.MarshalIndent(charts, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal charts: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(encoded)), nil
+		return formatListOutput(format, charts, chartDependenciesResult{Dependencies: charts}, nil), nil
 	}
 }
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -12,10 +11,11 @@ import (
 
 func NewGetChartValuesTool() mcp.Tool {
 	return mcp.NewTool("get_chart_values",
-		mcp.WithDescription("Retrieves values file for the chart. Supports both HTTP repositories and OCI registries."),
+		mcp.WithDescription("Retrieves values file for the chart. With output_format yaml or text the values.yaml file is returned as is, including comments; json returns it as a JSON string. Supports both HTTP repositories and OCI registries."),
+		readOnlyAnnotation("Get chart values"),
 		mcp.WithString("repository_url",
 			mcp.Required(),
-			mcp.Description("Helm repository URL. Supports HTTP repos (e.g., https://charts.example.com) and OCI registries (e.g., oci://ghcr.io/org/charts/mychart)"),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
 		),
 		mcp.WithString("chart_name",
 			mcp.Required(),
@@ -23,25 +23,28 @@ func NewGetChartValuesTool() mcp.Tool {
 		),
 		mcp.WithString("chart_version",
 			mcp.Description("Chart version. If omitted the latest version will be used")),
+		outputFormatParam(OutputJSON),
 	)
 }
 
 func GetChartValuesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		params, errResult := ExtractCommonParams(request, c, true)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
 		if errResult != nil {
 			return errResult, nil
 		}
 
-		values, err := c.GetChartValues(params.RepositoryURL, params.ChartName, params.ChartVersion)
+		values, err := c.GetChartValues(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to get chart values: %v", err)), nil
 		}
-		encoded, err := json.MarshalIndent(values, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal values: %v", err)), nil
+		if format != OutputJSON {
+			return mcp.NewToolResultText(values), nil
 		}
-
-		return mcp.NewToolResultText(string(encoded)), nil
+		return formatOutput(format, values, nil), nil
 	}
 }
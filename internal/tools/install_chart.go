@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewInstallChartTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Installs a chart into the Kubernetes cluster as a new Helm release, like 'helm install'. Changes the cluster; consider dry_run_install first."),
+		writeAnnotation("Install chart", false),
+		mcp.WithString("release_name",
+			mcp.Required(),
+			mcp.Description("Name of the release to install"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to install into. Defaults to the namespace of the kubeconfig context"),
+		),
+		contextParam,
+		clusterParam,
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("custom_values",
+			mcp.Description("JSON object of custom values to override chart defaults (e.g., {\"replicaCount\": 3})"),
+		),
+		setParam,
+		valuesURLParam,
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[cluster_client.Release](),
+	}
+	return mcp.NewTool("install_chart", append(opts, writeOptionsParams...)...)
+}
+
+func GetInstallChartHandler(c *helm_client.HelmClient, cc *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		cc, errResult := clusterClientFor(cc, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		name, err := request.RequireString("release_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+		customValues, errResult := extractValues(ctx, c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		opts, errResult := extractWriteOptions(request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		chart, err := c.LoadChart(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		release, err := cc.InstallChart(ctx, request.GetString("namespace", ""), name, chart, customValues, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to install chart: %v", err)), nil
+		}
+
+		return formatOutput(format, release, nil), nil
+	}
+}
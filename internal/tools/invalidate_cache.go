@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+// Scopes of invalidate_cache.
+const (
+	invalidateScopeAll        = "all"
+	invalidateScopeRepository = "repository"
+	invalidateScopeChart      = "chart"
+)
+
+func NewInvalidateCacheTool() mcp.Tool {
+	return mcp.NewTool("invalidate_cache",
+		mcp.WithDescription("Drops cached repository indexes and loaded charts, so the next request downloads them again. Use it after publishing a new chart version, or re-publishing an existing one, to make the server pick it up without restarting."),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Invalidate cache",
+			ReadOnlyHint:    mcp.ToBoolPtr(false),
+			DestructiveHint: mcp.ToBoolPtr(false),
+			IdempotentHint:  mcp.ToBoolPtr(true),
+			OpenWorldHint:   mcp.ToBoolPtr(false),
+		}),
+		mcp.WithString("scope",
+			mcp.Required(),
+			mcp.Description("What to drop: all drops every cached index and chart; repository drops the index and the charts of repository_url; chart drops the index of repository_url and the cached versions of chart_name, or only chart_version if set"),
+			mcp.Enum(invalidateScopeAll, invalidateScopeRepository, invalidateScopeChart),
+		),
+		mcp.WithString("repository_url",
+			mcp.Description("Helm repository URL or alias (e.g., bitnami), required for the repository and chart scopes"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Description("Chart name, required for the chart scope. Optional for OCI URLs, where it is extracted from the URL"),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version to drop with the chart scope. All cached versions of the chart are dropped if not set"),
+		),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_client.CacheInvalidation](),
+	)
+}
+
+func GetInvalidateCacheHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		scope, err := request.RequireString("scope")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var repositoryURL, chartName, chartVersion string
+		switch scope {
+		case invalidateScopeAll:
+		case invalidateScopeRepository, invalidateScopeChart:
+			repositoryURL, errResult = ExtractRepositoryURL(request, c)
+			if errResult != nil {
+				return errResult, nil
+			}
+			if scope == invalidateScopeRepository {
+				break
+			}
+			chartName = strings.TrimSpace(request.GetString("chart_name", ""))
+			if chartName == "" && helm_client.IsOCI(repositoryURL) {
+				chartName = helm_client.ExtractChartNameFromOCI(repositoryURL)
+			}
+			if chartName == "" {
+				return mcp.NewToolResultError("chart_name is required for the chart scope"), nil
+			}
+			chartVersion = strings.TrimSpace(request.GetString("chart_version", ""))
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("unknown scope %q, expected one of %q, %q or %q", scope, invalidateScopeAll, invalidateScopeRepository, invalidateScopeChart)), nil
+		}
+
+		result := c.InvalidateCache(repositoryURL, chartName, chartVersion)
+		return formatOutput(format, result, func() string {
+			return fmt.Sprintf("Dropped %d cached repository indexes and %d cached charts.", result.Indexes, result.Charts)
+		}), nil
+	}
+}
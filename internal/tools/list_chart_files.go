@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewListChartFilesTool() mcp.Tool {
+	return mcp.NewTool("list_chart_files",
+		mcp.WithDescription("Lists the files of a chart with their sizes, without contents. Files of subcharts are listed below charts/<subchart>/ and marked with the subchart name. Every file is also linked as a chart file resource. Use get_chart_file or read the resource to fetch single files. Supports both HTTP repositories and OCI registries."),
+		readOnlyAnnotation("List chart files"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used")),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[chartFilesResult](),
+	)
+}
+
+// chartFilesResult is the structured content of list_chart_files.
+type chartFilesResult struct {
+	Files []helm_parser.ChartFile `json:"files"`
+}
+
+func GetListChartFilesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		files, err := c.ListChartFiles(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to list chart files: %v", err)), nil
+		}
+		links, err := chartFileLinks(params, files)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to link chart files: %v", err)), nil
+		}
+		result := formatListOutput(format, files, chartFilesResult{Files: files}, nil)
+		if !result.IsError {
+			result.Content = append(result.Content, links...)
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewListChartReferrersTool() mcp.Tool {
+	return mcp.NewTool("list_chart_referrers",
+		mcp.WithDescription("Lists the artifacts attached to an OCI chart version, such as signatures, SBOMs and provenance attestations, using the OCI referrers API and the tags cosign attaches artifacts with. Each artifact is reported with its kind (signature, sbom, attestation or other), artifact type, digest and annotations. Only OCI registries support attached artifacts."),
+		readOnlyAnnotation("List chart referrers"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("OCI registry URL or alias (e.g., oci://ghcr.io/org/charts/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("artifact_type",
+			mcp.Description("Only list artifacts of this type (e.g., application/spdx+json). If omitted all artifacts are listed"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[helm_client.ChartReferrers](),
+	)
+}
+
+func GetListChartReferrersHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		referrers, err := c.ListChartReferrers(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, request.GetString("artifact_type", ""))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to list referrers: %v", err)), nil
+		}
+
+		return formatOutput(format, referrers, func() string {
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "%s@%s\n", referrers.Reference, referrers.Digest)
+			if len(referrers.Referrers) == 0 {
+				sb.WriteString("No attached artifacts\n")
+			}
+			for _, r := range referrers.Referrers {
+				artifactType := r.ArtifactType
+				if artifactType == "" {
+					artifactType = r.MediaType
+				}
+				fmt.Fprintf(&sb, "%s %s %s", r.Kind, artifactType, r.Digest)
+				if r.Tag != "" {
+					fmt.Fprintf(&sb, " (tag %s)", r.Tag)
+				}
+				sb.WriteString("\n")
+			}
+			return sb.String()
+		}), nil
+	}
+}
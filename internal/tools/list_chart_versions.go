@@ -13,33 +13,56 @@ import (
 func NewListChartVersionsTool() mcp.Tool {
 	return mcp.NewTool("list_chart_versions",
 		mcp.WithDescription("Lists all available versions (tags) for a chart. For OCI registries, this lists all tags. For HTTP repositories, lists all versions from the index."),
+		readOnlyAnnotation("List chart versions"),
 		mcp.WithString("repository_url",
 			mcp.Required(),
-			mcp.Description("Helm repository URL. Supports HTTP repos (e.g., https://charts.example.com) and OCI registries (e.g., oci://ghcr.io/org/charts/mychart)"),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
 		),
 		mcp.WithString("chart_name",
 			mcp.Required(),
 			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
 		),
+		mcp.WithBoolean("force_refresh",
+			mcp.Description("If true, re-downloads the repository index instead of using the cached copy. Defaults to false"),
+		),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[chartVersionsResult](),
 	)
 }
 
+// chartVersionsResult is the structured content of list_chart_versions.
+type chartVersionsResult struct {
+	Versions []string `json:"versions"`
+}
+
 func GetListChartVersionsHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		params, errResult := ExtractCommonParams(request, c, false)
+		format, errResult := extractOutputFormat(request, OutputText)
 		if errResult != nil {
 			return errResult, nil
 		}
+		params, errResult := ExtractCommonParams(ctx, request, c, false)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		if request.GetBool("force_refresh", false) {
+			c.InvalidateRepositoryIndex(params.RepositoryURL)
+		}
 
-		versions, err := c.ListChartVersions(params.RepositoryURL, params.ChartName)
+		versions, err := c.ListChartVersions(ctx, params.RepositoryURL, params.ChartName)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to list chart versions: %v", err)), nil
 		}
 
-		if len(versions) == 0 {
-			return mcp.NewToolResultText("No versions found"), nil
+		if versions == nil {
+			versions = []string{}
 		}
-
-		return mcp.NewToolResultText(strings.Join(versions, ", ")), nil
+		return formatListOutput(format, versions, chartVersionsResult{Versions: versions}, func() string {
+			if len(versions) == 0 {
+				return "No versions found"
+			}
+			return strings.Join(versions, ", ")
+		}), nil
 	}
 }
@@ -0,0 +1,35 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+)
+
+func NewListClustersTool() mcp.Tool {
+	return mcp.NewTool("list_clusters",
+		mcp.WithDescription("Lists the clusters the server is configured for with their kubeconfig and context. Pass a name as the cluster parameter of other cluster tools to use that cluster; the default cluster is used otherwise."),
+		readOnlyAnnotation("List clusters"),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[clustersResult](),
+	)
+}
+
+// clustersResult is the structured content of list_clusters.
+type clustersResult struct {
+	Clusters []cluster_client.ClusterInfo `json:"clusters"`
+}
+
+func GetListClustersHandler(c *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		clusters := c.Clusters()
+		return formatListOutput(format, clusters, clustersResult{Clusters: clusters}, nil), nil
+	}
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+)
+
+func NewListReleasesTool() mcp.Tool {
+	return mcp.NewTool("list_releases",
+		mcp.WithDescription("Lists the Helm releases installed in the Kubernetes cluster with their namespace, chart, chart version, app version, status and last deployed time. Releases are sorted by name and returned in pages with the total count and the offset of the next page."),
+		readOnlyAnnotation("List installed releases"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to list releases from. If omitted releases from all namespaces are listed"),
+		),
+		contextParam,
+		clusterParam,
+		mcp.WithString("filter",
+			mcp.Description("Regular expression matched against release names, e.g. ^prometheus"),
+		),
+		mcp.WithString("selector",
+			mcp.Description("Label selector matched against release labels, e.g. team=platform,tier!=cache"),
+		),
+		mcp.WithArray("status",
+			mcp.WithStringEnumItems(cluster_client.ReleaseStatuses()),
+			mcp.Description("Statuses of the releases to include, e.g. [\"failed\", \"pending\"]. pending includes pending-install, pending-upgrade and pending-rollback. Defaults to all statuses"),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of releases to skip. Use the nextOffset of a previous response to continue. Defaults to 0"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of releases to return. Set to 0 to return all. Defaults to 100"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[cluster_client.ReleaseList](),
+	)
+}
+
+// defaultReleaseLimit is the page size of list_releases unless the request
+// sets limit.
+const defaultReleaseLimit = 100
+
+func GetListReleasesHandler(c *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		c, errResult := clusterClientFor(c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		releases, err := c.ListReleases(ctx, request.GetString("namespace", ""), cluster_client.ListOptions{
+			Filter:   request.GetString("filter", ""),
+			Selector: request.GetString("selector", ""),
+			Statuses: request.GetStringSlice("status", nil),
+			Offset:   request.GetInt("offset", 0),
+			Limit:    request.GetInt("limit", defaultReleaseLimit),
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to list releases: %v", err)), nil
+		}
+
+		return formatOutput(format, releases, nil), nil
+	}
+}
@@ -13,25 +13,89 @@ import (
 func NewListChartsTool() mcp.Tool {
 	return mcp.NewTool("list_repository_charts",
 		mcp.WithDescription("Lists all charts available in the repository. For OCI registries, returns the chart name from the reference (OCI repos contain a single chart with multiple version tags)."),
+		readOnlyAnnotation("List repository charts"),
 		mcp.WithString("repository_url",
 			mcp.Required(),
-			mcp.Description("Helm repository URL. Supports HTTP repos (e.g., https://charts.example.com) and OCI registries (e.g., oci://ghcr.io/org/charts/mychart)"),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
 		),
+		mcp.WithBoolean("force_refresh",
+			mcp.Description("If true, re-downloads the repository index instead of using the cached copy. Defaults to false"),
+		),
+		mcp.WithBoolean("detailed",
+			mcp.Description("If true, returns a list with the latest version, app version, description, icon, home and source URLs and keywords of every chart instead of only the names. Defaults to false"),
+		),
+		mcp.WithString("keywords",
+			mcp.Description("Comma-separated list of keywords to filter charts by category, e.g. monitoring,database. Charts having any of the keywords are listed"),
+		),
+		mcp.WithString("output_format",
+			mcp.Description("Format of the result: json, yaml or text (plain text for reading, YAML for the detailed list). Defaults to text, or json if detailed is set"),
+			mcp.Enum(string(OutputJSON), string(OutputYAML), string(OutputText)),
+		),
+		mcp.WithOutputSchema[repositoryChartsResult](),
 	)
 }
 
+// repositoryChartsResult is the structured content of list_repository_charts.
+// Details is only set for detailed listings.
+type repositoryChartsResult struct {
+	Charts  []string                   `json:"charts"`
+	Details []helm_client.ChartSummary `json:"details,omitempty"`
+}
+
 func GetListChartsHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		repositoryURL, errResult := ExtractRepositoryURL(request)
+		repositoryURL, errResult := ExtractRepositoryURL(request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		if request.GetBool("force_refresh", false) {
+			c.InvalidateRepositoryIndex(repositoryURL)
+		}
+
+		detailed := request.GetBool("detailed", false)
+		def := OutputText
+		if detailed {
+			def = OutputJSON
+		}
+		format, errResult := extractOutputFormat(request, def)
 		if errResult != nil {
 			return errResult, nil
 		}
 
-		charts, err := c.ListCharts(repositoryURL)
+		var keywords []string
+		for _, keyword := range strings.Split(request.GetString("keywords", ""), ",") {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				keywords = append(keywords, keyword)
+			}
+		}
+
+		if detailed || len(keywords) > 0 {
+			charts, err := c.ListChartDetails(ctx, repositoryURL)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list charts: %v", err)), nil
+			}
+			if len(keywords) > 0 {
+				charts = helm_client.FilterChartsByKeywords(charts, keywords)
+			}
+			names := make([]string, 0, len(charts))
+			for _, chart := range charts {
+				names = append(names, chart.Name)
+			}
+			if !detailed {
+				return formatListOutput(format, names, repositoryChartsResult{Charts: names}, func() string { return strings.Join(names, ", ") }), nil
+			}
+			return formatListOutput(format, charts, repositoryChartsResult{Charts: names, Details: charts}, nil), nil
+		}
+
+		charts, err := c.ListCharts(ctx, repositoryURL)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to list charts: %v", err)), nil
 		}
 
-		return mcp.NewToolResultText(strings.Join(charts, ", ")), nil
+		if charts == nil {
+			charts = []string{}
+		}
+		return formatListOutput(format, charts, repositoryChartsResult{Charts: charts}, func() string { return strings.Join(charts, ", ") }), nil
 	}
 }
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"sigs.k8s.io/yaml"
+)
+
+// OutputFormat selects how a tool formats its result.
+type OutputFormat string
+
+const (
+	// OutputJSON returns the result as indented JSON.
+	OutputJSON OutputFormat = "json"
+	// OutputYAML returns the result as YAML.
+	OutputYAML OutputFormat = "yaml"
+	// OutputText returns the result as plain text for reading, e.g. a comma
+	// separated list. Results without a plain text form are returned as YAML.
+	OutputText OutputFormat = "text"
+)
+
+// ParseOutputFormat validates an output format; an empty value selects def.
+func ParseOutputFormat(s string, def OutputFormat) (OutputFormat, error) {
+	switch f := OutputFormat(s); f {
+	case "":
+		return def, nil
+	case OutputJSON, OutputYAML, OutputText:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, expected one of %q, %q or %q", s, OutputJSON, OutputYAML, OutputText)
+	}
+}
+
+// outputFormatParam is the output_format parameter of tools returning
+// results. def is the format of the tool when the parameter is not set.
+func outputFormatParam(def OutputFormat) mcp.ToolOption {
+	return mcp.WithString("output_format",
+		mcp.Description(fmt.Sprintf("Format of the result: json, yaml or text (plain text for reading, YAML if the result has no plain text form). Defaults to %s", def)),
+		mcp.Enum(string(OutputJSON), string(OutputYAML), string(OutputText)),
+	)
+}
+
+// extractOutputFormat reads the output_format parameter. Tools read it before
+// doing any work, so an invalid format does not waste or, for write tools,
+// repeat it.
+func extractOutputFormat(request mcp.CallToolRequest, def OutputFormat) (OutputFormat, *mcp.CallToolResult) {
+	format, err := ParseOutputFormat(request.GetString("output_format", ""), def)
+	if err != nil {
+		return "", mcp.NewToolResultError(err.Error())
+	}
+	return format, nil
+}
+
+// formatOutput returns v in format. text returns the plain text form of v for
+// OutputText; if nil, OutputText returns YAML. Results that are JSON objects
+// are also returned as structured content, whatever the format, for clients
+// consuming the output schema of the tool.
+func formatOutput(format OutputFormat, v any, text func() string) *mcp.CallToolResult {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal result: %v", err))
+	}
+
+	var result *mcp.CallToolResult
+	switch {
+	case format == OutputText && text != nil:
+		result = mcp.NewToolResultText(text())
+	case format == OutputJSON:
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, encoded, "", "  "); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal result: %v", err))
+		}
+		result = mcp.NewToolResultText(indented.String())
+	default:
+		converted, err := yaml.JSONToYAML(encoded)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal result: %v", err))
+		}
+		result = mcp.NewToolResultText(string(converted))
+	}
+
+	if bytes.HasPrefix(encoded, []byte("{")) {
+		result.StructuredContent = v
+	}
+	return result
+}
+
+// formatListOutput is formatOutput for results that are lists. Structured
+// content must be an object, so structured, the list wrapped in the type of
+// the output schema of the tool, is returned as structured content instead.
+func formatListOutput(format OutputFormat, list, structured any, text func() string) *mcp.CallToolResult {
+	result := formatOutput(format, list, text)
+	if !result.IsError {
+		result.StructuredContent = structured
+	}
+	return result
+}
+
+// joinOrNone joins values for text output, "none" if there are none.
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
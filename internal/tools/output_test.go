@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		in        string
+		def       OutputFormat
+		want      OutputFormat
+		wantError bool
+	}{
+		{in: "", def: OutputJSON, want: OutputJSON},
+		{in: "", def: OutputText, want: OutputText},
+		{in: "yaml", def: OutputJSON, want: OutputYAML},
+		{in: "text", def: OutputJSON, want: OutputText},
+		{in: "json", def: OutputYAML, want: OutputJSON},
+		{in: "xml", def: OutputJSON, wantError: true},
+		{in: "JSON", def: OutputJSON, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseOutputFormat(tt.in, tt.def)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOutputFormat(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatOutput(t *testing.T) {
+	v := struct {
+		Name     string   `json:"name"`
+		Versions []string `json:"versions"`
+	}{Name: "nginx", Versions: []string{"1.0.0", "1.1.0"}}
+	text := func() string { return "nginx: 1.0.0, 1.1.0" }
+
+	tests := []struct {
+		name   string
+		format OutputFormat
+		text   func() string
+		want   string
+	}{
+		{name: "json", format: OutputJSON, text: text, want: "{\n  \"name\": \"nginx\",\n  \"versions\": [\n    \"1.0.0\",\n    \"1.1.0\"\n  ]\n}"},
+		{name: "yaml", format: OutputYAML, text: text, want: "name: nginx\nversions:\n- 1.0.0\n- 1.1.0\n"},
+		{name: "text", format: OutputText, text: text, want: "nginx: 1.0.0, 1.1.0"},
+		{name: "text without plain form", format: OutputText, want: "name: nginx\nversions:\n- 1.0.0\n- 1.1.0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatOutput(tt.format, v, tt.text)
+			if result.IsError {
+				t.Fatalf("unexpected error result: %v", result.Content)
+			}
+			if got := result.Content[0].(mcp.TextContent).Text; got != tt.want {
+				t.Errorf("formatOutput = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatOutputStructuredContent(t *testing.T) {
+	object := map[string]any{"version": "1.0.0"}
+	result := formatOutput(OutputYAML, object, nil)
+	if !reflect.DeepEqual(result.StructuredContent, object) {
+		t.Errorf("StructuredContent = %v, want %v", result.StructuredContent, object)
+	}
+
+	list := []string{"1.0.0", "1.1.0"}
+	if result := formatOutput(OutputJSON, list, nil); result.StructuredContent != nil {
+		t.Errorf("expected no structured content for a list, got %v", result.StructuredContent)
+	}
+
+	structured := chartVersionsResult{Versions: list}
+	result = formatListOutput(OutputText, list, structured, func() string { return "1.0.0, 1.1.0" })
+	if !reflect.DeepEqual(result.StructuredContent, structured) {
+		t.Errorf("StructuredContent = %v, want %v", result.StructuredContent, structured)
+	}
+	if got := result.Content[0].(mcp.TextContent).Text; got != "1.0.0, 1.1.0" {
+		t.Errorf("text = %q, want %q", got, "1.0.0, 1.1.0")
+	}
+}
+
+func TestDependencyTreeSchema(t *testing.T) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(dependencyTreeSchema, &schema); err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		t.Fatalf("failed to resolve schema: %v", err)
+	}
+
+	tree := dependencyTreeResult{Dependencies: []helm_parser.DependencyNode{{
+		Name:            "redis",
+		Version:         "^18.0.0",
+		Repository:      "https://charts.bitnami.com/bitnami",
+		Bundled:         true,
+		ResolvedVersion: "18.1.0",
+		Children:        []helm_parser.DependencyNode{{Name: "common", Version: "2.x.x", Tags: []string{"bitnami"}, Bundled: true}},
+	}}}
+	encoded, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("failed to marshal tree: %v", err)
+	}
+	var instance any
+	if err := json.Unmarshal(encoded, &instance); err != nil {
+		t.Fatalf("failed to unmarshal tree: %v", err)
+	}
+	if err := resolved.Validate(instance); err != nil {
+		t.Errorf("tree does not match the schema: %v", err)
+	}
+
+	if err := resolved.Validate(map[string]any{"dependencies": []any{map[string]any{"name": "redis"}}}); err == nil {
+		t.Error("expected a dependency without version to be rejected")
+	}
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewPackageChartTool() mcp.Tool {
+	return mcp.NewTool("package_chart",
+		mcp.WithDescription("Packages a local chart directory into a chart archive, like helm package, and returns the path and sha256 digest of the archive. The archive is saved as <chart>-<version>.tgz below the pull directory configured on the server."),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Package chart",
+			ReadOnlyHint:    mcp.ToBoolPtr(false),
+			DestructiveHint: mcp.ToBoolPtr(true),
+			IdempotentHint:  mcp.ToBoolPtr(true),
+			OpenWorldHint:   mcp.ToBoolPtr(true),
+		}),
+		mcp.WithString("chart_path",
+			mcp.Required(),
+			mcp.Description("Local chart directory on the server to package, as a path or file:// URL (e.g., file:///workspace/charts/app)"),
+		),
+		mcp.WithString("destination",
+			mcp.Description("Directory to save the archive to, relative to the pull directory of the server or an absolute path below it. Created if missing. Defaults to the pull directory"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Overrides the chart version of Chart.yaml, like helm package --version. Must be a valid semantic version"),
+		),
+		mcp.WithString("app_version",
+			mcp.Description("Overrides the appVersion of Chart.yaml, like helm package --app-version"),
+		),
+		mcp.WithBoolean("dependency_update",
+			mcp.Description("If true, downloads the dependencies before packaging, like helm package --dependency-update, into a temporary copy of the chart, leaving the chart directory untouched. The dependency repositories must be allowed. Defaults to false"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("If true, replaces an existing archive. Defaults to false"),
+		),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_client.ChartPackage](),
+	)
+}
+
+// GetPackageChartHandler returns the handler of package_chart, writing
+// archives only below root.
+func GetPackageChartHandler(c *helm_client.HelmClient, root string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		chartPath, err := request.RequireString("chart_path")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		dir, err := resolvePullDestination(root, request.GetString("destination", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		pkg, err := c.PackageChart(ctx, chartPath, dir, request.GetString("version", ""), request.GetString("app_version", ""), request.GetBool("dependency_update", false), request.GetBool("overwrite", false))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to package chart: %v", err)), nil
+		}
+
+		return formatOutput(format, pkg, func() string {
+			return fmt.Sprintf("Packaged chart %s version %s to %s (digest %s)", pkg.Chart, pkg.Version, pkg.Path, pkg.Digest)
+		}), nil
+	}
+}
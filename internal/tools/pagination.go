@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultMaxBytes is the page size used for large text results unless the
+// request sets max_bytes, chosen to fit comfortably into an LLM context.
+const defaultMaxBytes = 100000
+
+// Page is a window of a larger text result.
+type Page struct {
+	Content string
+	// Offset is the byte offset of Content in the full text.
+	Offset int
+	// Total is the size of the full text in bytes.
+	Total int
+	// NextOffset is the offset to request the next page from, or -1 if Content
+	// reaches the end of the text.
+	NextOffset int
+}
+
+// Paginate returns the part of text starting at offset that fits into maxBytes.
+// Pages end at a line boundary where possible and never split a UTF-8
+// character. A non-positive maxBytes returns the rest of the text.
+func Paginate(text string, offset, maxBytes int) (*Page, error) {
+	if offset < 0 || offset > len(text) {
+		return nil, fmt.Errorf("offset %d is out of range, the content is %d bytes long", offset, len(text))
+	}
+
+	end := len(text)
+	if maxBytes > 0 && offset+maxBytes < end {
+		end = offset + maxBytes
+		if i := strings.LastIndexByte(text[offset:end], '\n'); i >= 0 {
+			end = offset + i + 1
+		} else {
+			// A single line longer than the page: cut it, but not inside a
+			// multi-byte character.
+			for end > offset+1 && !utf8.RuneStart(text[end]) {
+				end--
+			}
+		}
+	}
+
+	next := end
+	if end == len(text) {
+		next = -1
+	}
+	return &Page{Content: text[offset:end], Offset: offset, Total: len(text), NextOffset: next}, nil
+}
+
+// ExtractPagination reads the offset and max_bytes parameters from the request.
+func ExtractPagination(request mcp.CallToolRequest) (offset, maxBytes int) {
+	return request.GetInt("offset", 0), request.GetInt("max_bytes", defaultMaxBytes)
+}
+
+// WithPaginationNote appends continuation metadata to result if page does not
+// reach the end of the text, so clients know how to fetch the rest.
+func WithPaginationNote(result *mcp.CallToolResult, page *Page) *mcp.CallToolResult {
+	if page.NextOffset < 0 {
+		return result
+	}
+	result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf(
+		"Output truncated: returned bytes %d-%d of %d. Call again with offset=%d to continue.",
+		page.Offset, page.NextOffset, page.Total, page.NextOffset,
+	)))
+	return result
+}
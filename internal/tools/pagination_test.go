@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPaginate(t *testing.T) {
+	text := "line one\nline two\nline three\n"
+
+	tests := []struct {
+		name      string
+		text      string
+		offset    int
+		maxBytes  int
+		want      string
+		wantNext  int
+		wantError bool
+	}{
+		{name: "everything fits", text: text, maxBytes: 1000, want: text, wantNext: -1},
+		{name: "no limit", text: text, maxBytes: 0, want: text, wantNext: -1},
+		{name: "cut at line boundary", text: text, maxBytes: 20, want: "line one\nline two\n", wantNext: 18},
+		{name: "continue from offset", text: text, offset: 18, maxBytes: 20, want: "line three\n", wantNext: -1},
+		{name: "long line is cut", text: "abcdefgh", maxBytes: 3, want: "abc", wantNext: 3},
+		{name: "multi-byte character is not split", text: "aé", maxBytes: 2, want: "a", wantNext: 1},
+		{name: "offset at end", text: text, offset: len(text), maxBytes: 10, want: "", wantNext: -1},
+		{name: "offset out of range", text: text, offset: len(text) + 1, wantError: true},
+		{name: "negative offset", text: text, offset: -1, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := Paginate(tt.text, tt.offset, tt.maxBytes)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if page.Content != tt.want {
+				t.Errorf("Content = %q, want %q", page.Content, tt.want)
+			}
+			if page.NextOffset != tt.wantNext {
+				t.Errorf("NextOffset = %d, want %d", page.NextOffset, tt.wantNext)
+			}
+			if page.Total != len(tt.text) {
+				t.Errorf("Total = %d, want %d", page.Total, len(tt.text))
+			}
+		})
+	}
+}
+
+func TestWithPaginationNote(t *testing.T) {
+	page, err := Paginate("line one\nline two\n", 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := WithPaginationNote(mcp.NewToolResultText(page.Content), page)
+	if len(result.Content) != 2 {
+		t.Fatalf("expected 2 content items, got %d", len(result.Content))
+	}
+	note, ok := result.Content[1].(mcp.TextContent)
+	if !ok || !strings.Contains(note.Text, "offset=9") {
+		t.Errorf("expected continuation note with offset=9, got %v", result.Content[1])
+	}
+
+	last, err := Paginate("line one\n", 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := WithPaginationNote(mcp.NewToolResultText(last.Content), last); len(result.Content) != 1 {
+		t.Errorf("expected no continuation note for the last page, got %d content items", len(result.Content))
+	}
+}
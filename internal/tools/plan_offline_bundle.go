@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewPlanOfflineBundleTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Plans an offline bundle for an air-gapped install of a Helm chart: the chart archive URL and digest, every image of the chart with the digest it currently resolves to, and a shell script mirroring the chart and images to a registry with curl, helm and crane copy. The registries are only read; nothing is copied."),
+		readOnlyAnnotation("Plan offline bundle"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("target_registry",
+			mcp.Description("Registry host with an optional path to mirror to (e.g., registry.internal/mirror). Images keep their repository and tag below it and the chart is pushed to oci://<target_registry>/<chart>. If omitted, the script requires $REGISTRY to be set"),
+		),
+		mcp.WithBoolean("recursive",
+			mcp.Description("If true, includes images of subcharts as well. Defaults to true"),
+		),
+		mcp.WithString("custom_values",
+			mcp.Description("JSON object of custom values to override chart defaults (e.g., {\"image\": {\"tag\": \"v2\"}})"),
+		),
+		setParam,
+		valuesURLParam,
+		mcp.WithString("source",
+			mcp.Description("Where to look for images: rendered (default, images in the manifests rendered with the values), values (image references in the values, including those of optional features that are disabled by default) or all"),
+			mcp.Enum(string(helm_parser.ImagesRendered), string(helm_parser.ImagesValues), string(helm_parser.ImagesAll)),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[helm_client.BundlePlan](),
+	}
+	return mcp.NewTool("plan_offline_bundle", append(opts, renderOptionsParams...)...)
+}
+
+func GetPlanOfflineBundleHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		source, err := helm_parser.ParseImageSource(request.GetString("source", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		customValues, errResult := extractValues(ctx, c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		renderOpts, errResult := extractRenderOptions(request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		plan, err := c.PlanOfflineBundle(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, customValues, request.GetBool("recursive", true), source, request.GetString("target_registry", ""), renderOpts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to plan offline bundle: %v", err)), nil
+		}
+
+		return formatOutput(format, plan, func() string { return plan.Script }), nil
+	}
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewPreviewReleaseUpgradeTool() mcp.Tool {
+	return mcp.NewTool("preview_release_upgrade",
+		mcp.WithDescription("Previews the upgrade of an installed Helm release to another chart version without changing the cluster. Renders the target chart with the release's current values and returns a per-resource unified diff against the deployed manifest, like the helm-diff plugin."),
+		readOnlyAnnotation("Preview release upgrade"),
+		mcp.WithString("release_name",
+			mcp.Required(),
+			mcp.Description("Name of the release, as returned by list_releases"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the release. Defaults to the namespace of the kubeconfig context"),
+		),
+		contextParam,
+		clusterParam,
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias of the target chart. Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Target chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("custom_values",
+			mcp.Description("JSON object of values to set in addition to the release's current values (e.g., {\"replicaCount\": 3})"),
+		),
+		setParam,
+		valuesURLParam,
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[cluster_client.UpgradePreview](),
+	)
+}
+
+func GetPreviewReleaseUpgradeHandler(c *helm_client.HelmClient, cc *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		cc, errResult := clusterClientFor(cc, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		name, err := request.RequireString("release_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		customValues, errResult := extractValues(ctx, c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		target, err := c.LoadChart(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		preview, err := cc.PreviewUpgrade(ctx, request.GetString("namespace", ""), name, target, customValues)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to preview upgrade: %v", err)), nil
+		}
+
+		return formatOutput(format, preview, nil), nil
+	}
+}
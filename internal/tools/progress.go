@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+// ProgressContext returns a context that sends MCP progress notifications for
+// the client calls made with it, if the request asked for them by setting a
+// progress token. Otherwise ctx is returned unchanged.
+func ProgressContext(ctx context.Context, request mcp.CallToolRequest) context.Context {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return ctx
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return ctx
+	}
+
+	token := request.Params.Meta.ProgressToken
+	return helm_client.WithProgress(ctx, newProgressNotifier(func(progress float64, message string) {
+		_ = srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      progress,
+			"message":       message,
+		})
+	}))
+}
+
+// newProgressNotifier converts client progress into notifications. The total
+// amount of work is not known upfront, so progress counts stages: the n-th
+// stage starts at n, and a download within it advances towards n+1. Progress
+// therefore always increases, as required by the MCP specification.
+func newProgressNotifier(send func(progress float64, message string)) helm_client.ProgressFunc {
+	var (
+		mu    sync.Mutex
+		stage string
+		step  float64
+		last  float64
+	)
+	return func(s string, done, total int64) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if s != stage {
+			stage = s
+			step++
+		}
+		progress, message := step, s
+		switch {
+		case total > 0:
+			fraction := min(float64(done)/float64(total), 1)
+			progress += fraction * 0.99
+			message = fmt.Sprintf("%s: %d%%", s, int(fraction*100))
+		case done > 0:
+			message = fmt.Sprintf("%s: %.1f MiB", s, float64(done)/(1<<20))
+		}
+		if progress <= last {
+			if done == 0 || total > 0 {
+				return
+			}
+			// Downloads of unknown size advance by a small fixed amount.
+			progress = last + (step+0.99-last)/10
+		}
+		last = progress
+		send(progress, message)
+	}
+}
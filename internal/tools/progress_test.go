@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"testing"
+)
+
+func TestProgressNotifier(t *testing.T) {
+	type notification struct {
+		progress float64
+		message  string
+	}
+	var sent []notification
+	notify := newProgressNotifier(func(progress float64, message string) {
+		sent = append(sent, notification{progress, message})
+	})
+
+	notify("Downloading index.yaml", 0, 100)
+	notify("Downloading index.yaml", 50, 100)
+	notify("Downloading index.yaml", 100, 100)
+	notify("Downloading nginx-1.0.0.tgz", 0, 0)
+	notify("Downloading nginx-1.0.0.tgz", 1<<20, 0)
+	notify("Downloading nginx-1.0.0.tgz", 2<<20, 0)
+	notify("Loading chart", 0, 0)
+
+	wantMessages := []string{
+		"Downloading index.yaml: 0%",
+		"Downloading index.yaml: 50%",
+		"Downloading index.yaml: 100%",
+		"Downloading nginx-1.0.0.tgz",
+		"Downloading nginx-1.0.0.tgz: 1.0 MiB",
+		"Downloading nginx-1.0.0.tgz: 2.0 MiB",
+		"Loading chart",
+	}
+	if len(sent) != len(wantMessages) {
+		t.Fatalf("expected %d notifications, got %d: %v", len(wantMessages), len(sent), sent)
+	}
+	for i, n := range sent {
+		if n.message != wantMessages[i] {
+			t.Errorf("notification %d message = %q, want %q", i, n.message, wantMessages[i])
+		}
+		if i > 0 && n.progress <= sent[i-1].progress {
+			t.Errorf("progress must increase, got %v after %v", n.progress, sent[i-1].progress)
+		}
+	}
+	if sent[3].progress != 2 || sent[6].progress != 3 {
+		t.Errorf("expected stages to start at whole numbers, got %v", sent)
+	}
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewPullChartTool() mcp.Tool {
+	return mcp.NewTool("pull_chart",
+		mcp.WithDescription("Downloads a chart version to the filesystem of the server, like helm pull, to vendor it into a workspace for editing: saves the chart archive as <chart>-<version>.tgz, or unpacks it to a <chart> directory with untar. Files can only be written below the pull directory configured on the server. Existing files are never replaced unless overwrite is set, which replaces an existing archive or deletes and recreates an existing chart directory."),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Pull chart",
+			ReadOnlyHint:    mcp.ToBoolPtr(false),
+			DestructiveHint: mcp.ToBoolPtr(false),
+			IdempotentHint:  mcp.ToBoolPtr(true),
+			OpenWorldHint:   mcp.ToBoolPtr(true),
+		}),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com) and OCI registries (e.g., oci://ghcr.io/org/charts/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("destination",
+			mcp.Description("Directory to save the chart to, relative to the pull directory of the server or an absolute path below it. Created if missing. Defaults to the pull directory"),
+		),
+		mcp.WithBoolean("untar",
+			mcp.Description("If true, unpacks the chart to <destination>/<chart> instead of saving the archive. Defaults to false"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("If true, replaces an existing archive or chart directory. Defaults to false"),
+		),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[pullChartResult](),
+	)
+}
+
+type pullChartResult struct {
+	Chart   string `json:"chart"`
+	Version string `json:"version"`
+	// Path is the archive or the directory of the unpacked chart.
+	Path string `json:"path"`
+}
+
+// GetPullChartHandler returns the handler of pull_chart, writing charts only
+// below root.
+func GetPullChartHandler(c *helm_client.HelmClient, root string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		dir, err := resolvePullDestination(root, request.GetString("destination", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		path, err := c.PullChart(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, dir, request.GetBool("untar", false), request.GetBool("overwrite", false))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to pull chart: %v", err)), nil
+		}
+
+		result := pullChartResult{Chart: params.ChartName, Version: params.ChartVersion, Path: path}
+		return formatOutput(format, result, func() string {
+			return fmt.Sprintf("Saved chart %s version %s to %s", params.ChartName, params.ChartVersion, path)
+		}), nil
+	}
+}
+
+// resolvePullDestination returns the directory destination refers to, which
+// must be root or below it. Symbolic links are resolved, so a link inside
+// root cannot point pull_chart outside of it.
+func resolvePullDestination(root, destination string) (string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	dir := destination
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	dir = filepath.Clean(dir)
+	if !isBelow(root, dir) {
+		return "", fmt.Errorf("destination %q is outside of the pull directory %s", destination, root)
+	}
+
+	// Resolve the links of the longest existing part of the path; the rest
+	// is created by the pull.
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("pull directory %s is not accessible: %v", root, err)
+	}
+	existing, missing := dir, ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			if !isBelow(realRoot, resolved) {
+				return "", fmt.Errorf("destination %q is outside of the pull directory %s", destination, root)
+			}
+			return filepath.Join(resolved, missing), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		missing = filepath.Join(filepath.Base(existing), missing)
+		existing = filepath.Dir(existing)
+	}
+}
+
+// isBelow reports whether path is dir or inside it. Both must be clean.
+func isBelow(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolvePullDestination(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "charts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		destination string
+		want        string
+	}{
+		{"", realRoot},
+		{"charts", filepath.Join(realRoot, "charts")},
+		{"charts/new/nested", filepath.Join(realRoot, "charts", "new", "nested")},
+		{filepath.Join(root, "charts"), filepath.Join(realRoot, "charts")},
+		{"charts/../vendor", filepath.Join(realRoot, "vendor")},
+	} {
+		got, err := resolvePullDestination(root, tc.destination)
+		if err != nil || got != tc.want {
+			t.Errorf("resolvePullDestination(%q) = %q, %v; want %q", tc.destination, got, err, tc.want)
+		}
+	}
+
+	for _, destination := range []string{"..", "../other", outside, "escape", "escape/charts"} {
+		if got, err := resolvePullDestination(root, destination); err == nil {
+			t.Errorf("resolvePullDestination(%q) = %q, expected an error", destination, got)
+		}
+	}
+}
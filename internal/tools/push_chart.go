@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewPushChartTool() mcp.Tool {
+	return mcp.NewTool("push_chart",
+		mcp.WithDescription("Packages a local chart directory and pushes it to an OCI registry, like helm package followed by helm push, to publish internal charts. The chart is pushed as <registry_url>/<chart>:<version>, replacing an existing tag, using the registry credentials configured on the server."),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Push chart",
+			ReadOnlyHint:    mcp.ToBoolPtr(false),
+			DestructiveHint: mcp.ToBoolPtr(true),
+			IdempotentHint:  mcp.ToBoolPtr(true),
+			OpenWorldHint:   mcp.ToBoolPtr(true),
+		}),
+		mcp.WithString("chart_path",
+			mcp.Required(),
+			mcp.Description("Local chart directory on the server to package, as a path or file:// URL (e.g., file:///workspace/charts/app)"),
+		),
+		mcp.WithString("registry_url",
+			mcp.Required(),
+			mcp.Description("OCI registry URL or alias to push to, without the chart name (e.g., oci://registry.internal/charts)"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[helm_client.ChartPush](),
+	)
+}
+
+func GetPushChartHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		chartPath, err := request.RequireString("chart_path")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		registryURL, err := request.RequireString("registry_url")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		push, err := c.PushChart(ctx, chartPath, c.ResolveRepositoryURL(strings.TrimSpace(registryURL)))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to push chart: %v", err)), nil
+		}
+
+		return formatOutput(format, push, func() string {
+			return fmt.Sprintf("Pushed chart %s version %s to %s (digest %s)", push.Chart, push.Version, push.Reference, push.Digest)
+		}), nil
+	}
+}
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewRelocateChartImagesTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Computes the values overrides that make a Helm chart pull its images from a private registry: global.imageRegistry if the chart supports it and the registry or repository keys of the images in the values. Returns the overrides as values and as --set paths, the source to destination mapping of the images to copy, and the images still pulled from their original registry with the overrides, e.g. images hardcoded in templates."),
+		readOnlyAnnotation("Relocate chart images"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("target_registry",
+			mcp.Required(),
+			mcp.Description("Registry host with an optional path to pull the images from (e.g., registry.internal/mirror). Images keep their repository and tag below it"),
+		),
+		mcp.WithBoolean("recursive",
+			mcp.Description("If true, relocates images of subcharts as well. Defaults to false"),
+		),
+		mcp.WithString("custom_values",
+			mcp.Description("JSON object of custom values to override chart defaults (e.g., {\"image\": {\"tag\": \"v2\"}})"),
+		),
+		setParam,
+		valuesURLParam,
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[helm_parser.ImageRelocation](),
+	}
+	return mcp.NewTool("relocate_chart_images", append(opts, renderOptionsParams...)...)
+}
+
+func GetRelocateChartImagesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		prefix, err := request.RequireString("target_registry")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		customValues, errResult := extractValues(ctx, c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		renderOpts, errResult := extractRenderOptions(request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		relocation, err := c.RelocateChartImages(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, customValues, prefix, request.GetBool("recursive", false), renderOpts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to relocate images: %v", err)), nil
+		}
+
+		return formatOutput(format, relocation, func() string {
+			var sb strings.Builder
+			values, err := yaml.Marshal(relocation.Values)
+			if err != nil {
+				values = []byte(fmt.Sprintf("# failed to marshal values: %v\n", err))
+			}
+			sb.WriteString("Values:\n")
+			sb.Write(values)
+			sb.WriteString("\nImages:\n")
+			for _, m := range relocation.Mappings {
+				fmt.Fprintf(&sb, "%s -> %s\n", m.Source, m.Destination)
+			}
+			if len(relocation.Unrelocated) > 0 {
+				sb.WriteString("\nNot relocated by the values:\n")
+				for _, image := range relocation.Unrelocated {
+					fmt.Fprintf(&sb, "%s (%s)\n", image.FullImage, image.Source)
+				}
+			}
+			return sb.String()
+		}), nil
+	}
+}
@@ -0,0 +1,241 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// resultTTL is the time a truncated result is kept for
+	// get_result_continuation after it was last read.
+	resultTTL = 10 * time.Minute
+	// maxStoredResults is the number of truncated results kept at once. The
+	// least recently read result is dropped first.
+	maxStoredResults = 64
+)
+
+type storedResult struct {
+	text     string
+	lastRead time.Time
+}
+
+// ResultLimiter truncates tool results larger than a maximum size and keeps
+// their full text, so clients can fetch the rest with get_result_continuation
+// instead of receiving a single oversized response.
+type ResultLimiter struct {
+	maxBytes int
+
+	mu      sync.Mutex
+	results map[string]*storedResult
+}
+
+// NewResultLimiter returns a ResultLimiter truncating results to maxBytes.
+func NewResultLimiter(maxBytes int) *ResultLimiter {
+	return &ResultLimiter{
+		maxBytes: maxBytes,
+		results:  make(map[string]*storedResult),
+	}
+}
+
+// Middleware truncates results whose serialized size is larger than the
+// maximum size. The text is split at a line boundary where possible, so that
+// the first page with a continuation token for get_result_continuation fits
+// into the maximum size. The structured content of a truncated result is
+// dropped, as it cannot be split, which is why tools advertise no output
+// schema while results are limited; other content such as resource links is
+// returned with the first page.
+func (l *ResultLimiter) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil || result == nil || request.Params.Name == "get_result_continuation" {
+			return result, err
+		}
+		size, err := resultSize(result)
+		if err != nil || size <= l.maxBytes {
+			return result, nil
+		}
+
+		var texts []string
+		var other []mcp.Content
+		for _, c := range result.Content {
+			if text, ok := c.(mcp.TextContent); ok {
+				texts = append(texts, text.Text)
+			} else {
+				other = append(other, c)
+			}
+		}
+		text := strings.Join(texts, "\n")
+
+		id, err := newResultID()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("result of %d bytes exceeds the limit of %d bytes and could not be stored: %v", size, l.maxBytes, err)), nil
+		}
+		truncated, page, err := l.fitPage(id, text, 0, other)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if page.NextOffset >= 0 {
+			l.store(id, text)
+		}
+		truncated.IsError = result.IsError
+		return truncated, nil
+	}
+}
+
+// WithoutOutputSchema returns tool without its output schema. Results
+// truncated by a ResultLimiter have no structured content, which clients
+// validating results against the schema would reject.
+func WithoutOutputSchema(tool mcp.Tool) mcp.Tool {
+	tool.OutputSchema = mcp.ToolOutputSchema{}
+	tool.RawOutputSchema = nil
+	return tool
+}
+
+// resultSize returns the size of result serialized as JSON.
+func resultSize(result *mcp.CallToolResult) (int, error) {
+	data, err := json.Marshal(result)
+	return len(data), err
+}
+
+// fitPage returns the page of text starting at offset as a result of the
+// stored result id, followed by other, shrinking the page until the
+// serialized result fits into the maximum size.
+func (l *ResultLimiter) fitPage(id, text string, offset int, other []mcp.Content) (*mcp.CallToolResult, *Page, error) {
+	limit := l.maxBytes
+	for {
+		page, err := Paginate(text, offset, limit)
+		if err != nil {
+			return nil, nil, err
+		}
+		result := l.pageResult(id, page)
+		result.Content = append(result.Content, other...)
+		size, err := resultSize(result)
+		if err != nil {
+			return nil, nil, err
+		}
+		// A page of a single byte is returned even if the note and other
+		// content alone exceed the maximum size.
+		if size <= l.maxBytes || limit == 1 {
+			return result, page, nil
+		}
+		limit = max(1, len(page.Content)-(size-l.maxBytes))
+	}
+}
+
+func newResultID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// store keeps text as id. Expired results are dropped, and the least recently
+// read one if the limit of stored results is reached.
+func (l *ResultLimiter) store(id, text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	oldest := ""
+	for k, r := range l.results {
+		if now.Sub(r.lastRead) > resultTTL {
+			delete(l.results, k)
+			continue
+		}
+		if oldest == "" || r.lastRead.Before(l.results[oldest].lastRead) {
+			oldest = k
+		}
+	}
+	if len(l.results) >= maxStoredResults {
+		delete(l.results, oldest)
+	}
+	l.results[id] = &storedResult{text: text, lastRead: now}
+}
+
+func (l *ResultLimiter) load(id string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r, ok := l.results[id]
+	if !ok || time.Since(r.lastRead) > resultTTL {
+		delete(l.results, id)
+		return "", false
+	}
+	r.lastRead = time.Now()
+	return r.text, true
+}
+
+// pageResult returns page of the result stored as id, with the continuation
+// token of the next page if there is one.
+func (l *ResultLimiter) pageResult(id string, page *Page) *mcp.CallToolResult {
+	result := mcp.NewToolResultText(page.Content)
+	if page.NextOffset >= 0 {
+		result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf(
+			"Output truncated: returned bytes %d-%d of %d. Call get_result_continuation with continuation_token=%s to continue.",
+			page.Offset, page.NextOffset, page.Total, continuationToken(id, page.NextOffset),
+		)))
+	}
+	return result
+}
+
+// continuationToken encodes the id of a stored result and the offset of the
+// next page as <id>.<offset>.
+func continuationToken(id string, offset int) string {
+	return id + "." + strconv.Itoa(offset)
+}
+
+func parseContinuationToken(token string) (id string, offset int, err error) {
+	id, rawOffset, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if ok {
+		offset, err = strconv.Atoi(rawOffset)
+	}
+	if !ok || id == "" || err != nil {
+		return "", 0, fmt.Errorf("invalid continuation token %q", token)
+	}
+	return id, offset, nil
+}
+
+func NewGetResultContinuationTool() mcp.Tool {
+	return mcp.NewTool("get_result_continuation",
+		mcp.WithDescription("Returns the next part of a tool result that was truncated because it exceeded the maximum result size of the server. Truncated results end with a note containing the continuation token."),
+		readOnlyAnnotation("Get result continuation"),
+		mcp.WithString("continuation_token",
+			mcp.Required(),
+			mcp.Description("Continuation token from the note of the truncated result or of the previous continuation. Tokens expire 10 minutes after their result was last read"),
+		),
+	)
+}
+
+func GetResultContinuationHandler(l *ResultLimiter) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		token, err := request.RequireString("continuation_token")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		id, offset, err := parseContinuationToken(token)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		text, ok := l.load(id)
+		if !ok {
+			return mcp.NewToolResultError("continuation token is unknown or expired, call the original tool again"), nil
+		}
+		result, _, err := l.fitPage(id, text, offset, nil)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestResultLimiter(t *testing.T) {
+	const maxBytes = 400
+	full := strings.Repeat("0123456789\n", 100)
+	short := strings.Repeat("x", 250)
+	limiter := NewResultLimiter(maxBytes)
+	handler := limiter.Middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		switch request.Params.Name {
+		case "small":
+			return mcp.NewToolResultStructured(map[string]any{"ok": true}, "ok"), nil
+		case "short":
+			return mcp.NewToolResultStructured(map[string]any{"text": short}, short), nil
+		}
+		result := mcp.NewToolResultStructured(map[string]any{"text": full}, full)
+		result.Content = append(result.Content, mcp.NewResourceLink("helm-chart://file?path=values.yaml", "values.yaml", "", "application/yaml"))
+		return result, nil
+	})
+	continuation := GetResultContinuationHandler(limiter)
+	tokenRe := regexp.MustCompile(`continuation_token=(\S+) to continue`)
+
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		var request mcp.CallToolRequest
+		request.Params.Name = name
+		request.Params.Arguments = args
+		var result *mcp.CallToolResult
+		var err error
+		if name == "get_result_continuation" {
+			result, err = continuation(context.Background(), request)
+		} else {
+			result, err = handler(context.Background(), request)
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	small := call("small", nil)
+	if len(small.Content) != 1 || small.StructuredContent == nil {
+		t.Fatalf("expected a small result to be returned as is, got %+v", small)
+	}
+
+	// The text fits, but not together with the structured content.
+	result := call("short", nil)
+	if len(result.Content) != 1 || result.Content[0].(mcp.TextContent).Text != short || result.StructuredContent != nil {
+		t.Fatalf("expected the text without structured content, got %+v", result)
+	}
+
+	result = call("large", nil)
+	if result.StructuredContent != nil {
+		t.Error("expected the structured content of a truncated result to be dropped")
+	}
+	if _, ok := result.Content[len(result.Content)-1].(mcp.ResourceLink); !ok {
+		t.Error("expected the resource link to be kept with the first page")
+	}
+	var got strings.Builder
+	for pages := 1; ; pages++ {
+		if result.IsError {
+			t.Fatalf("unexpected error result: %v", result.Content)
+		}
+		if size, err := resultSize(result); err != nil || size > maxBytes {
+			t.Errorf("page %d serializes to %d bytes, want at most %d", pages, size, maxBytes)
+		}
+		page := result.Content[0].(mcp.TextContent).Text
+		got.WriteString(page)
+		if len(result.Content) == 1 {
+			break
+		}
+		note, ok := result.Content[1].(mcp.TextContent)
+		if !ok {
+			t.Fatalf("expected a continuation note, got %T", result.Content[1])
+		}
+		m := tokenRe.FindStringSubmatch(note.Text)
+		if m == nil {
+			t.Fatalf("no continuation token in %q", note.Text)
+		}
+		if pages > 100 {
+			t.Fatal("too many pages")
+		}
+		result = call("get_result_continuation", map[string]any{"continuation_token": m[1]})
+	}
+	if got.String() != full {
+		t.Errorf("pages add up to %q, want %q", got.String(), full)
+	}
+
+	for _, token := range []string{"", "abc", "abc.x", "0123.5"} {
+		if result := call("get_result_continuation", map[string]any{"continuation_token": token}); !result.IsError {
+			t.Errorf("expected an error for token %q", token)
+		}
+	}
+}
+
+func TestResultLimiterEviction(t *testing.T) {
+	limiter := NewResultLimiter(10)
+	limiter.store("first", "first")
+	limiter.results["first"].lastRead = time.Now().Add(-time.Minute)
+	for i := 0; i < maxStoredResults; i++ {
+		limiter.store(strconv.Itoa(i), "other")
+	}
+	if _, ok := limiter.load("first"); ok {
+		t.Error("expected the least recently read result to be dropped")
+	}
+	if len(limiter.results) != maxStoredResults {
+		t.Errorf("got %d stored results, want %d", len(limiter.results), maxStoredResults)
+	}
+}
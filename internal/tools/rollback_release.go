@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+)
+
+func NewRollbackReleaseTool() mcp.Tool {
+	return mcp.NewTool("rollback_release",
+		mcp.WithDescription("Rolls an installed Helm release back to a previous revision, like 'helm rollback'. Creates a new revision with the chart and values of the target revision. Changes the cluster."),
+		writeAnnotation("Roll back release", true),
+		mcp.WithString("release_name",
+			mcp.Required(),
+			mcp.Description("Name of the release, as returned by list_releases"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the release. Defaults to the namespace of the kubeconfig context"),
+		),
+		contextParam,
+		clusterParam,
+		mcp.WithNumber("revision",
+			mcp.Description("Revision to roll back to. Defaults to the revision before the current one"),
+		),
+		timeoutParam,
+		mcp.WithBoolean("wait",
+			mcp.Description("If true, waits for the resources to become ready. Defaults to false"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[cluster_client.Release](),
+	)
+}
+
+func GetRollbackReleaseHandler(c *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		c, errResult := clusterClientFor(c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		name, err := request.RequireString("release_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		opts, errResult := extractWriteOptions(request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		release, err := c.RollbackRelease(ctx, request.GetString("namespace", ""), name, request.GetInt("revision", 0), opts.Timeout, request.GetBool("wait", false))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to roll back release: %v", err)), nil
+		}
+
+		return formatOutput(format, release, nil), nil
+	}
+}
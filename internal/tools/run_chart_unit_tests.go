@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewRunChartUnitTestsTool() mcp.Tool {
+	return mcp.NewTool("run_chart_unit_tests",
+		mcp.WithDescription("Runs the helm-unittest suites of a chart (tests/*_test.yaml by default), like helm unittest, to run chart CI checks interactively. Templates are rendered offline with the release, capabilities, chart metadata, values files and set values of each suite and test, and every assertion is reported as passed, failed with the reason, or skipped. Supports the equal, matchRegex, contains, isNull, isEmpty, exists, isKind, isAPIVersion, hasDocuments, lengthEqual, isSubset, equalRaw, matchRegexRaw and failedTemplate assertions and their not variants; snapshot assertions are skipped. Suites of local charts are read from the chart directory, those of other charts from the files packaged with the chart."),
+		readOnlyAnnotation("Run chart unit tests"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("pattern",
+			mcp.Description("Glob pattern of the suite files relative to the chart directory, like helm unittest --file. Defaults to tests/*_test.yaml"),
+		),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.UnitTestReport](),
+	)
+}
+
+func GetRunChartUnitTestsHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.RunChartUnitTests(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, request.GetString("pattern", ""))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to run chart unit tests: %v", err)), nil
+		}
+		return formatOutput(format, report, func() string {
+			return formatUnitTestReport(report)
+		}), nil
+	}
+}
+
+// formatUnitTestReport lists the tests of every suite with their status,
+// followed by the reasons of failed assertions, like helm unittest.
+func formatUnitTestReport(report *helm_parser.UnitTestReport) string {
+	var sb strings.Builder
+	for _, suite := range report.Suites {
+		name := suite.File
+		if suite.Name != "" {
+			name = suite.Name + " (" + suite.File + ")"
+		}
+		if suite.Error != "" {
+			fmt.Fprintf(&sb, "FAIL %s\n  %s\n", name, suite.Error)
+			continue
+		}
+		fmt.Fprintf(&sb, "%s\n", name)
+		for _, test := range suite.Tests {
+			fmt.Fprintf(&sb, "  %s %s\n", strings.ToUpper(test.Status), test.Name)
+			if test.Error != "" {
+				fmt.Fprintf(&sb, "    %s\n", test.Error)
+			}
+			for i, a := range test.Assertions {
+				if a.Status != helm_parser.UnitTestPassed {
+					fmt.Fprintf(&sb, "    - asserts[%d] %s %s: %s\n", i, a.Type, a.Status, a.Message)
+				}
+			}
+		}
+	}
+
+	result := "PASSED"
+	if !report.Passed {
+		result = "FAILED"
+	}
+	fmt.Fprintf(&sb, "\n%s: %d suites, %d tests, %d failed, %d skipped\n", result, len(report.Suites), report.Tests, report.Failed, report.Skipped)
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+)
+
+func NewScanDeprecatedAPIsTool() mcp.Tool {
+	return mcp.NewTool("scan_deprecated_apis",
+		mcp.WithDescription("Scans the manifests of the installed Helm releases for resources using Kubernetes APIs that are deprecated or removed in a target Kubernetes version, with the API version to migrate to. Use it to audit releases before upgrading the cluster."),
+		readOnlyAnnotation("Scan releases for deprecated APIs"),
+		mcp.WithString("target_version",
+			mcp.Description("Kubernetes version to check against, e.g. 1.29. Defaults to the version of the cluster"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to scan releases in. If omitted releases from all namespaces are scanned"),
+		),
+		contextParam,
+		clusterParam,
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[cluster_client.DeprecationReport](),
+	)
+}
+
+func GetScanDeprecatedAPIsHandler(c *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		c, errResult := clusterClientFor(c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		report, err := c.ScanDeprecatedAPIs(ctx, request.GetString("namespace", ""), request.GetString("target_version", ""))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to scan releases: %v", err)), nil
+		}
+
+		return formatOutput(format, report, nil), nil
+	}
+}
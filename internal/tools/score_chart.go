@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewScoreChartTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Renders a chart with the given values and scores its workloads against Kubernetes best practices, like kube-score: readiness and liveness probes, CPU and memory requests and limits, image pull policies of mutable tags, and pod anti-affinity and a PodDisruptionBudget for workloads with several replicas. Returns a score from 0 to 100 per workload with recommendations for the failed checks."),
+		readOnlyAnnotation("Score chart best practices"),
+		outputFormatParam(OutputText),
+		mcp.WithOutputSchema[helm_parser.ScoreReport](),
+	}
+	return mcp.NewTool("score_chart", append(opts, chartRenderParams(`{"replicaCount": 3}`)...)...)
+}
+
+func GetScoreChartHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputText)
+		if errResult != nil {
+			return errResult, nil
+		}
+		render, errResult := extractChartRender(ctx, request, c)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		report, err := c.ScoreChart(ctx, render.RepositoryURL, render.ChartName, render.ChartVersion, render.Values, render.Options)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to score chart: %v", err)), nil
+		}
+
+		return formatOutput(format, report, func() string { return formatScoreReport(report) }), nil
+	}
+}
+
+func formatScoreReport(report *helm_parser.ScoreReport) string {
+	if len(report.Workloads) == 0 {
+		return "The chart renders no workloads to score"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Score %d/100 over %d workloads\n", report.Score, len(report.Workloads))
+	for _, w := range report.Workloads {
+		name := w.Kind + "/" + w.Name
+		if w.Namespace != "" {
+			name = w.Kind + "/" + w.Namespace + "/" + w.Name
+		}
+		fmt.Fprintf(&sb, "\n%s: %d/100\n", name, w.Score)
+		for _, check := range w.Checks {
+			if check.Grade == helm_parser.GradeSkipped {
+				continue
+			}
+			fmt.Fprintf(&sb, "  [%s] %s", check.Grade, check.ID)
+			if check.Message != "" && check.Grade != helm_parser.GradeOK {
+				fmt.Fprintf(&sb, ": %s\n    %s", check.Message, check.Recommendation)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+)
+
+func NewUninstallReleaseTool() mcp.Tool {
+	return mcp.NewTool("uninstall_release",
+		mcp.WithDescription("Uninstalls an installed Helm release, like 'helm uninstall', deleting the Kubernetes resources it created. Changes the cluster unless dry_run is set."),
+		writeAnnotation("Uninstall release", true),
+		mcp.WithString("release_name",
+			mcp.Required(),
+			mcp.Description("Name of the release, as returned by list_releases"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the release. Defaults to the namespace of the kubeconfig context"),
+		),
+		contextParam,
+		clusterParam,
+		mcp.WithBoolean("keep_history",
+			mcp.Description("If true, keeps the release history with status uninstalled, so the release can still be inspected and rolled back. Defaults to false"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("If true, only returns the release that would be uninstalled without deleting anything. Defaults to false"),
+		),
+		timeoutParam,
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[cluster_client.UninstallResult](),
+	)
+}
+
+func GetUninstallReleaseHandler(c *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		c, errResult := clusterClientFor(c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		name, err := request.RequireString("release_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		opts, errResult := extractWriteOptions(request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		result, err := c.UninstallRelease(ctx,
+			request.GetString("namespace", ""),
+			name,
+			request.GetBool("keep_history", false),
+			request.GetBool("dry_run", false),
+			opts.Timeout,
+		)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to uninstall release: %v", err)), nil
+		}
+
+		return formatOutput(format, result, nil), nil
+	}
+}
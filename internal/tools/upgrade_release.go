@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/cluster_client"
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+)
+
+func NewUpgradeReleaseTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Upgrades an installed Helm release to another chart version or new values, like 'helm upgrade'. Changes the cluster; consider preview_release_upgrade first."),
+		writeAnnotation("Upgrade release", true),
+		mcp.WithString("release_name",
+			mcp.Required(),
+			mcp.Description("Name of the release, as returned by list_releases"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the release. Defaults to the namespace of the kubeconfig context"),
+		),
+		contextParam,
+		clusterParam,
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias of the target chart. Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Target chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("custom_values",
+			mcp.Description("JSON object of values to set (e.g., {\"replicaCount\": 3})"),
+		),
+		setParam,
+		valuesURLParam,
+		mcp.WithBoolean("reuse_values",
+			mcp.Description("If true, values_url, custom_values and set are merged over the release's current values; if false, they replace them. Defaults to true"),
+		),
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[cluster_client.Release](),
+	}
+	return mcp.NewTool("upgrade_release", append(opts, writeOptionsParams...)...)
+}
+
+func GetUpgradeReleaseHandler(c *helm_client.HelmClient, cc *cluster_client.ClusterClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = ProgressContext(ctx, request)
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		cc, errResult := clusterClientFor(cc, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		name, err := request.RequireString("release_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+		customValues, errResult := extractValues(ctx, c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		opts, errResult := extractWriteOptions(request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		chart, err := c.LoadChart(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		release, err := cc.UpgradeRelease(ctx, request.GetString("namespace", ""), name, chart, customValues, request.GetBool("reuse_values", true), opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to upgrade release: %v", err)), nil
+		}
+
+		return formatOutput(format, release, nil), nil
+	}
+}
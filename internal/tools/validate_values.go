@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/zekker6/mcp-helm/lib/helm_client"
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func NewValidateValuesTool() mcp.Tool {
+	return mcp.NewTool("validate_values",
+		mcp.WithDescription("Validates values overrides for a chart before installing it. Checks the values merged with the chart defaults against the chart's values.schema.json (when present, including subcharts), and compares the overrides with the default values: keys that are neither in the defaults nor referenced by any template (likely typos such as ingress.enable instead of ingress.enabled, with a suggestion of the similar default key) and values whose type differs from the default. Returns whether the values are valid and the issues found with their path, severity, message and suggestion. Supports both HTTP repositories and OCI registries."),
+		readOnlyAnnotation("Validate values"),
+		mcp.WithString("repository_url",
+			mcp.Required(),
+			mcp.Description("Helm repository URL or alias (e.g., bitnami). Supports HTTP repos (e.g., https://charts.example.com), OCI registries (e.g., oci://ghcr.io/org/charts/mychart) and local chart directories (e.g., file:///path/to/mychart)"),
+		),
+		mcp.WithString("chart_name",
+			mcp.Required(),
+			mcp.Description("Chart name. For OCI URLs that already include the chart name, this can be empty."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("Chart version. If omitted the latest version will be used"),
+		),
+		mcp.WithString("custom_values",
+			mcp.Description("JSON object of values to validate (e.g., {\"ingress\": {\"enabled\": true}})"),
+		),
+		setParam,
+		valuesURLParam,
+		outputFormatParam(OutputJSON),
+		mcp.WithOutputSchema[helm_parser.ValuesValidation](),
+	)
+}
+
+func GetValidateValuesHandler(c *helm_client.HelmClient) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, errResult := extractOutputFormat(request, OutputJSON)
+		if errResult != nil {
+			return errResult, nil
+		}
+		params, errResult := ExtractCommonParams(ctx, request, c, true)
+		if errResult != nil {
+			return errResult, nil
+		}
+		customValues, errResult := extractValues(ctx, c, request)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		result, err := c.ValidateValues(ctx, params.RepositoryURL, params.ChartName, params.ChartVersion, customValues)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to validate values: %v", err)), nil
+		}
+		return formatOutput(format, result, nil), nil
+	}
+}
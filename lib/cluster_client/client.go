@@ -0,0 +1,866 @@
+package cluster_client
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v4/pkg/action"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/kube"
+	ri "helm.sh/helm/v4/pkg/release"
+	releasev1 "helm.sh/helm/v4/pkg/release/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	name        string
+	kubeconfig  string
+	kubeContext string
+	inCluster   bool
+	clusters    []NamedCluster
+}
+
+// DefaultClusterName is the name of the cluster a client was created for
+// unless WithName sets another.
+const DefaultClusterName = "default"
+
+// NamedCluster configures an additional cluster served by a client, see
+// WithClusters.
+type NamedCluster struct {
+	Name       string
+	Kubeconfig string
+	// Context defaults to the current context of the kubeconfig.
+	Context string
+}
+
+// WithName names the cluster the client is created for, so it can be
+// selected among the clusters added with WithClusters.
+func WithName(name string) ClientOption {
+	return func(o *clientOptions) {
+		o.name = name
+	}
+}
+
+// WithClusters adds named clusters that can be selected with ForCluster, so a
+// single client can serve a whole fleet.
+func WithClusters(clusters ...NamedCluster) ClientOption {
+	return func(o *clientOptions) {
+		o.clusters = append(o.clusters, clusters...)
+	}
+}
+
+// WithKubeconfig sets the kubeconfig file used to connect to the cluster.
+func WithKubeconfig(path string) ClientOption {
+	return func(o *clientOptions) {
+		o.kubeconfig = path
+	}
+}
+
+// WithKubeContext selects a kubeconfig context other than the current one.
+func WithKubeContext(name string) ClientOption {
+	return func(o *clientOptions) {
+		o.kubeContext = name
+	}
+}
+
+// WithInCluster connects to the cluster the process runs in, using the
+// service account of its pod, instead of a kubeconfig.
+func WithInCluster() ClientOption {
+	return func(o *clientOptions) {
+		o.inCluster = true
+	}
+}
+
+// ClusterClient reads the Helm releases installed in a Kubernetes cluster.
+type ClusterClient struct {
+	settings *cli.EnvSettings
+	options  clientOptions
+
+	// contexts caches the clients for other kubeconfig contexts, see
+	// ForContext.
+	contextsMu sync.Mutex
+	contexts   map[string]*ClusterClient
+	// clusters holds the clients for the clusters added with WithClusters,
+	// in the order they were added.
+	clusters []*ClusterClient
+
+	// newConfig returns the action configuration for namespace, or for all
+	// namespaces if namespace is empty. Tests replace it to use in-memory
+	// release storage.
+	newConfig func(namespace string) (*action.Configuration, error)
+	// kubeClient returns a client for reading Kubernetes resources. Tests
+	// replace it with a fake clientset.
+	kubeClient func() (kubernetes.Interface, error)
+}
+
+// NewClient creates a client for the cluster described by the kubeconfig, or
+// for the cluster it runs in with WithInCluster. Without a kubeconfig the
+// client falls back to the default kubeconfig locations, as the helm CLI
+// does. Releases are read from the storage backend selected by HELM_DRIVER,
+// as with the helm CLI.
+func NewClient(opts ...ClientOption) (*ClusterClient, error) {
+	options := clientOptions{name: DefaultClusterName}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	c, err := newClient(options)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{c.options.name: true}
+	for _, nc := range options.clusters {
+		if nc.Name == "" {
+			return nil, fmt.Errorf("cluster name must not be empty")
+		}
+		if names[nc.Name] {
+			return nil, fmt.Errorf("duplicate cluster name %q", nc.Name)
+		}
+		names[nc.Name] = true
+
+		client, err := newClient(clientOptions{name: nc.Name, kubeconfig: nc.Kubeconfig, kubeContext: nc.Context})
+		if err != nil {
+			return nil, fmt.Errorf("cluster %q: %v", nc.Name, err)
+		}
+		c.clusters = append(c.clusters, client)
+	}
+	return c, nil
+}
+
+func newClient(options clientOptions) (*ClusterClient, error) {
+	if options.inCluster {
+		if options.kubeconfig != "" || options.kubeContext != "" {
+			return nil, fmt.Errorf("in-cluster configuration cannot be combined with a kubeconfig or context")
+		}
+		// Without a kubeconfig, client-go uses the in-cluster configuration.
+		if _, err := rest.InClusterConfig(); err != nil {
+			return nil, fmt.Errorf("failed to load in-cluster configuration: %v", err)
+		}
+	}
+
+	settings := cli.New()
+	if options.kubeconfig != "" {
+		if _, err := os.Stat(options.kubeconfig); err != nil {
+			return nil, fmt.Errorf("failed to read kubeconfig: %v", err)
+		}
+		settings.KubeConfig = options.kubeconfig
+	}
+	if options.kubeContext != "" {
+		settings.KubeContext = options.kubeContext
+	}
+
+	c := &ClusterClient{settings: settings, options: options}
+	c.newConfig = func(namespace string) (*action.Configuration, error) {
+		cfg := action.NewConfiguration()
+		if err := cfg.Init(settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER")); err != nil {
+			return nil, fmt.Errorf("failed to initialize Helm configuration: %v", err)
+		}
+		return cfg, nil
+	}
+	c.kubeClient = func() (kubernetes.Interface, error) {
+		restConfig, err := settings.RESTClientGetter().ToRESTConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+		}
+		return kubernetes.NewForConfig(restConfig)
+	}
+	return c, nil
+}
+
+// ForCluster returns the client for a cluster added with WithClusters. An
+// empty name or the name of c returns c.
+func (c *ClusterClient) ForCluster(name string) (*ClusterClient, error) {
+	if name == "" || name == c.options.name {
+		return c, nil
+	}
+	for _, client := range c.clusters {
+		if client.options.name == name {
+			return client, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown cluster %q, use list_clusters to list the available clusters", name)
+}
+
+// ClusterInfo describes a cluster served by a client.
+type ClusterInfo struct {
+	Name       string `json:"name"`
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	Context    string `json:"context,omitempty"`
+	InCluster  bool   `json:"inCluster,omitempty"`
+	// Default is set for the cluster used when a call does not select one.
+	Default bool `json:"default"`
+}
+
+// Clusters returns the cluster of c, which is the default, followed by the
+// clusters added with WithClusters.
+func (c *ClusterClient) Clusters() []ClusterInfo {
+	info := func(client *ClusterClient) ClusterInfo {
+		return ClusterInfo{
+			Name:       client.options.name,
+			Kubeconfig: client.options.kubeconfig,
+			Context:    client.options.kubeContext,
+			InCluster:  client.options.inCluster,
+		}
+	}
+
+	clusters := []ClusterInfo{info(c)}
+	clusters[0].Default = true
+	for _, client := range c.clusters {
+		clusters = append(clusters, info(client))
+	}
+	return clusters
+}
+
+// ForContext returns a client for another context of the kubeconfig, so a
+// single server can read several clusters. An empty name returns c. Clients
+// are created once per context and reused.
+func (c *ClusterClient) ForContext(name string) (*ClusterClient, error) {
+	if name == "" || name == c.settings.KubeContext {
+		return c, nil
+	}
+	if c.options.inCluster {
+		return nil, fmt.Errorf("kubeconfig contexts are not available with in-cluster configuration")
+	}
+
+	c.contextsMu.Lock()
+	defer c.contextsMu.Unlock()
+	if client, ok := c.contexts[name]; ok {
+		return client, nil
+	}
+
+	raw, err := c.settings.RESTClientGetter().ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+	if _, ok := raw.Contexts[name]; !ok {
+		return nil, fmt.Errorf("context %q not found in kubeconfig", name)
+	}
+
+	options := c.options
+	options.kubeContext = name
+	options.clusters = nil
+	client, err := newClient(options)
+	if err != nil {
+		return nil, err
+	}
+	if c.contexts == nil {
+		c.contexts = make(map[string]*ClusterClient)
+	}
+	c.contexts[name] = client
+	return client, nil
+}
+
+// Release summarizes an installed Helm release.
+type Release struct {
+	Name         string    `json:"name"`
+	Namespace    string    `json:"namespace"`
+	Revision     int       `json:"revision"`
+	Chart        string    `json:"chart"`
+	ChartVersion string    `json:"chartVersion"`
+	AppVersion   string    `json:"appVersion,omitempty"`
+	Status       string    `json:"status"`
+	LastDeployed time.Time `json:"lastDeployed,omitzero"`
+}
+
+// ListOptions filters and pages the releases returned by ListReleases.
+type ListOptions struct {
+	// Filter is a regular expression matched against release names.
+	Filter string
+	// Selector is a label selector matched against release labels, e.g.
+	// "team=web,tier!=cache".
+	Selector string
+	// Statuses restricts the result to releases in these statuses, e.g.
+	// "deployed" or "failed". "pending" matches pending installs, upgrades and
+	// rollbacks. All statuses are listed if empty.
+	Statuses []string
+	// Offset is the number of matching releases to skip.
+	Offset int
+	// Limit is the maximum number of releases to return. 0 means no limit.
+	Limit int
+}
+
+// ReleaseList is a page of the releases matching ListOptions.
+type ReleaseList struct {
+	Releases []Release `json:"releases"`
+	// Total is the number of matching releases across all pages.
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	// NextOffset is the offset of the next page, or 0 on the last page.
+	NextOffset int `json:"nextOffset,omitempty"`
+}
+
+// listStates maps the statuses accepted in ListOptions to Helm list states.
+var listStates = map[string]action.ListStates{
+	"deployed":         action.ListDeployed,
+	"failed":           action.ListFailed,
+	"pending":          action.ListPendingInstall | action.ListPendingUpgrade | action.ListPendingRollback,
+	"pending-install":  action.ListPendingInstall,
+	"pending-upgrade":  action.ListPendingUpgrade,
+	"pending-rollback": action.ListPendingRollback,
+	"superseded":       action.ListSuperseded,
+	"uninstalled":      action.ListUninstalled,
+	"uninstalling":     action.ListUninstalling,
+}
+
+// ReleaseStatuses returns the statuses accepted in ListOptions, sorted.
+func ReleaseStatuses() []string {
+	return slices.Sorted(maps.Keys(listStates))
+}
+
+// ListReleases returns the latest revision of the releases in namespace, or
+// in all namespaces if namespace is empty, that match opts, sorted by name.
+// Uninstalled releases are included if their history was kept.
+func (c *ClusterClient) ListReleases(ctx context.Context, namespace string, opts ListOptions) (*ReleaseList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if opts.Offset < 0 || opts.Limit < 0 {
+		return nil, fmt.Errorf("offset and limit must not be negative")
+	}
+
+	stateMask := action.ListAll
+	if len(opts.Statuses) > 0 {
+		stateMask = 0
+		for _, status := range opts.Statuses {
+			state, ok := listStates[strings.ToLower(strings.TrimSpace(status))]
+			if !ok {
+				return nil, fmt.Errorf("unknown release status %q", status)
+			}
+			stateMask |= state
+		}
+	}
+
+	cfg, err := c.newConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	list := action.NewList(cfg)
+	list.AllNamespaces = namespace == ""
+	list.Filter = opts.Filter
+	list.Selector = opts.Selector
+	list.StateMask = stateMask
+
+	results, err := list.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ReleaseList{Releases: []Release{}, Total: len(results), Offset: opts.Offset}
+	end := len(results)
+	if opts.Limit > 0 && opts.Offset+opts.Limit < end {
+		end = opts.Offset + opts.Limit
+		page.NextOffset = end
+	}
+	for i := opts.Offset; i < end; i++ {
+		rel, err := toV1Release(results[i])
+		if err != nil {
+			return nil, err
+		}
+		page.Releases = append(page.Releases, summarize(rel))
+	}
+	return page, nil
+}
+
+// GetReleaseValues returns the values a release was installed or upgraded
+// with, like "helm get values". If all is set the chart defaults are merged
+// in, giving the computed values. revision selects an older revision; 0 means
+// the latest one. namespace defaults to the namespace of the kubeconfig
+// context.
+func (c *ClusterClient) GetReleaseValues(ctx context.Context, namespace, name string, revision int, all bool) (map[string]any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cfg, err := c.newConfig(c.namespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	get := action.NewGetValues(cfg)
+	get.Version = revision
+	get.AllValues = all
+
+	values, err := get.Run(name)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = map[string]any{}
+	}
+	return values, nil
+}
+
+// GetReleaseManifest returns the rendered manifest deployed by a release, like
+// "helm get manifest". revision and namespace are handled as in
+// GetReleaseValues.
+func (c *ClusterClient) GetReleaseManifest(ctx context.Context, namespace, name string, revision int) (string, error) {
+	rel, err := c.getRelease(ctx, namespace, name, revision)
+	if err != nil {
+		return "", err
+	}
+	return rel.Manifest, nil
+}
+
+// UpgradePreview shows how the manifest of a release would change when it is
+// upgraded to another chart.
+type UpgradePreview struct {
+	Release         string `json:"release"`
+	Namespace       string `json:"namespace"`
+	CurrentRevision int    `json:"currentRevision"`
+	CurrentChart    string `json:"currentChart"`
+	TargetChart     string `json:"targetChart"`
+	helm_parser.ManifestDiff
+}
+
+// PreviewUpgrade renders target with the values of the deployed release,
+// overridden by values, and diffs the result against the deployed manifest
+// resource by resource, like the helm-diff plugin. New chart defaults apply,
+// as with "helm upgrade --reset-then-reuse-values". Nothing is changed in the
+// cluster. target is modified by rendering and must not be shared.
+func (c *ClusterClient) PreviewUpgrade(ctx context.Context, namespace, name string, target *chartv2.Chart, values map[string]any) (*UpgradePreview, error) {
+	current, err := c.getRelease(ctx, namespace, name, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := c.newConfig(current.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.Namespace = current.Namespace
+	upgrade.DryRunStrategy = action.DryRunClient
+	upgrade.ResetThenReuseValues = true
+
+	rel, err := upgrade.RunWithContext(ctx, name, target, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render upgrade: %v", err)
+	}
+	upgraded, err := toV1Release(rel)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := helm_parser.DiffManifests(current.Manifest, upgraded.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarize(current)
+	return &UpgradePreview{
+		Release:         current.Name,
+		Namespace:       current.Namespace,
+		CurrentRevision: current.Version,
+		CurrentChart:    summary.Chart + "-" + summary.ChartVersion,
+		TargetChart:     target.Metadata.Name + "-" + target.Metadata.Version,
+		ManifestDiff:    *diff,
+	}, nil
+}
+
+// ValuesComparison compares the values of a release with the defaults of its
+// chart and of a newer chart version.
+type ValuesComparison struct {
+	Release      string `json:"release"`
+	Namespace    string `json:"namespace"`
+	CurrentChart string `json:"currentChart"`
+	TargetChart  string `json:"targetChart"`
+	// Overrides lists the values set by the user that differ from the
+	// defaults of the deployed chart.
+	Overrides []helm_parser.ValueChange `json:"overrides"`
+	// ChangedDefaults lists the defaults that differ between the deployed and
+	// the target chart.
+	ChangedDefaults []DefaultChange `json:"changedDefaults"`
+}
+
+// DefaultChange is a default value changed in the target chart.
+type DefaultChange struct {
+	helm_parser.ValueChange
+	// Overridden is set if the release overrides the value, so the new
+	// default does not apply to it.
+	Overridden bool `json:"overridden"`
+}
+
+// CompareValues compares the user-supplied values of the latest revision of
+// a release with the defaults of the chart it was deployed with, which are
+// stored in the release, and those defaults with the defaults of target.
+func (c *ClusterClient) CompareValues(ctx context.Context, namespace, name string, target *chartv2.Chart) (*ValuesComparison, error) {
+	rel, err := c.getRelease(ctx, namespace, name, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaults map[string]any
+	if rel.Chart != nil {
+		defaults = rel.Chart.Values
+	}
+
+	overrides := []helm_parser.ValueChange{}
+	flatConfig := helm_parser.FlattenValues(rel.Config)
+	for _, change := range helm_parser.DiffValues(defaults, rel.Config) {
+		if _, ok := flatConfig[change.Path]; ok {
+			overrides = append(overrides, change)
+		}
+	}
+
+	changedDefaults := []DefaultChange{}
+	for _, change := range helm_parser.DiffValues(defaults, target.Values) {
+		_, overridden := flatConfig[change.Path]
+		changedDefaults = append(changedDefaults, DefaultChange{ValueChange: change, Overridden: overridden})
+	}
+
+	summary := summarize(rel)
+	return &ValuesComparison{
+		Release:         rel.Name,
+		Namespace:       rel.Namespace,
+		CurrentChart:    summary.Chart + "-" + summary.ChartVersion,
+		TargetChart:     target.Metadata.Name + "-" + target.Metadata.Version,
+		Overrides:       overrides,
+		ChangedDefaults: changedDefaults,
+	}, nil
+}
+
+// DryRunResult is the outcome of a server-side dry run of an install.
+type DryRunResult struct {
+	Release   string `json:"release"`
+	Namespace string `json:"namespace"`
+	Chart     string `json:"chart"`
+	// Errors lists the problems found. The install is expected to succeed if
+	// it is empty.
+	Errors   []string `json:"errors"`
+	Manifest string   `json:"manifest,omitempty"`
+	Hooks    string   `json:"hooks,omitempty"`
+	Notes    string   `json:"notes,omitempty"`
+}
+
+// DryRunInstall checks whether chart would install as release name, like
+// "helm install --dry-run=server": templates are rendered with the cluster's
+// API versions and lookup functions, and the rendered resources are
+// validated against the cluster's OpenAPI schemas and checked for conflicts
+// with existing resources. The resources are then sent to the API server as
+// a server-side dry run, so admission webhooks and policies are applied too.
+// Nothing is persisted. Problems are reported in the result rather than as
+// error. chart is modified by rendering and must not be shared.
+func (c *ClusterClient) DryRunInstall(ctx context.Context, namespace, name string, chart *chartv2.Chart, values map[string]any) (*DryRunResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	namespace = c.namespace(namespace)
+	cfg, err := c.newConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DryRunResult{
+		Release:   name,
+		Namespace: namespace,
+		Chart:     chart.Metadata.Name + "-" + chart.Metadata.Version,
+		Errors:    []string{},
+	}
+
+	// Helm skips the release name check on dry runs.
+	if history, err := cfg.Releases.History(name); err == nil && len(history) > 0 {
+		result.Errors = append(result.Errors, fmt.Sprintf("release name %q is already in use in namespace %q", name, namespace))
+	}
+
+	install := action.NewInstall(cfg)
+	install.ReleaseName = name
+	install.Namespace = namespace
+	install.DryRunStrategy = action.DryRunServer
+
+	rel, err := install.RunWithContext(ctx, chart, values)
+	if rel != nil {
+		if r, convErr := toV1Release(rel); convErr == nil {
+			result.Manifest = r.Manifest
+			result.Notes = r.Info.Notes
+			for _, h := range r.Hooks {
+				result.Hooks += "---\n# Source: " + h.Path + "\n" + h.Manifest + "\n"
+			}
+		}
+	}
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, nil
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	resources, err := cfg.KubeClient.Build(strings.NewReader(result.Manifest), true)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("failed to build resources: %v", err))
+		return result, nil
+	}
+	if _, err := cfg.KubeClient.Create(resources, kube.ClientCreateOptionDryRun(true)); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("server-side dry run failed: %v", err))
+	}
+	return result, nil
+}
+
+// defaultWriteTimeout is the time to wait for Kubernetes operations of
+// installs and upgrades unless WriteOptions sets another, as in the helm CLI.
+const defaultWriteTimeout = 5 * time.Minute
+
+// WriteOptions configures InstallChart and UpgradeRelease.
+type WriteOptions struct {
+	// Timeout is the time to wait for Kubernetes operations, such as hooks
+	// or, with Atomic, resources becoming ready.
+	Timeout time.Duration
+	// Atomic waits for the resources to become ready and rolls the release
+	// back (or uninstalls it after a failed install) if they do not.
+	Atomic bool
+}
+
+func (o WriteOptions) timeout() time.Duration {
+	if o.Timeout > 0 {
+		return o.Timeout
+	}
+	return defaultWriteTimeout
+}
+
+// InstallChart installs chart as release name, like "helm install".
+func (c *ClusterClient) InstallChart(ctx context.Context, namespace, name string, chart *chartv2.Chart, values map[string]any, opts WriteOptions) (*Release, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	namespace = c.namespace(namespace)
+	cfg, err := c.newConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	install := action.NewInstall(cfg)
+	install.ReleaseName = name
+	install.Namespace = namespace
+	install.Timeout = opts.timeout()
+	install.RollbackOnFailure = opts.Atomic
+
+	rel, err := install.RunWithContext(ctx, chart, values)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeReleaser(rel)
+}
+
+// UpgradeRelease upgrades a release to chart, like "helm upgrade". If
+// reuseValues is set, values are merged over the values of the deployed
+// release and new chart defaults apply, as with
+// "--reset-then-reuse-values"; otherwise they replace them.
+func (c *ClusterClient) UpgradeRelease(ctx context.Context, namespace, name string, chart *chartv2.Chart, values map[string]any, reuseValues bool, opts WriteOptions) (*Release, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	namespace = c.namespace(namespace)
+	cfg, err := c.newConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.Namespace = namespace
+	upgrade.Timeout = opts.timeout()
+	upgrade.RollbackOnFailure = opts.Atomic
+	upgrade.ResetThenReuseValues = reuseValues
+	upgrade.ResetValues = !reuseValues
+
+	rel, err := upgrade.RunWithContext(ctx, name, chart, values)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeReleaser(rel)
+}
+
+// RollbackRelease rolls a release back to a previous revision, like "helm
+// rollback", creating a new revision. revision 0 rolls back to the revision
+// before the current one. If wait is set it waits for the resources to become
+// ready. It returns the new revision.
+func (c *ClusterClient) RollbackRelease(ctx context.Context, namespace, name string, revision int, timeout time.Duration, wait bool) (*Release, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	namespace = c.namespace(namespace)
+	cfg, err := c.newConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := action.NewRollback(cfg)
+	rollback.Version = revision
+	rollback.Timeout = WriteOptions{Timeout: timeout}.timeout()
+	rollback.WaitStrategy = kube.HookOnlyStrategy
+	if wait {
+		rollback.WaitStrategy = kube.StatusWatcherStrategy
+	}
+
+	_, err = runWithContext(ctx, func() (struct{}, error) {
+		return struct{}{}, rollback.Run(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := c.getRelease(ctx, namespace, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	summary := summarize(rel)
+	return &summary, nil
+}
+
+// UninstallResult is the outcome of uninstalling a release.
+type UninstallResult struct {
+	Release
+	// DryRun is set if the uninstall was only simulated.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Info lists the resources kept due to their resource policy.
+	Info string `json:"info,omitempty"`
+}
+
+// UninstallRelease uninstalls a release, like "helm uninstall", deleting its
+// resources. If keepHistory is set the release records are kept with status
+// uninstalled, so the release can still be inspected and rolled back. If
+// dryRun is set nothing is changed and the release that would be uninstalled
+// is returned.
+func (c *ClusterClient) UninstallRelease(ctx context.Context, namespace, name string, keepHistory, dryRun bool, timeout time.Duration) (*UninstallResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cfg, err := c.newConfig(c.namespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	uninstall := action.NewUninstall(cfg)
+	uninstall.KeepHistory = keepHistory
+	uninstall.DryRun = dryRun
+	uninstall.Timeout = WriteOptions{Timeout: timeout}.timeout()
+	uninstall.WaitStrategy = kube.HookOnlyStrategy
+
+	res, err := runWithContext(ctx, func() (*ri.UninstallReleaseResponse, error) {
+		return uninstall.Run(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	summary, err := summarizeReleaser(res.Release)
+	if err != nil {
+		return nil, err
+	}
+	return &UninstallResult{Release: *summary, DryRun: dryRun, Info: res.Info}, nil
+}
+
+// runWithContext runs fn and returns its result, or ctx.Err() as soon as ctx
+// is done. It is used for Helm actions that do not accept a context, such as
+// rollback and uninstall: the action keeps running in the background until it completes,
+// bounded by its own timeout, but the caller is no longer blocked on it.
+func runWithContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn()
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func summarizeReleaser(r ri.Releaser) (*Release, error) {
+	rel, err := toV1Release(r)
+	if err != nil {
+		return nil, err
+	}
+	summary := summarize(rel)
+	return &summary, nil
+}
+
+// getRelease returns a revision of a release, or its latest revision if
+// revision is 0.
+func (c *ClusterClient) getRelease(ctx context.Context, namespace, name string, revision int) (*releasev1.Release, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cfg, err := c.newConfig(c.namespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	get := action.NewGet(cfg)
+	get.Version = revision
+
+	rel, err := get.Run(name)
+	if err != nil {
+		return nil, err
+	}
+	return toV1Release(rel)
+}
+
+// namespace returns ns, or the namespace of the kubeconfig context if ns is
+// empty.
+func (c *ClusterClient) namespace(ns string) string {
+	if ns != "" {
+		return ns
+	}
+	return c.settings.Namespace()
+}
+
+func toV1Release(r ri.Releaser) (*releasev1.Release, error) {
+	switch rel := r.(type) {
+	case releasev1.Release:
+		return &rel, nil
+	case *releasev1.Release:
+		return rel, nil
+	default:
+		return nil, fmt.Errorf("unsupported release type: %T", r)
+	}
+}
+
+func summarize(rel *releasev1.Release) Release {
+	r := Release{
+		Name:      rel.Name,
+		Namespace: rel.Namespace,
+		Revision:  rel.Version,
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		r.Chart = rel.Chart.Metadata.Name
+		r.ChartVersion = rel.Chart.Metadata.Version
+		r.AppVersion = rel.Chart.Metadata.AppVersion
+	}
+	if rel.Info != nil {
+		r.Status = rel.Info.Status.String()
+		r.LastDeployed = rel.Info.LastDeployed
+	}
+	return r
+}
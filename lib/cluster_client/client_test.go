@@ -0,0 +1,629 @@
+package cluster_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v4/pkg/action"
+	chartcommon "helm.sh/helm/v4/pkg/chart/common"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/cli"
+	kubefake "helm.sh/helm/v4/pkg/kube/fake"
+	"helm.sh/helm/v4/pkg/release/common"
+	releasev1 "helm.sh/helm/v4/pkg/release/v1"
+	"helm.sh/helm/v4/pkg/storage"
+	"helm.sh/helm/v4/pkg/storage/driver"
+	"k8s.io/client-go/kubernetes"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func newTestClient(t *testing.T, releases ...*releasev1.Release) *ClusterClient {
+	t.Helper()
+
+	mem := driver.NewMemory()
+	for _, rel := range releases {
+		mem.SetNamespace(rel.Namespace)
+		if err := mem.Create(fmt.Sprintf("sh.helm.release.v1.%s.v%d", rel.Name, rel.Version), rel); err != nil {
+			t.Fatalf("failed to store release: %v", err)
+		}
+	}
+
+	return &ClusterClient{
+		settings: cli.New(),
+		newConfig: func(namespace string) (*action.Configuration, error) {
+			mem.SetNamespace(namespace)
+			cfg := action.NewConfiguration()
+			cfg.Releases = storage.Init(mem)
+			cfg.KubeClient = &kubefake.PrintingKubeClient{Out: io.Discard}
+			cfg.Capabilities = chartcommon.DefaultCapabilities
+			return cfg, nil
+		},
+		kubeClient: func() (kubernetes.Interface, error) {
+			return kubernetesfake.NewClientset(), nil
+		},
+	}
+}
+
+func testRelease(name, namespace string, revision int, status common.Status, deployed time.Time) *releasev1.Release {
+	return &releasev1.Release{
+		Name:      name,
+		Namespace: namespace,
+		Version:   revision,
+		Chart: &chartv2.Chart{Metadata: &chartv2.Metadata{
+			Name:       "app",
+			Version:    "1.2.3",
+			AppVersion: "4.5.6",
+		}},
+		Info: &releasev1.Info{Status: status, LastDeployed: deployed},
+	}
+}
+
+func TestListReleases(t *testing.T) {
+	deployed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cache := testRelease("cache", "data", 1, common.StatusPendingUpgrade, deployed)
+	cache.Labels = map[string]string{"team": "platform"}
+	c := newTestClient(t,
+		testRelease("web", "default", 1, common.StatusSuperseded, deployed.Add(-time.Hour)),
+		testRelease("web", "default", 2, common.StatusDeployed, deployed),
+		testRelease("db", "data", 1, common.StatusFailed, deployed),
+		cache,
+	)
+
+	list, err := c.ListReleases(context.Background(), "", ListOptions{})
+	if err != nil {
+		t.Fatalf("ListReleases: %v", err)
+	}
+	if list.Total != 3 || len(list.Releases) != 3 || list.NextOffset != 0 {
+		t.Fatalf("expected 3 releases on a single page, got %+v", list)
+	}
+
+	var web Release
+	for _, r := range list.Releases {
+		if r.Name == "web" {
+			web = r
+		}
+	}
+	want := Release{
+		Name:         "web",
+		Namespace:    "default",
+		Revision:     2,
+		Chart:        "app",
+		ChartVersion: "1.2.3",
+		AppVersion:   "4.5.6",
+		Status:       "deployed",
+		LastDeployed: deployed,
+	}
+	if web != want {
+		t.Fatalf("got %+v, want %+v", web, want)
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		opts      ListOptions
+		want      []string
+		next      int
+	}{
+		{"namespace", "data", ListOptions{}, []string{"cache", "db"}, 0},
+		{"filter", "", ListOptions{Filter: "^w"}, []string{"web"}, 0},
+		{"selector", "", ListOptions{Selector: "team=platform"}, []string{"cache"}, 0},
+		{"status", "", ListOptions{Statuses: []string{"failed", "deployed"}}, []string{"db", "web"}, 0},
+		{"pending", "", ListOptions{Statuses: []string{"pending"}}, []string{"cache"}, 0},
+		{"first page", "", ListOptions{Limit: 2}, []string{"cache", "db"}, 2},
+		{"last page", "", ListOptions{Offset: 2, Limit: 2}, []string{"web"}, 0},
+		{"past the end", "", ListOptions{Offset: 5}, []string{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := c.ListReleases(context.Background(), tt.namespace, tt.opts)
+			if err != nil {
+				t.Fatalf("ListReleases: %v", err)
+			}
+			names := []string{}
+			for _, r := range list.Releases {
+				names = append(names, r.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) || list.NextOffset != tt.next {
+				t.Fatalf("got %v with next offset %d, want %v with %d", names, list.NextOffset, tt.want, tt.next)
+			}
+		})
+	}
+
+	if _, err := c.ListReleases(context.Background(), "", ListOptions{Statuses: []string{"running"}}); err == nil {
+		t.Fatal("expected an error for an unknown status")
+	}
+	if _, err := c.ListReleases(context.Background(), "", ListOptions{Statuses: ReleaseStatuses()}); err != nil {
+		t.Fatalf("ListReleases with all statuses: %v", err)
+	}
+}
+
+func TestGetReleaseValues(t *testing.T) {
+	v1 := testRelease("web", "apps", 1, common.StatusSuperseded, time.Now())
+	v1.Config = map[string]any{"replicas": 1}
+	v2 := testRelease("web", "apps", 2, common.StatusDeployed, time.Now())
+	v2.Chart.Values = map[string]any{"replicas": 1, "image": "nginx"}
+	v2.Config = map[string]any{"replicas": 3}
+	c := newTestClient(t, v1, v2)
+
+	tests := []struct {
+		name     string
+		revision int
+		all      bool
+		want     map[string]any
+	}{
+		{"latest", 0, false, map[string]any{"replicas": 3}},
+		{"revision", 1, false, map[string]any{"replicas": 1}},
+		{"all", 0, true, map[string]any{"replicas": 3, "image": "nginx"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetReleaseValues(context.Background(), "apps", "web", tt.revision, tt.all)
+			if err != nil {
+				t.Fatalf("GetReleaseValues: %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := c.GetReleaseValues(context.Background(), "apps", "missing", 0, false); err == nil {
+		t.Fatal("expected an error for a missing release")
+	}
+}
+
+func TestGetReleaseManifest(t *testing.T) {
+	v1 := testRelease("web", "apps", 1, common.StatusSuperseded, time.Now())
+	v1.Manifest = "kind: Deployment\nspec:\n  replicas: 1\n"
+	v2 := testRelease("web", "apps", 2, common.StatusDeployed, time.Now())
+	v2.Manifest = "kind: Deployment\nspec:\n  replicas: 3\n"
+	c := newTestClient(t, v1, v2)
+
+	for revision, want := range map[int]string{0: v2.Manifest, 1: v1.Manifest, 2: v2.Manifest} {
+		got, err := c.GetReleaseManifest(context.Background(), "apps", "web", revision)
+		if err != nil {
+			t.Fatalf("GetReleaseManifest(revision %d): %v", revision, err)
+		}
+		if got != want {
+			t.Errorf("revision %d: got %q, want %q", revision, got, want)
+		}
+	}
+
+	if _, err := c.GetReleaseManifest(context.Background(), "apps", "web", 3); err == nil {
+		t.Fatal("expected an error for a missing revision")
+	}
+}
+
+func configMapChart(version, template string) *chartv2.Chart {
+	return &chartv2.Chart{
+		Metadata: &chartv2.Metadata{APIVersion: chartv2.APIVersionV2, Name: "app", Version: version},
+		Values:   map[string]any{"greeting": "hello", "extra": "default"},
+		Templates: []*chartcommon.File{
+			{Name: "templates/configmap.yaml", Data: []byte(template)},
+		},
+	}
+}
+
+func TestPreviewUpgrade(t *testing.T) {
+	current := testRelease("web", "apps", 1, common.StatusDeployed, time.Now())
+	current.Chart = configMapChart("1.0.0", "")
+	current.Config = map[string]any{"greeting": "hi"}
+	current.Manifest = `---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+data:
+  greeting: hi
+`
+	c := newTestClient(t, current)
+
+	target := configMapChart("2.0.0", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+data:
+  greeting: {{ .Values.greeting }}
+  extra: {{ .Values.extra }}
+`)
+	preview, err := c.PreviewUpgrade(context.Background(), "apps", "web", target, map[string]any{"extra": "override"})
+	if err != nil {
+		t.Fatalf("PreviewUpgrade: %v", err)
+	}
+
+	if preview.CurrentChart != "app-1.0.0" || preview.TargetChart != "app-2.0.0" || preview.CurrentRevision != 1 {
+		t.Errorf("unexpected preview header: %+v", preview)
+	}
+	if len(preview.Changes) != 1 {
+		t.Fatalf("expected 1 change, got %+v", preview.Changes)
+	}
+	want := `--- ConfigMap/web
++++ ConfigMap/web
+@@ -4,3 +4,4 @@
+   name: web
+ data:
+   greeting: hi
++  extra: override
+`
+	if got := preview.Changes[0]; got.Change != "modified" || got.Diff != want {
+		t.Fatalf("unexpected change %s:\n%s", got.Change, got.Diff)
+	}
+
+	// A preview must not create a new revision.
+	list, err := c.ListReleases(context.Background(), "apps", ListOptions{})
+	if err != nil {
+		t.Fatalf("ListReleases: %v", err)
+	}
+	releases := list.Releases
+	if len(releases) != 1 || releases[0].Revision != 1 {
+		t.Fatalf("preview changed the release: %+v", releases)
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	rel := testRelease("web", "apps", 1, common.StatusDeployed, time.Now())
+	rel.Chart = configMapChart("1.0.0", "")
+	rel.Chart.Values["image"] = map[string]any{"tag": "1.25"}
+	rel.Config = map[string]any{"greeting": "hi", "extra": "default", "image": map[string]any{"tag": "1.26"}}
+	c := newTestClient(t, rel)
+
+	target := configMapChart("2.0.0", "")
+	target.Values["greeting"] = "hello there"
+	target.Values["image"] = map[string]any{"tag": "1.27"}
+	delete(target.Values, "extra")
+
+	comparison, err := c.CompareValues(context.Background(), "apps", "web", target)
+	if err != nil {
+		t.Fatalf("CompareValues: %v", err)
+	}
+	if comparison.CurrentChart != "app-1.0.0" || comparison.TargetChart != "app-2.0.0" {
+		t.Errorf("unexpected charts: %+v", comparison)
+	}
+
+	wantOverrides := []helm_parser.ValueChange{
+		{Path: "greeting", Old: "hello", New: "hi"},
+		{Path: "image.tag", Old: "1.25", New: "1.26"},
+	}
+	if !reflect.DeepEqual(comparison.Overrides, wantOverrides) {
+		t.Errorf("got overrides %+v, want %+v", comparison.Overrides, wantOverrides)
+	}
+	wantChanged := []DefaultChange{
+		{ValueChange: helm_parser.ValueChange{Path: "extra", Old: "default"}, Overridden: true},
+		{ValueChange: helm_parser.ValueChange{Path: "greeting", Old: "hello", New: "hello there"}, Overridden: true},
+		{ValueChange: helm_parser.ValueChange{Path: "image.tag", Old: "1.25", New: "1.27"}, Overridden: true},
+	}
+	if !reflect.DeepEqual(comparison.ChangedDefaults, wantChanged) {
+		t.Errorf("got changed defaults %+v, want %+v", comparison.ChangedDefaults, wantChanged)
+	}
+}
+
+func TestDryRunInstall(t *testing.T) {
+	c := newTestClient(t, testRelease("taken", "apps", 1, common.StatusDeployed, time.Now()))
+	template := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+data:
+  greeting: {{ required "greeting is required" .Values.greeting }}
+`
+
+	result, err := c.DryRunInstall(context.Background(), "apps", "web", configMapChart("1.0.0", template), map[string]any{"greeting": "hi"})
+	if err != nil {
+		t.Fatalf("DryRunInstall: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if result.Chart != "app-1.0.0" || !strings.Contains(result.Manifest, "greeting: hi") {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	result, err = c.DryRunInstall(context.Background(), "apps", "web", configMapChart("1.0.0", template), map[string]any{"greeting": nil})
+	if err != nil {
+		t.Fatalf("DryRunInstall: %v", err)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "greeting is required") {
+		t.Fatalf("expected a render error, got %v", result.Errors)
+	}
+
+	result, err = c.DryRunInstall(context.Background(), "apps", "taken", configMapChart("1.0.0", template), nil)
+	if err != nil {
+		t.Fatalf("DryRunInstall: %v", err)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "name") {
+		t.Fatalf("expected an error for a name in use, got %v", result.Errors)
+	}
+
+	list, err := c.ListReleases(context.Background(), "", ListOptions{})
+	if err != nil {
+		t.Fatalf("ListReleases: %v", err)
+	}
+	releases := list.Releases
+	if len(releases) != 1 {
+		t.Fatalf("dry run stored a release: %+v", releases)
+	}
+}
+
+func TestInstallAndUpgrade(t *testing.T) {
+	c := newTestClient(t)
+	template := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+data:
+  greeting: {{ .Values.greeting }}
+  extra: {{ .Values.extra }}
+`
+
+	installed, err := c.InstallChart(context.Background(), "apps", "web", configMapChart("1.0.0", template), map[string]any{"greeting": "hi"}, WriteOptions{})
+	if err != nil {
+		t.Fatalf("InstallChart: %v", err)
+	}
+	if installed.Revision != 1 || installed.Status != "deployed" || installed.ChartVersion != "1.0.0" {
+		t.Fatalf("unexpected installed release: %+v", installed)
+	}
+
+	if _, err := c.InstallChart(context.Background(), "apps", "web", configMapChart("1.0.0", template), nil, WriteOptions{}); err == nil {
+		t.Fatal("expected an error for installing over an existing release")
+	}
+
+	upgraded, err := c.UpgradeRelease(context.Background(), "apps", "web", configMapChart("2.0.0", template), map[string]any{"extra": "more"}, true, WriteOptions{})
+	if err != nil {
+		t.Fatalf("UpgradeRelease: %v", err)
+	}
+	if upgraded.Revision != 2 || upgraded.ChartVersion != "2.0.0" {
+		t.Fatalf("unexpected upgraded release: %+v", upgraded)
+	}
+	values, err := c.GetReleaseValues(context.Background(), "apps", "web", 0, false)
+	if err != nil {
+		t.Fatalf("GetReleaseValues: %v", err)
+	}
+	if values["greeting"] != "hi" || values["extra"] != "more" {
+		t.Fatalf("upgrade did not reuse values: %v", values)
+	}
+
+	if _, err := c.UpgradeRelease(context.Background(), "apps", "web", configMapChart("3.0.0", template), map[string]any{"extra": "only"}, false, WriteOptions{}); err != nil {
+		t.Fatalf("UpgradeRelease: %v", err)
+	}
+	values, err = c.GetReleaseValues(context.Background(), "apps", "web", 0, false)
+	if err != nil {
+		t.Fatalf("GetReleaseValues: %v", err)
+	}
+	if _, ok := values["greeting"]; ok || values["extra"] != "only" {
+		t.Fatalf("upgrade without reuse kept old values: %v", values)
+	}
+}
+
+func TestRollbackRelease(t *testing.T) {
+	c := newTestClient(t)
+	template := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+`
+	if _, err := c.InstallChart(context.Background(), "apps", "web", configMapChart("1.0.0", template), nil, WriteOptions{}); err != nil {
+		t.Fatalf("InstallChart: %v", err)
+	}
+	if _, err := c.UpgradeRelease(context.Background(), "apps", "web", configMapChart("2.0.0", template), nil, true, WriteOptions{}); err != nil {
+		t.Fatalf("UpgradeRelease: %v", err)
+	}
+
+	rolledBack, err := c.RollbackRelease(context.Background(), "apps", "web", 0, 0, false)
+	if err != nil {
+		t.Fatalf("RollbackRelease: %v", err)
+	}
+	if rolledBack.Revision != 3 || rolledBack.ChartVersion != "1.0.0" || rolledBack.Status != "deployed" {
+		t.Fatalf("unexpected release after rollback: %+v", rolledBack)
+	}
+
+	if _, err := c.RollbackRelease(context.Background(), "apps", "web", 7, 0, false); err == nil {
+		t.Fatal("expected an error for a missing revision")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.RollbackRelease(ctx, "apps", "web", 0, 0, false); !errors.Is(err, context.Canceled) {
+		t.Fatalf("RollbackRelease with a cancelled context: got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRunWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	block := make(chan struct{})
+	defer close(block)
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := runWithContext(ctx, func() (struct{}, error) {
+			<-block
+			return struct{}{}, nil
+		})
+		errc <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("runWithContext: got %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runWithContext did not return after the context was cancelled")
+	}
+}
+
+func TestUninstallRelease(t *testing.T) {
+	c := newTestClient(t)
+	template := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+`
+	for _, name := range []string{"web", "db"} {
+		if _, err := c.InstallChart(context.Background(), "apps", name, configMapChart("1.0.0", template), nil, WriteOptions{}); err != nil {
+			t.Fatalf("InstallChart: %v", err)
+		}
+	}
+
+	result, err := c.UninstallRelease(context.Background(), "apps", "web", false, true, 0)
+	if err != nil {
+		t.Fatalf("UninstallRelease: %v", err)
+	}
+	if !result.DryRun || result.Name != "web" || result.Status != "deployed" {
+		t.Fatalf("unexpected dry run result: %+v", result)
+	}
+
+	if _, err := c.UninstallRelease(context.Background(), "apps", "web", false, false, 0); err != nil {
+		t.Fatalf("UninstallRelease: %v", err)
+	}
+	result, err = c.UninstallRelease(context.Background(), "apps", "db", true, false, 0)
+	if err != nil {
+		t.Fatalf("UninstallRelease: %v", err)
+	}
+	if result.DryRun || result.Status != "uninstalled" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	list, err := c.ListReleases(context.Background(), "apps", ListOptions{})
+	if err != nil {
+		t.Fatalf("ListReleases: %v", err)
+	}
+	releases := list.Releases
+	if len(releases) != 1 || releases[0].Name != "db" || releases[0].Status != "uninstalled" {
+		t.Fatalf("unexpected releases after uninstall: %+v", releases)
+	}
+
+	if _, err := c.UninstallRelease(context.Background(), "apps", "missing", false, false, 0); err == nil {
+		t.Fatal("expected an error for a missing release")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.UninstallRelease(ctx, "apps", "db", false, false, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("UninstallRelease with a cancelled context: got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestNewClientMissingKubeconfig(t *testing.T) {
+	if _, err := NewClient(WithKubeconfig("/nonexistent/kubeconfig")); err == nil {
+		t.Fatal("expected an error for a missing kubeconfig")
+	}
+}
+
+func TestNewClientInCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	if _, err := NewClient(WithInCluster()); err == nil {
+		t.Fatal("expected an error outside of a cluster")
+	}
+	if _, err := NewClient(WithInCluster(), WithKubeContext("staging")); err == nil {
+		t.Fatal("expected an error for in-cluster configuration with a context")
+	}
+}
+
+// writeKubeconfig writes a kubeconfig with a production and a staging
+// context, the former being current, and returns its path.
+func writeKubeconfig(t *testing.T) string {
+	t.Helper()
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: production
+clusters:
+- name: production
+  cluster: {server: https://production.example.com}
+- name: staging
+  cluster: {server: https://staging.example.com}
+contexts:
+- name: production
+  context: {cluster: production, namespace: apps}
+- name: staging
+  context: {cluster: staging, namespace: web}
+`), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	return kubeconfig
+}
+
+func TestForContext(t *testing.T) {
+	kubeconfig := writeKubeconfig(t)
+
+	c, err := NewClient(WithKubeconfig(kubeconfig))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if same, err := c.ForContext(""); err != nil || same != c {
+		t.Fatalf("ForContext(\"\") = %p, %v; want the client itself", same, err)
+	}
+
+	staging, err := c.ForContext("staging")
+	if err != nil {
+		t.Fatalf("ForContext: %v", err)
+	}
+	if staging.namespace("") != "web" || c.namespace("") != "apps" {
+		t.Fatalf("unexpected default namespaces %q and %q", staging.namespace(""), c.namespace(""))
+	}
+	if again, _ := c.ForContext("staging"); again != staging {
+		t.Fatal("expected the client for a context to be reused")
+	}
+
+	if _, err := c.ForContext("missing"); err == nil {
+		t.Fatal("expected an error for an unknown context")
+	}
+}
+
+func TestForCluster(t *testing.T) {
+	kubeconfig := writeKubeconfig(t)
+
+	c, err := NewClient(WithKubeconfig(kubeconfig), WithClusters(
+		NamedCluster{Name: "staging", Kubeconfig: kubeconfig, Context: "staging"},
+	))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	for _, name := range []string{"", DefaultClusterName} {
+		if same, err := c.ForCluster(name); err != nil || same != c {
+			t.Fatalf("ForCluster(%q) = %p, %v; want the client itself", name, same, err)
+		}
+	}
+
+	staging, err := c.ForCluster("staging")
+	if err != nil {
+		t.Fatalf("ForCluster: %v", err)
+	}
+	if staging.namespace("") != "web" {
+		t.Fatalf("unexpected default namespace %q", staging.namespace(""))
+	}
+	if _, err := c.ForCluster("missing"); err == nil {
+		t.Fatal("expected an error for an unknown cluster")
+	}
+
+	want := []ClusterInfo{
+		{Name: DefaultClusterName, Kubeconfig: kubeconfig, Default: true},
+		{Name: "staging", Kubeconfig: kubeconfig, Context: "staging"},
+	}
+	if got := c.Clusters(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Clusters() = %+v, want %+v", got, want)
+	}
+
+	for _, clusters := range [][]NamedCluster{
+		{{Name: DefaultClusterName, Kubeconfig: kubeconfig}},
+		{{Name: "", Kubeconfig: kubeconfig}},
+		{{Name: "missing", Kubeconfig: filepath.Join(t.TempDir(), "missing")}},
+	} {
+		if _, err := NewClient(WithKubeconfig(kubeconfig), WithClusters(clusters...)); err == nil {
+			t.Errorf("expected an error for clusters %+v", clusters)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package cluster_client
+
+import (
+	"context"
+	"fmt"
+
+	"helm.sh/helm/v4/pkg/action"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// DeprecationReport lists the installed releases whose manifests use APIs
+// deprecated or removed in a Kubernetes version.
+type DeprecationReport struct {
+	TargetVersion   string `json:"targetVersion"`
+	ScannedReleases int    `json:"scannedReleases"`
+	// Releases lists only the releases using deprecated APIs.
+	Releases []ReleaseDeprecations `json:"releases"`
+}
+
+// ReleaseDeprecations lists the deprecated APIs used by a release.
+type ReleaseDeprecations struct {
+	Release
+	DeprecatedAPIs []helm_parser.DeprecatedAPI `json:"deprecatedAPIs"`
+}
+
+// ScanDeprecatedAPIs parses the stored manifests of the deployed and failed
+// releases in namespace, or in all namespaces if namespace is empty, and
+// reports the resources using APIs deprecated or removed in targetVersion,
+// e.g. "1.25". targetVersion defaults to the version of the cluster.
+func (c *ClusterClient) ScanDeprecatedAPIs(ctx context.Context, namespace, targetVersion string) (*DeprecationReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if targetVersion == "" {
+		kc, err := c.kubeClient()
+		if err != nil {
+			return nil, err
+		}
+		info, err := kc.Discovery().ServerVersion()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the cluster version: %v", err)
+		}
+		targetVersion = info.GitVersion
+	}
+	target, err := helm_parser.ParseKubeVersion(targetVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := c.newConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	list := action.NewList(cfg)
+	list.AllNamespaces = namespace == ""
+	list.Deployed = true
+	list.Failed = true
+	list.SetStateMask()
+
+	results, err := list.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DeprecationReport{
+		TargetVersion:   target.String(),
+		ScannedReleases: len(results),
+		Releases:        []ReleaseDeprecations{},
+	}
+	for _, r := range results {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rel, err := toV1Release(r)
+		if err != nil {
+			return nil, err
+		}
+		resources, err := helm_parser.ParseManifestResources(rel.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("release %s/%s: %v", rel.Namespace, rel.Name, err)
+		}
+		if deprecated := helm_parser.FindDeprecatedAPIs(resources, target); len(deprecated) > 0 {
+			report.Releases = append(report.Releases, ReleaseDeprecations{
+				Release:        summarize(rel),
+				DeprecatedAPIs: deprecated,
+			})
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,70 @@
+package cluster_client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v4/pkg/release/common"
+	"k8s.io/apimachinery/pkg/version"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func TestScanDeprecatedAPIs(t *testing.T) {
+	legacy := testRelease("legacy", "apps", 1, common.StatusDeployed, time.Now())
+	legacy.Manifest = `---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+---
+apiVersion: autoscaling/v2beta2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+`
+	current := testRelease("current", "data", 1, common.StatusDeployed, time.Now())
+	current.Manifest = `---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+`
+	c := newTestClient(t, legacy, current)
+
+	report, err := c.ScanDeprecatedAPIs(context.Background(), "", "1.25")
+	if err != nil {
+		t.Fatalf("ScanDeprecatedAPIs: %v", err)
+	}
+	if report.ScannedReleases != 2 || report.TargetVersion != "1.25" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(report.Releases) != 1 || report.Releases[0].Name != "legacy" {
+		t.Fatalf("expected only release legacy, got %+v", report.Releases)
+	}
+	apis := report.Releases[0].DeprecatedAPIs
+	if len(apis) != 2 || apis[0].State != helm_parser.APIRemoved || apis[1].State != helm_parser.APIDeprecated {
+		t.Fatalf("unexpected deprecated APIs: %+v", apis)
+	}
+
+	c.kubeClient = func() (kubernetes.Interface, error) {
+		cs := kubernetesfake.NewClientset()
+		cs.Discovery().(*discoveryfake.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.22.4"}
+		return cs, nil
+	}
+	report, err = c.ScanDeprecatedAPIs(context.Background(), "apps", "")
+	if err != nil {
+		t.Fatalf("ScanDeprecatedAPIs: %v", err)
+	}
+	if report.TargetVersion != "1.22" || len(report.Releases) != 1 || len(report.Releases[0].DeprecatedAPIs) != 1 {
+		t.Fatalf("unexpected report for the cluster version: %+v", report)
+	}
+
+	if _, err := c.ScanDeprecatedAPIs(context.Background(), "", "next"); err == nil {
+		t.Fatal("expected an error for an invalid target version")
+	}
+}
@@ -0,0 +1,174 @@
+package cluster_client
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// ReleaseStatus is the Helm status of a release together with the readiness
+// of the workloads it deployed.
+type ReleaseStatus struct {
+	Release
+	Description string `json:"description,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+	// Healthy is set if every workload of the release is ready.
+	Healthy   bool             `json:"healthy"`
+	Workloads []WorkloadHealth `json:"workloads"`
+}
+
+// WorkloadHealth is the readiness of a Deployment, StatefulSet or DaemonSet.
+type WorkloadHealth struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Desired   int32  `json:"desired"`
+	Ready     int32  `json:"ready"`
+	Available int32  `json:"available"`
+	Healthy   bool   `json:"healthy"`
+	// Error is set if the workload or its pods could not be read.
+	Error string      `json:"error,omitempty"`
+	Pods  []PodHealth `json:"pods,omitempty"`
+}
+
+// PodHealth is the readiness of a pod of a workload.
+type PodHealth struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase"`
+	Ready    bool   `json:"ready"`
+	Restarts int32  `json:"restarts"`
+}
+
+// GetReleaseStatus returns the status of the latest revision of a release,
+// like "helm status", and reads the Deployments, StatefulSets and DaemonSets
+// in its manifest from the cluster to report how many of their replicas and
+// pods are ready. namespace defaults to the namespace of the kubeconfig
+// context.
+func (c *ClusterClient) GetReleaseStatus(ctx context.Context, namespace, name string) (*ReleaseStatus, error) {
+	rel, err := c.getRelease(ctx, namespace, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := helm_parser.ParseManifestResources(rel.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	kc, err := c.kubeClient()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ReleaseStatus{
+		Release:   summarize(rel),
+		Healthy:   true,
+		Workloads: []WorkloadHealth{},
+	}
+	if rel.Info != nil {
+		status.Description = rel.Info.Description
+		status.Notes = rel.Info.Notes
+	}
+	for _, r := range resources {
+		switch r.Kind {
+		case "Deployment", "StatefulSet", "DaemonSet":
+		default:
+			continue
+		}
+		ns := r.Namespace
+		if ns == "" {
+			ns = rel.Namespace
+		}
+		health := workloadHealth(ctx, kc, r.Kind, ns, r.Name)
+		status.Healthy = status.Healthy && health.Healthy
+		status.Workloads = append(status.Workloads, health)
+	}
+	return status, nil
+}
+
+// workloadHealth reads a workload and its pods. Failures are reported in the
+// result so that one unreadable workload does not hide the others.
+func workloadHealth(ctx context.Context, kc kubernetes.Interface, kind, namespace, name string) WorkloadHealth {
+	h := WorkloadHealth{Kind: kind, Name: name, Namespace: namespace}
+
+	var selector *metav1.LabelSelector
+	var err error
+	switch kind {
+	case "Deployment":
+		d, getErr := kc.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			h.Desired = replicas(d.Spec.Replicas)
+			h.Ready = d.Status.ReadyReplicas
+			h.Available = d.Status.AvailableReplicas
+			selector = d.Spec.Selector
+		}
+	case "StatefulSet":
+		s, getErr := kc.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			h.Desired = replicas(s.Spec.Replicas)
+			h.Ready = s.Status.ReadyReplicas
+			h.Available = s.Status.AvailableReplicas
+			selector = s.Spec.Selector
+		}
+	case "DaemonSet":
+		d, getErr := kc.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			h.Desired = d.Status.DesiredNumberScheduled
+			h.Ready = d.Status.NumberReady
+			h.Available = d.Status.NumberAvailable
+			selector = d.Spec.Selector
+		}
+	}
+	if apierrors.IsNotFound(err) {
+		h.Error = "not found in the cluster"
+		return h
+	}
+	if err != nil {
+		h.Error = fmt.Sprintf("failed to read %s: %v", kind, err)
+		return h
+	}
+	h.Healthy = h.Ready >= h.Desired && h.Available >= h.Desired
+
+	if selector == nil {
+		return h
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		h.Error = fmt.Sprintf("invalid selector: %v", err)
+		return h
+	}
+	pods, err := kc.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		h.Error = fmt.Sprintf("failed to list pods: %v", err)
+		return h
+	}
+	for _, pod := range pods.Items {
+		h.Pods = append(h.Pods, podHealth(&pod))
+	}
+	return h
+}
+
+func podHealth(pod *corev1.Pod) PodHealth {
+	p := PodHealth{Name: pod.Name, Phase: string(pod.Status.Phase)}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			p.Ready = cond.Status == corev1.ConditionTrue
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		p.Restarts += cs.RestartCount
+	}
+	return p
+}
+
+// replicas returns the desired number of replicas, which defaults to 1.
+func replicas(n *int32) int32 {
+	if n == nil {
+		return 1
+	}
+	return *n
+}
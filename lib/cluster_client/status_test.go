@@ -0,0 +1,94 @@
+package cluster_client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v4/pkg/release/common"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetReleaseStatus(t *testing.T) {
+	rel := testRelease("web", "apps", 1, common.StatusDeployed, time.Now())
+	rel.Info.Description = "Install complete"
+	rel.Manifest = `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+  namespace: data
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+	c := newTestClient(t, rel)
+
+	three := int32(3)
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	objects := []*appsv1.Deployment{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &three, Selector: selector},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 3, AvailableReplicas: 3},
+	}}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "data"},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 0},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "apps", Labels: map[string]string{"app": "web"}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			ContainerStatuses: []corev1.ContainerStatus{{RestartCount: 2}},
+		},
+	}
+	c.kubeClient = func() (kubernetes.Interface, error) {
+		return kubernetesfake.NewClientset(objects[0], statefulSet, pod), nil
+	}
+
+	status, err := c.GetReleaseStatus(context.Background(), "apps", "web")
+	if err != nil {
+		t.Fatalf("GetReleaseStatus: %v", err)
+	}
+	if status.Status != "deployed" || status.Description != "Install complete" || status.Healthy {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if len(status.Workloads) != 3 {
+		t.Fatalf("expected 3 workloads, got %+v", status.Workloads)
+	}
+
+	web := status.Workloads[0]
+	if !web.Healthy || web.Desired != 3 || web.Ready != 3 || web.Error != "" {
+		t.Errorf("unexpected deployment health: %+v", web)
+	}
+	if len(web.Pods) != 1 || !web.Pods[0].Ready || web.Pods[0].Restarts != 2 {
+		t.Errorf("unexpected pods: %+v", web.Pods)
+	}
+	if db := status.Workloads[1]; db.Healthy || db.Namespace != "data" || db.Desired != 1 {
+		t.Errorf("unexpected statefulset health: %+v", db)
+	}
+	if agent := status.Workloads[2]; agent.Healthy || agent.Error == "" {
+		t.Errorf("expected an error for a missing daemonset: %+v", agent)
+	}
+
+	if _, err := c.GetReleaseStatus(context.Background(), "apps", "missing"); err == nil {
+		t.Fatal("expected an error for a missing release")
+	}
+}
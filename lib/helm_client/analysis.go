@@ -0,0 +1,103 @@
+package helm_client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// renderAndAnalyze renders a chart version with customValues and runs analyze
+// on the manifest. action describes the analysis in errors, e.g. "score" or
+// "extract services of".
+func renderAndAnalyze[T any](ctx context.Context, c *HelmClient, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions, action string, analyze func(manifest string) (T, error)) (T, error) {
+	manifest, err := c.renderManifest(ctx, repoURL, chartName, version, customValues, opts)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	result, err := analyze(manifest)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("failed to %s chart %s version %s: %v", action, chartName, version, err)
+	}
+	return result, nil
+}
+
+// SummarizeChartAvailability renders a chart version with customValues and
+// summarizes what keeps its workloads available, see
+// helm_parser.SummarizeAvailability.
+func (c *HelmClient) SummarizeChartAvailability(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions) (*helm_parser.AvailabilityReport, error) {
+	return renderAndAnalyze(ctx, c, repoURL, chartName, version, customValues, opts, "summarize", helm_parser.SummarizeAvailability)
+}
+
+// EstimateChartFootprint renders a chart version with customValues and
+// estimates the resources requested by its workloads and their monthly cost,
+// see helm_parser.EstimateFootprint.
+func (c *HelmClient) EstimateChartFootprint(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, renderOpts helm_parser.RenderOptions, opts helm_parser.FootprintOptions) (*helm_parser.Footprint, error) {
+	return renderAndAnalyze(ctx, c, repoURL, chartName, version, customValues, renderOpts, "estimate the footprint of", func(manifest string) (*helm_parser.Footprint, error) {
+		return helm_parser.EstimateFootprint(manifest, opts)
+	})
+}
+
+// CheckPodSecurity renders a chart version with customValues and evaluates
+// its workloads against the Pod Security Standards, see
+// helm_parser.CheckPodSecurity.
+func (c *HelmClient) CheckPodSecurity(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions) (*helm_parser.PodSecurityReport, error) {
+	return renderAndAnalyze(ctx, c, repoURL, chartName, version, customValues, opts, "check", helm_parser.CheckPodSecurity)
+}
+
+// GetChartIngresses renders a chart version with customValues and returns
+// its Ingresses, Gateways and HTTPRoutes, see helm_parser.ExtractIngresses.
+func (c *HelmClient) GetChartIngresses(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions) (*helm_parser.IngressReport, error) {
+	return renderAndAnalyze(ctx, c, repoURL, chartName, version, customValues, opts, "extract ingresses of", helm_parser.ExtractIngresses)
+}
+
+// GetChartServices renders a chart version with customValues and returns its
+// Services and the ports it exposes outside the cluster, see
+// helm_parser.ExtractServices.
+func (c *HelmClient) GetChartServices(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions) (*helm_parser.ServiceReport, error) {
+	return renderAndAnalyze(ctx, c, repoURL, chartName, version, customValues, opts, "extract services of", helm_parser.ExtractServices)
+}
+
+// GetChartConfigUsage renders a chart version with customValues and maps
+// which of its workloads use which ConfigMaps and Secrets, see
+// helm_parser.MapConfigUsage.
+func (c *HelmClient) GetChartConfigUsage(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions) (*helm_parser.ConfigUsageReport, error) {
+	return renderAndAnalyze(ctx, c, repoURL, chartName, version, customValues, opts, "map config usage of", helm_parser.MapConfigUsage)
+}
+
+// GetChartStorage renders a chart version with customValues and returns the
+// storage it requests, see helm_parser.ExtractStorage.
+func (c *HelmClient) GetChartStorage(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions) (*helm_parser.StorageReport, error) {
+	return renderAndAnalyze(ctx, c, repoURL, chartName, version, customValues, opts, "extract storage of", helm_parser.ExtractStorage)
+}
+
+// GetChartScheduling renders a chart version with customValues and
+// summarizes the scheduling constraints of its workloads, see
+// helm_parser.SummarizeScheduling.
+func (c *HelmClient) GetChartScheduling(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions) (*helm_parser.SchedulingReport, error) {
+	return renderAndAnalyze(ctx, c, repoURL, chartName, version, customValues, opts, "summarize scheduling of", helm_parser.SummarizeScheduling)
+}
+
+// CheckChartHA renders a chart version with customValues and audits whether
+// its workloads are highly available, see helm_parser.AuditHA.
+func (c *HelmClient) CheckChartHA(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions) (*helm_parser.HAReport, error) {
+	return renderAndAnalyze(ctx, c, repoURL, chartName, version, customValues, opts, "audit HA of", helm_parser.AuditHA)
+}
+
+// CheckChartLabels renders a chart version with customValues and checks its
+// resources for the required labels, see helm_parser.CheckLabels.
+func (c *HelmClient) CheckChartLabels(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions, required []string) (*helm_parser.LabelReport, error) {
+	return renderAndAnalyze(ctx, c, repoURL, chartName, version, customValues, opts, "check labels of", func(manifest string) (*helm_parser.LabelReport, error) {
+		return helm_parser.CheckLabels(manifest, required)
+	})
+}
+
+// ScoreChart renders a chart version with customValues and checks the
+// workloads in the manifest against best practices, see
+// helm_parser.ScoreManifest.
+func (c *HelmClient) ScoreChart(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions) (*helm_parser.ScoreReport, error) {
+	return renderAndAnalyze(ctx, c, repoURL, chartName, version, customValues, opts, "score", helm_parser.ScoreManifest)
+}
@@ -0,0 +1,104 @@
+package helm_client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ChartVersion is a version of a chart with the version of the application it
+// ships.
+type ChartVersion struct {
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion"`
+}
+
+// GetChartAppVersion returns the appVersion of a chart version. For HTTP
+// repositories it is read from the index, OCI and local charts are loaded.
+func (c *HelmClient) GetChartAppVersion(ctx context.Context, repoURL, chartName, version string) (string, error) {
+	if c.IsLocal(repoURL) || IsOCI(repoURL) {
+		loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+		if err != nil {
+			return "", fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+		}
+		return loadedChart.Metadata.AppVersion, nil
+	}
+
+	versions, err := c.chartVersions(ctx, repoURL, chartName)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range versions {
+		if v.Version == version {
+			return v.AppVersion, nil
+		}
+	}
+	return "", fmt.Errorf("chart %s version %s not found in repository %s", chartName, version, repoURL)
+}
+
+// FindChartVersionsByAppVersion returns the versions of a chart shipping
+// appVersion, newest first. A partial version matches all versions it is a
+// prefix of, e.g. "11.2" matches "11.2.0" and "v11.2.1". OCI registries are not
+// supported, as they have no index to walk and every tag would have to be
+// pulled.
+func (c *HelmClient) FindChartVersionsByAppVersion(ctx context.Context, repoURL, chartName, appVersion string) ([]ChartVersion, error) {
+	if IsOCI(repoURL) {
+		return nil, fmt.Errorf("searching by app version is not supported for OCI registries, use get_chart_app_version for single versions instead")
+	}
+
+	var versions []ChartVersion
+	if c.IsLocal(repoURL) {
+		loadedChart, err := c.loadChart(ctx, repoURL, chartName, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load chart %s: %v", chartName, err)
+		}
+		versions = []ChartVersion{{Version: loadedChart.Metadata.Version, AppVersion: loadedChart.Metadata.AppVersion}}
+	} else {
+		var err error
+		if versions, err = c.chartVersions(ctx, repoURL, chartName); err != nil {
+			return nil, err
+		}
+	}
+
+	matches := []ChartVersion{}
+	for _, v := range versions {
+		if matchAppVersion(v.AppVersion, appVersion) {
+			matches = append(matches, v)
+		}
+	}
+	return matches, nil
+}
+
+// chartVersions returns the versions of a chart in an HTTP repository in the
+// order of the index, newest first.
+func (c *HelmClient) chartVersions(ctx context.Context, repoURL, chartName string) ([]ChartVersion, error) {
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return nil, err
+	}
+
+	entries, err := c.indexEntries(ctx, repoURL, chartName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chart versions: %v", err)
+	}
+	chartVersions, ok := entries[chartName]
+	if !ok || len(chartVersions) == 0 {
+		return nil, fmt.Errorf("chart %s not found in repository %s", chartName, repoURL)
+	}
+
+	versions := make([]ChartVersion, 0, len(chartVersions))
+	for _, v := range chartVersions {
+		versions = append(versions, ChartVersion{Version: v.Version, AppVersion: v.AppVersion})
+	}
+	return versions, nil
+}
+
+// matchAppVersion reports whether appVersion is want, or starts with want
+// followed by a dot. A leading "v" is ignored on both.
+func matchAppVersion(appVersion, want string) bool {
+	appVersion = strings.TrimPrefix(strings.TrimSpace(appVersion), "v")
+	want = strings.TrimPrefix(strings.TrimSpace(want), "v")
+	if want == "" {
+		return false
+	}
+	return appVersion == want || strings.HasPrefix(appVersion, want+".")
+}
@@ -0,0 +1,189 @@
+package helm_client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// BundlePlan lists everything to mirror for an air-gapped install of a chart
+// version, see PlanOfflineBundle.
+type BundlePlan struct {
+	Chart  BundleChart   `json:"chart"`
+	Images []BundleImage `json:"images"`
+	// Script is a shell script mirroring the chart and its images to
+	// $REGISTRY with curl, helm and crane.
+	Script string `json:"script"`
+}
+
+// BundleChart is the chart archive of a BundlePlan.
+type BundleChart struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// URL is the archive URL for HTTP repositories, the OCI reference for
+	// OCI registries and the chart directory for local charts.
+	URL string `json:"url"`
+	// Digest is the sha256 digest of the archive listed in the repository
+	// index, or the manifest digest for OCI registries. It is empty for local
+	// charts and indexes without digests.
+	Digest string `json:"digest,omitempty"`
+}
+
+// BundleImage is an image of a BundlePlan.
+type BundleImage struct {
+	// Image is the image as referenced by the chart.
+	Image string `json:"image"`
+	// Digest is the digest the image currently resolves to in its registry,
+	// and Pinned the image pinned to it. Error is set instead if the image
+	// could not be resolved.
+	Digest string `json:"digest,omitempty"`
+	Pinned string `json:"pinned,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Destination is the image in the target registry, if one was given.
+	Destination string `json:"destination,omitempty"`
+}
+
+// PlanOfflineBundle lists the chart archive and the images of a chart
+// version with their digests, and generates a script mirroring them to a
+// registry, the input of an air-gapped install. Images are extracted like
+// GetChartImages and resolved like VerifyImages. Images keep their repository
+// and tag below the target registry, as with helm_parser.RelocateImages, and
+// the chart is pushed to oci://<target registry>/<chart>. targetRegistry may
+// be empty, in which case the script requires $REGISTRY to be set.
+//
+// The script does not contain credentials; curl, helm and crane must be able
+// to read the sources and write to the target registry.
+func (c *HelmClient) PlanOfflineBundle(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, recursive bool, source helm_parser.ImageSource, targetRegistry string, opts helm_parser.RenderOptions) (*BundlePlan, error) {
+	if targetRegistry != "" {
+		var err error
+		if targetRegistry, err = helm_parser.ParseRegistryPrefix(targetRegistry); err != nil {
+			return nil, err
+		}
+	}
+
+	images, err := c.GetChartImages(ctx, repoURL, chartName, version, customValues, recursive, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := c.bundleChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	reportProgress(ctx, "Resolving image digests", 0, 0)
+	plan := &BundlePlan{Chart: chart, Images: make([]BundleImage, len(images))}
+	for i, status := range c.VerifyImages(ctx, images) {
+		image := BundleImage{Image: images[i].FullImage}
+		if status.Status == ImageFound {
+			image.Digest = status.Digest
+			image.Pinned = images[i].Registry + "/" + images[i].Repository + "@" + status.Digest
+		} else if status.Err != nil {
+			image.Error = fmt.Sprintf("%s: %v", status.Status, status.Err)
+		} else {
+			image.Error = status.Status
+		}
+		if targetRegistry != "" {
+			image.Destination = helm_parser.RelocatedImage(images[i], targetRegistry)
+		}
+		plan.Images[i] = image
+	}
+	plan.Script = c.bundleScript(plan, repoURL, images, targetRegistry)
+	return plan, nil
+}
+
+// bundleChart looks up the archive URL and digest of a chart version.
+func (c *HelmClient) bundleChart(ctx context.Context, repoURL, chartName, version string) (BundleChart, error) {
+	chart := BundleChart{Name: chartName, Version: version}
+	switch {
+	case c.IsLocal(repoURL):
+		chart.URL = localChartPath(repoURL)
+	case IsOCI(repoURL):
+		ref := parseOCIReference(repoURL, chartName, version)
+		chart.URL = "oci://" + ref
+
+		opCtx, cancel := withTimeout(ctx, c.options.downloadTimeout)
+		defer cancel()
+		regClient, err := c.ociClient(opCtx, repoURL)
+		if err != nil {
+			return chart, fmt.Errorf("failed to create registry client: %v", err)
+		}
+		desc, err := runWithContext(opCtx, func() (ocispec.Descriptor, error) {
+			return regClient.Resolve(ref)
+		})
+		if err != nil {
+			return chart, fmt.Errorf("failed to resolve OCI chart %s: %v", ref, timeoutErr(ctx, opCtx, c.options.downloadTimeout, err))
+		}
+		chart.Digest = desc.Digest.String()
+	default:
+		cv, err := c.indexChartVersion(ctx, repoURL, chartName, version)
+		if err != nil {
+			return chart, err
+		}
+		chart.URL = chartArchiveURL(repoURL, cv.URLs[0])
+		if cv.Digest != "" && !strings.Contains(cv.Digest, ":") {
+			chart.Digest = "sha256:" + cv.Digest
+		} else {
+			chart.Digest = cv.Digest
+		}
+	}
+	return chart, nil
+}
+
+// bundleScript returns a shell script mirroring the chart and the images of
+// plan to $REGISTRY, defaulting to targetRegistry.
+func (c *HelmClient) bundleScript(plan *BundlePlan, repoURL string, images []helm_parser.ImageReference, targetRegistry string) string {
+	var sb strings.Builder
+	chart := plan.Chart
+	archive := chart.Name + "-" + chart.Version + ".tgz"
+
+	sb.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&sb, "# Mirrors chart %s version %s and its %d images to $REGISTRY for an air-gapped install.\n", chart.Name, chart.Version, len(images))
+	sb.WriteString("# Requires curl, sha256sum, helm and crane with access to the source and target registries.\n")
+	sb.WriteString("set -eu\n\n")
+	if targetRegistry != "" {
+		fmt.Fprintf(&sb, "REGISTRY=\"${REGISTRY:-%s}\"\n\n", targetRegistry)
+	} else {
+		sb.WriteString("REGISTRY=\"${REGISTRY:?set REGISTRY to the registry to mirror to, e.g. registry.internal/mirror}\"\n\n")
+	}
+
+	sb.WriteString("# Chart\n")
+	switch {
+	case c.IsLocal(repoURL):
+		fmt.Fprintf(&sb, "helm package %s\n", shellQuote(chart.URL))
+		fmt.Fprintf(&sb, "helm push %s \"oci://$REGISTRY\"\n", shellQuote(archive))
+	case IsOCI(repoURL):
+		source := strings.TrimPrefix(chart.URL, "oci://")
+		if chart.Digest != "" {
+			source = strings.TrimSuffix(source, ":"+chart.Version) + "@" + chart.Digest
+		}
+		fmt.Fprintf(&sb, "crane copy %s \"$REGISTRY/%s:%s\"\n", shellQuote(source), chart.Name, chart.Version)
+	default:
+		fmt.Fprintf(&sb, "curl -fsSL -o %s %s\n", shellQuote(archive), shellQuote(chart.URL))
+		if hex, ok := strings.CutPrefix(chart.Digest, "sha256:"); ok {
+			fmt.Fprintf(&sb, "echo %s | sha256sum -c -\n", shellQuote(hex+"  "+archive))
+		}
+		fmt.Fprintf(&sb, "helm push %s \"oci://$REGISTRY\"\n", shellQuote(archive))
+	}
+
+	if len(images) > 0 {
+		sb.WriteString("\n# Images\n")
+	}
+	for i, image := range plan.Images {
+		source := image.Pinned
+		if source == "" {
+			fmt.Fprintf(&sb, "# %s could not be resolved (%s), copying it by tag.\n", image.Image, strings.Join(strings.Fields(image.Error), " "))
+			source = images[i].FullImage
+		}
+		fmt.Fprintf(&sb, "crane copy %s \"%s\"\n", shellQuote(source), helm_parser.RelocatedImage(images[i], "$REGISTRY"))
+	}
+	return sb.String()
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,104 @@
+package helm_client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/chart/loader"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func TestPlanOfflineBundle(t *testing.T) {
+	registry := newTestImageRegistry(t)
+	host := strings.TrimPrefix(registry.URL, "http://")
+
+	loaded, err := loader.LoadDir(strings.TrimPrefix(writeComparedChart(t, "8.5.0", host+"/single:1.0", "replicas: 1\n"), "file://"))
+	if err != nil {
+		t.Fatalf("load chart: %v", err)
+	}
+	tgzPath, err := chartutil.Save(loaded.(*chartv2.Chart), t.TempDir())
+	if err != nil {
+		t.Fatalf("save chart: %v", err)
+	}
+	tgz, err := os.ReadFile(tgzPath)
+	if err != nil {
+		t.Fatalf("read chart: %v", err)
+	}
+	sum := sha256.Sum256(tgz)
+
+	index, _ := yaml.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"entries": map[string]any{
+			"grafana": []any{map[string]any{
+				"name": "grafana", "version": "8.5.0", "urls": []string{"charts/grafana-8.5.0.tgz"}, "digest": hex.EncodeToString(sum[:]),
+			}},
+		},
+	})
+	repo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			_, _ = w.Write(index)
+		case "/charts/grafana-8.5.0.tgz":
+			_, _ = w.Write(tgz)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer repo.Close()
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true), WithPlainHTTP(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	values := map[string]any{"sidecar": map[string]any{"image": host + "/missing:2.0"}}
+	plan, err := client.PlanOfflineBundle(context.Background(), repo.URL, "grafana", "8.5.0", values, false, helm_parser.ImagesAll, "registry.internal/mirror/", helm_parser.RenderOptions{})
+	if err != nil {
+		t.Fatalf("PlanOfflineBundle() error = %v", err)
+	}
+
+	want := BundleChart{Name: "grafana", Version: "8.5.0", URL: repo.URL + "/charts/grafana-8.5.0.tgz", Digest: "sha256:" + hex.EncodeToString(sum[:])}
+	if plan.Chart != want {
+		t.Errorf("Chart = %+v, want %+v", plan.Chart, want)
+	}
+
+	images := make(map[string]BundleImage)
+	for _, image := range plan.Images {
+		images[image.Image] = image
+	}
+	single := images[host+"/single:1.0"]
+	if single.Digest == "" || single.Pinned != host+"/single@"+single.Digest || single.Destination != "registry.internal/mirror/single:1.0" {
+		t.Errorf("single image = %+v", single)
+	}
+	if missing := images[host+"/missing:2.0"]; missing.Digest != "" || !strings.HasPrefix(missing.Error, ImageMissing) {
+		t.Errorf("missing image = %+v", missing)
+	}
+
+	for _, line := range []string{
+		`REGISTRY="${REGISTRY:-registry.internal/mirror}"`,
+		"curl -fsSL -o 'grafana-8.5.0.tgz' '" + want.URL + "'",
+		"echo '" + hex.EncodeToString(sum[:]) + "  grafana-8.5.0.tgz' | sha256sum -c -",
+		`helm push 'grafana-8.5.0.tgz' "oci://$REGISTRY"`,
+		"crane copy '" + single.Pinned + `' "$REGISTRY/single:1.0"`,
+		"crane copy '" + host + `/missing:2.0' "$REGISTRY/missing:2.0"`,
+	} {
+		if !strings.Contains(plan.Script, line+"\n") {
+			t.Errorf("script does not contain %q:\n%s", line, plan.Script)
+		}
+	}
+
+	if _, err := client.PlanOfflineBundle(context.Background(), repo.URL, "grafana", "8.5.0", nil, false, helm_parser.ImagesRendered, "https://registry.internal", helm_parser.RenderOptions{}); err == nil {
+		t.Error("expected an error for an invalid target registry")
+	}
+}
@@ -0,0 +1,161 @@
+package helm_client
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// CacheInfo describes the caches of a HelmClient, to explain results that may
+// be stale or slow.
+type CacheInfo struct {
+	// Dir is the cache directory, see WithCacheDir.
+	Dir string `json:"dir"`
+	// IndexTTL is the time after which a cached index is downloaded again,
+	// empty if indexes are kept until restart.
+	IndexTTL     string           `json:"indexTTL,omitempty"`
+	Repositories []CachedIndex    `json:"repositories"`
+	Charts       ChartCacheInfo   `json:"charts"`
+	Archives     ArchiveCacheInfo `json:"archives"`
+}
+
+// CachedIndex is a repository index held in memory.
+type CachedIndex struct {
+	URL string `json:"url"`
+	// FetchedAt is the time the index was downloaded, in RFC 3339 format.
+	FetchedAt string `json:"fetchedAt"`
+	// Age is the time since the index was downloaded, e.g. 4m10s.
+	Age string `json:"age"`
+	// Expired reports whether the index is older than the index TTL and will
+	// be downloaded again on the next request.
+	Expired  bool `json:"expired"`
+	Charts   int  `json:"charts"`
+	Versions int  `json:"versions"`
+}
+
+// ChartCacheInfo describes the in-memory cache of loaded charts.
+type ChartCacheInfo struct {
+	// MaxEntries is the capacity of the cache; 0 if it is disabled.
+	MaxEntries int `json:"maxEntries"`
+	// Entries are the cached charts, most recently used first.
+	Entries []CachedChart `json:"entries"`
+}
+
+// CachedChart is a loaded chart version held in memory.
+type CachedChart struct {
+	Repository string `json:"repository"`
+	Chart      string `json:"chart"`
+	Version    string `json:"version"`
+}
+
+// ArchiveCacheInfo describes the chart archives kept on disk across restarts.
+type ArchiveCacheInfo struct {
+	Dir   string `json:"dir"`
+	Count int    `json:"count"`
+	Bytes int64  `json:"bytes"`
+}
+
+// GetCacheInfo reports the cached repository indexes, the loaded charts
+// cached in memory and the chart archives cached on disk.
+func (c *HelmClient) GetCacheInfo() (CacheInfo, error) {
+	info := CacheInfo{
+		Dir:          filepath.Dir(c.settings.ContentCache),
+		Repositories: []CachedIndex{},
+		Charts:       ChartCacheInfo{MaxEntries: max(c.options.chartCacheSize, 0), Entries: []CachedChart{}},
+		Archives:     ArchiveCacheInfo{Dir: c.settings.ContentCache},
+	}
+	if c.options.indexTTL > 0 {
+		info.IndexTTL = c.options.indexTTL.String()
+	}
+
+	c.reposMu.Lock()
+	for url, r := range c.repos {
+		index := CachedIndex{
+			URL:       url,
+			FetchedAt: r.fetchedAt.UTC().Format(time.RFC3339),
+			Age:       time.Since(r.fetchedAt).Round(time.Second).String(),
+			Expired:   c.indexExpired(r),
+		}
+		if r.repo.IndexFile != nil {
+			index.Charts = len(r.repo.IndexFile.Entries)
+			for _, versions := range r.repo.IndexFile.Entries {
+				index.Versions += len(versions)
+			}
+		}
+		info.Repositories = append(info.Repositories, index)
+	}
+	c.reposMu.Unlock()
+	sort.Slice(info.Repositories, func(i, j int) bool { return info.Repositories[i].URL < info.Repositories[j].URL })
+
+	for _, key := range c.charts.keys() {
+		info.Charts.Entries = append(info.Charts.Entries, CachedChart{Repository: key.repoURL, Chart: key.chart, Version: key.version})
+	}
+
+	err := filepath.WalkDir(c.settings.ContentCache, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		info.Archives.Count++
+		info.Archives.Bytes += fi.Size()
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return info, fmt.Errorf("failed to read the archive cache: %v", err)
+	}
+	return info, nil
+}
+
+// CacheInvalidation reports what InvalidateCache dropped.
+type CacheInvalidation struct {
+	// Indexes is the number of dropped repository indexes.
+	Indexes int `json:"indexes"`
+	// Charts is the number of dropped loaded charts.
+	Charts int `json:"charts"`
+}
+
+// InvalidateCache drops cached repository indexes, ChartMuseum API listings
+// and loaded charts, so the next request downloads them again and sees newly
+// published or re-published chart versions. An empty repoURL drops everything; otherwise the index of
+// repoURL is dropped together with its charts, restricted to chartName and
+// version if they are set.
+//
+// Chart archives on disk are kept: they are keyed by content digest, so a
+// re-published version is downloaded again once the index is refreshed.
+func (c *HelmClient) InvalidateCache(repoURL, chartName, version string) CacheInvalidation {
+	var result CacheInvalidation
+
+	c.reposMu.Lock()
+	if repoURL == "" {
+		result.Indexes = len(c.repos)
+		c.repos = nil
+	} else if _, ok := c.repos[repoURL]; ok {
+		delete(c.repos, repoURL)
+		result.Indexes = 1
+	}
+	c.reposMu.Unlock()
+
+	c.chartMuseumMu.Lock()
+	if repoURL == "" {
+		c.chartMuseum = nil
+	} else {
+		delete(c.chartMuseum, repoURL)
+	}
+	c.chartMuseumMu.Unlock()
+
+	result.Charts = c.charts.remove(func(key chartCacheKey) bool {
+		return (repoURL == "" || key.repoURL == repoURL) &&
+			(chartName == "" || key.chart == chartName) &&
+			(version == "" || key.version == version)
+	})
+	return result
+}
@@ -0,0 +1,137 @@
+package helm_client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/repo/v1"
+)
+
+func TestGetCacheInfo(t *testing.T) {
+	tgz := buildMatrixChartTGZ(t)
+	tgzPath := "/charts/" + matrixChart + "-" + matrixVersion + ".tgz"
+
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			_, _ = w.Write(createTestIndex(serverURL))
+		case tgzPath:
+			_, _ = w.Write(tgz)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	cacheDir := t.TempDir()
+	client, err := NewClient(WithCacheDir(cacheDir), WithChartCacheSize(4))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	info, err := client.GetCacheInfo()
+	if err != nil {
+		t.Fatalf("GetCacheInfo() error = %v", err)
+	}
+	if len(info.Repositories) != 0 || len(info.Charts.Entries) != 0 || info.Archives.Count != 0 {
+		t.Fatalf("expected empty caches, got %+v", info)
+	}
+
+	if _, err := client.GetChartValues(context.Background(), server.URL, matrixChart, matrixVersion); err != nil {
+		t.Fatalf("GetChartValues() error = %v", err)
+	}
+	sum := sha256.Sum256(tgz)
+	client.storeArchive(hex.EncodeToString(sum[:]), tgz)
+
+	info, err = client.GetCacheInfo()
+	if err != nil {
+		t.Fatalf("GetCacheInfo() error = %v", err)
+	}
+	if info.Dir != cacheDir {
+		t.Errorf("Dir = %q, want %q", info.Dir, cacheDir)
+	}
+	if info.IndexTTL != defaultIndexTTL.String() {
+		t.Errorf("IndexTTL = %q, want %q", info.IndexTTL, defaultIndexTTL)
+	}
+	if len(info.Repositories) != 1 {
+		t.Fatalf("got %d cached repositories, want 1", len(info.Repositories))
+	}
+	if r := info.Repositories[0]; r.URL != server.URL || r.Charts != 1 || r.Versions != 1 || r.Expired || r.FetchedAt == "" {
+		t.Errorf("unexpected cached repository %+v", r)
+	}
+	want := CachedChart{Repository: server.URL, Chart: matrixChart, Version: matrixVersion}
+	if info.Charts.MaxEntries != 4 || len(info.Charts.Entries) != 1 || info.Charts.Entries[0] != want {
+		t.Errorf("unexpected chart cache %+v, want the entry %+v", info.Charts, want)
+	}
+	if info.Archives.Count != 1 || info.Archives.Bytes != int64(len(tgz)) {
+		t.Errorf("archives = %+v, want 1 archive of %d bytes", info.Archives, len(tgz))
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "content")); err != nil {
+		t.Errorf("archive directory: %v", err)
+	}
+}
+
+func TestInvalidateCache(t *testing.T) {
+	client, err := NewClient(WithCacheDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	fill := func() {
+		client.reposMu.Lock()
+		client.repos = map[string]*cachedRepo{
+			"https://a.example.com": {repo: &repo.ChartRepository{}, fetchedAt: time.Now()},
+			"https://b.example.com": {repo: &repo.ChartRepository{}, fetchedAt: time.Now()},
+		}
+		client.reposMu.Unlock()
+		client.charts = newChartCache(8)
+		for _, key := range []chartCacheKey{
+			{repoURL: "https://a.example.com", chart: "nginx", version: "1.0.0"},
+			{repoURL: "https://a.example.com", chart: "nginx", version: "2.0.0"},
+			{repoURL: "https://a.example.com", chart: "redis", version: "1.0.0"},
+			{repoURL: "https://b.example.com", chart: "nginx", version: "1.0.0"},
+		} {
+			client.charts.add(key, &chartv2.Chart{})
+		}
+	}
+
+	tests := []struct {
+		name                    string
+		repoURL, chart, version string
+		want                    CacheInvalidation
+		wantIndexes, wantCharts int
+	}{
+		{name: "all", want: CacheInvalidation{Indexes: 2, Charts: 4}, wantIndexes: 0, wantCharts: 0},
+		{name: "repository", repoURL: "https://a.example.com", want: CacheInvalidation{Indexes: 1, Charts: 3}, wantIndexes: 1, wantCharts: 1},
+		{name: "chart", repoURL: "https://a.example.com", chart: "nginx", want: CacheInvalidation{Indexes: 1, Charts: 2}, wantIndexes: 1, wantCharts: 2},
+		{name: "chart version", repoURL: "https://a.example.com", chart: "nginx", version: "2.0.0", want: CacheInvalidation{Indexes: 1, Charts: 1}, wantIndexes: 1, wantCharts: 3},
+		{name: "unknown repository", repoURL: "https://c.example.com", want: CacheInvalidation{}, wantIndexes: 2, wantCharts: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fill()
+			got := client.InvalidateCache(tt.repoURL, tt.chart, tt.version)
+			if got != tt.want {
+				t.Errorf("InvalidateCache() = %+v, want %+v", got, tt.want)
+			}
+			if len(client.repos) != tt.wantIndexes {
+				t.Errorf("got %d cached indexes, want %d", len(client.repos), tt.wantIndexes)
+			}
+			if n := client.charts.len(); n != tt.wantCharts {
+				t.Errorf("got %d cached charts, want %d", n, tt.wantCharts)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package helm_client
+
+import (
+	"container/list"
+	"sync"
+
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// defaultChartCacheSize is the number of loaded charts kept in memory unless
+// configured otherwise with WithChartCacheSize.
+const defaultChartCacheSize = 32
+
+type chartCacheKey struct {
+	repoURL string
+	chart   string
+	version string
+}
+
+type chartCacheEntry struct {
+	key   chartCacheKey
+	chart *chartv2.Chart
+}
+
+// chartCache is a fixed-size LRU cache of loaded charts. Cached charts are
+// shared between callers and must be treated as read-only.
+type chartCache struct {
+	mu      sync.Mutex
+	maxSize int
+	ll      *list.List
+	items   map[chartCacheKey]*list.Element
+}
+
+// newChartCache returns an LRU cache holding up to maxSize charts. A
+// non-positive maxSize disables caching.
+func newChartCache(maxSize int) *chartCache {
+	return &chartCache{
+		maxSize: maxSize,
+		ll:      list.New(),
+		items:   make(map[chartCacheKey]*list.Element),
+	}
+}
+
+func (cc *chartCache) get(key chartCacheKey) (*chartv2.Chart, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	el, ok := cc.items[key]
+	if !ok {
+		return nil, false
+	}
+	cc.ll.MoveToFront(el)
+	return el.Value.(*chartCacheEntry).chart, true
+}
+
+func (cc *chartCache) add(key chartCacheKey, chart *chartv2.Chart) {
+	if cc.maxSize <= 0 {
+		return
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if el, ok := cc.items[key]; ok {
+		cc.ll.MoveToFront(el)
+		el.Value.(*chartCacheEntry).chart = chart
+		return
+	}
+
+	cc.items[key] = cc.ll.PushFront(&chartCacheEntry{key: key, chart: chart})
+	for cc.ll.Len() > cc.maxSize {
+		oldest := cc.ll.Back()
+		cc.ll.Remove(oldest)
+		delete(cc.items, oldest.Value.(*chartCacheEntry).key)
+	}
+}
+
+// keys returns the keys of the cached charts, most recently used first.
+func (cc *chartCache) keys() []chartCacheKey {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	keys := make([]chartCacheKey, 0, cc.ll.Len())
+	for el := cc.ll.Front(); el != nil; el = el.Next() {
+		keys = append(keys, el.Value.(*chartCacheEntry).key)
+	}
+	return keys
+}
+
+// remove drops the cached charts whose key matches and returns their number.
+func (cc *chartCache) remove(match func(chartCacheKey) bool) int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	removed := 0
+	for key, el := range cc.items {
+		if match(key) {
+			cc.ll.Remove(el)
+			delete(cc.items, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (cc *chartCache) len() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.ll.Len()
+}
+
+// copyChart returns a copy of ch and its subcharts that may be modified
+// without affecting cached charts. Helm actions rewrite the metadata,
+// dependencies and values of the charts they render; file contents are never
+// modified and stay shared.
+func copyChart(ch *chartv2.Chart) *chartv2.Chart {
+	cp := *ch
+	if ch.Metadata != nil {
+		md := *ch.Metadata
+		md.Dependencies = make([]*chartv2.Dependency, len(ch.Metadata.Dependencies))
+		for i, dep := range ch.Metadata.Dependencies {
+			if dep != nil {
+				d := *dep
+				md.Dependencies[i] = &d
+			}
+		}
+		cp.Metadata = &md
+	}
+	cp.Values = copyValues(ch.Values)
+
+	deps := make([]*chartv2.Chart, 0, len(ch.Dependencies()))
+	for _, dep := range ch.Dependencies() {
+		deps = append(deps, copyChart(dep))
+	}
+	cp.SetDependencies(deps...)
+	return &cp
+}
+
+func copyValues(values map[string]any) map[string]any {
+	if values == nil {
+		return nil
+	}
+	cp := make(map[string]any, len(values))
+	for k, v := range values {
+		cp[k] = copyValue(v)
+	}
+	return cp
+}
+
+func copyValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return copyValues(v)
+	case []any:
+		cp := make([]any, len(v))
+		for i, e := range v {
+			cp[i] = copyValue(e)
+		}
+		return cp
+	default:
+		return v
+	}
+}
@@ -0,0 +1,201 @@
+package helm_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestChartCacheEviction(t *testing.T) {
+	cache := newChartCache(2)
+	key := func(version string) chartCacheKey {
+		return chartCacheKey{repoURL: "https://charts.example.com", chart: "test", version: version}
+	}
+
+	cache.add(key("1"), &chartv2.Chart{})
+	cache.add(key("2"), &chartv2.Chart{})
+	// Touch "1" so that "2" becomes the least recently used entry.
+	if _, ok := cache.get(key("1")); !ok {
+		t.Fatal("expected entry 1 to be cached")
+	}
+	cache.add(key("3"), &chartv2.Chart{})
+
+	if cache.len() != 2 {
+		t.Errorf("cache.len() = %d, want 2", cache.len())
+	}
+	if _, ok := cache.get(key("2")); ok {
+		t.Error("expected least recently used entry 2 to be evicted")
+	}
+	for _, v := range []string{"1", "3"} {
+		if _, ok := cache.get(key(v)); !ok {
+			t.Errorf("expected entry %s to be cached", v)
+		}
+	}
+}
+
+func TestChartCacheDisabled(t *testing.T) {
+	cache := newChartCache(0)
+	cache.add(chartCacheKey{chart: "test"}, &chartv2.Chart{})
+
+	if cache.len() != 0 {
+		t.Errorf("cache.len() = %d, want 0", cache.len())
+	}
+}
+
+func TestLoadChartUsesCache(t *testing.T) {
+	tgz := buildMatrixChartTGZ(t)
+	tgzPath := "/charts/" + matrixChart + "-" + matrixVersion + ".tgz"
+
+	var tgzHits atomic.Int32
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			_, _ = w.Write(createTestIndex(serverURL))
+		case tgzPath:
+			tgzHits.Add(1)
+			_, _ = w.Write(tgz)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	tests := []struct {
+		name     string
+		opts     []ClientOption
+		wantHits int32
+	}{
+		{name: "enabled by default", wantHits: 1},
+		{name: "disabled", opts: []ClientOption{WithChartCacheSize(0)}, wantHits: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tgzHits.Store(0)
+			client, err := NewClient(tt.opts...)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			for range 3 {
+				if _, err := client.GetChartValues(context.Background(), server.URL, matrixChart, matrixVersion); err != nil {
+					t.Fatalf("GetChartValues() error = %v", err)
+				}
+			}
+
+			if hits := tgzHits.Load(); hits != tt.wantHits {
+				t.Errorf("chart archive downloaded %d times, want %d", hits, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestLoadChartSharesConcurrentDownloads(t *testing.T) {
+	const requests = 4
+
+	tgz := buildMatrixChartTGZ(t)
+	tgzPath := "/charts/" + matrixChart + "-" + matrixVersion + ".tgz"
+
+	var tgzHits atomic.Int32
+	release := make(chan struct{})
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			_, _ = w.Write(createTestIndex(serverURL))
+		case tgzPath:
+			tgzHits.Add(1)
+			<-release
+			_, _ = w.Write(tgz)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	// Without the chart cache every request would download the chart.
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithChartCacheSize(0))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetChartValues(context.Background(), server.URL, matrixChart, matrixVersion)
+			errs <- err
+		}()
+	}
+
+	// Hold the download until the other requests had the time to join it.
+	deadline := time.After(5 * time.Second)
+	for tgzHits.Load() == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("chart archive was not requested")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("GetChartValues() error = %v", err)
+		}
+	}
+	if hits := tgzHits.Load(); hits != 1 {
+		t.Errorf("chart archive downloaded %d times, want 1", hits)
+	}
+}
+
+func TestCopyChart(t *testing.T) {
+	sub := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "sub", Version: "1.0.0"},
+		Values:   map[string]any{"enabled": true},
+	}
+	parent := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{
+			Name:         "parent",
+			Version:      "1.0.0",
+			Dependencies: []*chartv2.Dependency{{Name: "sub", Version: "1.0.0", Condition: "sub.enabled"}},
+		},
+		Values: map[string]any{"nested": map[string]any{"list": []any{"a"}}},
+	}
+	parent.SetDependencies(sub)
+
+	cp := copyChart(parent)
+	cp.Metadata.Dependencies[0].Name = "alias"
+	cp.Metadata.Dependencies[0].Enabled = true
+	cp.Values["nested"].(map[string]any)["list"].([]any)[0] = "b"
+	cp.Dependencies()[0].Values["enabled"] = false
+	cp.SetDependencies()
+
+	if parent.Metadata.Dependencies[0].Name != "sub" || parent.Metadata.Dependencies[0].Enabled {
+		t.Error("modifying the copy changed the dependency metadata of the original")
+	}
+	if parent.Values["nested"].(map[string]any)["list"].([]any)[0] != "a" {
+		t.Error("modifying the copy changed the values of the original")
+	}
+	if len(parent.Dependencies()) != 1 || sub.Values["enabled"] != true {
+		t.Error("modifying the copy changed the subcharts of the original")
+	}
+	if sub.Parent() != parent {
+		t.Error("copying changed the parent of the original subchart")
+	}
+}
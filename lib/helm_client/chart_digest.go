@@ -0,0 +1,73 @@
+package helm_client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Types of chart digests, see ChartDigest.
+const (
+	// ChartDigestArchive is the sha256 digest of the chart archive of an
+	// HTTP repository.
+	ChartDigestArchive = "archive"
+	// ChartDigestManifest is the digest of the manifest of an OCI chart.
+	ChartDigestManifest = "manifest"
+)
+
+// ChartDigest is the digest identifying a published chart version, see
+// GetChartDigest.
+type ChartDigest struct {
+	Chart   string `json:"chart"`
+	Version string `json:"version"`
+	// Digest is in <algorithm>:<hex> form, e.g. sha256:2c26b4...
+	Digest string `json:"digest"`
+	// Type is ChartDigestArchive or ChartDigestManifest.
+	Type string `json:"type"`
+	// Reference is the OCI chart pinned to Digest, e.g.
+	// oci://ghcr.io/org/charts/app@sha256:2c26b4..., empty for HTTP
+	// repositories.
+	Reference string `json:"reference,omitempty"`
+	// Computed reports that the repository index lists no digest, so Digest
+	// was computed from the downloaded archive.
+	Computed bool `json:"computed,omitempty"`
+}
+
+// GetChartDigest returns the digest of a chart version, to pin it
+// immutably: the archive digest listed in the index of HTTP repositories, or
+// the manifest digest for OCI registries. If the index lists no digest, the
+// archive is downloaded and its digest computed. Local charts have no
+// published digest.
+func (c *HelmClient) GetChartDigest(ctx context.Context, repoURL, chartName, version string) (*ChartDigest, error) {
+	if c.IsLocal(repoURL) {
+		return nil, fmt.Errorf("local chart %s has no published digest", localChartPath(repoURL))
+	}
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return nil, err
+	}
+
+	chart, err := c.bundleChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, err
+	}
+	result := &ChartDigest{Chart: chartName, Version: version, Digest: chart.Digest, Type: ChartDigestArchive}
+	if IsOCI(repoURL) {
+		result.Type = ChartDigestManifest
+		result.Reference = strings.TrimSuffix(chart.URL, ":"+version) + "@" + chart.Digest
+		return result, nil
+	}
+	if result.Digest != "" {
+		return result, nil
+	}
+
+	data, _, err := c.httpChartArchive(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	result.Digest = "sha256:" + hex.EncodeToString(sum[:])
+	result.Computed = true
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package helm_client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestGetChartDigest(t *testing.T) {
+	tgz := buildMatrixChartTGZ(t)
+	sum := sha256.Sum256(tgz)
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithPlainHTTP(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	// The test index lists no digest, so it is computed from the archive.
+	repoURL, _ := startHTTPChartRepo(t, false, tgz)
+	digest, err := client.GetChartDigest(context.Background(), repoURL, matrixChart, matrixVersion)
+	if err != nil {
+		t.Fatalf("GetChartDigest() error = %v", err)
+	}
+	want := ChartDigest{Chart: matrixChart, Version: matrixVersion, Digest: "sha256:" + hex.EncodeToString(sum[:]), Type: ChartDigestArchive, Computed: true}
+	if *digest != want {
+		t.Errorf("GetChartDigest() = %+v, want %+v", *digest, want)
+	}
+
+	host := startOCIRegistry(t, "", "", tgz)
+	manifestDigest := buildOCIArtifact(t, "charts/"+matrixChart, matrixVersion, tgz).manifestDgst.String()
+	digest, err = client.GetChartDigest(context.Background(), "oci://"+host+"/charts", matrixChart, matrixVersion)
+	if err != nil {
+		t.Fatalf("GetChartDigest() error = %v", err)
+	}
+	want = ChartDigest{
+		Chart:     matrixChart,
+		Version:   matrixVersion,
+		Digest:    manifestDigest,
+		Type:      ChartDigestManifest,
+		Reference: "oci://" + host + "/charts/" + matrixChart + "@" + manifestDigest,
+	}
+	if *digest != want {
+		t.Errorf("GetChartDigest() = %+v, want %+v", *digest, want)
+	}
+
+	if _, err := client.GetChartDigest(context.Background(), writeComparedChart(t, "1.0.0", "nginx:1.27", ""), "grafana", "1.0.0"); err == nil {
+		t.Error("expected an error for a local chart")
+	}
+}
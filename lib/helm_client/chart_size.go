@@ -0,0 +1,98 @@
+package helm_client
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+// Default chart size limits, see WithMaxChartSize.
+const (
+	defaultMaxChartSize             = 20 << 20  // 20 MiB
+	defaultMaxDecompressedChartSize = 100 << 20 // 100 MiB
+)
+
+// checkChartSize rejects a chart archive that exceeds the configured archive
+// size or whose content exceeds the configured decompressed size, before it is
+// handed to the Helm loader. Decompression is streamed and stops at the limit,
+// so a decompression bomb is never expanded in memory.
+func (c *HelmClient) checkChartSize(data []byte) error {
+	if limit := c.options.maxChartSize; limit > 0 && int64(len(data)) > limit {
+		return fmt.Errorf("chart archive is %d bytes, larger than the maximum chart size of %d bytes", len(data), limit)
+	}
+
+	limit := c.options.maxDecompressedChartSize
+	if limit <= 0 {
+		return nil
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		// Not a gzip archive: leave the error to the loader.
+		return nil
+	}
+	defer func() { _ = gz.Close() }()
+
+	var total int64
+	tr := tar.NewReader(gz)
+	for {
+		_, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			// Corrupt archive: leave the error to the loader.
+			return nil
+		}
+
+		n, err := io.Copy(io.Discard, io.LimitReader(tr, limit-total+1))
+		total += n
+		if total > limit {
+			return fmt.Errorf("decompressed chart is larger than the maximum decompressed chart size of %d bytes", limit)
+		}
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// checkOCIChartSize fetches the manifest desc of the OCI chart ref and
+// rejects the chart if its chart layer exceeds the configured archive size,
+// so an oversized chart is not pulled at all.
+func (c *HelmClient) checkOCIChartSize(ctx context.Context, repoURL, ref string, desc ocispec.Descriptor) error {
+	limit := c.options.maxChartSize
+	if limit <= 0 {
+		return nil
+	}
+
+	repo, err := c.chartRepository(ctx, repoURL, ref)
+	if err != nil {
+		return err
+	}
+	_, data, err := fetchManifest(ctx, repo, desc.Digest.String())
+	if err != nil {
+		return fmt.Errorf("failed to fetch manifest: %v", err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("invalid manifest: %v", err)
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != registry.ChartLayerMediaType && layer.MediaType != registry.LegacyChartLayerMediaType {
+			continue
+		}
+		if layer.Size > limit {
+			return fmt.Errorf("chart archive is %d bytes, larger than the maximum chart size of %d bytes", layer.Size, limit)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package helm_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+func TestCheckChartSize(t *testing.T) {
+	tgz := buildMatrixChartTGZ(t)
+
+	tests := []struct {
+		name             string
+		archiveSize      int64
+		decompressedSize int64
+		wantErr          string
+	}{
+		{name: "default limits", archiveSize: defaultMaxChartSize, decompressedSize: defaultMaxDecompressedChartSize},
+		{name: "limits disabled", archiveSize: 0, decompressedSize: 0},
+		{name: "archive too large", archiveSize: 16, decompressedSize: 0, wantErr: "maximum chart size"},
+		{name: "decompressed content too large", archiveSize: 0, decompressedSize: 16, wantErr: "maximum decompressed chart size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(WithCacheDir(t.TempDir()), WithMaxChartSize(tt.archiveSize, tt.decompressedSize))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			err = client.checkChartSize(tgz)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkChartSize() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkChartSize() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaxChartSizeDownload(t *testing.T) {
+	tgz := buildMatrixChartTGZ(t)
+	repoURL, _ := startHTTPChartRepo(t, false, tgz)
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithMaxChartSize(int64(len(tgz)-1), 0))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	_, err = client.GetChartValues(context.Background(), repoURL, matrixChart, matrixVersion)
+	if err == nil || !strings.Contains(err.Error(), "maximum chart size") {
+		t.Errorf("GetChartValues() error = %v, want a chart size error", err)
+	}
+}
+
+func TestMaxChartSizeOCI(t *testing.T) {
+	tgz := buildMatrixChartTGZ(t)
+	artifact := buildOCIArtifact(t, "charts/"+matrixChart, matrixVersion, tgz)
+	layer := digest.FromBytes(tgz).String()
+
+	var layerHits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/blobs/"+layer) {
+			layerHits.Add(1)
+		}
+		artifact.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	repoURL := "oci://" + strings.TrimPrefix(server.URL, "http://") + "/charts/" + matrixChart
+
+	cacheDir := t.TempDir()
+	client, err := NewClient(WithCacheDir(cacheDir), WithPlainHTTP(true), WithMaxChartSize(int64(len(tgz)-1), 0))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	_, err = client.GetChartValues(context.Background(), repoURL, "", matrixVersion)
+	if err == nil || !strings.Contains(err.Error(), "maximum chart size") {
+		t.Errorf("GetChartValues() error = %v, want a chart size error", err)
+	}
+	if hits := layerHits.Load(); hits != 0 {
+		t.Errorf("oversized chart layer downloaded %d times, want 0", hits)
+	}
+}
@@ -0,0 +1,118 @@
+package helm_client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/repo/v1"
+)
+
+// ChartSummary describes the latest version of a chart in a repository, with
+// the metadata UIs need to render a chart card.
+type ChartSummary struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	AppVersion  string   `json:"appVersion,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Icon        string   `json:"icon,omitempty"`
+	Home        string   `json:"home,omitempty"`
+	Sources     []string `json:"sources,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+}
+
+func summarizeChart(m *chartv2.Metadata) ChartSummary {
+	return ChartSummary{
+		Name:        m.Name,
+		Version:     m.Version,
+		AppVersion:  m.AppVersion,
+		Description: m.Description,
+		Icon:        m.Icon,
+		Home:        m.Home,
+		Sources:     m.Sources,
+		Keywords:    m.Keywords,
+		Deprecated:  m.Deprecated,
+	}
+}
+
+// ListChartDetails lists the charts of a repository like ListCharts, with the
+// metadata of their latest versions. For HTTP repositories the metadata is
+// taken from the index; OCI and local charts are loaded.
+func (c *HelmClient) ListChartDetails(ctx context.Context, repoURL string) ([]ChartSummary, error) {
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return nil, err
+	}
+
+	if c.IsLocal(repoURL) || IsOCI(repoURL) {
+		charts, err := c.ListCharts(ctx, repoURL)
+		if err != nil {
+			return nil, err
+		}
+		summaries := make([]ChartSummary, 0, len(charts))
+		for _, chartName := range charts {
+			version, err := c.GetChartLatestVersion(ctx, repoURL, chartName)
+			if err != nil {
+				return nil, err
+			}
+			loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+			}
+			summaries = append(summaries, summarizeChart(loadedChart.Metadata))
+		}
+		return summaries, nil
+	}
+
+	entries, err := c.indexEntries(ctx, repoURL, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list charts: %v", err)
+	}
+
+	summaries := make([]ChartSummary, 0, len(entries))
+	for _, versions := range entries {
+		// IndexFile.SortEntries() sorts versions in descending order, so the
+		// first one is the latest.
+		if len(versions) > 0 && versions[0].Metadata != nil {
+			summaries = append(summaries, summarizeChart(versions[0].Metadata))
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+
+	return summaries, nil
+}
+
+// FilterChartsByKeywords returns the charts having any of keywords, compared
+// case-insensitively, e.g. to find the charts in the monitoring category.
+func FilterChartsByKeywords(charts []ChartSummary, keywords []string) []ChartSummary {
+	filtered := []ChartSummary{}
+	for _, chart := range charts {
+		if slices.ContainsFunc(chart.Keywords, func(k string) bool {
+			return slices.ContainsFunc(keywords, func(want string) bool { return strings.EqualFold(k, want) })
+		}) {
+			filtered = append(filtered, chart)
+		}
+	}
+	return filtered
+}
+
+// indexEntries returns the index entries of an HTTP repository, from the
+// ChartMuseum API if the repository provides it. chartName limits the
+// entries fetched from ChartMuseum to a single chart.
+func (c *HelmClient) indexEntries(ctx context.Context, repoURL, chartName string) (map[string]repo.ChartVersions, error) {
+	entries, ok, err := c.chartMuseumEntries(ctx, repoURL, chartName)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return entries, nil
+	}
+	helmRepo, err := c.getRepo(ctx, repoURL, repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add repository: %v", err)
+	}
+	return helmRepo.IndexFile.Entries, nil
+}
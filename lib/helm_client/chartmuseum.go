@@ -0,0 +1,186 @@
+package helm_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v4/pkg/repo/v1"
+
+	"github.com/zekker6/mcp-helm/lib/metrics"
+)
+
+// chartMuseumAPIURL returns the ChartMuseum API URL for a repository, e.g.
+// "https://cm.example.com/org" + "nginx" -> "https://cm.example.com/api/org/charts/nginx".
+// Multi-tenant ChartMuseum serves the repository path below /api.
+func chartMuseumAPIURL(repoURL string, elem ...string) (string, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid repository URL %s: %v", repoURL, err)
+	}
+	u.Path = path.Join(append([]string{"/api", u.Path, "charts"}, elem...)...)
+	return u.String(), nil
+}
+
+// chartMuseumRepo is what is known about a repository probed for the
+// ChartMuseum API: whether it is served by ChartMuseum, and the chart
+// versions listed through the API, by chart name or "" for all charts.
+type chartMuseumRepo struct {
+	isChartMuseum bool
+	entries       map[string]*chartMuseumListing
+}
+
+// chartMuseumListing is a response of the ChartMuseum API with the time it
+// was fetched. It expires like a cached index, see WithIndexTTL.
+type chartMuseumListing struct {
+	entries   map[string]repo.ChartVersions
+	fetchedAt time.Time
+}
+
+// chartMuseumEntries lists chart versions through the ChartMuseum API instead
+// of downloading the full index.yaml. If chartName is empty all charts are
+// listed. ok is false if the repository is not served by ChartMuseum, in which
+// case callers fall back to the index.
+//
+// A repository is detected as ChartMuseum once by its health endpoint; the
+// result of the detection and the listed versions are cached per repository
+// URL until the repository index is invalidated.
+func (c *HelmClient) chartMuseumEntries(ctx context.Context, repoURL, chartName string) (entries map[string]repo.ChartVersions, ok bool, err error) {
+	if !strings.HasPrefix(repoURL, "http://") && !strings.HasPrefix(repoURL, "https://") {
+		return nil, false, nil
+	}
+
+	c.chartMuseumMu.Lock()
+	cm, known := c.chartMuseum[repoURL]
+	var listing *chartMuseumListing
+	if known {
+		listing = cm.entries[chartName]
+	}
+	c.chartMuseumMu.Unlock()
+
+	if !known {
+		isChartMuseum, err := c.probeChartMuseum(ctx, repoURL)
+		if err != nil {
+			return nil, false, err
+		}
+		c.chartMuseumMu.Lock()
+		if cm, known = c.chartMuseum[repoURL]; !known {
+			if c.chartMuseum == nil {
+				c.chartMuseum = make(map[string]*chartMuseumRepo)
+			}
+			cm = &chartMuseumRepo{isChartMuseum: isChartMuseum, entries: make(map[string]*chartMuseumListing)}
+			c.chartMuseum[repoURL] = cm
+		}
+		c.chartMuseumMu.Unlock()
+	}
+	if !cm.isChartMuseum {
+		return nil, false, nil
+	}
+
+	cached := listing != nil && !c.listingExpired(listing)
+	metrics.CacheLookup(metrics.CacheIndex, cached)
+	if cached {
+		return listing.entries, true, nil
+	}
+
+	entries, err = c.fetchChartMuseumEntries(ctx, repoURL, chartName)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, false, ctx.Err()
+		}
+		return nil, true, err
+	}
+
+	c.chartMuseumMu.Lock()
+	cm.entries[chartName] = &chartMuseumListing{entries: entries, fetchedAt: time.Now()}
+	c.chartMuseumMu.Unlock()
+	return entries, true, nil
+}
+
+// listingExpired reports whether a ChartMuseum listing is older than the
+// index TTL, see indexExpired.
+func (c *HelmClient) listingExpired(l *chartMuseumListing) bool {
+	ttl := c.options.indexTTL
+	return ttl > 0 && time.Since(l.fetchedAt) > ttl
+}
+
+// probeChartMuseum reports whether repoURL is served by ChartMuseum, which
+// answers {"healthy":true} on /health at the root of the server, also for
+// multi-tenant repositories. Only a cancelled request returns an error: any
+// other failure means the repository is not served by ChartMuseum.
+func (c *HelmClient) probeChartMuseum(ctx context.Context, repoURL string) (bool, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return false, fmt.Errorf("invalid repository URL %s: %v", repoURL, err)
+	}
+	healthURL := *u
+	healthURL.Path, healthURL.RawPath = "/health", ""
+
+	opCtx, cancel := withTimeout(ctx, c.options.repoTimeout)
+	defer cancel()
+
+	g, err := c.getters(opCtx, repoURL).ByScheme(u.Scheme)
+	if err != nil {
+		return false, err
+	}
+	resp, err := g.Get(healthURL.String(), c.getterOptions(repoURL)...)
+	if err != nil {
+		return false, ctx.Err()
+	}
+
+	var health struct {
+		Healthy bool `json:"healthy"`
+	}
+	return json.Unmarshal(resp.Bytes(), &health) == nil && health.Healthy, nil
+}
+
+func (c *HelmClient) fetchChartMuseumEntries(ctx context.Context, repoURL, chartName string) (map[string]repo.ChartVersions, error) {
+	var elem []string
+	if chartName != "" {
+		elem = append(elem, chartName)
+	}
+	apiURL, err := chartMuseumAPIURL(repoURL, elem...)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL %s: %v", repoURL, err)
+	}
+	opCtx, cancel := withTimeout(ctx, c.options.repoTimeout)
+	defer cancel()
+
+	g, err := c.getters(opCtx, repoURL).ByScheme(u.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.Get(apiURL, c.getterOptions(repoURL)...)
+	if err = timeoutErr(ctx, opCtx, c.options.repoTimeout, err); err != nil {
+		return nil, fmt.Errorf("failed to query ChartMuseum API: %v", err)
+	}
+
+	entries := make(map[string]repo.ChartVersions)
+	if chartName == "" {
+		err = json.Unmarshal(resp.Bytes(), &entries)
+	} else {
+		var versions repo.ChartVersions
+		err = json.Unmarshal(resp.Bytes(), &versions)
+		entries[chartName] = versions
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ChartMuseum API response: %v", err)
+	}
+
+	// Reuse the index sorting so versions are in the same (descending semver)
+	// order as when they are read from index.yaml.
+	index := repo.NewIndexFile()
+	index.Entries = entries
+	index.SortEntries()
+	return index.Entries, nil
+}
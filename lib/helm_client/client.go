@@ -3,17 +3,22 @@ package helm_client
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
-	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 	"helm.sh/helm/v4/pkg/chart/loader"
 	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
 	"helm.sh/helm/v4/pkg/cli"
@@ -26,10 +31,17 @@ import (
 
 	"github.com/zekker6/mcp-helm/lib/helm_parser"
 	"github.com/zekker6/mcp-helm/lib/logger"
+	"github.com/zekker6/mcp-helm/lib/metrics"
 )
 
-var (
-	tmpDir = "/tmp/helm_cache"
+// defaultIndexTTL is how long a repository index is cached unless configured
+// otherwise with WithIndexTTL.
+const defaultIndexTTL = 10 * time.Minute
+
+// Default network timeouts, see WithRepoTimeout and WithDownloadTimeout.
+const (
+	defaultRepoTimeout     = 30 * time.Second
+	defaultDownloadTimeout = time.Minute
 )
 
 type ClientOption func(*clientOptions)
@@ -40,15 +52,51 @@ type clientOptions struct {
 	plainHTTP       bool
 
 	// Shared auth options (used for both OCI and HTTP repos)
-	username string
-	password string
+	username    string
+	password    string
+	bearerToken string
 
-	// HTTP repository TLS options
+	// TLS options (used for both OCI and HTTP repos, except insecureSkipTLSVerify
+	// and passCredentialsAll which only apply to HTTP repos)
 	certFile              string
 	keyFile               string
 	caFile                string
 	insecureSkipTLSVerify bool
 	passCredentialsAll    bool
+
+	// Helm downloader plugins directory
+	pluginsDirectory string
+
+	// Maximum number of loaded charts kept in memory
+	chartCacheSize int
+
+	// Directory for Helm configuration, repository indexes and chart archives
+	cacheDir string
+
+	// Time after which a downloaded repository index is re-downloaded
+	indexTTL time.Duration
+
+	// Network timeouts for index fetches and chart downloads
+	repoTimeout     time.Duration
+	downloadTimeout time.Duration
+
+	// Retries of transient download failures and the initial delay between them
+	retries      int
+	retryBackoff time.Duration
+
+	// Limits on the size of a chart archive and of its decompressed content
+	maxChartSize             int64
+	maxDecompressedChartSize int64
+
+	// Repository URL patterns, see WithAllowedRepos and WithDeniedRepos.
+	allowedRepos []string
+	deniedRepos  []string
+
+	// Repository aliases in addition to the built-in ones, see WithRepoAliases.
+	repoAliases map[string]string
+
+	// Whether charts on the local filesystem may be read, see WithLocalCharts.
+	localCharts bool
 }
 
 // WithCredentialsFile sets the path to a Docker-style credentials file for OCI registries.
@@ -67,6 +115,15 @@ func WithBasicAuth(username, password string) ClientOption {
 	}
 }
 
+// WithBearerToken sets a token sent as "Authorization: Bearer" header to HTTP
+// repositories, e.g. for repositories behind OAuth proxies or GitLab's package
+// registry. It replaces basic auth for HTTP repositories.
+func WithBearerToken(token string) ClientOption {
+	return func(o *clientOptions) {
+		o.bearerToken = token
+	}
+}
+
 // WithPlainHTTP enables plain HTTP (no TLS) for OCI registry connections.
 func WithPlainHTTP(enabled bool) ClientOption {
 	return func(o *clientOptions) {
@@ -74,15 +131,24 @@ func WithPlainHTTP(enabled bool) ClientOption {
 	}
 }
 
-// WithTLSClientConfig sets client certificate and key for mTLS authentication with HTTP repositories.
-func WithTLSClientConfig(certFile, keyFile string) ClientOption {
+// WithClientTLS sets client certificate and key for mTLS authentication with
+// both HTTP repositories and OCI registries.
+func WithClientTLS(certFile, keyFile string) ClientOption {
 	return func(o *clientOptions) {
 		o.certFile = certFile
 		o.keyFile = keyFile
 	}
 }
 
-// WithCAFile sets the CA certificate file for verifying HTTP repository server certificates.
+// WithTLSClientConfig sets client certificate and key for mTLS authentication.
+//
+// Deprecated: use WithClientTLS.
+func WithTLSClientConfig(certFile, keyFile string) ClientOption {
+	return WithClientTLS(certFile, keyFile)
+}
+
+// WithCAFile sets the CA certificate file for verifying server certificates of
+// both HTTP repositories and OCI registries.
 func WithCAFile(caFile string) ClientOption {
 	return func(o *clientOptions) {
 		o.caFile = caFile
@@ -96,6 +162,100 @@ func WithInsecureSkipTLSVerify(skip bool) ClientOption {
 	}
 }
 
+// WithPluginsDirectory sets the directory Helm downloader plugins are
+// discovered in, enabling repositories on schemes such as s3:// or gs://.
+// Defaults to Helm's plugins directory ($HELM_PLUGINS).
+func WithPluginsDirectory(dir string) ClientOption {
+	return func(o *clientOptions) {
+		o.pluginsDirectory = dir
+	}
+}
+
+// WithCacheDir sets the directory for repository indexes, downloaded chart
+// archives and temporary files; the client writes nowhere else. Archives are
+// kept across restarts, keyed by digest. Defaults to "mcp-helm" inside the user
+// cache directory.
+func WithCacheDir(dir string) ClientOption {
+	return func(o *clientOptions) {
+		o.cacheDir = dir
+	}
+}
+
+// WithIndexTTL sets how long a downloaded HTTP repository index is used
+// before it is downloaded again, so newly published chart versions become
+// visible. Zero keeps indexes until they are invalidated.
+func WithIndexTTL(ttl time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.indexTTL = ttl
+	}
+}
+
+// WithRepoTimeout limits the time spent fetching a repository index, listing
+// ChartMuseum charts or listing OCI tags. Zero disables the timeout.
+func WithRepoTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.repoTimeout = timeout
+	}
+}
+
+// WithDownloadTimeout limits the time spent downloading a single chart archive
+// from an HTTP repository or pulling it from an OCI registry. Zero disables the
+// timeout.
+func WithDownloadTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.downloadTimeout = timeout
+	}
+}
+
+// WithRetry sets how often index fetches and chart downloads are retried after
+// a transient failure (a 5xx response or a reset connection), and the delay
+// before the first retry. The delay doubles after every attempt. Zero retries
+// disables retrying.
+func WithRetry(retries int, backoff time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.retries = retries
+		o.retryBackoff = backoff
+	}
+}
+
+// WithMaxChartSize limits the size of a downloaded chart archive and the total
+// size of its decompressed content, in bytes. Charts exceeding either limit are
+// rejected before they are loaded. Zero disables the respective limit.
+func WithMaxChartSize(archiveSize, decompressedSize int64) ClientOption {
+	return func(o *clientOptions) {
+		o.maxChartSize = archiveSize
+		o.maxDecompressedChartSize = decompressedSize
+	}
+}
+
+// WithChartCacheSize sets the maximum number of loaded charts kept in an
+// in-memory LRU cache, so repeated requests for the same chart version do not
+// download it again. Zero disables the cache.
+func WithChartCacheSize(size int) ClientOption {
+	return func(o *clientOptions) {
+		o.chartCacheSize = size
+	}
+}
+
+// WithAllowedRepos restricts the client to repositories whose URL matches one
+// of patterns. In a pattern "*" matches any sequence of characters, e.g.
+// "oci://registry.internal/*". All repositories are allowed if no patterns
+// are given.
+func WithAllowedRepos(patterns ...string) ClientOption {
+	return func(o *clientOptions) {
+		o.allowedRepos = append(o.allowedRepos, patterns...)
+	}
+}
+
+// WithDeniedRepos rejects repositories whose URL matches one of patterns,
+// using the same syntax as WithAllowedRepos. Denied patterns take precedence
+// over allowed ones.
+func WithDeniedRepos(patterns ...string) ClientOption {
+	return func(o *clientOptions) {
+		o.deniedRepos = append(o.deniedRepos, patterns...)
+	}
+}
+
 // WithPassCredentialsAll enables passing credentials to all domains when following redirects.
 func WithPassCredentialsAll(pass bool) ClientOption {
 	return func(o *clientOptions) {
@@ -123,10 +283,46 @@ type HelmClient struct {
 	routeMu    sync.Mutex
 	routeCache map[string]*registry.Client
 
+	// registryOpts and registryOptsCreds are the options registryClient and
+	// registryClientCreds were created with, and registryTransport is their
+	// HTTP transport. ociClient uses them to create per-call clients.
+	registryOpts      []registry.ClientOption
+	registryOptsCreds []registry.ClientOption
+	registryTransport http.RoundTripper
+
 	options *clientOptions
 
-	reposMu sync.Mutex
-	repos   map[string]*repo.ChartRepository
+	// charts caches loaded remote charts; local charts are never cached.
+	charts *chartCache
+	// archives persists downloaded chart archives across restarts, keyed by
+	// content digest.
+	archives *downloader.DiskCache
+	// tempDir holds temporary chart downloads, so nothing is written outside
+	// the cache directory.
+	tempDir string
+	ws      *workspace
+	// snapshotDir holds the manifest snapshots of CompareManifestSnapshot,
+	// shared by the clients of a cache directory.
+	snapshotDir string
+
+	// httpClient is the HTTP client used for HTTP repositories, see httpGetter.
+	httpClient *http.Client
+
+	reposMu     sync.Mutex
+	repos       map[string]*cachedRepo
+	repoFetches singleflight.Group
+	// chartLoads shares loads of the same chart version between concurrent
+	// requests.
+	chartLoads singleflight.Group
+
+	// chartMuseum records per repository URL whether it is served by
+	// ChartMuseum and the versions listed through its API, see
+	// chartMuseumEntries.
+	chartMuseumMu sync.Mutex
+	chartMuseum   map[string]*chartMuseumRepo
+
+	allowedRepos []repoPattern
+	deniedRepos  []repoPattern
 }
 
 // NewClient creates a new HelmClient with optional configuration.
@@ -141,29 +337,92 @@ type HelmClient struct {
 // and every other registry falls back to the static basic-auth client. HTTP
 // repositories always use the basic-auth credentials (scoped per repository in
 // getRepo).
-func NewClient(opts ...ClientOption) (*HelmClient, error) {
-	options := &clientOptions{}
+//
+// Each client works in a private workspace inside the cache directory, see
+// workspace; call Close to remove it when the client is no longer needed.
+func NewClient(opts ...ClientOption) (_ *HelmClient, err error) {
+	options := &clientOptions{
+		chartCacheSize:  defaultChartCacheSize,
+		indexTTL:        defaultIndexTTL,
+		repoTimeout:     defaultRepoTimeout,
+		downloadTimeout: defaultDownloadTimeout,
+		retries:         defaultRetries,
+		retryBackoff:    defaultRetryBackoff,
+
+		maxChartSize:             defaultMaxChartSize,
+		maxDecompressedChartSize: defaultMaxDecompressedChartSize,
+	}
 	for _, opt := range opts {
 		opt(options)
 	}
 
+	cacheDir := options.cacheDir
+	if cacheDir == "" {
+		cacheDir = defaultCacheDir()
+	}
+
+	ws, err := newWorkspace(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = ws.close()
+		}
+	}()
+
 	settings := cli.New()
-	settings.RepositoryCache = path.Join(tmpDir, "helm-cache")
-	settings.RegistryConfig = path.Join(tmpDir, "helm-registry.conf")
-	settings.RepositoryConfig = path.Join(tmpDir, "helm-repository.conf")
+	settings.RepositoryCache = filepath.Join(ws.dir, "helm-cache")
+	settings.RegistryConfig = filepath.Join(cacheDir, "helm-registry.conf")
+	settings.RepositoryConfig = filepath.Join(ws.dir, "helm-repository.conf")
+	settings.ContentCache = filepath.Join(cacheDir, "content")
+	if options.pluginsDirectory != "" {
+		settings.PluginsDirectory = options.pluginsDirectory
+	}
 
 	baseOpts := []registry.ClientOption{registry.ClientOptEnableCache(true)}
 	if options.plainHTTP {
 		baseOpts = append(baseOpts, registry.ClientOptPlainHTTP())
 	}
 
+	tlsConfig, err := registryTLSConfig(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure OCI registry TLS: %w", err)
+	}
+	var registryTransport http.RoundTripper = registry.NewTransport(false)
+	if tlsConfig != nil {
+		tlsClient := newTLSHTTPClient(tlsConfig)
+		baseOpts = append(baseOpts, registry.ClientOptHTTPClient(tlsClient))
+		registryTransport = tlsClient.Transport
+	}
+
 	hasBasicAuth := options.username != "" && options.password != ""
 	hasCredsFile := options.credentialsFile != ""
 
 	client := &HelmClient{
 		settings: settings,
 		options:  options,
+		charts:   newChartCache(options.chartCacheSize),
+		archives: &downloader.DiskCache{Root: settings.ContentCache},
+		tempDir:  filepath.Join(ws.dir, "tmp"),
+		ws:       ws,
+
+		snapshotDir: filepath.Join(cacheDir, "snapshots"),
+
+		registryTransport: registryTransport,
+	}
+	for _, p := range options.allowedRepos {
+		client.allowedRepos = append(client.allowedRepos, newRepoPattern(p))
+	}
+	for _, p := range options.deniedRepos {
+		client.deniedRepos = append(client.deniedRepos, newRepoPattern(p))
+	}
+
+	httpClient, err := newHTTPClient(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
 	}
+	client.httpClient = httpClient
 
 	if hasBasicAuth && hasCredsFile {
 		// Both auth methods configured: route OCI requests per host. A host is
@@ -176,21 +435,24 @@ func NewClient(opts ...ClientOption) (*HelmClient, error) {
 			return nil, fmt.Errorf("failed to load registry credentials file %q: %w", options.credentialsFile, err)
 		}
 
-		credsClient, err := registry.NewClient(withRegistryOpts(baseOpts,
-			registry.ClientOptCredentialsFile(options.credentialsFile))...)
+		credsOpts := withRegistryOpts(baseOpts, registry.ClientOptCredentialsFile(options.credentialsFile))
+		credsClient, err := registry.NewClient(credsOpts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create OCI registry client (credentials file): %w", err)
 		}
 
-		basicClient, err := registry.NewClient(withRegistryOpts(baseOpts,
+		basicOpts := withRegistryOpts(baseOpts,
 			registry.ClientOptCredentialsFile(settings.RegistryConfig),
-			registry.ClientOptBasicAuth(options.username, options.password))...)
+			registry.ClientOptBasicAuth(options.username, options.password))
+		basicClient, err := registry.NewClient(basicOpts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create OCI registry client (basic auth): %w", err)
 		}
 
 		client.registryClient = basicClient
 		client.registryClientCreds = credsClient
+		client.registryOpts = basicOpts
+		client.registryOptsCreds = credsOpts
 		client.credStore = credStore
 		client.routeCache = make(map[string]*registry.Client)
 
@@ -224,10 +486,17 @@ func NewClient(opts ...ClientOption) (*HelmClient, error) {
 		return nil, fmt.Errorf("failed to create registry client: %w", err)
 	}
 	client.registryClient = regClient
+	client.registryOpts = regOpts
 
 	return client, nil
 }
 
+// Close removes the client's private workspace. The client must not be used
+// afterwards.
+func (c *HelmClient) Close() error {
+	return c.ws.close()
+}
+
 // withRegistryOpts returns a fresh slice combining base and extra registry
 // client options without mutating base's backing array.
 func withRegistryOpts(base []registry.ClientOption, extra ...registry.ClientOption) []registry.ClientOption {
@@ -332,9 +601,10 @@ func IsOCI(url string) bool {
 func parseOCIReference(repoURL, chartName, version string) string {
 	ref := strings.TrimPrefix(repoURL, "oci://")
 
-	// Remove any existing tag from ref for comparison
+	// Remove any existing tag from ref for comparison. Only look for it after
+	// the last "/", so a registry port such as localhost:5000 is kept.
 	refWithoutTag := ref
-	if idx := strings.Index(ref, ":"); idx != -1 {
+	if idx := strings.LastIndex(ref, ":"); idx > strings.LastIndex(ref, "/") {
 		refWithoutTag = ref[:idx]
 	}
 
@@ -372,21 +642,67 @@ func ExtractChartNameFromOCI(repoURL string) string {
 	return ""
 }
 
-func (c *HelmClient) getRepo(name, url string) (*repo.ChartRepository, error) {
+func (c *HelmClient) getRepo(ctx context.Context, name, url string) (*repo.ChartRepository, error) {
 	c.reposMu.Lock()
-	defer c.reposMu.Unlock()
+	v, exists := c.repos[name]
+	c.reposMu.Unlock()
+	cached := exists && !c.indexExpired(v)
+	metrics.CacheLookup(metrics.CacheIndex, cached)
+	if cached {
+		return v.repo, nil
+	}
+	return c.fetchRepo(ctx, name, url)
+}
 
-	if c.repos == nil {
-		c.repos = make(map[string]*repo.ChartRepository)
-	}
+// fetchRepo downloads the index of the repository at url and caches it under
+// name, replacing any cached index.
+func (c *HelmClient) fetchRepo(ctx context.Context, name, url string) (*repo.ChartRepository, error) {
+	for {
+		// Download outside the lock so indexes of different repositories are
+		// fetched concurrently, while concurrent requests for the same
+		// repository share a single download.
+		fetch := c.repoFetches.DoChan(name, func() (any, error) {
+			requestedRepo, err := c.downloadRepo(ctx, name, url)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				return nil, err
+			}
 
-	// todo: refresh index periodically based on last update time or a fixed interval
-	if v, exists := c.repos[name]; exists {
-		return v, nil
+			c.reposMu.Lock()
+			if c.repos == nil {
+				c.repos = make(map[string]*cachedRepo)
+			}
+			c.repos[name] = &cachedRepo{repo: requestedRepo, fetchedAt: time.Now()}
+			c.reposMu.Unlock()
+			return requestedRepo, nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case res := <-fetch:
+			// The shared download runs with the context of the caller that
+			// started it; if that caller went away, try again with ours.
+			if isContextError(res.Err) && ctx.Err() == nil {
+				continue
+			}
+			if res.Err != nil {
+				return nil, res.Err
+			}
+			return res.Val.(*repo.ChartRepository), nil
+		}
 	}
+}
+
+// downloadRepo downloads and parses the index of the repository at url.
+func (c *HelmClient) downloadRepo(ctx context.Context, name, url string) (*repo.ChartRepository, error) {
+	opCtx, cancel := withTimeout(ctx, c.options.repoTimeout)
+	defer cancel()
 
 	entry := &repo.Entry{
-		Name: name,
+		Name: repoCacheName(name),
 		URL:  url,
 	}
 
@@ -401,15 +717,19 @@ func (c *HelmClient) getRepo(name, url string) (*repo.ChartRepository, error) {
 		entry.PassCredentialsAll = c.options.passCredentialsAll
 	}
 
-	requestedRepo, err := repo.NewChartRepository(entry, getter.All(c.settings))
+	requestedRepo, err := repo.NewChartRepository(entry, c.getters(opCtx, url))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create chart repository: %v", err)
 	}
+	// NewChartRepository defaults to Helm's global cache ($HELM_CACHE_HOME).
+	requestedRepo.CachePath = c.settings.RepositoryCache
 
+	start := time.Now()
 	indexFileLocation, err := requestedRepo.DownloadIndexFile()
-	if err != nil {
+	if err = timeoutErr(ctx, opCtx, c.options.repoTimeout, err); err != nil {
 		return nil, fmt.Errorf("failed to download repository index: %v", err)
 	}
+	metrics.IndexDownloadDuration.Observe(time.Since(start).Seconds())
 
 	file, err := repo.LoadIndexFile(indexFileLocation)
 	if err != nil {
@@ -418,11 +738,59 @@ func (c *HelmClient) getRepo(name, url string) (*repo.ChartRepository, error) {
 	requestedRepo.IndexFile = file
 	requestedRepo.IndexFile.SortEntries()
 
-	c.repos[name] = requestedRepo
 	return requestedRepo, nil
 }
 
-func (c *HelmClient) ListCharts(repoURL string) ([]string, error) {
+// repoCacheName returns the name a repository index is cached under. The
+// repository URL cannot be used directly: it contains characters that are not
+// valid in file names on every platform, e.g. ':' on Windows.
+func repoCacheName(repoURL string) string {
+	sum := sha256.Sum256([]byte(repoURL))
+	return hex.EncodeToString(sum[:8])
+}
+
+// cachedRepo is a repository with its downloaded index and the time the
+// index was fetched.
+type cachedRepo struct {
+	repo      *repo.ChartRepository
+	fetchedAt time.Time
+}
+
+// indexExpired reports whether the cached index is older than the configured
+// TTL. A non-positive TTL keeps indexes until they are invalidated.
+func (c *HelmClient) indexExpired(r *cachedRepo) bool {
+	ttl := c.options.indexTTL
+	return ttl > 0 && time.Since(r.fetchedAt) > ttl
+}
+
+// InvalidateRepositoryIndex drops the cached index of the HTTP repository at
+// repoURL, together with what is cached from its ChartMuseum API, so the next
+// request re-downloads it and sees newly published charts. It is a no-op for
+// OCI registries and local charts, which are always queried directly.
+func (c *HelmClient) InvalidateRepositoryIndex(repoURL string) {
+	c.reposMu.Lock()
+	delete(c.repos, repoURL)
+	c.reposMu.Unlock()
+
+	c.chartMuseumMu.Lock()
+	delete(c.chartMuseum, repoURL)
+	c.chartMuseumMu.Unlock()
+}
+
+func (c *HelmClient) ListCharts(ctx context.Context, repoURL string) ([]string, error) {
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return nil, err
+	}
+
+	if c.IsLocal(repoURL) {
+		// A local chart source points to a single chart directory
+		chartName, err := c.LocalChartName(repoURL)
+		if err != nil {
+			return nil, err
+		}
+		return []string{chartName}, nil
+	}
+
 	if IsOCI(repoURL) {
 		// For OCI, each repository contains a single chart
 		// Return the chart name extracted from the URL
@@ -433,13 +801,20 @@ func (c *HelmClient) ListCharts(repoURL string) ([]string, error) {
 		return []string{chartName}, nil
 	}
 
-	helmRepo, err := c.getRepo(repoURL, repoURL)
+	entries, ok, err := c.chartMuseumEntries(ctx, repoURL, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to add repository: %v", err)
+		return nil, fmt.Errorf("failed to list charts: %v", err)
+	}
+	if !ok {
+		helmRepo, err := c.getRepo(ctx, repoURL, repoURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add repository: %v", err)
+		}
+		entries = helmRepo.IndexFile.Entries
 	}
 
 	charts := make(map[string]bool)
-	for _, entry := range helmRepo.IndexFile.Entries {
+	for _, entry := range entries {
 		for _, version := range entry {
 			if !charts[version.Name] {
 				charts[version.Name] = true
@@ -457,10 +832,22 @@ func (c *HelmClient) ListCharts(repoURL string) ([]string, error) {
 	return chartsList, nil
 }
 
-func (c *HelmClient) ListChartVersions(repoURL string, chart string) ([]string, error) {
+func (c *HelmClient) ListChartVersions(ctx context.Context, repoURL string, chart string) ([]string, error) {
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return nil, err
+	}
+
+	if c.IsLocal(repoURL) {
+		loadedChart, err := c.loadChartFromLocal(repoURL, chart, "")
+		if err != nil {
+			return nil, err
+		}
+		return []string{loadedChart.Metadata.Version}, nil
+	}
+
 	if IsOCI(repoURL) {
 		ref := parseOCIReference(repoURL, chart, "")
-		tags, err := c.registryClientFor(repoURL).Tags(ref)
+		tags, err := c.ociTags(ctx, repoURL, ref)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list tags for OCI chart %s: %v", ref, err)
 		}
@@ -468,13 +855,20 @@ func (c *HelmClient) ListChartVersions(repoURL string, chart string) ([]string,
 		return tags, nil
 	}
 
-	helmRepo, err := c.getRepo(repoURL, repoURL)
+	entries, ok, err := c.chartMuseumEntries(ctx, repoURL, chart)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add repository: %v", err)
+		return nil, fmt.Errorf("failed to list chart versions: %v", err)
+	}
+	if !ok {
+		helmRepo, err := c.getRepo(ctx, repoURL, repoURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add repository: %v", err)
+		}
+		entries = helmRepo.IndexFile.Entries
 	}
 
 	versions := make([]string, 0)
-	for k, v := range helmRepo.IndexFile.Entries {
+	for k, v := range entries {
 		if k != chart {
 			continue
 		}
@@ -488,8 +882,29 @@ func (c *HelmClient) ListChartVersions(repoURL string, chart string) ([]string,
 	return versions, nil
 }
 
-func (c *HelmClient) GetChartValues(repoURL, chartName, version string) (string, error) {
-	loadedChart, err := c.loadChart(repoURL, chartName, version)
+// ociTags lists the tags of an OCI chart, sorted in descending semver order.
+func (c *HelmClient) ociTags(ctx context.Context, repoURL, ref string) ([]string, error) {
+	opCtx, cancel := withTimeout(ctx, c.options.repoTimeout)
+	defer cancel()
+
+	regClient, err := c.ociClient(opCtx, repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %v", err)
+	}
+
+	var tags []string
+	err = retry(opCtx, c.options, func() error {
+		var err error
+		tags, err = runWithContext(opCtx, func() ([]string, error) {
+			return regClient.Tags(ref)
+		})
+		return err
+	})
+	return tags, timeoutErr(ctx, opCtx, c.options.repoTimeout, err)
+}
+
+func (c *HelmClient) GetChartValues(ctx context.Context, repoURL, chartName, version string) (string, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
 	if err != nil {
 		return "", fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
 	}
@@ -506,8 +921,8 @@ func (c *HelmClient) GetChartValues(repoURL, chartName, version string) (string,
 	return string(rawContent), nil
 }
 
-func (c *HelmClient) GetChartContents(repoURL, chartName, version string, recursive bool) (string, error) {
-	loadedChart, err := c.loadChart(repoURL, chartName, version)
+func (c *HelmClient) GetChartContents(ctx context.Context, repoURL, chartName, version string, recursive bool, filter helm_parser.ContentFilter) (string, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
 	if err != nil {
 		return "", fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
 	}
@@ -516,51 +931,216 @@ func (c *HelmClient) GetChartContents(repoURL, chartName, version string, recurs
 		return "", fmt.Errorf("chart %s version %s not found", chartName, version)
 	}
 
-	contents, err := helm_parser.GetChartContents(loadedChart, recursive)
+	reportProgress(ctx, "Collecting chart contents", 0, 0)
+	contents, err := helm_parser.GetChartContents(loadedChart, recursive, filter)
 	if err != nil {
 		return "", fmt.Errorf("failed to get chart contents for %s version %s: %v", chartName, version, err)
 	}
 	return contents, nil
 }
 
-func (c *HelmClient) loadChart(repoURL string, chartName string, version string) (*chartv2.Chart, error) {
-	if IsOCI(repoURL) {
-		return c.loadChartFromOCI(repoURL, chartName, version)
+func (c *HelmClient) ListChartFiles(ctx context.Context, repoURL, chartName, version string) ([]helm_parser.ChartFile, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
 	}
 
-	return c.loadChartFromHTTP(repoURL, chartName, version)
+	return helm_parser.ListChartFiles(loadedChart), nil
 }
 
-func (c *HelmClient) loadChartFromOCI(repoURL, chartName, version string) (*chartv2.Chart, error) {
+func (c *HelmClient) GetChartFile(ctx context.Context, repoURL, chartName, version, path string) (string, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+	}
+
+	data, err := helm_parser.GetChartFile(loadedChart, path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (c *HelmClient) loadChart(ctx context.Context, repoURL string, chartName string, version string) (*chartv2.Chart, error) {
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return nil, err
+	}
+
+	if c.IsLocal(repoURL) {
+		return c.loadChartFromLocal(repoURL, chartName, version)
+	}
+
+	key := chartCacheKey{repoURL: repoURL, chart: chartName, version: version}
+	cached, ok := c.charts.get(key)
+	metrics.CacheLookup(metrics.CacheChart, ok)
+	if ok {
+		return cached, nil
+	}
+
+	for {
+		// Concurrent requests for the same chart version share a single
+		// download, like index downloads in getRepo.
+		load := c.chartLoads.DoChan(strings.Join([]string{repoURL, chartName, version}, "\x00"), func() (any, error) {
+			var (
+				loadedChart *chartv2.Chart
+				err         error
+			)
+			if IsOCI(repoURL) {
+				loadedChart, err = c.loadChartFromOCI(ctx, repoURL, chartName, version)
+			} else {
+				loadedChart, err = c.loadChartFromHTTP(ctx, repoURL, chartName, version)
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				return nil, err
+			}
+
+			c.charts.add(key, loadedChart)
+			return loadedChart, nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case res := <-load:
+			if isContextError(res.Err) && ctx.Err() == nil {
+				continue
+			}
+			if res.Err != nil {
+				return nil, res.Err
+			}
+			return res.Val.(*chartv2.Chart), nil
+		}
+	}
+}
+
+// LoadChart returns a chart for rendering with Helm actions, e.g. to preview
+// an upgrade. The returned chart is a copy that the caller may modify.
+func (c *HelmClient) LoadChart(ctx context.Context, repoURL, chartName, version string) (*chartv2.Chart, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+	}
+	if loadedChart == nil {
+		return nil, fmt.Errorf("chart %s version %s not found", chartName, version)
+	}
+	return copyChart(loadedChart), nil
+}
+
+func (c *HelmClient) loadChartFromOCI(ctx context.Context, repoURL, chartName, version string) (*chartv2.Chart, error) {
 	ref := parseOCIReference(repoURL, chartName, version)
 
-	result, err := c.registryClientFor(repoURL).Pull(ref, registry.PullOptWithChart(true))
+	data, err := c.pullOCIChartArchive(ctx, repoURL, ref)
+	if err != nil {
+		return nil, err
+	}
+	reportProgress(ctx, "Loading chart", 0, 0)
+	if err := c.checkChartSize(data); err != nil {
+		return nil, fmt.Errorf("OCI chart %s: %v", ref, err)
+	}
+
+	loadedChart, err := loader.LoadArchive(bytes.NewReader(data))
 	if err != nil {
+		return nil, fmt.Errorf("failed to load OCI chart archive %s: %v", ref, err)
+	}
+
+	v2Chart, ok := loadedChart.(*chartv2.Chart)
+	if !ok {
+		return nil, fmt.Errorf("charts V3 format is not supported for OCI chart %s", ref)
+	}
+
+	return v2Chart, nil
+}
+
+// pullOCIChartArchive returns the chart archive for an OCI reference. The
+// manifest digest is resolved first so a chart already in the on-disk cache is
+// not pulled again; the registry verifies pulled content against the manifest,
+// so the manifest digest identifies the archive. Charts larger than the
+// maximum chart size are rejected before they are pulled and never cached.
+func (c *HelmClient) pullOCIChartArchive(ctx context.Context, repoURL, ref string) ([]byte, error) {
+	opCtx, cancel := withTimeout(ctx, c.options.downloadTimeout)
+	defer cancel()
+
+	regClient, err := c.ociClient(opCtx, repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %v", err)
+	}
+
+	desc, err := runWithContext(opCtx, func() (ocispec.Descriptor, error) {
+		return regClient.Resolve(ref)
+	})
+	if err == nil {
+		if data, ok := c.cachedArchive(desc.Digest.String()); ok {
+			return data, nil
+		}
+		if err := c.checkOCIChartSize(opCtx, repoURL, ref, desc); err != nil {
+			return nil, fmt.Errorf("OCI chart %s: %v", ref, timeoutErr(ctx, opCtx, c.options.downloadTimeout, err))
+		}
+	} else if opCtx.Err() != nil {
+		return nil, fmt.Errorf("failed to resolve OCI chart %s: %v", ref, timeoutErr(ctx, opCtx, c.options.downloadTimeout, err))
+	}
+
+	reportProgress(ctx, "Pulling "+ref, 0, 0)
+	start := time.Now()
+	var result *registry.PullResult
+	err = retry(opCtx, c.options, func() error {
+		var err error
+		result, err = runWithContext(opCtx, func() (*registry.PullResult, error) {
+			return regClient.Pull(ref, registry.PullOptWithChart(true))
+		})
+		return err
+	})
+	if err = timeoutErr(ctx, opCtx, c.options.downloadTimeout, err); err != nil {
 		return nil, fmt.Errorf("failed to pull OCI chart %s: %v", ref, err)
 	}
 
 	if result.Chart == nil || len(result.Chart.Data) == 0 {
 		return nil, fmt.Errorf("no chart data returned for OCI chart %s", ref)
 	}
+	metrics.ChartDownloadDuration.WithLabelValues("oci").Observe(time.Since(start).Seconds())
 
-	loadedChart, err := loader.LoadArchive(bytes.NewReader(result.Chart.Data))
+	if err := c.checkChartSize(result.Chart.Data); err != nil {
+		return nil, fmt.Errorf("OCI chart %s: %v", ref, err)
+	}
+	if result.Manifest != nil {
+		c.storeArchive(result.Manifest.Digest, result.Chart.Data)
+	}
+	return result.Chart.Data, nil
+}
+
+func (c *HelmClient) loadChartFromHTTP(ctx context.Context, repoURL, chartName, version string) (*chartv2.Chart, error) {
+	data, chartURL, err := c.httpChartArchive(ctx, repoURL, chartName, version)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load OCI chart archive %s: %v", ref, err)
+		return nil, err
+	}
+
+	reportProgress(ctx, "Loading chart", 0, 0)
+	if err := c.checkChartSize(data); err != nil {
+		return nil, fmt.Errorf("chart %s: %v", chartURL, err)
+	}
+
+	loadedChart, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart archive %s: %v", chartURL, err)
 	}
 
 	v2Chart, ok := loadedChart.(*chartv2.Chart)
 	if !ok {
-		return nil, fmt.Errorf("charts V3 format is not supported for OCI chart %s", ref)
+		return nil, fmt.Errorf("charts V3 format is not supported")
 	}
 
 	return v2Chart, nil
 }
 
-func (c *HelmClient) loadChartFromHTTP(repoURL, chartName, version string) (*chartv2.Chart, error) {
-	// TODO: implement caching for values file
-	helmRepo, err := c.getRepo(repoURL, repoURL)
+// httpChartArchive returns the archive of a chart version of an HTTP
+// repository and the URL it is published at, from the on-disk cache if it
+// holds the archive.
+func (c *HelmClient) httpChartArchive(ctx context.Context, repoURL, chartName, version string) ([]byte, string, error) {
+	helmRepo, err := c.getRepo(ctx, repoURL, repoURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get repository: %v", err)
+		return nil, "", fmt.Errorf("failed to get repository: %v", err)
 	}
 
 	var cv *repo.ChartVersion
@@ -580,80 +1160,122 @@ func (c *HelmClient) loadChartFromHTTP(repoURL, chartName, version string) (*cha
 		}
 	}
 	if cv == nil {
-		return nil, fmt.Errorf("failed to find chart %s version %s", chartName, version)
+		return nil, "", fmt.Errorf("failed to find chart %s version %s", chartName, version)
 	}
 
 	if len(cv.URLs) == 0 {
-		return nil, fmt.Errorf("no download URLs found for chart %s version %s", chartName, version)
+		return nil, "", fmt.Errorf("no download URLs found for chart %s version %s", chartName, version)
+	}
+
+	chartURL := chartArchiveURL(helmRepo.Config.URL, cv.URLs[0])
+
+	data, ok := c.cachedArchive(cv.Digest)
+	if !ok {
+		data, err = c.downloadChartArchive(ctx, helmRepo, chartURL, chartName, version)
+		if err != nil {
+			return nil, "", err
+		}
+		// Only cache archives whose content matches the index digest, so a
+		// cache hit is guaranteed to be the exact published chart.
+		if verifiedDigest(cv.Digest, data) {
+			c.storeArchive(cv.Digest, data)
+		}
 	}
+	return data, chartURL, nil
+}
 
-	chartURL := cv.URLs[0]
-	if !strings.HasPrefix(chartURL, "http://") && !strings.HasPrefix(chartURL, "https://") {
-		repoBaseURL := strings.TrimSuffix(helmRepo.Config.URL, "/")
-		chartURL = fmt.Sprintf("%s/%s", repoBaseURL, strings.TrimPrefix(chartURL, "/"))
+// chartArchiveURL returns the URL of a chart archive listed in the index of
+// the repository at repoURL. Relative URLs are resolved against the
+// repository. Absolute URLs may use any scheme a getter is registered for,
+// e.g. s3:// via a downloader plugin.
+func chartArchiveURL(repoURL, chartURL string) string {
+	if strings.Contains(chartURL, "://") {
+		return chartURL
 	}
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(repoURL, "/"), strings.TrimPrefix(chartURL, "/"))
+}
 
-	tempDir, err := os.MkdirTemp("", "helm-chart-")
+// downloadChartArchive downloads a chart archive from an HTTP repository (or
+// any scheme served by a downloader plugin) and returns its content.
+func (c *HelmClient) downloadChartArchive(ctx context.Context, helmRepo *repo.ChartRepository, chartURL, chartName, version string) ([]byte, error) {
+	if err := os.MkdirAll(c.tempDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %v", err)
+	}
+	tempDir, err := os.MkdirTemp(c.tempDir, "helm-chart-")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temp dir: %v", err)
 	}
 	defer func() { _ = os.RemoveAll(tempDir) }()
 
+	opCtx, cancel := withTimeout(ctx, c.options.downloadTimeout)
+	defer cancel()
+
 	chartPath := filepath.Join(tempDir, fmt.Sprintf("%s-%s", chartName, version))
 	_ = os.MkdirAll(chartPath, 0755)
 
-	// Forward the same auth options getRepo applies to the index download.
-	// The Helm SDK's ChartDownloader does not auto-discover credentials from
-	// the repo.Entry, so they must be passed explicitly here; otherwise the
-	// .tgz fetch from a private repository goes out unauthenticated even though
-	// the index download was authenticated. Empty values are no-ops, so this is
-	// safe for public repositories.
-	downloadOpts := []getter.Option{
-		getter.WithURL(helmRepo.Config.URL), // Pass repo URL for context if needed by getters
-	}
-	if c.options != nil {
-		downloadOpts = append(downloadOpts,
-			getter.WithBasicAuth(c.options.username, c.options.password),
-			getter.WithTLSClientConfig(c.options.certFile, c.options.keyFile, c.options.caFile),
-			getter.WithInsecureSkipVerifyTLS(c.options.insecureSkipTLSVerify),
-			getter.WithPassCredentialsAll(c.options.passCredentialsAll),
-		)
-	}
-
 	dl := downloader.ChartDownloader{
 		Out:              io.Discard,
 		Keyring:          "",
-		Getters:          getter.All(c.settings),
-		Options:          downloadOpts,
+		Getters:          c.chartGetters(opCtx, helmRepo.Config.URL),
+		Options:          c.getterOptions(helmRepo.Config.URL),
 		RepositoryConfig: c.settings.RepositoryConfig,
 		RepositoryCache:  c.settings.RepositoryCache,
 		ContentCache:     c.settings.ContentCache,
 		Verify:           downloader.VerifyNever,
 	}
 
+	start := time.Now()
 	chartOutputPath, _, err := dl.DownloadTo(chartURL, version, chartPath)
-	if err != nil {
+	if err = timeoutErr(ctx, opCtx, c.options.downloadTimeout, err); err != nil {
 		return nil, fmt.Errorf("failed to download chart %s version %s from %s: %v", chartName, version, chartURL, err)
 	}
+	metrics.ChartDownloadDuration.WithLabelValues("http").Observe(time.Since(start).Seconds())
 
-	// Load the downloaded chart
-	loadedChart, err := loader.Load(chartOutputPath)
+	data, err := os.ReadFile(chartOutputPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load chart from %s: %v", chartPath, err)
+		return nil, fmt.Errorf("failed to read chart from %s: %v", chartOutputPath, err)
 	}
+	return data, nil
+}
 
-	v2Chart, ok := loadedChart.(*chartv2.Chart)
-	if !ok {
-		return nil, fmt.Errorf("charts V3 format is not supported")
+// getterOptions returns the getter options for fetching content of the HTTP
+// repository at repoURL, forwarding the same auth options getRepo applies to
+// the index download. The Helm SDK's ChartDownloader does not auto-discover
+// credentials from the repo.Entry, so they must be passed explicitly; otherwise
+// the .tgz fetch from a private repository goes out unauthenticated even though
+// the index download was authenticated. Empty values are no-ops, so this is
+// safe for public repositories.
+func (c *HelmClient) getterOptions(repoURL string) []getter.Option {
+	opts := []getter.Option{
+		getter.WithURL(repoURL), // Pass repo URL for context if needed by getters
 	}
-
-	return v2Chart, nil
+	if c.options != nil {
+		opts = append(opts,
+			getter.WithBasicAuth(c.options.username, c.options.password),
+			getter.WithTLSClientConfig(c.options.certFile, c.options.keyFile, c.options.caFile),
+			getter.WithInsecureSkipVerifyTLS(c.options.insecureSkipTLSVerify),
+			getter.WithPassCredentialsAll(c.options.passCredentialsAll),
+		)
+	}
+	return opts
 }
 
-func (c *HelmClient) GetChartLatestVersion(repoURL, chartName string) (string, error) {
+func (c *HelmClient) GetChartLatestVersion(ctx context.Context, repoURL, chartName string) (string, error) {
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return "", err
+	}
+
+	if c.IsLocal(repoURL) {
+		loadedChart, err := c.loadChartFromLocal(repoURL, chartName, "")
+		if err != nil {
+			return "", err
+		}
+		return loadedChart.Metadata.Version, nil
+	}
+
 	if IsOCI(repoURL) {
 		ref := parseOCIReference(repoURL, chartName, "")
-		tags, err := c.registryClientFor(repoURL).Tags(ref)
+		tags, err := c.ociTags(ctx, repoURL, ref)
 		if err != nil {
 			return "", fmt.Errorf("failed to list tags for OCI chart %s: %v", ref, err)
 		}
@@ -664,12 +1286,19 @@ func (c *HelmClient) GetChartLatestVersion(repoURL, chartName string) (string, e
 		return tags[0], nil
 	}
 
-	helmRepo, err := c.getRepo(repoURL, repoURL)
+	entries, ok, err := c.chartMuseumEntries(ctx, repoURL, chartName)
 	if err != nil {
-		return "", fmt.Errorf("failed to get repository: %v", err)
+		return "", fmt.Errorf("failed to get chart versions: %v", err)
+	}
+	if !ok {
+		helmRepo, err := c.getRepo(ctx, repoURL, repoURL)
+		if err != nil {
+			return "", fmt.Errorf("failed to get repository: %v", err)
+		}
+		entries = helmRepo.IndexFile.Entries
 	}
 
-	chartVersions, ok := helmRepo.IndexFile.Entries[chartName]
+	chartVersions, ok := entries[chartName]
 	if !ok || len(chartVersions) == 0 {
 		return "", fmt.Errorf("chart %s not found in repository %s", chartName, repoURL)
 	}
@@ -679,17 +1308,17 @@ func (c *HelmClient) GetChartLatestVersion(repoURL, chartName string) (string, e
 	return latestVersion, nil
 }
 
-func (c *HelmClient) GetChartLatestValues(repoURL, chartName string) (string, error) {
-	v, err := c.GetChartLatestVersion(repoURL, chartName)
+func (c *HelmClient) GetChartLatestValues(ctx context.Context, repoURL, chartName string) (string, error) {
+	v, err := c.GetChartLatestVersion(ctx, repoURL, chartName)
 	if err != nil {
 		return "", fmt.Errorf("failed to get chart %s version %s: %v", chartName, v, err)
 	}
 
-	return c.GetChartValues(repoURL, chartName, v)
+	return c.GetChartValues(ctx, repoURL, chartName, v)
 }
 
-func (c *HelmClient) GetChartDependencies(repoURL, chartName, version string) ([]string, error) {
-	loadedChart, err := c.loadChart(repoURL, chartName, version)
+func (c *HelmClient) GetChartDependencies(ctx context.Context, repoURL, chartName, version string) ([]string, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
 	}
@@ -705,8 +1334,39 @@ func (c *HelmClient) GetChartDependencies(repoURL, chartName, version string) ([
 	return deps, nil
 }
 
-func (c *HelmClient) GetChartImages(repoURL, chartName, version string, customValues map[string]any, recursive bool) ([]helm_parser.ImageReference, error) {
-	loadedChart, err := c.loadChart(repoURL, chartName, version)
+// GetChartDependencyTree returns the dependencies of a chart as a tree, see
+// helm_parser.GetChartDependencyTree.
+func (c *HelmClient) GetChartDependencyTree(ctx context.Context, repoURL, chartName, version string) ([]helm_parser.DependencyNode, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+	}
+
+	if loadedChart == nil {
+		return nil, fmt.Errorf("chart %s version %s not found", chartName, version)
+	}
+
+	return helm_parser.GetChartDependencyTree(loadedChart), nil
+}
+
+// FindClusterFeatures reports the template constructs of a chart that behave
+// differently offline than against a live cluster, see
+// helm_parser.FindClusterFeatures.
+func (c *HelmClient) FindClusterFeatures(ctx context.Context, repoURL, chartName, version string, recursive bool) (helm_parser.ClusterFeatureReport, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return helm_parser.ClusterFeatureReport{}, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+	}
+
+	if loadedChart == nil {
+		return helm_parser.ClusterFeatureReport{}, fmt.Errorf("chart %s version %s not found", chartName, version)
+	}
+
+	return helm_parser.FindClusterFeatures(loadedChart, recursive), nil
+}
+
+func (c *HelmClient) GetChartImages(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, recursive bool, source helm_parser.ImageSource, opts helm_parser.RenderOptions) ([]helm_parser.ImageReference, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
 	}
@@ -715,9 +1375,55 @@ func (c *HelmClient) GetChartImages(repoURL, chartName, version string, customVa
 		return nil, fmt.Errorf("chart %s version %s not found", chartName, version)
 	}
 
-	images, err := helm_parser.GetChartImages(loadedChart, customValues, recursive)
+	reportProgress(ctx, "Rendering templates and extracting images", 0, 0)
+	// Rendering does not take a context; stop waiting for it on cancellation.
+	images, err := runWithContext(ctx, func() ([]helm_parser.ImageReference, error) {
+		return helm_parser.GetChartImages(loadedChart, customValues, recursive, source, opts)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract images from chart %s version %s: %v", chartName, version, err)
 	}
 	return images, nil
 }
+
+// RelocateChartImages computes the values overrides repointing the images of
+// a chart version to the registry prefix, see helm_parser.RelocateImages.
+func (c *HelmClient) RelocateChartImages(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, prefix string, recursive bool, opts helm_parser.RenderOptions) (*helm_parser.ImageRelocation, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+	}
+
+	if loadedChart == nil {
+		return nil, fmt.Errorf("chart %s version %s not found", chartName, version)
+	}
+
+	reportProgress(ctx, "Rendering templates and relocating images", 0, 0)
+	// Rendering does not take a context; stop waiting for it on cancellation.
+	relocation, err := runWithContext(ctx, func() (*helm_parser.ImageRelocation, error) {
+		return helm_parser.RelocateImages(loadedChart, customValues, prefix, recursive, opts)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to relocate images of chart %s version %s: %v", chartName, version, err)
+	}
+	return relocation, nil
+}
+
+// renderManifest renders a chart version with customValues into a manifest,
+// see helm_parser.RenderManifest.
+func (c *HelmClient) renderManifest(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions) (string, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+	}
+
+	reportProgress(ctx, "Rendering templates", 0, 0)
+	// Rendering does not take a context; stop waiting for it on cancellation.
+	manifest, err := runWithContext(ctx, func() (string, error) {
+		return helm_parser.RenderManifest(loadedChart, customValues, opts)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render chart %s version %s: %v", chartName, version, err)
+	}
+	return manifest, nil
+}
@@ -1,11 +1,22 @@
 package helm_client
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -21,6 +32,7 @@ type testChartEntry struct {
 	Name    string   `json:"name"`
 	Version string   `json:"version"`
 	URLs    []string `json:"urls"`
+	Digest  string   `json:"digest,omitempty"`
 }
 
 func createTestIndex(baseURL string) []byte {
@@ -73,7 +85,7 @@ func TestBasicAuthRequired(t *testing.T) {
 			t.Fatalf("NewClient() error = %v", err)
 		}
 
-		_, err = client.ListCharts(server.URL)
+		_, err = client.ListCharts(context.Background(), server.URL)
 		if err == nil {
 			t.Error("expected error when accessing protected repo without auth")
 		}
@@ -85,7 +97,7 @@ func TestBasicAuthRequired(t *testing.T) {
 			t.Fatalf("NewClient() error = %v", err)
 		}
 
-		_, err = client.ListCharts(server.URL)
+		_, err = client.ListCharts(context.Background(), server.URL)
 		if err == nil {
 			t.Error("expected error when accessing protected repo with wrong credentials")
 		}
@@ -97,7 +109,7 @@ func TestBasicAuthRequired(t *testing.T) {
 			t.Fatalf("NewClient() error = %v", err)
 		}
 
-		charts, err := client.ListCharts(server.URL)
+		charts, err := client.ListCharts(context.Background(), server.URL)
 		if err != nil {
 			t.Fatalf("ListCharts() error = %v", err)
 		}
@@ -138,7 +150,7 @@ func TestInsecureSkipTLSVerify(t *testing.T) {
 			t.Fatalf("NewClient() error = %v", err)
 		}
 
-		_, err = client.ListCharts(server.URL)
+		_, err = client.ListCharts(context.Background(), server.URL)
 		if err == nil {
 			t.Error("expected TLS verification error with self-signed cert")
 		}
@@ -150,7 +162,7 @@ func TestInsecureSkipTLSVerify(t *testing.T) {
 			t.Fatalf("NewClient() error = %v", err)
 		}
 
-		charts, err := client.ListCharts(server.URL)
+		charts, err := client.ListCharts(context.Background(), server.URL)
 		if err != nil {
 			t.Fatalf("ListCharts() error = %v", err)
 		}
@@ -202,7 +214,7 @@ func TestCredentialsFile(t *testing.T) {
 		t.Fatalf("NewClient() error = %v", err)
 	}
 
-	_, err = client.ListCharts(serverURL)
+	_, err = client.ListCharts(context.Background(), serverURL)
 	if err != nil {
 		t.Fatalf("ListCharts() error = %v", err)
 	}
@@ -413,7 +425,7 @@ func TestCombinedOptions(t *testing.T) {
 			t.Fatalf("NewClient() error = %v", err)
 		}
 
-		_, err = client.ListCharts(server.URL)
+		_, err = client.ListCharts(context.Background(), server.URL)
 		if err == nil {
 			t.Error("expected TLS verification error")
 		}
@@ -425,7 +437,7 @@ func TestCombinedOptions(t *testing.T) {
 			t.Fatalf("NewClient() error = %v", err)
 		}
 
-		_, err = client.ListCharts(server.URL)
+		_, err = client.ListCharts(context.Background(), server.URL)
 		if err == nil {
 			t.Error("expected auth error")
 		}
@@ -440,7 +452,7 @@ func TestCombinedOptions(t *testing.T) {
 			t.Fatalf("NewClient() error = %v", err)
 		}
 
-		charts, err := client.ListCharts(server.URL)
+		charts, err := client.ListCharts(context.Background(), server.URL)
 		if err != nil {
 			t.Fatalf("ListCharts() error = %v", err)
 		}
@@ -450,3 +462,212 @@ func TestCombinedOptions(t *testing.T) {
 		}
 	})
 }
+
+// writeServerCA writes the certificate of a TLS test server as a PEM CA bundle.
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	path := filepath.Join(t.TempDir(), "ca.crt")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write CA file: %v", err)
+	}
+	return path
+}
+
+func TestOCIRegistryCAFile(t *testing.T) {
+	tgz := buildMatrixChartTGZ(t)
+	server := httptest.NewTLSServer(buildOCIArtifact(t, "charts/"+matrixChart, matrixVersion, tgz))
+	defer server.Close()
+
+	repoURL := "oci://" + strings.TrimPrefix(server.URL, "https://") + "/charts/" + matrixChart
+
+	t.Run("without CA file fails", func(t *testing.T) {
+		client, err := NewClient()
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		_, err = client.ListChartVersions(context.Background(), repoURL, "")
+		if err == nil {
+			t.Error("expected TLS verification error with self-signed cert")
+		}
+	})
+
+	t.Run("with CA file succeeds", func(t *testing.T) {
+		client, err := NewClient(WithCAFile(writeServerCA(t, server)))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		versions, err := client.ListChartVersions(context.Background(), repoURL, "")
+		if err != nil {
+			t.Fatalf("ListChartVersions() error = %v", err)
+		}
+		if !slices.Contains(versions, matrixVersion) {
+			t.Fatalf("expected version %q in %v", matrixVersion, versions)
+		}
+	})
+
+	t.Run("invalid CA file fails client creation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ca.crt")
+		if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+			t.Fatalf("write CA file: %v", err)
+		}
+
+		if _, err := NewClient(WithCAFile(path)); err == nil {
+			t.Error("expected error for CA file without certificates")
+		}
+	})
+}
+
+// writeClientKeyPair generates a self-signed client certificate and writes it
+// and its key as PEM files.
+func writeClientKeyPair(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mcp-helm-test-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600); err != nil {
+		t.Fatalf("write client cert: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write client key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestOCIRegistryClientTLS(t *testing.T) {
+	tgz := buildMatrixChartTGZ(t)
+	server := httptest.NewUnstartedServer(buildOCIArtifact(t, "charts/"+matrixChart, matrixVersion, tgz))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	repoURL := "oci://" + strings.TrimPrefix(server.URL, "https://") + "/charts/" + matrixChart
+	caFile := writeServerCA(t, server)
+
+	t.Run("without client certificate fails", func(t *testing.T) {
+		client, err := NewClient(WithCAFile(caFile))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		_, err = client.ListChartVersions(context.Background(), repoURL, "")
+		if err == nil {
+			t.Error("expected TLS handshake error without client certificate")
+		}
+	})
+
+	t.Run("with client certificate succeeds", func(t *testing.T) {
+		certFile, keyFile := writeClientKeyPair(t)
+		client, err := NewClient(WithCAFile(caFile), WithClientTLS(certFile, keyFile))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		versions, err := client.ListChartVersions(context.Background(), repoURL, "")
+		if err != nil {
+			t.Fatalf("ListChartVersions() error = %v", err)
+		}
+		if !slices.Contains(versions, matrixVersion) {
+			t.Fatalf("expected version %q in %v", matrixVersion, versions)
+		}
+	})
+}
+
+func TestBearerTokenAuth(t *testing.T) {
+	const validToken = "test-token"
+
+	tgz := buildMatrixChartTGZ(t)
+	tgzPath := "/charts/" + matrixChart + "-" + matrixVersion + ".tgz"
+
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+validToken {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/index.yaml":
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = w.Write(createTestIndex(serverURL))
+		case tgzPath:
+			w.Header().Set("Content-Type", "application/gzip")
+			_, _ = w.Write(tgz)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	t.Run("without token fails", func(t *testing.T) {
+		client, err := NewClient()
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		_, err = client.ListCharts(context.Background(), server.URL)
+		if err == nil {
+			t.Error("expected error when accessing protected repo without token")
+		}
+	})
+
+	t.Run("with wrong token fails", func(t *testing.T) {
+		client, err := NewClient(WithBearerToken("wrong-token"))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		_, err = client.ListCharts(context.Background(), server.URL)
+		if err == nil {
+			t.Error("expected error when accessing protected repo with wrong token")
+		}
+	})
+
+	t.Run("with correct token succeeds", func(t *testing.T) {
+		client, err := NewClient(WithBearerToken(validToken))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		charts, err := client.ListCharts(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("ListCharts() error = %v", err)
+		}
+		if !slices.Contains(charts, matrixChart) {
+			t.Fatalf("expected to find %q, got %v", matrixChart, charts)
+		}
+
+		values, err := client.GetChartValues(context.Background(), server.URL, matrixChart, matrixVersion)
+		if err != nil {
+			t.Fatalf("GetChartValues() error = %v", err)
+		}
+		if !strings.Contains(values, matrixMarker) {
+			t.Errorf("expected chart values to contain %q, got: %q", matrixMarker, values)
+		}
+	})
+}
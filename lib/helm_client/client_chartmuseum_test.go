@@ -0,0 +1,176 @@
+package helm_client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChartMuseumAPIURL(t *testing.T) {
+	tests := []struct {
+		repoURL string
+		elem    []string
+		want    string
+	}{
+		{repoURL: "https://cm.example.com", want: "https://cm.example.com/api/charts"},
+		{repoURL: "https://cm.example.com/", elem: []string{"nginx"}, want: "https://cm.example.com/api/charts/nginx"},
+		{repoURL: "https://cm.example.com/org/repo", elem: []string{"nginx"}, want: "https://cm.example.com/api/org/repo/charts/nginx"},
+	}
+
+	for _, tt := range tests {
+		got, err := chartMuseumAPIURL(tt.repoURL, tt.elem...)
+		if err != nil {
+			t.Fatalf("chartMuseumAPIURL(%q) error = %v", tt.repoURL, err)
+		}
+		if got != tt.want {
+			t.Errorf("chartMuseumAPIURL(%q, %v) = %q, want %q", tt.repoURL, tt.elem, got, tt.want)
+		}
+	}
+}
+
+// startChartMuseum serves the ChartMuseum chart listing API for tenant path
+// prefix and counts index.yaml downloads, which must not happen, and API
+// requests.
+func startChartMuseum(t *testing.T, prefix string) (string, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+
+	charts := map[string][]testChartEntry{
+		"alpha": {{Name: "alpha", Version: "1.0.0"}, {Name: "alpha", Version: "1.10.0"}, {Name: "alpha", Version: "1.2.0"}},
+		"beta":  {{Name: "beta", Version: "0.1.0"}},
+	}
+
+	var indexHits, apiHits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			apiHits.Add(1)
+		}
+		switch r.URL.Path {
+		case "/health":
+			_, _ = w.Write([]byte(`{"healthy":true}`))
+		case prefix + "/index.yaml":
+			indexHits.Add(1)
+			http.Error(w, "index.yaml should not be downloaded", http.StatusInternalServerError)
+		case "/api" + prefix + "/charts":
+			_ = json.NewEncoder(w).Encode(charts)
+		case "/api" + prefix + "/charts/alpha":
+			_ = json.NewEncoder(w).Encode(charts["alpha"])
+		case "/api" + prefix + "/charts/beta":
+			_ = json.NewEncoder(w).Encode(charts["beta"])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + prefix, &indexHits, &apiHits
+}
+
+func TestChartMuseumListing(t *testing.T) {
+	for _, prefix := range []string{"", "/org/repo"} {
+		t.Run("prefix="+prefix, func(t *testing.T) {
+			repoURL, indexHits, _ := startChartMuseum(t, prefix)
+			client := newTestClient(t)
+
+			charts, err := client.ListCharts(context.Background(), repoURL)
+			if err != nil {
+				t.Fatalf("ListCharts() error = %v", err)
+			}
+			if !slices.Equal(charts, []string{"alpha", "beta"}) {
+				t.Errorf("ListCharts() = %v, want [alpha beta]", charts)
+			}
+
+			versions, err := client.ListChartVersions(context.Background(), repoURL, "alpha")
+			if err != nil {
+				t.Fatalf("ListChartVersions() error = %v", err)
+			}
+			if want := []string{"1.10.0", "1.2.0", "1.0.0"}; !slices.Equal(versions, want) {
+				t.Errorf("ListChartVersions() = %v, want %v", versions, want)
+			}
+
+			latest, err := client.GetChartLatestVersion(context.Background(), repoURL, "alpha")
+			if err != nil {
+				t.Fatalf("GetChartLatestVersion() error = %v", err)
+			}
+			if latest != "1.10.0" {
+				t.Errorf("GetChartLatestVersion() = %q, want %q", latest, "1.10.0")
+			}
+
+			if _, err := client.GetChartLatestVersion(context.Background(), repoURL, "missing"); err == nil {
+				t.Error("expected error for chart missing from ChartMuseum")
+			}
+
+			if hits := indexHits.Load(); hits != 0 {
+				t.Errorf("index.yaml was downloaded %d times, want 0", hits)
+			}
+		})
+	}
+}
+
+func TestChartMuseumListingCached(t *testing.T) {
+	repoURL, _, apiHits := startChartMuseum(t, "")
+	client, err := NewClient(WithIndexTTL(time.Hour))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	for range 3 {
+		if _, err := client.ListChartVersions(context.Background(), repoURL, "alpha"); err != nil {
+			t.Fatalf("ListChartVersions() error = %v", err)
+		}
+	}
+	if hits := apiHits.Load(); hits != 1 {
+		t.Errorf("ChartMuseum API queried %d times, want 1", hits)
+	}
+
+	client.InvalidateRepositoryIndex(repoURL)
+	if _, err := client.ListChartVersions(context.Background(), repoURL, "alpha"); err != nil {
+		t.Fatalf("ListChartVersions() error = %v", err)
+	}
+	client.InvalidateCache("", "", "")
+	if _, err := client.ListChartVersions(context.Background(), repoURL, "alpha"); err != nil {
+		t.Fatalf("ListChartVersions() error = %v", err)
+	}
+	if hits := apiHits.Load(); hits != 3 {
+		t.Errorf("ChartMuseum API queried %d times after invalidation, want 3", hits)
+	}
+}
+
+func TestChartMuseumProbedOnce(t *testing.T) {
+	var indexHits, otherHits atomic.Int32
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index.yaml" {
+			indexHits.Add(1)
+			_, _ = w.Write(createTestIndex(serverURL))
+			return
+		}
+		otherHits.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	client, err := NewClient(WithIndexTTL(time.Hour))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	for _, chart := range []string{"test-chart", "missing", "test-chart", "other"} {
+		_, _ = client.ListChartVersions(context.Background(), server.URL, chart)
+	}
+	if _, err := client.ListCharts(context.Background(), server.URL); err != nil {
+		t.Fatalf("ListCharts() error = %v", err)
+	}
+
+	if hits := otherHits.Load(); hits != 1 {
+		t.Errorf("server received %d requests besides index.yaml, want a single ChartMuseum probe", hits)
+	}
+	if hits := indexHits.Load(); hits != 1 {
+		t.Errorf("index downloaded %d times, want 1", hits)
+	}
+}
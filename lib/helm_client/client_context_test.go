@@ -0,0 +1,179 @@
+package helm_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestContextCancellation(t *testing.T) {
+	var (
+		serverURL string
+		block     atomic.Bool
+		aborted   = make(chan struct{}, 1)
+	)
+	block.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		if block.Load() {
+			// Hold the index download until the client gives up on it.
+			<-r.Context().Done()
+			aborted <- struct{}{}
+			return
+		}
+		_, _ = w.Write(createTestIndex(serverURL))
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	client, err := NewClient(WithCacheDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	t.Run("deadline aborts index download", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := client.ListCharts(ctx, server.URL)
+		// Client methods wrap errors with %v, so match the message.
+		if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+			t.Fatalf("ListCharts() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+
+		select {
+		case <-aborted:
+		case <-time.After(5 * time.Second):
+			t.Fatal("index download was not aborted on the server side")
+		}
+	})
+
+	t.Run("cancelled request is not cached", func(t *testing.T) {
+		block.Store(false)
+
+		charts, err := client.ListCharts(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("ListCharts() error = %v", err)
+		}
+		if len(charts) == 0 {
+			t.Error("ListCharts() returned no charts")
+		}
+	})
+
+	t.Run("cancelled context fails before any request", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.GetChartValues(ctx, "oci://localhost:1/charts/nginx", "nginx", "1.0.0")
+		if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+			t.Errorf("GetChartValues() error = %v, want %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestOCIRequestCancellation(t *testing.T) {
+	aborted := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hold every registry request until the client gives up on it.
+		<-r.Context().Done()
+		aborted <- r.URL.Path
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithPlainHTTP(true), WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	repoURL := "oci://" + strings.TrimPrefix(server.URL, "http://") + "/charts/nginx"
+
+	calls := map[string]func(ctx context.Context) error{
+		"tags": func(ctx context.Context) error {
+			_, err := client.ListChartVersions(ctx, repoURL, "nginx")
+			return err
+		},
+		"pull": func(ctx context.Context) error {
+			_, err := client.GetChartValues(ctx, repoURL, "nginx", "1.0.0")
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				cancel()
+			}()
+
+			if err := call(ctx); err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+				t.Errorf("error = %v, want %v", err, context.Canceled)
+			}
+
+			select {
+			case <-aborted:
+			case <-time.After(5 * time.Second):
+				t.Fatal("registry request was not aborted on the server side")
+			}
+		})
+	}
+}
+
+func TestNetworkTimeouts(t *testing.T) {
+	tgzPath := "/charts/" + matrixChart + "-" + matrixVersion + ".tgz"
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow/index.yaml", tgzPath:
+			<-r.Context().Done()
+		case "/index.yaml":
+			_, _ = w.Write(createTestIndex(serverURL))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	tests := []struct {
+		name string
+		opts []ClientOption
+		call func(c *HelmClient) error
+	}{
+		{
+			name: "repository timeout",
+			opts: []ClientOption{WithRepoTimeout(100 * time.Millisecond)},
+			call: func(c *HelmClient) error {
+				_, err := c.ListCharts(context.Background(), server.URL+"/slow")
+				return err
+			},
+		},
+		{
+			name: "download timeout",
+			opts: []ClientOption{WithDownloadTimeout(100 * time.Millisecond)},
+			call: func(c *HelmClient) error {
+				_, err := c.GetChartValues(context.Background(), server.URL, matrixChart, matrixVersion)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(append(tt.opts, WithCacheDir(t.TempDir()))...)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			err = tt.call(client)
+			if err == nil || !strings.Contains(err.Error(), "timed out after 100ms") {
+				t.Errorf("error = %v, want a timeout error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package helm_client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// newTestImageRegistry serves a multi-platform image "multi:1.0" and a
+// single-platform image "single:1.0". The "private" repository requires
+// credentials.
+func newTestImageRegistry(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	config := []byte(`{"architecture":"arm64","os":"linux","variant":"v8"}`)
+	configDesc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: digest.FromBytes(config), Size: int64(len(config))}
+	manifest, _ := json.Marshal(ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: configDesc})
+	index, _ := json.Marshal(ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{
+			{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.FromBytes(manifest), Size: int64(len(manifest)), Platform: &ocispec.Platform{OS: "linux", Architecture: "amd64"}},
+			{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.FromBytes(manifest), Size: int64(len(manifest)), Platform: &ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}},
+			{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.FromBytes(manifest), Size: int64(len(manifest)), Platform: &ocispec.Platform{OS: "unknown", Architecture: "unknown"}},
+		},
+	})
+
+	content := map[string]struct {
+		mediaType string
+		data      []byte
+	}{
+		"/v2/multi/manifests/1.0":                        {ocispec.MediaTypeImageIndex, index},
+		"/v2/single/manifests/1.0":                       {ocispec.MediaTypeImageManifest, manifest},
+		"/v2/single/blobs/" + configDesc.Digest.String(): {ocispec.MediaTypeImageConfig, config},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v2/private/") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"DENIED","message":"requested access to the resource is denied"}]}`))
+			return
+		}
+		c, ok := content[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`))
+			return
+		}
+		w.Header().Set("Content-Type", c.mediaType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(c.data)))
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(c.data).String())
+		if r.Method != http.MethodHead {
+			_, _ = w.Write(c.data)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetImagePlatforms(t *testing.T) {
+	server := newTestImageRegistry(t)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithPlainHTTP(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	images := []helm_parser.ImageReference{
+		{Registry: host, Repository: "multi", Tag: "1.0", FullImage: host + "/multi:1.0"},
+		{Registry: host, Repository: "single", Tag: "1.0", FullImage: host + "/single:1.0"},
+		{Registry: host, Repository: "missing", Tag: "1.0", FullImage: host + "/missing:1.0"},
+	}
+	results := client.GetImagePlatforms(context.Background(), images)
+	if len(results) != len(images) {
+		t.Fatalf("got %d results, want %d", len(results), len(images))
+	}
+
+	if want := []string{"linux/amd64", "linux/arm64/v8"}; results[0].Err != nil || !reflect.DeepEqual(results[0].Platforms, want) {
+		t.Errorf("multi-platform image: got %v, %v; want %v", results[0].Platforms, results[0].Err, want)
+	}
+	if want := []string{"linux/arm64/v8"}; results[1].Err != nil || !reflect.DeepEqual(results[1].Platforms, want) {
+		t.Errorf("single-platform image: got %v, %v; want %v", results[1].Platforms, results[1].Err, want)
+	}
+	if results[2].Err == nil {
+		t.Error("expected an error for a missing image")
+	}
+}
+
+func TestVerifyImages(t *testing.T) {
+	server := newTestImageRegistry(t)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithPlainHTTP(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	images := []helm_parser.ImageReference{
+		{Registry: host, Repository: "multi", Tag: "1.0", FullImage: host + "/multi:1.0"},
+		{Registry: host, Repository: "single", Tag: "2.0", FullImage: host + "/single:2.0"},
+		{Registry: host, Repository: "private", Tag: "1.0", FullImage: host + "/private:1.0"},
+		{Registry: "127.0.0.1:1", Repository: "app", Tag: "1.0", FullImage: "127.0.0.1:1/app:1.0"},
+	}
+	want := []string{ImageFound, ImageMissing, ImageUnauthorized, ImageError}
+
+	results := client.VerifyImages(context.Background(), images)
+	for i, result := range results {
+		if result.Status != want[i] {
+			t.Errorf("%s: status = %s (%v), want %s", images[i].FullImage, result.Status, result.Err, want[i])
+		}
+	}
+	if !strings.HasPrefix(results[0].Digest, "sha256:") {
+		t.Errorf("expected the digest of a found image, got %q", results[0].Digest)
+	}
+}
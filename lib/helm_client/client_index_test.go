@@ -0,0 +1,291 @@
+package helm_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startCountingRepo serves a test index and counts index.yaml downloads.
+func startCountingRepo(t *testing.T) (string, *atomic.Int32) {
+	t.Helper()
+
+	var indexHits atomic.Int32
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index.yaml" {
+			indexHits.Add(1)
+			_, _ = w.Write(createTestIndex(serverURL))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+	serverURL = server.URL
+	return server.URL, &indexHits
+}
+
+func TestIndexTTL(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		wantHits int32
+	}{
+		{name: "index cached within TTL", ttl: time.Hour, wantHits: 1},
+		{name: "zero TTL keeps index", ttl: 0, wantHits: 1},
+		{name: "expired index is downloaded again", ttl: time.Nanosecond, wantHits: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoURL, indexHits := startCountingRepo(t)
+			client, err := NewClient(WithIndexTTL(tt.ttl))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			for range 3 {
+				time.Sleep(time.Millisecond)
+				if _, err := client.ListCharts(context.Background(), repoURL); err != nil {
+					t.Fatalf("ListCharts() error = %v", err)
+				}
+			}
+
+			if hits := indexHits.Load(); hits != tt.wantHits {
+				t.Errorf("index downloaded %d times, want %d", hits, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestInvalidateRepositoryIndex(t *testing.T) {
+	repoURL, indexHits := startCountingRepo(t)
+	client, err := NewClient(WithIndexTTL(time.Hour))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, err := client.ListCharts(context.Background(), repoURL); err != nil {
+		t.Fatalf("ListCharts() error = %v", err)
+	}
+	client.InvalidateRepositoryIndex(repoURL)
+	if _, err := client.ListCharts(context.Background(), repoURL); err != nil {
+		t.Fatalf("ListCharts() error = %v", err)
+	}
+
+	if hits := indexHits.Load(); hits != 2 {
+		t.Errorf("index downloaded %d times, want 2", hits)
+	}
+}
+
+func TestKeepIndexesWarm(t *testing.T) {
+	repoURL, indexHits := startCountingRepo(t)
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithIndexTTL(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	waitForHits := func(want int32) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for indexHits.Load() < want {
+			if time.Now().After(deadline) {
+				t.Fatalf("index downloaded %d times, want at least %d", indexHits.Load(), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client.KeepIndexesWarm(ctx, []string{repoURL, "oci://registry.example.com/charts/skipped"})
+	waitForHits(1)
+	if _, err := client.ListCharts(context.Background(), repoURL); err != nil {
+		t.Fatalf("ListCharts() error = %v", err)
+	}
+
+	// The index is downloaded again before it expires.
+	waitForHits(3)
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	hits := indexHits.Load()
+	time.Sleep(200 * time.Millisecond)
+	if got := indexHits.Load(); got != hits {
+		t.Errorf("index downloaded %d times after cancellation, want %d", got, hits)
+	}
+}
+
+func TestLoadIndexesConcurrently(t *testing.T) {
+	const repos = 3
+
+	// Every index request blocks until all repositories are being downloaded
+	// at once, so a serial implementation would fail with a timeout.
+	var arrived sync.WaitGroup
+	arrived.Add(repos)
+	allArrived := make(chan struct{})
+	go func() {
+		arrived.Wait()
+		close(allArrived)
+	}()
+
+	repoURLs := make([]string, 0, repos+2)
+	for range repos {
+		var once sync.Once
+		var serverURL string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/index.yaml" {
+				http.NotFound(w, r)
+				return
+			}
+			once.Do(arrived.Done)
+			select {
+			case <-allArrived:
+			case <-time.After(5 * time.Second):
+				http.Error(w, "index downloads are not concurrent", http.StatusGatewayTimeout)
+				return
+			}
+			_, _ = w.Write(createTestIndex(serverURL))
+		}))
+		t.Cleanup(server.Close)
+		serverURL = server.URL
+		repoURLs = append(repoURLs, server.URL)
+	}
+
+	failing := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(failing.Close)
+	repoURLs = append(repoURLs, failing.URL, "oci://registry.example.com/charts/skipped")
+
+	client := newTestClient(t)
+	errs := client.LoadIndexes(context.Background(), repoURLs)
+
+	if len(errs) != 1 || errs[failing.URL] == nil {
+		t.Fatalf("LoadIndexes() errors = %v, want a single error for %s", errs, failing.URL)
+	}
+	for _, repoURL := range repoURLs[:repos] {
+		charts, err := client.ListCharts(context.Background(), repoURL)
+		if err != nil {
+			t.Fatalf("ListCharts(%s) error = %v", repoURL, err)
+		}
+		if len(charts) == 0 {
+			t.Errorf("ListCharts(%s) returned no charts", repoURL)
+		}
+	}
+}
+
+// startMetadataRepo serves an index whose entries carry chart metadata.
+func startMetadataRepo(t *testing.T) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`apiVersion: v1
+entries:
+  grafana:
+    - name: grafana
+      version: 8.5.0
+      appVersion: 11.2.0
+      description: The leading tool for querying and visualizing time series and metrics.
+      icon: https://example.com/grafana.svg
+      home: https://grafana.com
+      sources: [https://github.com/grafana/grafana]
+      keywords: [monitoring, dashboards]
+      urls: [grafana-8.5.0.tgz]
+    - name: grafana
+      version: 8.4.0
+      appVersion: 11.1.0
+      urls: [grafana-8.4.0.tgz]
+  alpine:
+    - name: alpine
+      version: 0.1.0
+      urls: [alpine-0.1.0.tgz]
+`))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestListChartDetails(t *testing.T) {
+	repoURL := startMetadataRepo(t)
+
+	client, err := NewClient(WithCacheDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	charts, err := client.ListChartDetails(context.Background(), repoURL)
+	if err != nil {
+		t.Fatalf("ListChartDetails() error = %v", err)
+	}
+	want := []ChartSummary{
+		{Name: "alpine", Version: "0.1.0"},
+		{
+			Name:        "grafana",
+			Version:     "8.5.0",
+			AppVersion:  "11.2.0",
+			Description: "The leading tool for querying and visualizing time series and metrics.",
+			Icon:        "https://example.com/grafana.svg",
+			Home:        "https://grafana.com",
+			Sources:     []string{"https://github.com/grafana/grafana"},
+			Keywords:    []string{"monitoring", "dashboards"},
+		},
+	}
+	if !reflect.DeepEqual(charts, want) {
+		t.Fatalf("ListChartDetails() = %+v, want %+v", charts, want)
+	}
+
+	if got := FilterChartsByKeywords(charts, []string{"database", "Monitoring"}); len(got) != 1 || got[0].Name != "grafana" {
+		t.Errorf("FilterChartsByKeywords() = %+v, want grafana", got)
+	}
+	if got := FilterChartsByKeywords(charts, []string{"ingress"}); len(got) != 0 {
+		t.Errorf("FilterChartsByKeywords() = %+v, want none", got)
+	}
+}
+
+func TestChartAppVersions(t *testing.T) {
+	repoURL := startMetadataRepo(t)
+
+	client, err := NewClient(WithCacheDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	appVersion, err := client.GetChartAppVersion(ctx, repoURL, "grafana", "8.4.0")
+	if err != nil || appVersion != "11.1.0" {
+		t.Errorf("GetChartAppVersion() = %q, %v; want 11.1.0", appVersion, err)
+	}
+	if _, err := client.GetChartAppVersion(ctx, repoURL, "grafana", "1.0.0"); err == nil {
+		t.Error("expected an error for an unknown version")
+	}
+
+	tests := []struct {
+		appVersion string
+		want       []ChartVersion
+	}{
+		{"11.2.0", []ChartVersion{{Version: "8.5.0", AppVersion: "11.2.0"}}},
+		{"v11", []ChartVersion{{Version: "8.5.0", AppVersion: "11.2.0"}, {Version: "8.4.0", AppVersion: "11.1.0"}}},
+		{"11.3", []ChartVersion{}},
+		{"1", []ChartVersion{}},
+	}
+	for _, tt := range tests {
+		got, err := client.FindChartVersionsByAppVersion(ctx, repoURL, "grafana", tt.appVersion)
+		if err != nil {
+			t.Fatalf("FindChartVersionsByAppVersion(%q) error = %v", tt.appVersion, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FindChartVersionsByAppVersion(%q) = %+v, want %+v", tt.appVersion, got, tt.want)
+		}
+	}
+
+	if _, err := client.FindChartVersionsByAppVersion(ctx, "oci://registry.example.com/charts/grafana", "grafana", "11"); err == nil {
+		t.Error("expected an error for an OCI registry")
+	}
+}
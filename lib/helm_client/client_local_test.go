@@ -0,0 +1,180 @@
+package helm_client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const (
+	localChart   = "local-chart"
+	localVersion = "0.1.0"
+	localMarker  = "hello-from-local"
+)
+
+// writeLocalChart writes a minimal unpacked chart and returns its directory.
+func writeLocalChart(t *testing.T) string {
+	t.Helper()
+
+	chartDir := filepath.Join(t.TempDir(), "src")
+	if err := os.MkdirAll(filepath.Join(chartDir, "templates"), 0o755); err != nil {
+		t.Fatalf("mkdir chart dir: %v", err)
+	}
+
+	files := map[string]string{
+		"Chart.yaml":  "apiVersion: v2\nname: " + localChart + "\nversion: " + localVersion + "\n",
+		"values.yaml": "message: " + localMarker + "\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n" +
+			"  name: {{ .Release.Name }}-cm\ndata:\n  message: {{ .Values.message | quote }}\n",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(chartDir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return chartDir
+}
+
+func TestIsLocal(t *testing.T) {
+	dir := t.TempDir()
+	client := newTestClient(t)
+	noLocal, err := NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	tests := []struct {
+		url          string
+		want         bool
+		wantDisabled bool
+	}{
+		{url: "file://" + dir, want: true, wantDisabled: true},
+		{url: "file:///does/not/exist", want: true, wantDisabled: true},
+		{url: dir, want: true},
+		{url: filepath.Join(dir, "missing"), want: false},
+		{url: "https://charts.example.com", want: false},
+		{url: "oci://ghcr.io/org/charts/mychart", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := client.IsLocal(tt.url); got != tt.want {
+			t.Errorf("IsLocal(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+		if got := noLocal.IsLocal(tt.url); got != tt.wantDisabled {
+			t.Errorf("IsLocal(%q) with local charts disabled = %v, want %v", tt.url, got, tt.wantDisabled)
+		}
+	}
+}
+
+func TestLocalChart(t *testing.T) {
+	chartDir := writeLocalChart(t)
+	client := newTestClient(t)
+
+	for _, repoURL := range []string{chartDir, "file://" + chartDir} {
+		t.Run(repoURL, func(t *testing.T) {
+			charts, err := client.ListCharts(context.Background(), repoURL)
+			if err != nil {
+				t.Fatalf("ListCharts() error = %v", err)
+			}
+			if !slices.Equal(charts, []string{localChart}) {
+				t.Errorf("ListCharts() = %v, want [%s]", charts, localChart)
+			}
+
+			versions, err := client.ListChartVersions(context.Background(), repoURL, "")
+			if err != nil {
+				t.Fatalf("ListChartVersions() error = %v", err)
+			}
+			if !slices.Equal(versions, []string{localVersion}) {
+				t.Errorf("ListChartVersions() = %v, want [%s]", versions, localVersion)
+			}
+
+			latest, err := client.GetChartLatestVersion(context.Background(), repoURL, localChart)
+			if err != nil {
+				t.Fatalf("GetChartLatestVersion() error = %v", err)
+			}
+			if latest != localVersion {
+				t.Errorf("GetChartLatestVersion() = %q, want %q", latest, localVersion)
+			}
+
+			values, err := client.GetChartValues(context.Background(), repoURL, localChart, localVersion)
+			if err != nil {
+				t.Fatalf("GetChartValues() error = %v", err)
+			}
+			if !strings.Contains(values, localMarker) {
+				t.Errorf("expected chart values to contain %q, got: %q", localMarker, values)
+			}
+		})
+	}
+
+	t.Run("version mismatch fails", func(t *testing.T) {
+		if _, err := client.GetChartValues(context.Background(), chartDir, localChart, "9.9.9"); err == nil {
+			t.Error("expected error for version not matching the local chart")
+		}
+	})
+
+	t.Run("name mismatch fails", func(t *testing.T) {
+		if _, err := client.GetChartValues(context.Background(), chartDir, "other-chart", localVersion); err == nil {
+			t.Error("expected error for name not matching the local chart")
+		}
+	})
+
+	t.Run("lists and reads files", func(t *testing.T) {
+		files, err := client.ListChartFiles(context.Background(), chartDir, localChart, localVersion)
+		if err != nil {
+			t.Fatalf("ListChartFiles() error = %v", err)
+		}
+		var paths []string
+		for _, f := range files {
+			paths = append(paths, f.Path)
+		}
+		if want := []string{"Chart.yaml", "templates/configmap.yaml", "values.yaml"}; !slices.Equal(paths, want) {
+			t.Errorf("ListChartFiles() paths = %v, want %v", paths, want)
+		}
+
+		content, err := client.GetChartFile(context.Background(), chartDir, localChart, localVersion, "templates/configmap.yaml")
+		if err != nil {
+			t.Fatalf("GetChartFile() error = %v", err)
+		}
+		if !strings.Contains(content, "kind: ConfigMap") {
+			t.Errorf("GetChartFile() = %q, want the ConfigMap template", content)
+		}
+	})
+
+	t.Run("reads changes from disk", func(t *testing.T) {
+		const updated = "updated-marker"
+		if err := os.WriteFile(filepath.Join(chartDir, "values.yaml"), []byte("message: "+updated+"\n"), 0o644); err != nil {
+			t.Fatalf("write values.yaml: %v", err)
+		}
+
+		values, err := client.GetChartValues(context.Background(), chartDir, localChart, localVersion)
+		if err != nil {
+			t.Fatalf("GetChartValues() error = %v", err)
+		}
+		if !strings.Contains(values, updated) {
+			t.Errorf("expected chart values to contain %q, got: %q", updated, values)
+		}
+	})
+}
+
+func TestLocalChartDisabled(t *testing.T) {
+	chartDir := writeLocalChart(t)
+	client, err := NewClient(WithCacheDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	repoURL := "file://" + chartDir
+	if _, err := client.ListCharts(context.Background(), repoURL); err == nil || !strings.Contains(err.Error(), "disabled") {
+		t.Errorf("ListCharts(%q) error = %v, want local charts disabled", repoURL, err)
+	}
+	if _, err := client.GetChartValues(context.Background(), repoURL, localChart, localVersion); err == nil || !strings.Contains(err.Error(), "disabled") {
+		t.Errorf("GetChartValues(%q) error = %v, want local charts disabled", repoURL, err)
+	}
+	if _, err := client.ListCharts(context.Background(), chartDir); err == nil || strings.Contains(err.Error(), "disabled") {
+		t.Errorf("ListCharts(%q) error = %v, want the path not to be read as a local chart", chartDir, err)
+	}
+}
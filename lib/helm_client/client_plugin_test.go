@@ -0,0 +1,77 @@
+package helm_client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeFakeGetterPlugin installs a legacy Helm downloader plugin serving the
+// "fake://" scheme from files under the plugin's data directory, and returns
+// the plugins directory and the data directory.
+func writeFakeGetterPlugin(t *testing.T) (pluginsDir, dataDir string) {
+	t.Helper()
+
+	pluginsDir = t.TempDir()
+	pluginDir := filepath.Join(pluginsDir, "fake-getter")
+	dataDir = filepath.Join(pluginDir, "data")
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatalf("mkdir plugin dir: %v", err)
+	}
+
+	pluginYAML := "name: fake-getter\nversion: 0.1.0\ndownloaders:\n" +
+		"- command: fetch.sh\n  protocols:\n  - fake\n"
+	// Downloader plugins receive certFile keyFile caFile URL and write the
+	// content to stdout.
+	fetchScript := "#!/bin/sh\nexec cat \"$HELM_PLUGIN_DIR/data/${4#fake://}\"\n"
+
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.yaml"), []byte(pluginYAML), 0o644); err != nil {
+		t.Fatalf("write plugin.yaml: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, "fetch.sh"), []byte(fetchScript), 0o755); err != nil {
+		t.Fatalf("write fetch.sh: %v", err)
+	}
+	return pluginsDir, dataDir
+}
+
+func TestDownloaderPlugin(t *testing.T) {
+	const repoURL = "fake://repo"
+
+	pluginsDir, dataDir := writeFakeGetterPlugin(t)
+	tgz := buildMatrixChartTGZ(t)
+
+	chartsDir := filepath.Join(dataDir, "repo", "charts")
+	if err := os.MkdirAll(chartsDir, 0o755); err != nil {
+		t.Fatalf("mkdir charts dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "repo", "index.yaml"), createTestIndex(repoURL), 0o644); err != nil {
+		t.Fatalf("write index.yaml: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(chartsDir, matrixChart+"-"+matrixVersion+".tgz"), tgz, 0o644); err != nil {
+		t.Fatalf("write chart archive: %v", err)
+	}
+
+	client, err := NewClient(WithPluginsDirectory(pluginsDir))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	versions, err := client.ListChartVersions(context.Background(), repoURL, matrixChart)
+	if err != nil {
+		t.Fatalf("ListChartVersions() error = %v", err)
+	}
+	if !slices.Contains(versions, matrixVersion) {
+		t.Fatalf("expected version %q in %v", matrixVersion, versions)
+	}
+
+	values, err := client.GetChartValues(context.Background(), repoURL, matrixChart, matrixVersion)
+	if err != nil {
+		t.Fatalf("GetChartValues() error = %v", err)
+	}
+	if !strings.Contains(values, matrixMarker) {
+		t.Errorf("expected chart values to contain %q, got: %q", matrixMarker, values)
+	}
+}
@@ -1,8 +1,11 @@
 package helm_client
 
 import (
+	"context"
 	"strings"
 	"testing"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
 )
 
 const (
@@ -15,7 +18,7 @@ const (
 
 func newTestClient(t *testing.T) *HelmClient {
 	t.Helper()
-	client, err := NewClient()
+	client, err := NewClient(WithLocalCharts(true))
 	if err != nil {
 		t.Fatalf("NewClient() error = %v", err)
 	}
@@ -36,7 +39,7 @@ func TestNewClient(t *testing.T) {
 
 func TestListCharts(t *testing.T) {
 	client := newTestClient(t)
-	charts, err := client.ListCharts(testRepoURL)
+	charts, err := client.ListCharts(context.Background(), testRepoURL)
 	if err != nil {
 		t.Fatalf("ListCharts() error = %v", err)
 	}
@@ -59,7 +62,7 @@ func TestListCharts(t *testing.T) {
 
 func TestListChartVersions(t *testing.T) {
 	client := newTestClient(t)
-	versions, err := client.ListChartVersions(testRepoURL, testChartName)
+	versions, err := client.ListChartVersions(context.Background(), testRepoURL, testChartName)
 	if err != nil {
 		t.Fatalf("ListChartVersions() error = %v", err)
 	}
@@ -70,7 +73,7 @@ func TestListChartVersions(t *testing.T) {
 
 func TestGetChartLatestVersion(t *testing.T) {
 	client := newTestClient(t)
-	version, err := client.GetChartLatestVersion(testRepoURL, testChartName)
+	version, err := client.GetChartLatestVersion(context.Background(), testRepoURL, testChartName)
 	if err != nil {
 		t.Fatalf("GetChartLatestVersion() error = %v", err)
 	}
@@ -83,12 +86,12 @@ func TestGetChartValues(t *testing.T) {
 	client := newTestClient(t)
 
 	// Get the latest version first
-	version, err := client.GetChartLatestVersion(testRepoURL, testChartName)
+	version, err := client.GetChartLatestVersion(context.Background(), testRepoURL, testChartName)
 	if err != nil {
 		t.Fatalf("GetChartLatestVersion() error = %v", err)
 	}
 
-	values, err := client.GetChartValues(testRepoURL, testChartName, version)
+	values, err := client.GetChartValues(context.Background(), testRepoURL, testChartName, version)
 	if err != nil {
 		t.Fatalf("GetChartValues() error = %v", err)
 	}
@@ -104,7 +107,7 @@ func TestGetChartValues(t *testing.T) {
 
 func TestGetChartLatestValues(t *testing.T) {
 	client := newTestClient(t)
-	values, err := client.GetChartLatestValues(testRepoURL, testChartName)
+	values, err := client.GetChartLatestValues(context.Background(), testRepoURL, testChartName)
 	if err != nil {
 		t.Fatalf("GetChartLatestValues() error = %v", err)
 	}
@@ -117,13 +120,13 @@ func TestGetChartContents(t *testing.T) {
 	client := newTestClient(t)
 
 	// Get the latest version first
-	version, err := client.GetChartLatestVersion(testRepoURL, testChartName)
+	version, err := client.GetChartLatestVersion(context.Background(), testRepoURL, testChartName)
 	if err != nil {
 		t.Fatalf("GetChartLatestVersion() error = %v", err)
 	}
 
 	// Test without recursion
-	contents, err := client.GetChartContents(testRepoURL, testChartName, version, false)
+	contents, err := client.GetChartContents(context.Background(), testRepoURL, testChartName, version, false, helm_parser.ContentAll)
 	if err != nil {
 		t.Fatalf("GetChartContents(recursive=false) error = %v", err)
 	}
@@ -132,7 +135,7 @@ func TestGetChartContents(t *testing.T) {
 	}
 
 	// Test with recursion
-	contentsRecursive, err := client.GetChartContents(testRepoURL, testChartName, version, true)
+	contentsRecursive, err := client.GetChartContents(context.Background(), testRepoURL, testChartName, version, true, helm_parser.ContentAll)
 	if err != nil {
 		t.Fatalf("GetChartContents(recursive=true) error = %v", err)
 	}
@@ -150,12 +153,12 @@ func TestGetChartDependencies(t *testing.T) {
 	client := newTestClient(t)
 
 	// Get the latest version first
-	version, err := client.GetChartLatestVersion(testRepoURL, testChartName)
+	version, err := client.GetChartLatestVersion(context.Background(), testRepoURL, testChartName)
 	if err != nil {
 		t.Fatalf("GetChartLatestVersion() error = %v", err)
 	}
 
-	deps, err := client.GetChartDependencies(testRepoURL, testChartName, version)
+	deps, err := client.GetChartDependencies(context.Background(), testRepoURL, testChartName, version)
 	if err != nil {
 		t.Fatalf("GetChartDependencies() error = %v", err)
 	}
@@ -171,12 +174,12 @@ func TestGetChartImages(t *testing.T) {
 	client := newTestClient(t)
 
 	// Get the latest version first
-	version, err := client.GetChartLatestVersion(testRepoURL, testChartName)
+	version, err := client.GetChartLatestVersion(context.Background(), testRepoURL, testChartName)
 	if err != nil {
 		t.Fatalf("GetChartLatestVersion() error = %v", err)
 	}
 
-	images, err := client.GetChartImages(testRepoURL, testChartName, version, nil, false)
+	images, err := client.GetChartImages(context.Background(), testRepoURL, testChartName, version, nil, false, helm_parser.ImagesRendered, helm_parser.RenderOptions{})
 	if err != nil {
 		t.Fatalf("GetChartImages() error = %v", err)
 	}
@@ -235,6 +238,8 @@ func TestParseOCIReference(t *testing.T) {
 		{"oci://ghcr.io/org/charts/mychart", "", "1.0.0", "ghcr.io/org/charts/mychart:1.0.0"},
 		{"oci://ghcr.io/org/charts/mychart", "", "", "ghcr.io/org/charts/mychart"},
 		{"oci://docker.io/library/mysql", "", "8.0", "docker.io/library/mysql:8.0"},
+		{"oci://localhost:5000/charts", "mychart", "1.0.0", "localhost:5000/charts/mychart:1.0.0"},
+		{"oci://localhost:5000/charts/mychart", "mychart", "", "localhost:5000/charts/mychart"},
 	}
 
 	for _, tt := range tests {
@@ -268,7 +273,7 @@ func TestExtractChartNameFromOCI(t *testing.T) {
 
 func TestListChartsOCI(t *testing.T) {
 	client := newTestClient(t)
-	charts, err := client.ListCharts(testOCIRepoURL)
+	charts, err := client.ListCharts(context.Background(), testOCIRepoURL)
 	if err != nil {
 		t.Fatalf("ListCharts() error = %v", err)
 	}
@@ -284,7 +289,7 @@ func TestListChartsOCI(t *testing.T) {
 
 func TestListChartVersionsOCI(t *testing.T) {
 	client := newTestClient(t)
-	versions, err := client.ListChartVersions(testOCIRepoURL, testOCIChartName)
+	versions, err := client.ListChartVersions(context.Background(), testOCIRepoURL, testOCIChartName)
 	if err != nil {
 		t.Fatalf("ListChartVersions() error = %v", err)
 	}
@@ -296,7 +301,7 @@ func TestListChartVersionsOCI(t *testing.T) {
 
 func TestGetChartLatestVersionOCI(t *testing.T) {
 	client := newTestClient(t)
-	version, err := client.GetChartLatestVersion(testOCIRepoURL, testOCIChartName)
+	version, err := client.GetChartLatestVersion(context.Background(), testOCIRepoURL, testOCIChartName)
 	if err != nil {
 		t.Fatalf("GetChartLatestVersion() error = %v", err)
 	}
@@ -309,12 +314,12 @@ func TestGetChartLatestVersionOCI(t *testing.T) {
 func TestGetChartValuesOCI(t *testing.T) {
 	client := newTestClient(t)
 
-	version, err := client.GetChartLatestVersion(testOCIRepoURL, testOCIChartName)
+	version, err := client.GetChartLatestVersion(context.Background(), testOCIRepoURL, testOCIChartName)
 	if err != nil {
 		t.Fatalf("GetChartLatestVersion() error = %v", err)
 	}
 
-	values, err := client.GetChartValues(testOCIRepoURL, testOCIChartName, version)
+	values, err := client.GetChartValues(context.Background(), testOCIRepoURL, testOCIChartName, version)
 	if err != nil {
 		t.Fatalf("GetChartValues() error = %v", err)
 	}
@@ -0,0 +1,156 @@
+package helm_client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// ChartVariant is a chart as published by one repository, see CompareCharts.
+type ChartVariant struct {
+	RepositoryURL string `json:"repositoryURL"`
+	Chart         string `json:"chart"`
+	// Version is the compared version, the latest unless requested otherwise.
+	Version     string `json:"version"`
+	AppVersion  string `json:"appVersion,omitempty"`
+	Description string `json:"description,omitempty"`
+	// VersionCount is the number of published versions, and RecentVersions
+	// are the most recent ones, newest first.
+	VersionCount   int      `json:"versionCount"`
+	RecentVersions []string `json:"recentVersions"`
+	// Images are the images of the manifests rendered with the default
+	// values, including those of subcharts. ImagesError is set instead if
+	// the chart could not be rendered.
+	Images      []helm_parser.ImageReference `json:"images"`
+	ImagesError string                       `json:"imagesError,omitempty"`
+	// Registries are the registries the images are pulled from.
+	Registries []string `json:"registries"`
+}
+
+// ChartComparison compares the same chart published by two repositories.
+type ChartComparison struct {
+	A ChartVariant `json:"a"`
+	B ChartVariant `json:"b"`
+	// ValueDifferences are the default values that differ, Old being the
+	// value of A and New the value of B. CommonValues is the number of
+	// default values that are equal.
+	ValueDifferences []helm_parser.ValueChange `json:"valueDifferences"`
+	CommonValues     int                       `json:"commonValues"`
+	// CommonImages are the images, without registry and tag, both variants
+	// deploy; ImagesOnlyInA and ImagesOnlyInB the ones only one deploys.
+	CommonImages  []string `json:"commonImages"`
+	ImagesOnlyInA []string `json:"imagesOnlyInA"`
+	ImagesOnlyInB []string `json:"imagesOnlyInB"`
+}
+
+// CompareCharts compares the chart chartName published by the repositories
+// at repoA and repoB, e.g. the Bitnami and the upstream variant of a chart:
+// their versions, default values and images. versionA and versionB select
+// the compared versions; the latest are compared if they are empty.
+func (c *HelmClient) CompareCharts(ctx context.Context, chartName, repoA, versionA, repoB, versionB string) (*ChartComparison, error) {
+	var comparison ChartComparison
+	var valuesA, valuesB map[string]any
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		comparison.A, valuesA, err = c.chartVariant(gctx, repoA, chartName, versionA)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		comparison.B, valuesB, err = c.chartVariant(gctx, repoB, chartName, versionB)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	comparison.ValueDifferences = helm_parser.DiffValues(valuesA, valuesB)
+	flatB := helm_parser.FlattenValues(valuesB)
+	for path, a := range helm_parser.FlattenValues(valuesA) {
+		if b, ok := flatB[path]; ok && reflect.DeepEqual(a, b) {
+			comparison.CommonValues++
+		}
+	}
+
+	imagesA, imagesB := imageRepositories(comparison.A.Images), imageRepositories(comparison.B.Images)
+	comparison.CommonImages, comparison.ImagesOnlyInA, comparison.ImagesOnlyInB = []string{}, []string{}, []string{}
+	for image := range imagesA {
+		if imagesB[image] {
+			comparison.CommonImages = append(comparison.CommonImages, image)
+		} else {
+			comparison.ImagesOnlyInA = append(comparison.ImagesOnlyInA, image)
+		}
+	}
+	for image := range imagesB {
+		if !imagesA[image] {
+			comparison.ImagesOnlyInB = append(comparison.ImagesOnlyInB, image)
+		}
+	}
+	sort.Strings(comparison.CommonImages)
+	sort.Strings(comparison.ImagesOnlyInA)
+	sort.Strings(comparison.ImagesOnlyInB)
+	return &comparison, nil
+}
+
+// chartVariant describes version of the chart chartName in the repository
+// at repoURL, the latest if version is empty, and returns its default values.
+func (c *HelmClient) chartVariant(ctx context.Context, repoURL, chartName, version string) (ChartVariant, map[string]any, error) {
+	variant := ChartVariant{RepositoryURL: repoURL, Chart: chartName, Version: version, Images: []helm_parser.ImageReference{}, Registries: []string{}}
+
+	versions, err := c.ListChartVersions(ctx, repoURL, chartName)
+	if err != nil {
+		return variant, nil, fmt.Errorf("failed to list versions of chart %s in %s: %v", chartName, repoURL, err)
+	}
+	if len(versions) == 0 {
+		return variant, nil, fmt.Errorf("chart %s not found in repository %s", chartName, repoURL)
+	}
+	variant.VersionCount = len(versions)
+	variant.RecentVersions = versions[:min(len(versions), maxCandidateVersions)]
+	if variant.Version == "" {
+		variant.Version = versions[0]
+	}
+
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, variant.Version)
+	if err != nil {
+		return variant, nil, fmt.Errorf("failed to load chart %s version %s from %s: %v", chartName, variant.Version, repoURL, err)
+	}
+	if loadedChart.Metadata != nil {
+		variant.AppVersion = loadedChart.Metadata.AppVersion
+		variant.Description = loadedChart.Metadata.Description
+	}
+
+	images, err := c.GetChartImages(ctx, repoURL, chartName, variant.Version, nil, true, helm_parser.ImagesRendered, helm_parser.RenderOptions{})
+	if err != nil {
+		variant.ImagesError = err.Error()
+	} else {
+		variant.Images = images
+	}
+	registries := make(map[string]bool)
+	for _, image := range variant.Images {
+		if !registries[image.Registry] {
+			registries[image.Registry] = true
+			variant.Registries = append(variant.Registries, image.Registry)
+		}
+	}
+	sort.Strings(variant.Registries)
+
+	return variant, loadedChart.Values, nil
+}
+
+// imageRepositories returns the repositories of images without registry and
+// tag, e.g. "grafana/grafana", so variants pulling the same image from a
+// mirror match.
+func imageRepositories(images []helm_parser.ImageReference) map[string]bool {
+	repositories := make(map[string]bool, len(images))
+	for _, image := range images {
+		repositories[image.Repository] = true
+	}
+	return repositories
+}
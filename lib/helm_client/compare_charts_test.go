@@ -0,0 +1,85 @@
+package helm_client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// writeComparedChart writes a chart deploying image with values to a directory
+// and returns its file:// URL.
+func writeComparedChart(t *testing.T, version, image, values string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"Chart.yaml":  "apiVersion: v2\nname: grafana\nversion: " + version + "\nappVersion: \"11.2.0\"\n",
+		"values.yaml": values,
+		"templates/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+spec:
+  template:
+    spec:
+      containers:
+        - name: grafana
+          image: ` + image + "\n",
+	}
+	for name, body := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return "file://" + dir
+}
+
+func TestCompareCharts(t *testing.T) {
+	repoA := writeComparedChart(t, "8.5.0", "docker.io/grafana/grafana:11.2.0", "replicas: 1\nservice:\n  port: 80\n  type: ClusterIP\n")
+	repoB := writeComparedChart(t, "11.3.0", "registry.example.com/bitnami/grafana:11.2.0", "replicas: 1\nservice:\n  port: 3000\n  type: ClusterIP\nmetrics:\n  enabled: false\n")
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	comparison, err := client.CompareCharts(context.Background(), "grafana", repoA, "", repoB, "")
+	if err != nil {
+		t.Fatalf("CompareCharts() error = %v", err)
+	}
+
+	if a := comparison.A; a.Version != "8.5.0" || a.VersionCount != 1 || a.AppVersion != "11.2.0" || !reflect.DeepEqual(a.Registries, []string{"docker.io"}) {
+		t.Errorf("unexpected variant A %+v", a)
+	}
+	if b := comparison.B; b.Version != "11.3.0" || !reflect.DeepEqual(b.Registries, []string{"registry.example.com"}) || b.ImagesError != "" {
+		t.Errorf("unexpected variant B %+v", b)
+	}
+	wantDiff := []helm_parser.ValueChange{
+		{Path: "metrics.enabled", New: false},
+		{Path: "service.port", Old: float64(80), New: float64(3000)},
+	}
+	if !reflect.DeepEqual(comparison.ValueDifferences, wantDiff) {
+		t.Errorf("ValueDifferences = %#v, want %#v", comparison.ValueDifferences, wantDiff)
+	}
+	if comparison.CommonValues != 2 {
+		t.Errorf("CommonValues = %d, want 2", comparison.CommonValues)
+	}
+	if !reflect.DeepEqual(comparison.ImagesOnlyInA, []string{"grafana/grafana"}) ||
+		!reflect.DeepEqual(comparison.ImagesOnlyInB, []string{"bitnami/grafana"}) ||
+		len(comparison.CommonImages) != 0 {
+		t.Errorf("unexpected image comparison: common %v, only in A %v, only in B %v", comparison.CommonImages, comparison.ImagesOnlyInA, comparison.ImagesOnlyInB)
+	}
+
+	if _, err := client.CompareCharts(context.Background(), "grafana", repoA, "", "file://"+t.TempDir(), ""); err == nil {
+		t.Error("expected an error for a repository without the chart")
+	}
+}
@@ -0,0 +1,63 @@
+package helm_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// runWithContext runs fn and returns its result, or ctx.Err() as soon as ctx
+// is done. It is used for Helm SDK calls that do not accept a context, such as
+// OCI registry operations and template rendering: such a call keeps running in
+// the background until it completes, but the caller is no longer blocked on
+// it and its result is discarded. Requests of OCI registry clients created by
+// ociClient are aborted on cancellation as well, so such calls end promptly.
+func runWithContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn()
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// isContextError reports whether err is a cancellation or deadline error.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// withTimeout limits ctx to timeout for a single network operation. A
+// non-positive timeout leaves ctx without a deadline.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timeoutErr replaces err with a descriptive error if the operation context
+// opCtx, created by withTimeout from ctx, hit its own deadline. Errors caused
+// by cancellation of ctx itself are returned unchanged.
+func timeoutErr(ctx, opCtx context.Context, timeout time.Duration, err error) error {
+	if err != nil && ctx.Err() == nil && errors.Is(opCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package helm_client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+	"helm.sh/helm/v4/pkg/downloader"
+
+	"github.com/zekker6/mcp-helm/lib/logger"
+	"github.com/zekker6/mcp-helm/lib/metrics"
+)
+
+// defaultCacheDir returns the cache directory used unless configured with
+// WithCacheDir: "mcp-helm" inside the user cache directory ($XDG_CACHE_HOME or
+// ~/.cache on Linux), or inside the temp directory if that cannot be determined.
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "mcp-helm")
+}
+
+// parseDigest decodes a sha256 digest in "sha256:<hex>" or plain hex form.
+func parseDigest(digest string) ([sha256.Size]byte, bool) {
+	var key [sha256.Size]byte
+
+	digest = strings.TrimPrefix(digest, "sha256:")
+	raw, err := hex.DecodeString(digest)
+	if err != nil || len(raw) != sha256.Size {
+		return key, false
+	}
+	copy(key[:], raw)
+	return key, true
+}
+
+// cachedArchive returns a chart archive previously stored in the on-disk cache
+// under digest.
+func (c *HelmClient) cachedArchive(digest string) ([]byte, bool) {
+	key, ok := parseDigest(digest)
+	if !ok {
+		return nil, false
+	}
+
+	p, err := c.archives.Get(key, downloader.CacheChart)
+	if err != nil {
+		metrics.CacheLookup(metrics.CacheArchive, false)
+		return nil, false
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		metrics.CacheLookup(metrics.CacheArchive, false)
+		return nil, false
+	}
+	metrics.CacheLookup(metrics.CacheArchive, true)
+	return data, true
+}
+
+// storeArchive persists a chart archive in the on-disk cache under digest.
+// Failures are logged only: the cache is an optimisation and must not fail
+// the request.
+func (c *HelmClient) storeArchive(digest string, data []byte) {
+	key, ok := parseDigest(digest)
+	if !ok {
+		return
+	}
+
+	if _, err := c.archives.Put(key, bytes.NewReader(data), downloader.CacheChart); err != nil {
+		logger.Warn("failed to store chart archive in cache", zap.String("digest", digest), zap.Error(err))
+	}
+}
+
+// verifiedDigest reports whether data matches the sha256 digest, so archives
+// are only cached under a key that really identifies their content.
+func verifiedDigest(digest string, data []byte) bool {
+	key, ok := parseDigest(digest)
+	return ok && sha256.Sum256(data) == key
+}
@@ -0,0 +1,164 @@
+package helm_client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseDigest(t *testing.T) {
+	sum := sha256.Sum256([]byte("chart"))
+	hexSum := hex.EncodeToString(sum[:])
+
+	for _, digest := range []string{hexSum, "sha256:" + hexSum} {
+		key, ok := parseDigest(digest)
+		if !ok || key != sum {
+			t.Errorf("parseDigest(%q) = %x, %v; want %x, true", digest, key, ok, sum)
+		}
+	}
+	for _, digest := range []string{"", "sha256:", "not-hex", "abcd"} {
+		if _, ok := parseDigest(digest); ok {
+			t.Errorf("parseDigest(%q) should fail", digest)
+		}
+	}
+}
+
+func TestDiskCacheHTTP(t *testing.T) {
+	tgz := buildMatrixChartTGZ(t)
+	sum := sha256.Sum256(tgz)
+	tgzPath := "/charts/" + matrixChart + "-" + matrixVersion + ".tgz"
+
+	var tgzHits atomic.Int32
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			_ = json.NewEncoder(w).Encode(testIndexFile{
+				APIVersion: "v1",
+				Generated:  time.Now(),
+				Entries: map[string][]testChartEntry{
+					matrixChart: {{
+						Name:    matrixChart,
+						Version: matrixVersion,
+						URLs:    []string{serverURL + tgzPath},
+						Digest:  hex.EncodeToString(sum[:]),
+					}},
+				},
+			})
+		case tgzPath:
+			tgzHits.Add(1)
+			_, _ = w.Write(tgz)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	cacheDir := t.TempDir()
+	// A fresh client per iteration simulates a restart; the in-memory cache is
+	// disabled so only the on-disk cache can serve repeated requests.
+	for range 2 {
+		client, err := NewClient(WithCacheDir(cacheDir), WithChartCacheSize(0))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		values, err := client.GetChartValues(context.Background(), server.URL, matrixChart, matrixVersion)
+		if err != nil {
+			t.Fatalf("GetChartValues() error = %v", err)
+		}
+		if !strings.Contains(values, matrixMarker) {
+			t.Errorf("expected chart values to contain %q, got: %q", matrixMarker, values)
+		}
+	}
+
+	if hits := tgzHits.Load(); hits != 1 {
+		t.Errorf("chart archive downloaded %d times, want 1", hits)
+	}
+}
+
+func TestDiskCacheOCI(t *testing.T) {
+	tgz := buildMatrixChartTGZ(t)
+	artifact := buildOCIArtifact(t, "charts/"+matrixChart, matrixVersion, tgz)
+
+	var blobHits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/blobs/") {
+			blobHits.Add(1)
+		}
+		artifact.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	repoURL := "oci://" + strings.TrimPrefix(server.URL, "http://") + "/charts/" + matrixChart
+	cacheDir := t.TempDir()
+
+	var firstRunHits int32
+	for i := range 2 {
+		client, err := NewClient(WithCacheDir(cacheDir), WithChartCacheSize(0), WithPlainHTTP(true))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		values, err := client.GetChartValues(context.Background(), repoURL, "", matrixVersion)
+		if err != nil {
+			t.Fatalf("GetChartValues() error = %v", err)
+		}
+		if !strings.Contains(values, matrixMarker) {
+			t.Errorf("expected chart values to contain %q, got: %q", matrixMarker, values)
+		}
+
+		if i == 0 {
+			firstRunHits = blobHits.Load()
+		}
+	}
+
+	if firstRunHits == 0 {
+		t.Fatal("expected blobs to be pulled on the first run")
+	}
+	if hits := blobHits.Load(); hits != firstRunHits {
+		t.Errorf("blobs pulled again after restart: %d requests, want %d", hits, firstRunHits)
+	}
+}
+
+func TestCacheDirHoldsAllFiles(t *testing.T) {
+	// Point Helm's own cache elsewhere to detect writes outside the cache dir.
+	helmCache := t.TempDir()
+	t.Setenv("HELM_CACHE_HOME", helmCache)
+
+	tgz := buildMatrixChartTGZ(t)
+	repoURL, _ := startHTTPChartRepo(t, false, tgz)
+
+	cacheDir := t.TempDir()
+	client, err := NewClient(WithCacheDir(cacheDir))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if _, err := client.GetChartValues(context.Background(), repoURL, matrixChart, matrixVersion); err != nil {
+		t.Fatalf("GetChartValues() error = %v", err)
+	}
+
+	index := filepath.Join(client.ws.dir, "helm-cache", repoCacheName(repoURL)+"-index.yaml")
+	if !strings.HasPrefix(index, cacheDir) {
+		t.Errorf("expected workspace %s inside cache dir %s", client.ws.dir, cacheDir)
+	}
+	if _, err := os.Stat(index); err != nil {
+		t.Errorf("expected repository index in cache dir: %v", err)
+	}
+	if entries, _ := os.ReadDir(helmCache); len(entries) != 0 {
+		t.Errorf("expected nothing written to Helm's cache, found %d entries", len(entries))
+	}
+	if entries, _ := os.ReadDir(client.tempDir); len(entries) != 0 {
+		t.Errorf("expected temporary downloads to be removed, found %d entries", len(entries))
+	}
+}
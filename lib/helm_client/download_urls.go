@@ -0,0 +1,54 @@
+package helm_client
+
+import (
+	"context"
+	"fmt"
+
+	"helm.sh/helm/v4/pkg/repo/v1"
+)
+
+// GetChartDownloadURLs returns the absolute URLs a chart version can be
+// downloaded from: the archive URLs listed in the index of HTTP repositories,
+// with relative URLs resolved against the repository as when loading the
+// chart, or the OCI reference for OCI registries. Local charts have no
+// download URL.
+func (c *HelmClient) GetChartDownloadURLs(ctx context.Context, repoURL, chartName, version string) ([]string, error) {
+	if c.IsLocal(repoURL) {
+		return nil, fmt.Errorf("local chart %s has no download URL", localChartPath(repoURL))
+	}
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return nil, err
+	}
+	if IsOCI(repoURL) {
+		return []string{"oci://" + parseOCIReference(repoURL, chartName, version)}, nil
+	}
+
+	cv, err := c.indexChartVersion(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(cv.URLs))
+	for i, u := range cv.URLs {
+		urls[i] = chartArchiveURL(repoURL, u)
+	}
+	return urls, nil
+}
+
+// indexChartVersion returns the index entry of a chart version of an HTTP
+// repository, which lists at least one download URL.
+func (c *HelmClient) indexChartVersion(ctx context.Context, repoURL, chartName, version string) (*repo.ChartVersion, error) {
+	entries, err := c.indexEntries(ctx, repoURL, chartName)
+	if err != nil {
+		return nil, err
+	}
+	for _, cv := range entries[chartName] {
+		if cv.Version != version {
+			continue
+		}
+		if len(cv.URLs) == 0 {
+			return nil, fmt.Errorf("no download URLs found for chart %s version %s", chartName, version)
+		}
+		return cv, nil
+	}
+	return nil, fmt.Errorf("failed to find chart %s version %s", chartName, version)
+}
@@ -0,0 +1,55 @@
+package helm_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetChartDownloadURLs(t *testing.T) {
+	index := []byte(`apiVersion: v1
+entries:
+  app:
+    - name: app
+      version: 1.0.0
+      urls:
+        - charts/app-1.0.0.tgz
+        - https://mirror.example.com/app-1.0.0.tgz
+`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repo/index.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(index)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithCacheDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	urls, err := client.GetChartDownloadURLs(context.Background(), server.URL+"/repo/", "app", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetChartDownloadURLs() error = %v", err)
+	}
+	if want := []string{server.URL + "/repo/charts/app-1.0.0.tgz", "https://mirror.example.com/app-1.0.0.tgz"}; !reflect.DeepEqual(urls, want) {
+		t.Errorf("GetChartDownloadURLs() = %v, want %v", urls, want)
+	}
+
+	if _, err := client.GetChartDownloadURLs(context.Background(), server.URL+"/repo/", "app", "2.0.0"); err == nil {
+		t.Error("expected an error for a missing version")
+	}
+
+	urls, err = client.GetChartDownloadURLs(context.Background(), "oci://ghcr.io/org/charts", "app", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetChartDownloadURLs() error = %v", err)
+	}
+	if want := []string{"oci://ghcr.io/org/charts/app:1.0.0"}; !reflect.DeepEqual(urls, want) {
+		t.Errorf("GetChartDownloadURLs() = %v, want %v", urls, want)
+	}
+}
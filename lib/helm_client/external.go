@@ -0,0 +1,29 @@
+package helm_client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// FindExternalDependencies renders a chart version with customValues and
+// reports the services outside the cluster it expects, found in the
+// effective values and the rendered manifest, see
+// helm_parser.FindExternalDependencies.
+func (c *HelmClient) FindExternalDependencies(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions) (*helm_parser.DependencyReport, error) {
+	values, err := c.GetEffectiveValues(ctx, repoURL, chartName, version, customValues)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := c.renderManifest(ctx, repoURL, chartName, version, customValues, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := helm_parser.FindExternalDependencies(manifest, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find external dependencies of chart %s version %s: %v", chartName, version, err)
+	}
+	return report, nil
+}
@@ -0,0 +1,34 @@
+package helm_client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func TestFindExternalDependencies(t *testing.T) {
+	chartDir := writeLocalChart(t)
+	values := "message: hello\nexternalDatabase:\n  host: db.example.com\n"
+	if err := os.WriteFile(filepath.Join(chartDir, "values.yaml"), []byte(values), 0o644); err != nil {
+		t.Fatalf("write values: %v", err)
+	}
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	custom := map[string]any{"smtp": map[string]any{"host": "smtp.example.com"}}
+	report, err := client.FindExternalDependencies(context.Background(), "file://"+chartDir, localChart, localVersion, custom, helm_parser.RenderOptions{})
+	if err != nil {
+		t.Fatalf("FindExternalDependencies() error = %v", err)
+	}
+	if want := []string{helm_parser.DependencyDatabase, helm_parser.DependencySMTP}; !reflect.DeepEqual(report.Categories, want) {
+		t.Errorf("categories = %v, want %v", report.Categories, want)
+	}
+}
@@ -0,0 +1,268 @@
+package helm_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// artifactHubURL is the Artifact Hub instance searched by FindChart.
+var artifactHubURL = "https://artifacthub.io"
+
+const (
+	// maxCandidateVersions is the number of versions listed per candidate.
+	maxCandidateVersions = 10
+	// artifactHubSearchLimit is the number of Artifact Hub search results
+	// requested; only results named like the chart are kept.
+	artifactHubSearchLimit = 60
+)
+
+// Sources of chart candidates.
+const (
+	CandidateSourceAlias       = "alias"
+	CandidateSourceArtifactHub = "artifacthub"
+)
+
+// ChartCandidate is a repository providing a chart, found by FindChart.
+type ChartCandidate struct {
+	RepositoryURL string `json:"repositoryURL"`
+	// Alias is the repository alias the chart was found through.
+	Alias string `json:"alias,omitempty"`
+	// Source is where the candidate was found: "alias" for the repositories
+	// of the repository aliases, or "artifacthub".
+	Source        string `json:"source"`
+	Chart         string `json:"chart"`
+	LatestVersion string `json:"latestVersion"`
+	AppVersion    string `json:"appVersion,omitempty"`
+	Description   string `json:"description,omitempty"`
+	// Versions are the most recent versions, newest first. Artifact Hub
+	// candidates only have their latest version.
+	Versions []string `json:"versions,omitempty"`
+	// Official and VerifiedPublisher are set by Artifact Hub for charts
+	// published by the authors of the software and by verified repositories.
+	Official          bool `json:"official,omitempty"`
+	VerifiedPublisher bool `json:"verifiedPublisher,omitempty"`
+}
+
+// FindChartResult is the result of FindChart.
+type FindChartResult struct {
+	Candidates []ChartCandidate `json:"candidates"`
+	// Errors maps the repositories that could not be searched, or
+	// "artifacthub", to the error.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// FindChart searches the repositories of the repository aliases, and Artifact
+// Hub if searchArtifactHub is set, for charts named chartName, compared
+// case-insensitively. Repositories that are not allowed are skipped. OCI
+// repositories are searched for chartName below their URL; as registries do
+// not tell a missing chart from other errors, their errors are ignored.
+func (c *HelmClient) FindChart(ctx context.Context, chartName string, searchArtifactHub bool) FindChartResult {
+	result := FindChartResult{Candidates: []ChartCandidate{}}
+
+	// Search every repository once, under its alphabetically first alias.
+	aliases := c.RepoAliases()
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var repos []string
+	repoAlias := make(map[string]string)
+	for _, name := range names {
+		repoURL := aliases[name]
+		if _, ok := repoAlias[repoURL]; ok || c.IsLocal(repoURL) || c.checkRepoAllowed(repoURL) != nil {
+			continue
+		}
+		repoAlias[repoURL] = name
+		repos = append(repos, repoURL)
+	}
+
+	var mu sync.Mutex
+	found := make(map[string][]ChartCandidate)
+	addError := func(source string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if result.Errors == nil {
+			result.Errors = make(map[string]string)
+		}
+		result.Errors[source] = err.Error()
+	}
+
+	var g errgroup.Group
+	g.SetLimit(maxParallelIndexDownloads)
+	for _, repoURL := range repos {
+		g.Go(func() error {
+			candidates, err := c.findChartInRepo(ctx, repoURL, chartName)
+			if err != nil {
+				addError(repoURL, err)
+				return nil
+			}
+			for i := range candidates {
+				candidates[i].Alias = repoAlias[repoURL]
+			}
+			mu.Lock()
+			found[repoURL] = candidates
+			mu.Unlock()
+			return nil
+		})
+	}
+	var hubCandidates []ChartCandidate
+	if searchArtifactHub {
+		g.Go(func() error {
+			var err error
+			if hubCandidates, err = c.searchArtifactHub(ctx, chartName); err != nil {
+				addError(CandidateSourceArtifactHub, err)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	seen := make(map[string]bool)
+	for _, repoURL := range repos {
+		for _, candidate := range found[repoURL] {
+			seen[normalizeRepoURL(candidate.RepositoryURL)] = true
+			result.Candidates = append(result.Candidates, candidate)
+		}
+	}
+	for _, candidate := range hubCandidates {
+		if !seen[normalizeRepoURL(candidate.RepositoryURL)] {
+			result.Candidates = append(result.Candidates, candidate)
+		}
+	}
+	return result
+}
+
+// findChartInRepo returns the charts named chartName in the repository at
+// repoURL.
+func (c *HelmClient) findChartInRepo(ctx context.Context, repoURL, chartName string) ([]ChartCandidate, error) {
+	if IsOCI(repoURL) {
+		versions, err := c.ListChartVersions(ctx, repoURL, chartName)
+		if err != nil || len(versions) == 0 {
+			return nil, nil
+		}
+		return []ChartCandidate{{
+			RepositoryURL: repoURL,
+			Source:        CandidateSourceAlias,
+			Chart:         chartName,
+			LatestVersion: versions[0],
+			Versions:      versions[:min(len(versions), maxCandidateVersions)],
+		}}, nil
+	}
+
+	entries, err := c.indexEntries(ctx, repoURL, "")
+	if err != nil {
+		return nil, err
+	}
+	var candidates []ChartCandidate
+	for name, versions := range entries {
+		if !strings.EqualFold(name, chartName) || len(versions) == 0 {
+			continue
+		}
+		candidate := ChartCandidate{
+			RepositoryURL: repoURL,
+			Source:        CandidateSourceAlias,
+			Chart:         name,
+			LatestVersion: versions[0].Version,
+		}
+		if m := versions[0].Metadata; m != nil {
+			candidate.AppVersion = m.AppVersion
+			candidate.Description = m.Description
+		}
+		for _, v := range versions[:min(len(versions), maxCandidateVersions)] {
+			candidate.Versions = append(candidate.Versions, v.Version)
+		}
+		candidates = append(candidates, candidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Chart < candidates[j].Chart })
+	return candidates, nil
+}
+
+// artifactHubPackage is a package in the response of the Artifact Hub search
+// API.
+type artifactHubPackage struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"app_version"`
+	Description string `json:"description"`
+	Official    bool   `json:"official"`
+	Repository  struct {
+		URL               string `json:"url"`
+		Official          bool   `json:"official"`
+		VerifiedPublisher bool   `json:"verified_publisher"`
+	} `json:"repository"`
+}
+
+// searchArtifactHub searches Artifact Hub for Helm charts named chartName.
+// Official charts and charts from verified publishers come first.
+func (c *HelmClient) searchArtifactHub(ctx context.Context, chartName string) ([]ChartCandidate, error) {
+	opCtx, cancel := withTimeout(ctx, c.options.repoTimeout)
+	defer cancel()
+
+	query := url.Values{
+		"ts_query_web": {chartName},
+		// Kind 0 is Helm charts.
+		"kind":  {"0"},
+		"limit": {fmt.Sprint(artifactHubSearchLimit)},
+	}
+	req, err := http.NewRequestWithContext(opCtx, http.MethodGet, artifactHubURL+"/api/v1/packages/search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err = timeoutErr(ctx, opCtx, c.options.repoTimeout, err); err != nil {
+		return nil, fmt.Errorf("failed to search Artifact Hub: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to search Artifact Hub: %s", resp.Status)
+	}
+
+	var body struct {
+		Packages []artifactHubPackage `json:"packages"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode Artifact Hub response: %v", err)
+	}
+
+	var candidates []ChartCandidate
+	for _, p := range body.Packages {
+		if !strings.EqualFold(p.Name, chartName) || p.Repository.URL == "" || c.checkRepoAllowed(p.Repository.URL) != nil {
+			continue
+		}
+		candidates = append(candidates, ChartCandidate{
+			RepositoryURL:     p.Repository.URL,
+			Source:            CandidateSourceArtifactHub,
+			Chart:             p.Name,
+			LatestVersion:     p.Version,
+			AppVersion:        p.AppVersion,
+			Description:       p.Description,
+			Official:          p.Official || p.Repository.Official,
+			VerifiedPublisher: p.Repository.VerifiedPublisher,
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return artifactHubRank(candidates[i]) > artifactHubRank(candidates[j])
+	})
+	return candidates, nil
+}
+
+func artifactHubRank(c ChartCandidate) int {
+	rank := 0
+	if c.Official {
+		rank += 2
+	}
+	if c.VerifiedPublisher {
+		rank++
+	}
+	return rank
+}
@@ -0,0 +1,76 @@
+package helm_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFindChart(t *testing.T) {
+	repoURL := startMetadataRepo(t)
+	otherURL := startMetadataRepo(t)
+	deniedURL := startMetadataRepo(t)
+
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/packages/search" || r.URL.Query().Get("ts_query_web") != "grafana" || r.URL.Query().Get("kind") != "0" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"packages": [
+			{"name": "grafana-agent", "version": "1.0.0", "repository": {"url": "https://agent.example.com"}},
+			{"name": "grafana", "version": "7.0.0", "repository": {"url": "https://fork.example.com"}},
+			{"name": "grafana", "version": "8.5.0", "repository": {"url": "` + repoURL + `/", "official": true}},
+			{"name": "grafana", "version": "8.6.0", "app_version": "11.3.0", "repository": {"url": "https://verified.example.com", "verified_publisher": true}}
+		]}`))
+	}))
+	defer hub.Close()
+
+	defaults, hubURL := defaultRepoAliases, artifactHubURL
+	defaultRepoAliases, artifactHubURL = map[string]string{"builtin": repoURL}, hub.URL
+	t.Cleanup(func() { defaultRepoAliases, artifactHubURL = defaults, hubURL })
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithDeniedRepos(deniedURL), WithRepoAliases(map[string]string{
+		"a-same": repoURL,
+		"other":  otherURL,
+		"denied": deniedURL,
+	}))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	fromAlias := func(alias, url string) ChartCandidate {
+		return ChartCandidate{
+			RepositoryURL: url,
+			Alias:         alias,
+			Source:        CandidateSourceAlias,
+			Chart:         "grafana",
+			LatestVersion: "8.5.0",
+			AppVersion:    "11.2.0",
+			Description:   "The leading tool for querying and visualizing time series and metrics.",
+			Versions:      []string{"8.5.0", "8.4.0"},
+		}
+	}
+
+	got := client.FindChart(context.Background(), "Grafana", false)
+	want := FindChartResult{Candidates: []ChartCandidate{fromAlias("a-same", repoURL), fromAlias("other", otherURL)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindChart() = %+v, want %+v", got, want)
+	}
+
+	got = client.FindChart(context.Background(), "grafana", true)
+	want.Candidates = append(want.Candidates,
+		ChartCandidate{RepositoryURL: "https://verified.example.com", Source: CandidateSourceArtifactHub, Chart: "grafana", LatestVersion: "8.6.0", AppVersion: "11.3.0", VerifiedPublisher: true},
+		ChartCandidate{RepositoryURL: "https://fork.example.com", Source: CandidateSourceArtifactHub, Chart: "grafana", LatestVersion: "7.0.0"},
+	)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindChart() = %+v, want %+v", got, want)
+	}
+
+	got = client.FindChart(context.Background(), "missing", true)
+	if len(got.Candidates) != 0 || got.Errors[CandidateSourceArtifactHub] == "" {
+		t.Errorf("FindChart() = %+v, want no candidates and an Artifact Hub error", got)
+	}
+}
@@ -0,0 +1,147 @@
+package helm_client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"helm.sh/helm/v4/pkg/getter"
+)
+
+// httpGetter fetches HTTP repository content. It replaces Helm's HTTP getter,
+// which does not accept a context, so index and chart downloads are aborted
+// when the tool call that started them is cancelled. It also supports an
+// "Authorization: Bearer" header, which Helm's getter lacks, for repositories
+// behind OAuth proxies or GitLab's package registry.
+type httpGetter struct {
+	ctx     context.Context
+	client  *http.Client
+	repoURL string
+	options *clientOptions
+	// maxSize limits the size of a fetched document; zero means no limit.
+	maxSize int64
+}
+
+// Get implements getter.Getter. Helm getter options are ignored: TLS settings
+// are already applied to the HTTP client, and credentials are taken from the
+// client options. Transient failures are retried, see retry.
+func (g *httpGetter) Get(href string, _ ...getter.Option) (*bytes.Buffer, error) {
+	var buf *bytes.Buffer
+	err := retry(g.ctx, g.options, func() error {
+		var err error
+		buf, err = g.get(href)
+		return err
+	})
+	return buf, err
+}
+
+func (g *httpGetter) get(href string) (*bytes.Buffer, error) {
+	req, err := http.NewRequestWithContext(g.ctx, http.MethodGet, href, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "mcp-helm")
+
+	// Same scoping rule as Helm's basic auth: only send credentials to the
+	// repository host unless passing credentials to all domains is enabled.
+	repo, err := url.Parse(g.repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse repository URL: %w", err)
+	}
+	if g.options.passCredentialsAll || (repo.Scheme == req.URL.Scheme && repo.Host == req.URL.Host) {
+		switch {
+		case g.options.bearerToken != "":
+			req.Header.Set("Authorization", "Bearer "+g.options.bearerToken)
+		case g.options.username != "" && g.options.password != "":
+			req.SetBasicAuth(g.options.username, g.options.password)
+		}
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{url: href, status: resp.Status, statusCode: resp.StatusCode}
+	}
+
+	if g.maxSize > 0 && resp.ContentLength > g.maxSize {
+		return nil, fmt.Errorf("%s is larger than the maximum chart size of %d bytes", href, g.maxSize)
+	}
+
+	body := newProgressReader(g.ctx, resp.Body, "Downloading "+path.Base(req.URL.Path), resp.ContentLength)
+	if g.maxSize > 0 {
+		// Read one byte more than allowed to detect an oversized body that
+		// did not announce its length.
+		body = io.LimitReader(body, g.maxSize+1)
+	}
+	buf := bytes.NewBuffer(nil)
+	if _, err = io.Copy(buf, body); err != nil {
+		return nil, err
+	}
+	if g.maxSize > 0 && int64(buf.Len()) > g.maxSize {
+		return nil, fmt.Errorf("%s is larger than the maximum chart size of %d bytes", href, g.maxSize)
+	}
+	return buf, nil
+}
+
+// newHTTPClient builds the HTTP client used by httpGetter, honouring the
+// configured CA, client certificate and TLS verification settings.
+func newHTTPClient(o *clientOptions) (*http.Client, error) {
+	tlsConfig, err := registryTLSConfig(o)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig.InsecureSkipVerify = o.insecureSkipTLSVerify
+
+	return &http.Client{
+		Transport: &http.Transport{
+			DisableCompression: true,
+			Proxy:              http.ProxyFromEnvironment,
+			TLSClientConfig:    tlsConfig,
+		},
+		// No client timeout: requests are bounded by the repository and
+		// download timeouts through their context.
+	}, nil
+}
+
+// getters returns the getter providers used for the HTTP repository at
+// repoURL. http(s) fetches are served by httpGetter bound to ctx; every other
+// scheme keeps Helm's built-in getters, including downloader plugins.
+func (c *HelmClient) getters(ctx context.Context, repoURL string) getter.Providers {
+	return c.httpGetters(ctx, repoURL, 0)
+}
+
+// chartGetters is like getters, but aborts http(s) downloads larger than the
+// configured maximum chart archive size.
+func (c *HelmClient) chartGetters(ctx context.Context, repoURL string) getter.Providers {
+	return c.httpGetters(ctx, repoURL, c.options.maxChartSize)
+}
+
+func (c *HelmClient) httpGetters(ctx context.Context, repoURL string, maxSize int64) getter.Providers {
+	provider := getter.Provider{
+		Schemes: []string{"http", "https"},
+		New: func(_ ...getter.Option) (getter.Getter, error) {
+			return &httpGetter{
+				ctx:     ctx,
+				client:  c.httpClient,
+				repoURL: repoURL,
+				options: c.options,
+				maxSize: maxSize,
+			}, nil
+		},
+	}
+	// Providers.ByScheme returns the first match, so this provider takes
+	// precedence over Helm's HTTP getter.
+	return append(getter.Providers{provider}, getter.All(c.settings)...)
+}
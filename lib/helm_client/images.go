@@ -0,0 +1,235 @@
+package helm_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/sync/errgroup"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// maxParallelImageLookups bounds the number of images looked up in their
+// registries at the same time.
+const maxParallelImageLookups = 8
+
+// maxManifestSize limits the size of a fetched image manifest or config.
+const maxManifestSize = 4 << 20
+
+// dockerManifestListMediaType is the Docker equivalent of an OCI image index.
+const dockerManifestListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+// ImagePlatforms lists the platforms an image is published for, such as
+// linux/amd64 or linux/arm64/v8, or the error looking them up.
+type ImagePlatforms struct {
+	Platforms []string
+	Err       error
+}
+
+// GetImagePlatforms looks up the platforms of images in their registries,
+// using at most maxParallelImageLookups workers. Multi-platform images report
+// the platforms of their manifest list, single-platform images the platform
+// in their config. The result has an entry for every image, in order.
+func (c *HelmClient) GetImagePlatforms(ctx context.Context, images []helm_parser.ImageReference) []ImagePlatforms {
+	results := make([]ImagePlatforms, len(images))
+	forEachImage(images, func(i int, image helm_parser.ImageReference) {
+		platforms, err := c.imagePlatforms(ctx, image)
+		results[i] = ImagePlatforms{Platforms: platforms, Err: err}
+	})
+	return results
+}
+
+// Image states reported by VerifyImages.
+const (
+	ImageFound = "found"
+	// ImageMissing means the registry does not have the tag or digest.
+	ImageMissing = "missing"
+	// ImageUnauthorized means the registry requires credentials, or denies
+	// access with the configured ones. Some registries, like Docker Hub,
+	// also report missing repositories this way.
+	ImageUnauthorized = "unauthorized"
+	// ImageError means the registry could not be queried.
+	ImageError = "error"
+)
+
+// ImageStatus reports whether an image exists in its registry.
+type ImageStatus struct {
+	// Status is one of ImageFound, ImageMissing, ImageUnauthorized and
+	// ImageError.
+	Status string
+	// Digest is the digest of the manifest of a found image.
+	Digest string
+	Err    error
+}
+
+// VerifyImages checks that images exist in their registries by resolving
+// their manifests, using at most maxParallelImageLookups workers. It catches
+// charts whose default tags were never published. The result has an entry
+// for every image, in order.
+func (c *HelmClient) VerifyImages(ctx context.Context, images []helm_parser.ImageReference) []ImageStatus {
+	results := make([]ImageStatus, len(images))
+	forEachImage(images, func(i int, image helm_parser.ImageReference) {
+		results[i] = c.verifyImage(ctx, image)
+	})
+	return results
+}
+
+func (c *HelmClient) verifyImage(ctx context.Context, image helm_parser.ImageReference) ImageStatus {
+	repo, err := c.imageRepository(ctx, image)
+	if err != nil {
+		return ImageStatus{Status: ImageError, Err: err}
+	}
+
+	desc, err := repo.Resolve(ctx, imageReference(image))
+	if err == nil {
+		return ImageStatus{Status: ImageFound, Digest: desc.Digest.String()}
+	}
+
+	var errResp *errcode.ErrorResponse
+	switch {
+	case errors.Is(err, errdef.ErrNotFound):
+		return ImageStatus{Status: ImageMissing, Err: err}
+	case errors.As(err, &errResp) && errResp.StatusCode == http.StatusNotFound:
+		return ImageStatus{Status: ImageMissing, Err: err}
+	case errors.As(err, &errResp) && (errResp.StatusCode == http.StatusUnauthorized || errResp.StatusCode == http.StatusForbidden):
+		return ImageStatus{Status: ImageUnauthorized, Err: err}
+	default:
+		return ImageStatus{Status: ImageError, Err: err}
+	}
+}
+
+// forEachImage calls fn for every image, using at most
+// maxParallelImageLookups workers.
+func forEachImage(images []helm_parser.ImageReference, fn func(i int, image helm_parser.ImageReference)) {
+	var g errgroup.Group
+	g.SetLimit(maxParallelImageLookups)
+	for i, image := range images {
+		g.Go(func() error {
+			fn(i, image)
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
+
+func (c *HelmClient) imagePlatforms(ctx context.Context, image helm_parser.ImageReference) ([]string, error) {
+	repo, err := c.imageRepository(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, data, err := fetchManifest(ctx, repo, imageReference(image))
+	if err != nil {
+		return nil, err
+	}
+
+	var platforms []string
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageIndex, dockerManifestListMediaType:
+		var index ocispec.Index
+		if err := json.Unmarshal(data, &index); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest list of %s: %v", image.FullImage, err)
+		}
+		for _, m := range index.Manifests {
+			// Attestation manifests are listed with an unknown platform.
+			if m.Platform == nil || m.Platform.OS == "unknown" {
+				continue
+			}
+			platforms = append(platforms, formatPlatform(*m.Platform))
+		}
+	default:
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest of %s: %v", image.FullImage, err)
+		}
+		rc, err := repo.Fetch(ctx, manifest.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch config of %s: %v", image.FullImage, err)
+		}
+		defer func() { _ = rc.Close() }()
+		var platform ocispec.Platform
+		if err := json.NewDecoder(io.LimitReader(rc, maxManifestSize)).Decode(&platform); err != nil {
+			return nil, fmt.Errorf("failed to parse config of %s: %v", image.FullImage, err)
+		}
+		platforms = append(platforms, formatPlatform(platform))
+	}
+
+	slices.Sort(platforms)
+	return slices.Compact(platforms), nil
+}
+
+func fetchManifest(ctx context.Context, repo *remote.Repository, reference string) (ocispec.Descriptor, []byte, error) {
+	desc, rc, err := repo.FetchReference(ctx, reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxManifestSize))
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	return desc, data, nil
+}
+
+// imageRepository returns a client for the repository of image whose requests
+// are cancelled together with ctx. It uses the TLS settings of OCI registries
+// and the credentials from -registry-credentials, or the Docker config if not
+// set. The basic auth credentials of chart repositories are not sent to image
+// registries.
+func (c *HelmClient) imageRepository(ctx context.Context, image helm_parser.ImageReference) (*remote.Repository, error) {
+	host := image.Registry
+	if host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+	repo, err := remote.NewRepository(host + "/" + image.Repository)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image %s: %v", image.FullImage, err)
+	}
+
+	var store credentials.Store
+	if c.options.credentialsFile != "" {
+		store, err = newCredStore(c.options.credentialsFile)
+	} else {
+		store, err = credentials.NewStoreFromDocker(credentials.StoreOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry credentials: %v", err)
+	}
+
+	repo.PlainHTTP = c.options.plainHTTP
+	repo.Client = &auth.Client{
+		Client:     &http.Client{Transport: &callTransport{ctx: ctx, base: c.registryTransport}},
+		Cache:      auth.NewCache(),
+		Credential: credentials.Credential(store),
+	}
+	return repo, nil
+}
+
+// imageReference returns the digest of image if it is pinned, otherwise its
+// tag.
+func imageReference(image helm_parser.ImageReference) string {
+	if image.Digest != "" {
+		return image.Digest
+	}
+	return image.Tag
+}
+
+func formatPlatform(p ocispec.Platform) string {
+	platform := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		platform += "/" + p.Variant
+	}
+	return platform
+}
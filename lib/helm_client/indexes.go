@@ -0,0 +1,101 @@
+package helm_client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"helm.sh/helm/v4/pkg/repo/v1"
+
+	"github.com/zekker6/mcp-helm/lib/logger"
+)
+
+// maxParallelIndexDownloads bounds the number of repository indexes
+// downloaded at the same time by multi-repository operations.
+const maxParallelIndexDownloads = 4
+
+// LoadIndexes downloads and parses the indexes of several HTTP repositories
+// concurrently, using at most maxParallelIndexDownloads workers. Indexes that
+// are already cached and not expired are not downloaded again. OCI registries
+// and local charts have no index and are skipped.
+//
+// The returned map holds the error for every repository that failed to load;
+// it is empty if all indexes were loaded.
+func (c *HelmClient) LoadIndexes(ctx context.Context, repoURLs []string) map[string]error {
+	return c.loadIndexes(ctx, repoURLs, c.getRepo)
+}
+
+// KeepIndexesWarm loads the indexes of the HTTP repositories repoURLs in the
+// background and keeps them loaded until ctx is done, so tool calls do not
+// wait for their download. Indexes are downloaded again after half the index
+// TTL, before they expire, while requests keep using the cached ones; without
+// a TTL they are loaded once. Failures are logged and retried on the next
+// refresh.
+func (c *HelmClient) KeepIndexesWarm(ctx context.Context, repoURLs []string) {
+	warm := func(load func(ctx context.Context, name, url string) (*repo.ChartRepository, error)) {
+		start := time.Now()
+		errs := c.loadIndexes(ctx, repoURLs, load)
+		if ctx.Err() != nil {
+			return
+		}
+		for repoURL, err := range errs {
+			logger.Warn("failed to prewarm repository index", zap.String("repository", repoURL), zap.Error(err))
+		}
+		logger.Debug("prewarmed repository indexes", zap.Int("repositories", len(repoURLs)), zap.Int("failed", len(errs)), zap.Duration("duration", time.Since(start)))
+	}
+
+	go func() {
+		warm(c.getRepo)
+
+		ttl := c.options.indexTTL
+		if ttl <= 0 {
+			return
+		}
+		ticker := time.NewTicker(ttl / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				warm(c.fetchRepo)
+			}
+		}
+	}()
+}
+
+// loadIndexes loads the indexes of repoURLs concurrently with load, which is
+// either getRepo or fetchRepo.
+func (c *HelmClient) loadIndexes(ctx context.Context, repoURLs []string, load func(ctx context.Context, name, url string) (*repo.ChartRepository, error)) map[string]error {
+	var (
+		mu   sync.Mutex
+		errs = make(map[string]error)
+	)
+
+	var g errgroup.Group
+	g.SetLimit(maxParallelIndexDownloads)
+	for _, repoURL := range repoURLs {
+		if c.IsLocal(repoURL) || IsOCI(repoURL) {
+			continue
+		}
+		if err := c.checkRepoAllowed(repoURL); err != nil {
+			mu.Lock()
+			errs[repoURL] = err
+			mu.Unlock()
+			continue
+		}
+		g.Go(func() error {
+			if _, err := load(ctx, repoURL, repoURL); err != nil {
+				mu.Lock()
+				errs[repoURL] = err
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return errs
+}
@@ -0,0 +1,102 @@
+package helm_client
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"helm.sh/helm/v4/pkg/chart/loader"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+const fileScheme = "file://"
+
+// WithLocalCharts allows reading charts from the local filesystem, given as
+// file:// URLs or paths to chart directories. Local charts are rejected by
+// default, as they expose the filesystem of the host to callers of the client.
+func WithLocalCharts(enabled bool) ClientOption {
+	return func(o *clientOptions) {
+		o.localCharts = enabled
+	}
+}
+
+// IsLocal reports whether repoURL points to a chart on the local filesystem,
+// either as a file:// URL or as a plain path to an existing directory. Plain
+// paths are only looked up if local charts are enabled with WithLocalCharts,
+// so that callers cannot probe the filesystem otherwise.
+func (c *HelmClient) IsLocal(repoURL string) bool {
+	if strings.HasPrefix(repoURL, fileScheme) {
+		return true
+	}
+	if !c.options.localCharts || strings.Contains(repoURL, "://") {
+		return false
+	}
+	info, err := os.Stat(repoURL)
+	return err == nil && info.IsDir()
+}
+
+// checkLocalAllowed returns an error unless local charts are enabled with
+// WithLocalCharts.
+func (c *HelmClient) checkLocalAllowed(repoURL string) error {
+	if !c.options.localCharts {
+		return fmt.Errorf("access to local chart %s is disabled", repoURL)
+	}
+	return nil
+}
+
+// localChartPath converts a local repository URL into a filesystem path,
+// e.g. "file:///charts/mychart" -> "/charts/mychart".
+func localChartPath(repoURL string) string {
+	return strings.TrimPrefix(repoURL, fileScheme)
+}
+
+// loadLocalChart loads an unpacked chart directory. Unlike remote charts it is
+// read from disk on every call, so edits are picked up immediately.
+func (c *HelmClient) loadLocalChart(repoURL string) (*chartv2.Chart, error) {
+	if err := c.checkLocalAllowed(repoURL); err != nil {
+		return nil, err
+	}
+	dir := localChartPath(repoURL)
+
+	loadedChart, err := loader.LoadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load local chart %s: %v", dir, err)
+	}
+
+	v2Chart, ok := loadedChart.(*chartv2.Chart)
+	if !ok {
+		return nil, fmt.Errorf("charts V3 format is not supported for local chart %s", dir)
+	}
+
+	return v2Chart, nil
+}
+
+// LocalChartName returns the name declared in Chart.yaml of a local chart.
+func (c *HelmClient) LocalChartName(repoURL string) (string, error) {
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return "", err
+	}
+	loadedChart, err := c.loadLocalChart(repoURL)
+	if err != nil {
+		return "", err
+	}
+	return loadedChart.Name(), nil
+}
+
+// loadChartFromLocal loads a local chart and checks that it matches the
+// requested name and version. Empty values match any chart.
+func (c *HelmClient) loadChartFromLocal(repoURL, chartName, version string) (*chartv2.Chart, error) {
+	loadedChart, err := c.loadLocalChart(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if chartName != "" && loadedChart.Name() != chartName {
+		return nil, fmt.Errorf("local chart at %s is %s, not %s", localChartPath(repoURL), loadedChart.Name(), chartName)
+	}
+	if version != "" && loadedChart.Metadata.Version != version {
+		return nil, fmt.Errorf("local chart %s has version %s, not %s", loadedChart.Name(), loadedChart.Metadata.Version, version)
+	}
+
+	return loadedChart, nil
+}
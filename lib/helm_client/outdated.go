@@ -0,0 +1,135 @@
+package helm_client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"golang.org/x/sync/errgroup"
+)
+
+// DependencyUpdate reports how far a chart dependency is behind the latest
+// version in its repository.
+type DependencyUpdate struct {
+	Name       string `json:"name"`
+	Repository string `json:"repository"`
+	// Constraint is the version constraint declared in Chart.yaml.
+	Constraint string `json:"constraint"`
+	// Current is the version of the bundled subchart, or the highest version
+	// satisfying the constraint if the dependency is not bundled.
+	Current  string `json:"current,omitempty"`
+	Latest   string `json:"latest,omitempty"`
+	Outdated bool   `json:"outdated"`
+	// Behind is "major", "minor" or "patch" for outdated dependencies.
+	Behind string `json:"behind,omitempty"`
+	// ConstraintAllowsLatest reports whether updating the dependencies
+	// without changing Chart.yaml picks up the latest version.
+	ConstraintAllowsLatest bool `json:"constraintAllowsLatest"`
+	// Error is set if the dependency could not be checked.
+	Error string `json:"error,omitempty"`
+}
+
+// CheckDependencyUpdates queries the repository of every dependency declared
+// by a chart for its latest version, using at most maxParallelIndexDownloads
+// workers, and reports the dependencies that are behind.
+func (c *HelmClient) CheckDependencyUpdates(ctx context.Context, repoURL, chartName, version string) ([]DependencyUpdate, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+	}
+	if loadedChart == nil {
+		return nil, fmt.Errorf("chart %s version %s not found", chartName, version)
+	}
+
+	bundled := make(map[string]string)
+	for _, sub := range loadedChart.Dependencies() {
+		bundled[sub.Name()] = sub.Metadata.Version
+	}
+
+	deps := loadedChart.Metadata.Dependencies
+	updates := make([]DependencyUpdate, len(deps))
+
+	var g errgroup.Group
+	g.SetLimit(maxParallelIndexDownloads)
+	for i, dep := range deps {
+		updates[i] = DependencyUpdate{
+			Name:       dep.Name,
+			Repository: dep.Repository,
+			Constraint: dep.Version,
+			Current:    bundled[dep.Name],
+		}
+		g.Go(func() error {
+			if err := c.checkDependencyUpdate(ctx, &updates[i]); err != nil {
+				updates[i].Error = err.Error()
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return updates, nil
+}
+
+func (c *HelmClient) checkDependencyUpdate(ctx context.Context, u *DependencyUpdate) error {
+	switch {
+	case u.Repository == "" || strings.HasPrefix(u.Repository, "file://"):
+		return fmt.Errorf("dependencies without a remote repository cannot be checked")
+	case strings.HasPrefix(u.Repository, "@") || strings.HasPrefix(u.Repository, "alias:"):
+		return fmt.Errorf("repository alias %s refers to the local Helm configuration and cannot be resolved", u.Repository)
+	}
+
+	latest, err := c.GetChartLatestVersion(ctx, u.Repository, u.Name)
+	if err != nil {
+		return err
+	}
+	u.Latest = latest
+	latestVersion, err := semver.NewVersion(latest)
+	if err != nil {
+		return fmt.Errorf("latest version %q is not a semantic version", latest)
+	}
+
+	constraint, err := semver.NewConstraint(u.Constraint)
+	if err != nil {
+		return fmt.Errorf("invalid version constraint %q: %v", u.Constraint, err)
+	}
+	u.ConstraintAllowsLatest = constraint.Check(latestVersion)
+
+	if u.Current == "" {
+		versions, err := c.ListChartVersions(ctx, u.Repository, u.Name)
+		if err != nil {
+			return err
+		}
+		var best *semver.Version
+		for _, v := range versions {
+			sv, err := semver.NewVersion(v)
+			if err != nil || !constraint.Check(sv) {
+				continue
+			}
+			if best == nil || sv.GreaterThan(best) {
+				best = sv
+			}
+		}
+		if best == nil {
+			return fmt.Errorf("no version of %s satisfies %q", u.Name, u.Constraint)
+		}
+		u.Current = best.Original()
+	}
+
+	current, err := semver.NewVersion(u.Current)
+	if err != nil {
+		return fmt.Errorf("current version %q is not a semantic version", u.Current)
+	}
+	if latestVersion.GreaterThan(current) {
+		u.Outdated = true
+		switch {
+		case latestVersion.Major() > current.Major():
+			u.Behind = "major"
+		case latestVersion.Minor() > current.Minor():
+			u.Behind = "minor"
+		default:
+			u.Behind = "patch"
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package helm_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckDependencyUpdates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`apiVersion: v1
+entries:
+  redis:
+    - {name: redis, version: 18.1.0, urls: [redis-18.1.0.tgz]}
+    - {name: redis, version: 17.3.2, urls: [redis-17.3.2.tgz]}
+    - {name: redis, version: 17.0.0, urls: [redis-17.0.0.tgz]}
+  postgresql:
+    - {name: postgresql, version: 15.2.3, urls: [postgresql-15.2.3.tgz]}
+`))
+	}))
+	defer server.Close()
+
+	chartDir := filepath.Join(t.TempDir(), "umbrella")
+	if err := os.MkdirAll(chartDir, 0o755); err != nil {
+		t.Fatalf("mkdir chart dir: %v", err)
+	}
+	chartYAML := `apiVersion: v2
+name: umbrella
+version: 1.0.0
+dependencies:
+  - {name: redis, version: 17.x, repository: "` + server.URL + `"}
+  - {name: postgresql, version: ~15.2.0, repository: "` + server.URL + `"}
+  - {name: common, version: 1.0.0, repository: "file://../common"}
+`
+	if err := os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(chartYAML), 0o644); err != nil {
+		t.Fatalf("write Chart.yaml: %v", err)
+	}
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	updates, err := client.CheckDependencyUpdates(context.Background(), "file://"+chartDir, "umbrella", "")
+	if err != nil {
+		t.Fatalf("CheckDependencyUpdates() error = %v", err)
+	}
+	if len(updates) != 3 {
+		t.Fatalf("got %d updates, want 3: %+v", len(updates), updates)
+	}
+
+	want := []DependencyUpdate{
+		{Name: "redis", Repository: server.URL, Constraint: "17.x", Current: "17.3.2", Latest: "18.1.0", Outdated: true, Behind: "major"},
+		{Name: "postgresql", Repository: server.URL, Constraint: "~15.2.0", Current: "15.2.3", Latest: "15.2.3", ConstraintAllowsLatest: true},
+	}
+	if !reflect.DeepEqual(updates[:2], want) {
+		t.Errorf("CheckDependencyUpdates() = %+v, want %+v", updates[:2], want)
+	}
+	if updates[2].Error == "" {
+		t.Errorf("expected an error for a file:// dependency, got %+v", updates[2])
+	}
+}
@@ -0,0 +1,202 @@
+package helm_client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/downloader"
+)
+
+// ChartPackage reports a chart archive written by PackageChart.
+type ChartPackage struct {
+	Chart      string `json:"chart"`
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion,omitempty"`
+	// Path is the written archive, <dir>/<chart>-<version>.tgz.
+	Path string `json:"path"`
+	// Digest is the sha256 digest of the archive, e.g. sha256:2c26b4...
+	Digest string `json:"digest"`
+}
+
+// PackageChart packages the local chart directory repoURL, a path or file://
+// URL, into dir/<chart>-<version>.tgz like helm package, replacing an existing
+// archive only if overwrite is set. version and appVersion override those of
+// Chart.yaml if not empty. With dependencyUpdate the dependencies are
+// downloaded first like helm dependency update, into a copy of the chart so
+// the chart directory itself is left untouched.
+func (c *HelmClient) PackageChart(ctx context.Context, repoURL, dir, version, appVersion string, dependencyUpdate, overwrite bool) (*ChartPackage, error) {
+	if !c.IsLocal(repoURL) {
+		return nil, fmt.Errorf("%s is not a local chart directory", repoURL)
+	}
+	if err := c.checkLocalAllowed(repoURL); err != nil {
+		return nil, err
+	}
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return nil, err
+	}
+	if version != "" {
+		if _, err := semver.StrictNewVersion(version); err != nil {
+			return nil, fmt.Errorf("invalid version %q: %v", version, err)
+		}
+	}
+
+	chartDir := localChartPath(repoURL)
+	if dependencyUpdate {
+		if err := os.MkdirAll(c.tempDir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create temp dir: %v", err)
+		}
+		tempDir, err := os.MkdirTemp(c.tempDir, "helm-dependency-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temp dir: %v", err)
+		}
+		defer func() { _ = os.RemoveAll(tempDir) }()
+
+		chartCopy := filepath.Join(tempDir, "chart")
+		if err := c.updateDependencies(ctx, chartDir, chartCopy); err != nil {
+			return nil, err
+		}
+		chartDir = chartCopy
+	}
+
+	loadedChart, err := c.loadLocalChart(chartDir)
+	if err != nil {
+		return nil, err
+	}
+	if version != "" {
+		loadedChart.Metadata.Version = version
+	}
+	if appVersion != "" {
+		loadedChart.Metadata.AppVersion = appVersion
+	}
+	if missing := missingDependencies(loadedChart); len(missing) > 0 {
+		return nil, fmt.Errorf("dependencies of chart %s are missing in its charts/ directory, update them first: %s", loadedChart.Name(), strings.Join(missing, ", "))
+	}
+
+	data, err := c.packageChart(loadedChart)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %v", dir, err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.tgz", loadedChart.Name(), loadedChart.Metadata.Version))
+	if err := writeChartArchive(path, data, overwrite); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	return &ChartPackage{
+		Chart:      loadedChart.Name(),
+		Version:    loadedChart.Metadata.Version,
+		AppVersion: loadedChart.Metadata.AppVersion,
+		Path:       path,
+		Digest:     "sha256:" + hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// updateDependencies copies the chart directory chartDir to chartCopy and
+// downloads the dependencies to the charts/ directory of the copy, like helm
+// dependency update. The repositories of the dependencies must be allowed;
+// relative file:// repositories are resolved against chartDir.
+func (c *HelmClient) updateDependencies(ctx context.Context, chartDir, chartCopy string) error {
+	if err := os.CopyFS(chartCopy, os.DirFS(chartDir)); err != nil {
+		return fmt.Errorf("failed to copy chart %s: %v", chartDir, err)
+	}
+	chartFile := filepath.Join(chartCopy, chartutil.ChartfileName)
+	metadata, err := chartutil.LoadChartfile(chartFile)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %v", chartutil.ChartfileName, err)
+	}
+	for _, dep := range metadata.Dependencies {
+		switch {
+		case dep.Repository == "":
+			continue
+		case strings.HasPrefix(dep.Repository, "@"), strings.HasPrefix(dep.Repository, "alias:"):
+			return fmt.Errorf("dependency %s refers to repository alias %s, which is not supported: use the repository URL", dep.Name, dep.Repository)
+		case strings.HasPrefix(dep.Repository, fileScheme):
+			if path := localChartPath(dep.Repository); !filepath.IsAbs(path) {
+				dep.Repository = fileScheme + filepath.Join(chartDir, path)
+			}
+		}
+		if err := c.checkRepoAllowed(dep.Repository); err != nil {
+			return fmt.Errorf("dependency %s: %v", dep.Name, err)
+		}
+	}
+	if err := chartutil.SaveChartfile(chartFile, metadata); err != nil {
+		return fmt.Errorf("failed to write %s: %v", chartutil.ChartfileName, err)
+	}
+
+	opCtx, cancel := withTimeout(ctx, c.options.downloadTimeout)
+	defer cancel()
+	regClient, err := c.ociClient(opCtx, "")
+	if err != nil {
+		return fmt.Errorf("failed to create registry client: %v", err)
+	}
+
+	man := &downloader.Manager{
+		Out:              io.Discard,
+		ChartPath:        chartCopy,
+		Verify:           downloader.VerifyNever,
+		Getters:          c.chartGetters(opCtx, ""),
+		RegistryClient:   regClient,
+		RepositoryConfig: c.settings.RepositoryConfig,
+		RepositoryCache:  c.settings.RepositoryCache,
+		ContentCache:     c.settings.ContentCache,
+	}
+	reportProgress(ctx, "Updating dependencies of "+chartDir, 0, 0)
+	_, err = runWithContext(opCtx, func() (struct{}, error) {
+		return struct{}{}, man.Update()
+	})
+	if err = timeoutErr(ctx, opCtx, c.options.downloadTimeout, err); err != nil {
+		return fmt.Errorf("failed to update dependencies of %s: %v", chartDir, err)
+	}
+	return nil
+}
+
+// missingDependencies returns the dependencies declared in Chart.yaml of ch
+// that are not in its charts/ directory, which helm package rejects.
+func missingDependencies(ch *chartv2.Chart) []string {
+	present := make(map[string]bool, len(ch.Dependencies()))
+	for _, dep := range ch.Dependencies() {
+		present[dep.Name()] = true
+	}
+	var missing []string
+	for _, dep := range ch.Metadata.Dependencies {
+		if !present[dep.Name] {
+			missing = append(missing, dep.Name)
+		}
+	}
+	return missing
+}
+
+// packageChart returns the chart archive of ch, as written by helm package.
+func (c *HelmClient) packageChart(ch *chartv2.Chart) ([]byte, error) {
+	if err := os.MkdirAll(c.tempDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %v", err)
+	}
+	tempDir, err := os.MkdirTemp(c.tempDir, "helm-package-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	path, err := chartutil.Save(ch, tempDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to package chart %s: %v", ch.Name(), err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read packaged chart %s: %v", path, err)
+	}
+	return data, nil
+}
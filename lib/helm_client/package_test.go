@@ -0,0 +1,120 @@
+package helm_client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/chart/loader"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestPackageChart(t *testing.T) {
+	chartDir := writeLocalChart(t)
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	dir := filepath.Join(t.TempDir(), "dist")
+	pkg, err := client.PackageChart(context.Background(), "file://"+chartDir, dir, "2.0.0", "v9", false, false)
+	if err != nil {
+		t.Fatalf("PackageChart() error = %v", err)
+	}
+	if want := filepath.Join(dir, localChart+"-2.0.0.tgz"); pkg.Path != want {
+		t.Errorf("Path = %s, want %s", pkg.Path, want)
+	}
+	if pkg.Chart != localChart || pkg.Version != "2.0.0" || pkg.AppVersion != "v9" {
+		t.Errorf("PackageChart() = %+v, want chart %s version 2.0.0 appVersion v9", pkg, localChart)
+	}
+
+	data, err := os.ReadFile(pkg.Path)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	if want := "sha256:" + hex.EncodeToString(sum[:]); pkg.Digest != want {
+		t.Errorf("Digest = %s, want %s", pkg.Digest, want)
+	}
+	loaded, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("load archive: %v", err)
+	}
+	if meta := loaded.(*chartv2.Chart).Metadata; meta.Version != "2.0.0" || meta.AppVersion != "v9" {
+		t.Errorf("packaged Chart.yaml has version %s appVersion %s, want 2.0.0 and v9", meta.Version, meta.AppVersion)
+	}
+
+	if _, err := client.PackageChart(context.Background(), chartDir, dir, "2.0.0", "", false, false); err == nil {
+		t.Error("expected an error for an existing archive without overwrite")
+	}
+	if _, err := client.PackageChart(context.Background(), chartDir, dir, "2.0.0", "", false, true); err != nil {
+		t.Errorf("PackageChart(overwrite) error = %v", err)
+	}
+	if _, err := client.PackageChart(context.Background(), chartDir, dir, "latest", "", false, false); err == nil {
+		t.Error("expected an error for a version that is not semver")
+	}
+}
+
+func TestPackageChartDependencyUpdate(t *testing.T) {
+	repoURL, _ := startHTTPChartRepo(t, false, buildMatrixChartTGZ(t))
+	chartDir := writeLocalChart(t)
+	chartYAML := "apiVersion: v2\nname: " + localChart + "\nversion: " + localVersion + "\n" +
+		"dependencies:\n- name: " + matrixChart + "\n  version: " + matrixVersion + "\n  repository: " + repoURL + "\n"
+	if err := os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(chartYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	dir := t.TempDir()
+	if _, err := client.PackageChart(context.Background(), chartDir, dir, "", "", false, false); err == nil {
+		t.Error("expected an error for missing dependencies without dependency update")
+	}
+
+	pkg, err := client.PackageChart(context.Background(), chartDir, dir, "", "", true, false)
+	if err != nil {
+		t.Fatalf("PackageChart(dependency update) error = %v", err)
+	}
+	for _, name := range []string{"Chart.lock", "charts"} {
+		if _, err := os.Stat(filepath.Join(chartDir, name)); !os.IsNotExist(err) {
+			t.Errorf("expected the chart directory to be left untouched, found %s", name)
+		}
+	}
+	loaded, err := loader.Load(pkg.Path)
+	if err != nil {
+		t.Fatalf("load archive: %v", err)
+	}
+	deps := loaded.(*chartv2.Chart).Dependencies()
+	if len(deps) != 1 || deps[0].Name() != matrixChart {
+		t.Errorf("packaged dependencies = %v, want %s", deps, matrixChart)
+	}
+
+	denied, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true), WithDeniedRepos(repoURL))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = denied.Close() }()
+	if _, err := denied.PackageChart(context.Background(), chartDir, t.TempDir(), "", "", true, false); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("expected the dependency repository to be denied, got %v", err)
+	}
+
+	allowed, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true), WithAllowedRepos(repoURL))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = allowed.Close() }()
+	if _, err := allowed.PackageChart(context.Background(), chartDir, t.TempDir(), "", "", false, false); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("expected the local chart to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package helm_client
+
+import (
+	"context"
+	"io"
+)
+
+// ProgressFunc receives progress updates of a long-running client call.
+// stage describes the current step, e.g. "Downloading nginx-1.2.3.tgz". For
+// downloads done and total are byte counts, total being zero if the size is
+// unknown; for other stages both are zero.
+type ProgressFunc func(stage string, done, total int64)
+
+type progressKey struct{}
+
+// WithProgress returns a context that makes client calls made with it report
+// their progress to fn.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress reports progress to the ProgressFunc attached to ctx, if any.
+func reportProgress(ctx context.Context, stage string, done, total int64) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		fn(stage, done, total)
+	}
+}
+
+// progressReportStep is the amount of data read between progress reports of
+// downloads of unknown size.
+const progressReportStep = 1 << 20
+
+// progressReader reports the progress of reading a download to the
+// ProgressFunc of ctx, in steps of 10% or progressReportStep bytes if the
+// size is unknown.
+type progressReader struct {
+	ctx   context.Context
+	r     io.Reader
+	stage string
+	total int64
+
+	done     int64
+	reported int64
+}
+
+func newProgressReader(ctx context.Context, r io.Reader, stage string, total int64) io.Reader {
+	if _, ok := ctx.Value(progressKey{}).(ProgressFunc); !ok {
+		return r
+	}
+	reportProgress(ctx, stage, 0, max(total, 0))
+	return &progressReader{ctx: ctx, r: r, stage: stage, total: max(total, 0)}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+
+	step := int64(progressReportStep)
+	if p.total > 0 {
+		step = max(p.total/10, 1)
+	}
+	if p.done-p.reported >= step || (err == io.EOF && p.done > p.reported) {
+		p.reported = p.done
+		reportProgress(p.ctx, p.stage, p.done, p.total)
+	}
+	return n, err
+}
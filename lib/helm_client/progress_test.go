@@ -0,0 +1,48 @@
+package helm_client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+)
+
+func TestProgressReader(t *testing.T) {
+	type report struct {
+		stage       string
+		done, total int64
+	}
+	var reports []report
+	ctx := WithProgress(context.Background(), func(stage string, done, total int64) {
+		reports = append(reports, report{stage, done, total})
+	})
+
+	data := bytes.Repeat([]byte("x"), 1000)
+	r := newProgressReader(ctx, bytes.NewReader(data), "Downloading chart.tgz", int64(len(data)))
+	// Read in chunks of 100 bytes to get one report per 10%.
+	buf := make([]byte, 100)
+	for {
+		if _, err := r.Read(buf); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+	}
+
+	if len(reports) != 11 {
+		t.Fatalf("expected 11 reports, got %d: %v", len(reports), reports)
+	}
+	if reports[0].done != 0 || reports[10].done != 1000 {
+		t.Errorf("expected reports from 0 to 1000 bytes, got %v", reports)
+	}
+	for _, rep := range reports {
+		if rep.stage != "Downloading chart.tgz" || rep.total != 1000 {
+			t.Errorf("unexpected report %v", rep)
+		}
+	}
+
+	plain := bytes.NewReader(data)
+	if r := newProgressReader(context.Background(), plain, "Downloading chart.tgz", 0); r != io.Reader(plain) {
+		t.Error("expected the reader to be returned unchanged without a ProgressFunc")
+	}
+}
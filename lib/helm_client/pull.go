@@ -0,0 +1,103 @@
+package helm_client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"helm.sh/helm/v4/pkg/chart/loader"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+)
+
+// PullChart saves the archive of a chart version to dir as
+// <chart>-<version>.tgz, like helm pull, or unpacks it to dir/<chart> if
+// untar is set, and returns the path written. Existing files are only
+// replaced if overwrite is set. Local charts are already on disk and cannot
+// be pulled.
+func (c *HelmClient) PullChart(ctx context.Context, repoURL, chartName, version, dir string, untar, overwrite bool) (string, error) {
+	if c.IsLocal(repoURL) {
+		return "", fmt.Errorf("local chart %s is already on disk", localChartPath(repoURL))
+	}
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return "", err
+	}
+
+	var data []byte
+	var err error
+	if IsOCI(repoURL) {
+		data, err = c.pullOCIChartArchive(ctx, repoURL, parseOCIReference(repoURL, chartName, version))
+	} else {
+		data, _, err = c.httpChartArchive(ctx, repoURL, chartName, version)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := c.checkChartSize(data); err != nil {
+		return "", fmt.Errorf("chart %s version %s: %v", chartName, version, err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %v", dir, err)
+	}
+
+	if !untar {
+		path := filepath.Join(dir, fmt.Sprintf("%s-%s.tgz", chartName, version))
+		if err := writeChartArchive(path, data, overwrite); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+
+	// The archive is unpacked to a directory named after the chart in its
+	// Chart.yaml.
+	loadedChart, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to load chart archive: %v", err)
+	}
+	v2Chart, ok := loadedChart.(*chartv2.Chart)
+	if !ok {
+		return "", fmt.Errorf("charts V3 format is not supported")
+	}
+	path := filepath.Join(dir, v2Chart.Name())
+	if _, err := os.Lstat(path); err == nil {
+		if !overwrite {
+			return "", fmt.Errorf("%s already exists", path)
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return "", fmt.Errorf("failed to remove %s: %v", path, err)
+		}
+	}
+	if err := chartutil.Expand(dir, bytes.NewReader(data)); err != nil {
+		return "", fmt.Errorf("failed to unpack chart: %v", err)
+	}
+	return path, nil
+}
+
+// writeChartArchive writes the chart archive data to path, replacing an
+// existing file only if overwrite is set.
+func writeChartArchive(path string, data []byte, overwrite bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !overwrite {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save chart: %v", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to save chart: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to save chart: %v", err)
+	}
+	return nil
+}
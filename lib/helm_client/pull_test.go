@@ -0,0 +1,66 @@
+package helm_client
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPullChart(t *testing.T) {
+	tgz := buildMatrixChartTGZ(t)
+	repoURL, _ := startHTTPChartRepo(t, false, tgz)
+	host := startOCIRegistry(t, "", "", tgz)
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithPlainHTTP(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	for name, repo := range map[string]string{"http": repoURL, "oci": "oci://" + host + "/charts"} {
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "vendor")
+
+			path, err := client.PullChart(context.Background(), repo, matrixChart, matrixVersion, dir, false, false)
+			if err != nil {
+				t.Fatalf("PullChart() error = %v", err)
+			}
+			if want := filepath.Join(dir, matrixChart+"-"+matrixVersion+".tgz"); path != want {
+				t.Errorf("PullChart() = %s, want %s", path, want)
+			}
+			if data, err := os.ReadFile(path); err != nil || !bytes.Equal(data, tgz) {
+				t.Errorf("saved archive differs from the published one (err %v)", err)
+			}
+			if _, err := client.PullChart(context.Background(), repo, matrixChart, matrixVersion, dir, false, false); err == nil {
+				t.Error("expected an error for an existing archive without overwrite")
+			}
+
+			path, err = client.PullChart(context.Background(), repo, matrixChart, matrixVersion, dir, true, false)
+			if err != nil {
+				t.Fatalf("PullChart(untar) error = %v", err)
+			}
+			if want := filepath.Join(dir, matrixChart); path != want {
+				t.Errorf("PullChart(untar) = %s, want %s", path, want)
+			}
+			values, err := os.ReadFile(filepath.Join(path, "values.yaml"))
+			if err != nil || !bytes.Contains(values, []byte(matrixMarker)) {
+				t.Errorf("unpacked values.yaml = %q (err %v)", values, err)
+			}
+
+			if err := os.WriteFile(filepath.Join(path, "values.yaml"), []byte("edited: true\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.PullChart(context.Background(), repo, matrixChart, matrixVersion, dir, true, false); err == nil {
+				t.Error("expected an error for an existing directory without overwrite")
+			}
+			if _, err := client.PullChart(context.Background(), repo, matrixChart, matrixVersion, dir, true, true); err != nil {
+				t.Fatalf("PullChart(overwrite) error = %v", err)
+			}
+			if values, _ := os.ReadFile(filepath.Join(path, "values.yaml")); !bytes.Contains(values, []byte(matrixMarker)) {
+				t.Errorf("values.yaml was not replaced: %q", values)
+			}
+		})
+	}
+}
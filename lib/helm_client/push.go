@@ -0,0 +1,79 @@
+package helm_client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+// ChartPush reports a chart pushed by PushChart.
+type ChartPush struct {
+	Chart   string `json:"chart"`
+	Version string `json:"version"`
+	// Reference is the pushed chart, e.g.
+	// oci://registry.internal/charts/app:1.0.0.
+	Reference string `json:"reference"`
+	// Digest is the digest of the pushed manifest, to pin the chart.
+	Digest string `json:"digest"`
+}
+
+// PushChart packages the local chart directory repoURL, a path or file://
+// URL, like helm package, and pushes it to the OCI registry at registryURL as
+// <registryURL>/<chart>:<version>, like helm push. An existing tag is
+// replaced. Like helm package, it fails if dependencies are missing in the
+// charts/ directory. The registry is authenticated like when pulling charts.
+func (c *HelmClient) PushChart(ctx context.Context, repoURL, registryURL string) (*ChartPush, error) {
+	if !c.IsLocal(repoURL) {
+		return nil, fmt.Errorf("%s is not a local chart directory", repoURL)
+	}
+	if !IsOCI(registryURL) {
+		return nil, fmt.Errorf("charts can only be pushed to OCI registries, %s is not one", registryURL)
+	}
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return nil, err
+	}
+	if err := c.checkRepoAllowed(registryURL); err != nil {
+		return nil, err
+	}
+
+	loadedChart, err := c.loadLocalChart(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	if missing := missingDependencies(loadedChart); len(missing) > 0 {
+		return nil, fmt.Errorf("dependencies of chart %s are missing in its charts/ directory, update them first: %s", loadedChart.Name(), strings.Join(missing, ", "))
+	}
+	data, err := c.packageChart(loadedChart)
+	if err != nil {
+		return nil, err
+	}
+
+	name, version := loadedChart.Name(), loadedChart.Metadata.Version
+	ref := parseOCIReference(registryURL, name, version)
+
+	opCtx, cancel := withTimeout(ctx, c.options.downloadTimeout)
+	defer cancel()
+	regClient, err := c.ociClient(opCtx, registryURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %v", err)
+	}
+
+	reportProgress(ctx, "Pushing "+ref, 0, 0)
+	result, err := runWithContext(opCtx, func() (*registry.PushResult, error) {
+		return regClient.Push(data, ref)
+	})
+	if err = timeoutErr(ctx, opCtx, c.options.downloadTimeout, err); err != nil {
+		return nil, fmt.Errorf("failed to push chart %s version %s to %s: %v", name, version, ref, err)
+	}
+
+	// A re-pushed version replaces the one that may be cached.
+	c.InvalidateCache(registryURL, name, version)
+
+	push := &ChartPush{Chart: name, Version: version, Reference: "oci://" + ref}
+	if result.Manifest != nil {
+		push.Digest = result.Manifest.Digest
+	}
+	return push, nil
+}
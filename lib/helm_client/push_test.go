@@ -0,0 +1,149 @@
+package helm_client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// startPushRegistry starts a minimal in-memory OCI registry accepting blob
+// and manifest uploads and serving them back, and returns its host.
+func startPushRegistry(t *testing.T) string {
+	t.Helper()
+
+	var mu sync.Mutex
+	blobs := map[string][]byte{}
+	manifests := map[string][]byte{}
+	manifestTypes := map[string]string{}
+	uploads := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		path := strings.TrimPrefix(r.URL.Path, "/v2/")
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case strings.HasSuffix(path, "/blobs/uploads/") && r.Method == http.MethodPost:
+			uploads++
+			w.Header().Set("Location", fmt.Sprintf("/v2/%supload-%d", path, uploads))
+			w.WriteHeader(http.StatusAccepted)
+		case strings.Contains(path, "/blobs/uploads/upload-") && r.Method == http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			digest := r.URL.Query().Get("digest")
+			sum := sha256.Sum256(data)
+			if digest != "sha256:"+hex.EncodeToString(sum[:]) {
+				http.Error(w, "digest mismatch", http.StatusBadRequest)
+				return
+			}
+			blobs[digest] = data
+			w.Header().Set("Docker-Content-Digest", digest)
+			w.WriteHeader(http.StatusCreated)
+		case strings.Contains(path, "/blobs/"):
+			digest := path[strings.LastIndex(path, "/")+1:]
+			data, ok := blobs[digest]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+			w.Header().Set("Docker-Content-Digest", digest)
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(data)
+			}
+		case strings.Contains(path, "/manifests/"):
+			ref := path[strings.LastIndex(path, "/")+1:]
+			if r.Method == http.MethodPut {
+				data, _ := io.ReadAll(r.Body)
+				sum := sha256.Sum256(data)
+				digest := "sha256:" + hex.EncodeToString(sum[:])
+				for _, key := range []string{ref, digest} {
+					manifests[key] = data
+					manifestTypes[key] = r.Header.Get("Content-Type")
+				}
+				w.Header().Set("Docker-Content-Digest", digest)
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+			data, ok := manifests[ref]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			sum := sha256.Sum256(data)
+			w.Header().Set("Content-Type", manifestTypes[ref])
+			w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+			w.Header().Set("Docker-Content-Digest", "sha256:"+hex.EncodeToString(sum[:]))
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(data)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestPushChart(t *testing.T) {
+	chartDir := writeLocalChart(t)
+	host := startPushRegistry(t)
+	registryURL := "oci://" + host + "/charts"
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true), WithPlainHTTP(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	push, err := client.PushChart(context.Background(), "file://"+chartDir, registryURL)
+	if err != nil {
+		t.Fatalf("PushChart() error = %v", err)
+	}
+	if push.Chart != localChart || push.Version != localVersion {
+		t.Errorf("PushChart() pushed %s %s, want %s %s", push.Chart, push.Version, localChart, localVersion)
+	}
+	if want := registryURL + "/" + localChart + ":" + localVersion; push.Reference != want {
+		t.Errorf("Reference = %s, want %s", push.Reference, want)
+	}
+	if !strings.HasPrefix(push.Digest, "sha256:") {
+		t.Errorf("Digest = %q, want a sha256 digest", push.Digest)
+	}
+
+	dir := t.TempDir()
+	path, err := client.PullChart(context.Background(), registryURL, localChart, localVersion, dir, true, false)
+	if err != nil {
+		t.Fatalf("PullChart() of the pushed chart error = %v", err)
+	}
+	values, err := os.ReadFile(filepath.Join(path, "values.yaml"))
+	if err != nil || !bytes.Contains(values, []byte(localMarker)) {
+		t.Errorf("pulled values.yaml = %q (err %v)", values, err)
+	}
+
+	if _, err := client.PushChart(context.Background(), registryURL, registryURL); err == nil {
+		t.Error("expected an error for a chart that is not local")
+	}
+	if _, err := client.PushChart(context.Background(), "file://"+chartDir, "https://charts.example.com"); err == nil {
+		t.Error("expected an error for a registry that is not OCI")
+	}
+
+	chartYAML := "apiVersion: v2\nname: " + localChart + "\nversion: " + localVersion + "\n" +
+		"dependencies:\n- name: dep\n  version: 1.0.0\n  repository: https://charts.example.com\n"
+	if err := os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(chartYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.PushChart(context.Background(), chartDir, registryURL); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected an error for missing dependencies, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package helm_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+)
+
+// Kinds of artifacts attached to a chart, see Referrer.
+const (
+	ReferrerSignature   = "signature"
+	ReferrerSBOM        = "sbom"
+	ReferrerAttestation = "attestation"
+	ReferrerOther       = "other"
+)
+
+// legacyCosignTags are the suffixes of the tags cosign attaches artifacts
+// with when the registry does not support the referrers API, e.g.
+// sha256-<hex>.sig for the signatures of the manifest sha256:<hex>.
+var legacyCosignTags = map[string]string{
+	".sig":  ReferrerSignature,
+	".sbom": ReferrerSBOM,
+	".att":  ReferrerAttestation,
+}
+
+// ChartReferrers lists the artifacts attached to an OCI chart, see
+// ListChartReferrers.
+type ChartReferrers struct {
+	// Reference is the chart reference, e.g. ghcr.io/org/charts/app:1.0.0.
+	Reference string `json:"reference"`
+	// Digest is the digest of the chart manifest the artifacts refer to.
+	Digest    string     `json:"digest"`
+	Referrers []Referrer `json:"referrers"`
+}
+
+// Referrer is an artifact attached to a chart.
+type Referrer struct {
+	// Kind is one of ReferrerSignature, ReferrerSBOM, ReferrerAttestation
+	// and ReferrerOther, guessed from the artifact type.
+	Kind         string `json:"kind"`
+	ArtifactType string `json:"artifactType,omitempty"`
+	MediaType    string `json:"mediaType"`
+	Digest       string `json:"digest"`
+	Size         int64  `json:"size"`
+	// Tag is set for artifacts attached with a cosign tag, such as
+	// sha256-<hex>.sig, rather than the referrers API.
+	Tag         string            `json:"tag,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ListChartReferrers lists the artifacts attached to an OCI chart version,
+// such as signatures, SBOMs and provenance attestations. Artifacts are listed
+// with the referrers API, or the referrers tag schema if the registry does
+// not support it, and with the tags cosign uses for registries without
+// referrers support. artifactType limits the result to a single artifact
+// type; empty lists all of them.
+func (c *HelmClient) ListChartReferrers(ctx context.Context, repoURL, chartName, version, artifactType string) (*ChartReferrers, error) {
+	if !IsOCI(repoURL) {
+		return nil, fmt.Errorf("referrers are only available for OCI registries, %s is not one", repoURL)
+	}
+	if err := c.checkRepoAllowed(repoURL); err != nil {
+		return nil, err
+	}
+
+	opCtx, cancel := withTimeout(ctx, c.options.repoTimeout)
+	defer cancel()
+
+	ref := parseOCIReference(repoURL, chartName, version)
+	repo, err := c.chartRepository(opCtx, repoURL, ref)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := repo.Resolve(opCtx, repo.Reference.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve OCI chart %s: %v", ref, timeoutErr(ctx, opCtx, c.options.repoTimeout, err))
+	}
+
+	result := &ChartReferrers{Reference: ref, Digest: desc.Digest.String(), Referrers: []Referrer{}}
+	err = repo.Referrers(opCtx, desc, artifactType, func(referrers []ocispec.Descriptor) error {
+		for _, r := range referrers {
+			result.Referrers = append(result.Referrers, Referrer{
+				Kind:         referrerKind(r.ArtifactType),
+				ArtifactType: r.ArtifactType,
+				MediaType:    r.MediaType,
+				Digest:       r.Digest.String(),
+				Size:         r.Size,
+				Annotations:  r.Annotations,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list referrers of OCI chart %s: %v", ref, timeoutErr(ctx, opCtx, c.options.repoTimeout, err))
+	}
+
+	if artifactType == "" {
+		for suffix, kind := range legacyCosignTags {
+			tag := strings.Replace(desc.Digest.String(), ":", "-", 1) + suffix
+			tagged, err := repo.Resolve(opCtx, tag)
+			if errors.Is(err, errdef.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve tag %s of OCI chart %s: %v", tag, ref, timeoutErr(ctx, opCtx, c.options.repoTimeout, err))
+			}
+			result.Referrers = append(result.Referrers, Referrer{
+				Kind:         kind,
+				ArtifactType: tagged.ArtifactType,
+				MediaType:    tagged.MediaType,
+				Digest:       tagged.Digest.String(),
+				Size:         tagged.Size,
+				Tag:          tag,
+				Annotations:  tagged.Annotations,
+			})
+		}
+	}
+
+	sort.Slice(result.Referrers, func(i, j int) bool {
+		a, b := result.Referrers[i], result.Referrers[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Digest < b.Digest
+	})
+	return result, nil
+}
+
+// chartRepository returns a client for the OCI repository of the chart
+// reference ref whose requests are cancelled together with ctx. It
+// authenticates like the registry client chosen by registryClientFor:
+// with -username/-password unless the credentials file has credentials for
+// the registry, otherwise with the credentials file, falling back to the
+// Docker config.
+func (c *HelmClient) chartRepository(ctx context.Context, repoURL, ref string) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI chart reference %s: %v", ref, err)
+	}
+
+	var credential auth.CredentialFunc
+	useCredsFile := c.registryClientCreds != nil && c.registryClientFor(repoURL) == c.registryClientCreds
+	if c.options.username != "" && c.options.password != "" && !useCredsFile {
+		credential = auth.StaticCredential(repo.Reference.Registry, auth.Credential{Username: c.options.username, Password: c.options.password})
+	} else {
+		credsFile := c.settings.RegistryConfig
+		if c.options.credentialsFile != "" {
+			credsFile = c.options.credentialsFile
+		}
+		storeOptions := credentials.StoreOptions{DetectDefaultNativeStore: true}
+		var store credentials.Store
+		store, err = credentials.NewStore(credsFile, storeOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load registry credentials: %v", err)
+		}
+		if dockerStore, err := credentials.NewStoreFromDocker(storeOptions); err == nil {
+			store = credentials.NewStoreWithFallbacks(store, dockerStore)
+		}
+		credential = credentials.Credential(store)
+	}
+
+	repo.PlainHTTP = c.options.plainHTTP
+	repo.Client = &auth.Client{
+		Client:     &http.Client{Transport: &callTransport{ctx: ctx, base: c.registryTransport}},
+		Cache:      auth.NewCache(),
+		Credential: credential,
+	}
+	return repo, nil
+}
+
+// referrerKind guesses the kind of an artifact from its type, e.g.
+// application/vnd.dev.cosign.artifact.sig.v1+json is a signature and
+// application/spdx+json an SBOM.
+func referrerKind(artifactType string) string {
+	t := strings.ToLower(artifactType)
+	switch {
+	case strings.Contains(t, "signature") || strings.Contains(t, ".sig.") || strings.Contains(t, "cosign.simplesigning"):
+		return ReferrerSignature
+	case strings.Contains(t, "spdx") || strings.Contains(t, "cyclonedx") || strings.Contains(t, "syft") || strings.Contains(t, "sbom"):
+		return ReferrerSBOM
+	case strings.Contains(t, "in-toto") || strings.Contains(t, "slsa") || strings.Contains(t, "provenance") || strings.Contains(t, "attestation") || strings.Contains(t, "sigstore.bundle"):
+		return ReferrerAttestation
+	default:
+		return ReferrerOther
+	}
+}
@@ -0,0 +1,105 @@
+package helm_client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestListChartReferrers(t *testing.T) {
+	chartManifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json"}`)
+	chartDigest := digest.FromBytes(chartManifest)
+	attestation := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","artifactType":"application/vnd.dsse.envelope.v1+json"}`)
+	referrers, _ := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{
+			{MediaType: ocispec.MediaTypeImageManifest, ArtifactType: "application/spdx+json", Digest: digest.FromString("sbom"), Size: 100},
+			{MediaType: ocispec.MediaTypeImageManifest, ArtifactType: "application/vnd.dev.cosign.artifact.sig.v1+json", Digest: digest.FromString("sig"), Size: 200,
+				Annotations: map[string]string{"dev.sigstore.cosign/signature": "MEUCIQ"}},
+		},
+	})
+
+	content := map[string]struct {
+		mediaType string
+		data      []byte
+	}{
+		"/v2/charts/app/manifests/1.0.0": {ocispec.MediaTypeImageManifest, chartManifest},
+		"/v2/charts/app/manifests/" + strings.Replace(chartDigest.String(), ":", "-", 1) + ".att": {ocispec.MediaTypeImageManifest, attestation},
+		"/v2/charts/app/referrers/" + chartDigest.String():                                        {ocispec.MediaTypeImageIndex, referrers},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, ok := content[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`))
+			return
+		}
+		w.Header().Set("Content-Type", c.mediaType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(c.data)))
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(c.data).String())
+		if r.Method != http.MethodHead {
+			_, _ = w.Write(c.data)
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithPlainHTTP(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	result, err := client.ListChartReferrers(context.Background(), "oci://"+host+"/charts", "app", "1.0.0", "")
+	if err != nil {
+		t.Fatalf("ListChartReferrers() error = %v", err)
+	}
+	if result.Reference != host+"/charts/app:1.0.0" || result.Digest != chartDigest.String() {
+		t.Errorf("got reference %s digest %s", result.Reference, result.Digest)
+	}
+
+	var kinds []string
+	for _, r := range result.Referrers {
+		kinds = append(kinds, r.Kind)
+	}
+	if got, want := strings.Join(kinds, ","), "attestation,sbom,signature"; got != want {
+		t.Fatalf("kinds = %s, want %s: %+v", got, want, result.Referrers)
+	}
+	if att := result.Referrers[0]; att.Tag == "" || att.Digest != digest.FromBytes(attestation).String() {
+		t.Errorf("attestation = %+v", att)
+	}
+	if sig := result.Referrers[2]; sig.Size != 200 || sig.Annotations["dev.sigstore.cosign/signature"] != "MEUCIQ" {
+		t.Errorf("signature = %+v", sig)
+	}
+
+	if _, err := client.ListChartReferrers(context.Background(), "https://charts.example.com", "app", "1.0.0", ""); err == nil {
+		t.Error("expected an error for an HTTP repository")
+	}
+}
+
+func TestReferrerKind(t *testing.T) {
+	for artifactType, want := range map[string]string{
+		"application/vnd.dev.cosign.artifact.sig.v1+json": ReferrerSignature,
+		"application/vnd.cncf.notary.signature":           ReferrerSignature,
+		"application/vnd.cyclonedx+json":                  ReferrerSBOM,
+		"application/spdx+json":                           ReferrerSBOM,
+		"application/vnd.in-toto+json":                    ReferrerAttestation,
+		"application/vnd.dev.sigstore.bundle.v0.3+json":   ReferrerAttestation,
+		"application/vnd.example.readme":                  ReferrerOther,
+		"":                                                ReferrerOther,
+	} {
+		if got := referrerKind(artifactType); got != want {
+			t.Errorf("referrerKind(%q) = %s, want %s", artifactType, got, want)
+		}
+	}
+}
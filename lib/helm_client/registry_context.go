@@ -0,0 +1,47 @@
+package helm_client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+// callContext keeps the values of a request context created by Helm's
+// registry client, which is always derived from context.Background, but is
+// cancelled together with the tool call it was made for.
+type callContext struct {
+	context.Context
+	call context.Context
+}
+
+func (c callContext) Deadline() (time.Time, bool) { return c.call.Deadline() }
+func (c callContext) Done() <-chan struct{}       { return c.call.Done() }
+func (c callContext) Err() error                  { return c.call.Err() }
+
+// callTransport binds the requests of a registry client to ctx, so they are
+// aborted immediately when ctx is cancelled.
+type callTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *callTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(callContext{Context: req.Context(), call: t.ctx}))
+}
+
+// ociClient returns a registry client for repoURL whose HTTP requests are
+// cancelled together with ctx. Helm's registry client does not accept a
+// context, so without it a pull would keep downloading after the tool call
+// that started it has been cancelled. The client is chosen and configured as
+// by registryClientFor.
+func (c *HelmClient) ociClient(ctx context.Context, repoURL string) (*registry.Client, error) {
+	opts := c.registryOpts
+	if c.registryClientFor(repoURL) == c.registryClientCreds && c.registryClientCreds != nil {
+		opts = c.registryOptsCreds
+	}
+	return registry.NewClient(withRegistryOpts(opts, registry.ClientOptHTTPClient(&http.Client{
+		Transport: &callTransport{ctx: ctx, base: c.registryTransport},
+	}))...)
+}
@@ -0,0 +1,50 @@
+package helm_client
+
+import "strings"
+
+// defaultRepoAliases are the repository aliases available without
+// configuration, see WithRepoAliases.
+var defaultRepoAliases = map[string]string{
+	"bitnami":              "https://charts.bitnami.com/bitnami",
+	"prometheus-community": "https://prometheus-community.github.io/helm-charts",
+	"grafana":              "https://grafana.github.io/helm-charts",
+}
+
+// WithRepoAliases adds repository aliases, mapping short names like
+// "internal" to repository URLs. They take precedence over the built-in
+// aliases of the same name.
+func WithRepoAliases(aliases map[string]string) ClientOption {
+	return func(o *clientOptions) {
+		if o.repoAliases == nil {
+			o.repoAliases = make(map[string]string, len(aliases))
+		}
+		for name, url := range aliases {
+			o.repoAliases[name] = url
+		}
+	}
+}
+
+// RepoAliases returns the built-in and configured repository aliases.
+func (c *HelmClient) RepoAliases() map[string]string {
+	aliases := make(map[string]string, len(defaultRepoAliases)+len(c.options.repoAliases))
+	for name, url := range defaultRepoAliases {
+		aliases[name] = url
+	}
+	for name, url := range c.options.repoAliases {
+		aliases[name] = url
+	}
+	return aliases
+}
+
+// ResolveRepositoryURL returns the repository URL of the alias repoURL, or
+// repoURL itself if it is not an alias.
+func (c *HelmClient) ResolveRepositoryURL(repoURL string) string {
+	name := strings.TrimSpace(repoURL)
+	if url, ok := c.options.repoAliases[name]; ok {
+		return url
+	}
+	if url, ok := defaultRepoAliases[name]; ok {
+		return url
+	}
+	return repoURL
+}
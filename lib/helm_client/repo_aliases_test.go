@@ -0,0 +1,33 @@
+package helm_client
+
+import "testing"
+
+func TestResolveRepositoryURL(t *testing.T) {
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithRepoAliases(map[string]string{
+		"internal": "https://charts.internal.example.com",
+		"grafana":  "https://grafana-mirror.internal.example.com",
+	}))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"bitnami", "https://charts.bitnami.com/bitnami"},
+		{" prometheus-community ", "https://prometheus-community.github.io/helm-charts"},
+		{"internal", "https://charts.internal.example.com"},
+		// Configured aliases override the built-in ones.
+		{"grafana", "https://grafana-mirror.internal.example.com"},
+		{"https://charts.example.com", "https://charts.example.com"},
+		{"oci://ghcr.io/org/charts/bitnami", "oci://ghcr.io/org/charts/bitnami"},
+		{"unknown", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := client.ResolveRepositoryURL(tt.in); got != tt.want {
+			t.Errorf("ResolveRepositoryURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package helm_client
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// repoPattern matches repository URLs against a glob pattern in which "*"
+// matches any sequence of characters, including "/".
+type repoPattern struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func newRepoPattern(pattern string) repoPattern {
+	parts := strings.Split(normalizeRepoURL(pattern), "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return repoPattern{
+		pattern: pattern,
+		re:      regexp.MustCompile("^" + strings.Join(parts, ".*") + "$"),
+	}
+}
+
+func (p repoPattern) match(repoURL string) bool {
+	return p.re.MatchString(repoURL)
+}
+
+// defaultPorts are the ports of the URL schemes that may be omitted.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"oci":   "443",
+}
+
+// normalizeRepoURL reduces a repository URL or pattern to the form patterns
+// are matched against, so that spellings of the same repository match alike:
+// the scheme and host are lowercased, the default port, userinfo, query and
+// fragment are dropped, the path is unescaped and the trailing slash is
+// stripped. "HTTPS://user@Charts.Example.com:443/charts/?x" and
+// "https://charts.example.com/charts" are the same repository. Values without
+// a scheme, such as local paths, only lose the trailing slash.
+func normalizeRepoURL(repoURL string) string {
+	u, err := url.Parse(strings.TrimSpace(repoURL))
+	if err != nil || u.Scheme == "" || u.Opaque != "" {
+		return strings.TrimRight(repoURL, "/")
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	if port := u.Port(); port != "" && port == defaultPorts[scheme] {
+		host = strings.TrimSuffix(host, ":"+port)
+	}
+	return strings.TrimRight(scheme+"://"+host+u.Path, "/")
+}
+
+// checkRepoAllowed returns an error if repoURL matches a pattern configured
+// with WithDeniedRepos, or if allowed patterns are configured with
+// WithAllowedRepos and none of them matches it.
+func (c *HelmClient) checkRepoAllowed(repoURL string) error {
+	u := normalizeRepoURL(repoURL)
+	for _, p := range c.deniedRepos {
+		if p.match(u) {
+			return fmt.Errorf("access to repository %s is denied by pattern %q", repoURL, p.pattern)
+		}
+	}
+	if len(c.allowedRepos) == 0 {
+		return nil
+	}
+	for _, p := range c.allowedRepos {
+		if p.match(u) {
+			return nil
+		}
+	}
+	return fmt.Errorf("access to repository %s is not allowed: it does not match any allowed repository pattern", repoURL)
+}
@@ -0,0 +1,95 @@
+package helm_client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// TestManifestReports covers the client side of the tools rendering a chart
+// and analyzing the manifest; the analysis itself is tested in helm_parser.
+func TestManifestReports(t *testing.T) {
+	chartDir := writeLocalChart(t)
+	repoURL := "file://" + chartDir
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+	denied, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true), WithDeniedRepos("file://*"))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = denied.Close() }()
+
+	type report func(c *HelmClient, version string) error
+	ctx := context.Background()
+	var opts helm_parser.RenderOptions
+	reports := map[string]report{
+		"ScoreChart": func(c *HelmClient, version string) error {
+			_, err := c.ScoreChart(ctx, repoURL, localChart, version, nil, opts)
+			return err
+		},
+		"EstimateChartFootprint": func(c *HelmClient, version string) error {
+			_, err := c.EstimateChartFootprint(ctx, repoURL, localChart, version, nil, opts, helm_parser.FootprintOptions{})
+			return err
+		},
+		"CheckPodSecurity": func(c *HelmClient, version string) error {
+			_, err := c.CheckPodSecurity(ctx, repoURL, localChart, version, nil, opts)
+			return err
+		},
+		"SummarizeChartAvailability": func(c *HelmClient, version string) error {
+			_, err := c.SummarizeChartAvailability(ctx, repoURL, localChart, version, nil, opts)
+			return err
+		},
+		"GetChartIngresses": func(c *HelmClient, version string) error {
+			_, err := c.GetChartIngresses(ctx, repoURL, localChart, version, nil, opts)
+			return err
+		},
+		"GetChartServices": func(c *HelmClient, version string) error {
+			_, err := c.GetChartServices(ctx, repoURL, localChart, version, nil, opts)
+			return err
+		},
+		"GetChartConfigUsage": func(c *HelmClient, version string) error {
+			_, err := c.GetChartConfigUsage(ctx, repoURL, localChart, version, nil, opts)
+			return err
+		},
+		"GetChartStorage": func(c *HelmClient, version string) error {
+			_, err := c.GetChartStorage(ctx, repoURL, localChart, version, nil, opts)
+			return err
+		},
+		"GetChartScheduling": func(c *HelmClient, version string) error {
+			_, err := c.GetChartScheduling(ctx, repoURL, localChart, version, nil, opts)
+			return err
+		},
+		"CheckChartHA": func(c *HelmClient, version string) error {
+			_, err := c.CheckChartHA(ctx, repoURL, localChart, version, nil, opts)
+			return err
+		},
+		"CheckChartLabels": func(c *HelmClient, version string) error {
+			_, err := c.CheckChartLabels(ctx, repoURL, localChart, version, nil, opts, nil)
+			return err
+		},
+		"FindExternalDependencies": func(c *HelmClient, version string) error {
+			_, err := c.FindExternalDependencies(ctx, repoURL, localChart, version, nil, opts)
+			return err
+		},
+	}
+
+	for name, report := range reports {
+		t.Run(name, func(t *testing.T) {
+			if err := report(client, localVersion); err != nil {
+				t.Errorf("%s() error = %v", name, err)
+			}
+			if err := report(client, "9.9.9"); err == nil || !strings.Contains(err.Error(), "9.9.9") {
+				t.Errorf("%s() error = %v, want the missing version to be reported", name, err)
+			}
+			if err := report(denied, localVersion); err == nil || !strings.Contains(err.Error(), "denied") {
+				t.Errorf("%s() error = %v, want the repository to be denied", name, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package helm_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+
+	"github.com/zekker6/mcp-helm/lib/logger"
+)
+
+// Default retry policy, see WithRetry.
+const (
+	defaultRetries      = 3
+	defaultRetryBackoff = 500 * time.Millisecond
+)
+
+// statusError is returned by httpGetter for a non-200 response.
+type statusError struct {
+	url        string
+	status     string
+	statusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("failed to fetch %s : %s", e.url, e.status)
+}
+
+// isTransient reports whether err is a failure worth retrying: a 5xx response
+// or a connection dropped by the server, as commonly seen with GitHub
+// Pages-hosted repositories under load.
+func isTransient(err error) bool {
+	if isContextError(err) {
+		return false
+	}
+
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.statusCode >= http.StatusInternalServerError
+	}
+	var re *errcode.ErrorResponse
+	if errors.As(err, &re) {
+		return re.StatusCode >= http.StatusInternalServerError
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retry runs fn until it succeeds, fails with a non-transient error or the
+// configured number of retries is used up. The delay between attempts starts
+// at the configured backoff and doubles after every attempt. Waiting stops as
+// soon as ctx is done, returning the last error.
+func retry(ctx context.Context, o *clientOptions, fn func() error) error {
+	delay := o.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= o.retries || !isTransient(err) {
+			return err
+		}
+
+		logger.Debug("retrying after transient failure", zap.Int("attempt", attempt+1), zap.Duration("delay", delay), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
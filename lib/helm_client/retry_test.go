@@ -0,0 +1,84 @@
+package helm_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "service unavailable", err: &statusError{statusCode: http.StatusServiceUnavailable}, want: true},
+		{name: "not found", err: &statusError{statusCode: http.StatusNotFound}, want: false},
+		{name: "connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), want: true},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: false},
+		{name: "other error", err: errors.New("boom"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransient(tt.err); got != tt.want {
+				t.Errorf("isTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		retries  int
+		wantErr  bool
+		wantHits int32
+	}{
+		{name: "recovers after transient failures", status: http.StatusServiceUnavailable, retries: 3, wantHits: 3},
+		{name: "retries disabled", status: http.StatusServiceUnavailable, retries: 0, wantErr: true, wantHits: 1},
+		{name: "retries exhausted", status: http.StatusServiceUnavailable, retries: 1, wantErr: true, wantHits: 2},
+		{name: "client error is not retried", status: http.StatusNotFound, retries: 3, wantErr: true, wantHits: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The first two index requests fail with tt.status.
+			var indexHits atomic.Int32
+			var serverURL string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/index.yaml" {
+					http.NotFound(w, r)
+					return
+				}
+				if indexHits.Add(1) <= 2 {
+					http.Error(w, http.StatusText(tt.status), tt.status)
+					return
+				}
+				_, _ = w.Write(createTestIndex(serverURL))
+			}))
+			defer server.Close()
+			serverURL = server.URL
+
+			client, err := NewClient(WithCacheDir(t.TempDir()), WithRetry(tt.retries, time.Millisecond))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			_, err = client.ListCharts(context.Background(), server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListCharts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if hits := indexHits.Load(); hits != tt.wantHits {
+				t.Errorf("index requested %d times, want %d", hits, tt.wantHits)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package helm_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// snapshotName restricts snapshot names, which are file names in the
+// snapshot directory.
+var snapshotName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// manifestSnapshot is a stored snapshot, see CompareManifestSnapshot.
+type manifestSnapshot struct {
+	Repository string    `json:"repository"`
+	Chart      string    `json:"chart"`
+	Version    string    `json:"version"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Manifest   string    `json:"manifest"`
+}
+
+// SnapshotComparison is the result of CompareManifestSnapshot.
+type SnapshotComparison struct {
+	Snapshot string `json:"snapshot"`
+	// Created reports that the snapshot did not exist and was created from
+	// this render, so there is nothing to compare with.
+	Created bool `json:"created,omitempty"`
+	// Updated reports that the snapshot was replaced by this render.
+	Updated bool `json:"updated,omitempty"`
+	// Chart, Version and CreatedAt describe the render the snapshot was
+	// taken from, before an update.
+	Chart     string    `json:"chart"`
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Diff compares the snapshot with this render, nil if it was created.
+	Diff *helm_parser.ManifestDiff `json:"diff,omitempty"`
+}
+
+// CompareManifestSnapshot renders a chart version with customValues and
+// compares the manifest with the snapshot called name, for regression checks
+// of chart or values changes. If the snapshot does not exist it is created
+// from the render. With update an existing snapshot is replaced by the
+// render after comparing. Snapshots are kept in the cache directory, so they
+// survive restarts and are shared by the instances using it.
+func (c *HelmClient) CompareManifestSnapshot(ctx context.Context, repoURL, chartName, version string, customValues map[string]any, opts helm_parser.RenderOptions, name string, update bool) (*SnapshotComparison, error) {
+	if !snapshotName.MatchString(name) {
+		return nil, fmt.Errorf("invalid snapshot name %q: use up to 128 letters, digits, '.', '_' and '-', starting with a letter or digit", name)
+	}
+
+	manifest, err := c.renderManifest(ctx, repoURL, chartName, version, customValues, opts)
+	if err != nil {
+		return nil, err
+	}
+	current := manifestSnapshot{
+		Repository: repoURL,
+		Chart:      chartName,
+		Version:    version,
+		CreatedAt:  time.Now().UTC(),
+		Manifest:   manifest,
+	}
+
+	path := filepath.Join(c.snapshotDir, name+".json")
+	previous, err := readSnapshot(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := writeSnapshot(path, current); err != nil {
+			return nil, err
+		}
+		return &SnapshotComparison{Snapshot: name, Created: true, Chart: chartName, Version: version, CreatedAt: current.CreatedAt}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := helm_parser.DiffManifests(previous.Manifest, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare with snapshot %s: %v", name, err)
+	}
+	result := &SnapshotComparison{
+		Snapshot:  name,
+		Chart:     previous.Chart,
+		Version:   previous.Version,
+		CreatedAt: previous.CreatedAt,
+		Diff:      diff,
+	}
+	if update {
+		if err := writeSnapshot(path, current); err != nil {
+			return nil, err
+		}
+		result.Updated = true
+	}
+	return result, nil
+}
+
+func readSnapshot(path string) (*manifestSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot manifestSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %v", path, err)
+	}
+	return &snapshot, nil
+}
+
+// writeSnapshot writes a snapshot atomically, so concurrent readers never
+// see a partial snapshot.
+func writeSnapshot(path string, snapshot manifestSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %v", err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".snapshot-")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %v", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write snapshot: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %v", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %v", err)
+	}
+	return nil
+}
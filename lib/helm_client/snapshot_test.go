@@ -0,0 +1,65 @@
+package helm_client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func TestCompareManifestSnapshot(t *testing.T) {
+	chartDir := writeLocalChart(t)
+	repoURL := "file://" + chartDir
+	cacheDir := t.TempDir()
+
+	client, err := NewClient(WithCacheDir(cacheDir), WithLocalCharts(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	compare := func(values map[string]any, update bool) *SnapshotComparison {
+		t.Helper()
+		result, err := client.CompareManifestSnapshot(context.Background(), repoURL, localChart, localVersion, values, helm_parser.RenderOptions{}, "baseline", update)
+		if err != nil {
+			t.Fatalf("CompareManifestSnapshot() error = %v", err)
+		}
+		return result
+	}
+
+	if result := compare(nil, false); !result.Created || result.Diff != nil {
+		t.Fatalf("first comparison = %+v, want the snapshot to be created", result)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "snapshots", "baseline.json")); err != nil {
+		t.Errorf("snapshot not stored in the cache directory: %v", err)
+	}
+
+	if result := compare(nil, false); result.Created || len(result.Diff.Changes) != 0 || result.Diff.Unchanged != 1 {
+		t.Errorf("unchanged render = %+v, want no changes", result)
+	}
+
+	changed := map[string]any{"message": "changed"}
+	result := compare(changed, false)
+	if len(result.Diff.Changes) != 1 || result.Diff.Changes[0].Change != helm_parser.ChangeModified || !strings.Contains(result.Diff.Changes[0].Diff, "+  message: \"changed\"") {
+		t.Fatalf("changed render = %+v, want the ConfigMap to be modified", result.Diff)
+	}
+	if result.Updated {
+		t.Error("Updated = true without update")
+	}
+
+	if result := compare(changed, true); !result.Updated || len(result.Diff.Changes) != 1 {
+		t.Errorf("update = %+v, want the change to be reported and the snapshot updated", result)
+	}
+	if result := compare(changed, false); len(result.Diff.Changes) != 0 {
+		t.Errorf("render after update = %+v, want no changes", result.Diff)
+	}
+
+	for _, name := range []string{"", "../escape", "a/b", ".hidden"} {
+		if _, err := client.CompareManifestSnapshot(context.Background(), repoURL, localChart, localVersion, nil, helm_parser.RenderOptions{}, name, false); err == nil {
+			t.Errorf("expected an error for snapshot name %q", name)
+		}
+	}
+}
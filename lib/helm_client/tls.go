@@ -0,0 +1,55 @@
+package helm_client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// registryTLSConfig builds the TLS configuration used for OCI registry
+// connections. It returns nil when no custom TLS settings are configured, so
+// the registry client keeps Helm's default transport.
+func registryTLSConfig(o *clientOptions) (*tls.Config, error) {
+	if o.caFile == "" && o.certFile == "" {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{}
+
+	if o.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.certFile, o.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate %q: %w", o.certFile, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if o.caFile == "" {
+		return cfg, nil
+	}
+
+	caPEM, err := os.ReadFile(o.caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file %q: %w", o.caFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no valid certificates found in CA file %q", o.caFile)
+	}
+	cfg.RootCAs = pool
+
+	return cfg, nil
+}
+
+// newTLSHTTPClient returns an HTTP client using tlsConfig while keeping the
+// proxy behaviour of the default transport.
+func newTLSHTTPClient(tlsConfig *tls.Config) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+			Proxy:           http.ProxyFromEnvironment,
+		},
+	}
+}
@@ -0,0 +1,74 @@
+package helm_client
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// RunChartUnitTests runs the helm-unittest suites of a chart matching
+// pattern, helm_parser.DefaultUnitTestPattern if empty, like helm unittest.
+// Suites and the values files they reference are read from the chart
+// directory of local charts, and from the files packaged with other charts.
+func (c *HelmClient) RunChartUnitTests(ctx context.Context, repoURL, chartName, version, pattern string) (*helm_parser.UnitTestReport, error) {
+	if pattern == "" {
+		pattern = helm_parser.DefaultUnitTestPattern
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+	}
+
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+	}
+
+	var readFile func(name string) ([]byte, error)
+	var names []string
+	if c.IsLocal(repoURL) {
+		// Suites are often excluded by .helmignore, so they are read from
+		// disk rather than the loaded chart. The fs.FS rejects paths outside
+		// of the chart directory.
+		root := os.DirFS(localChartPath(repoURL))
+		readFile = func(name string) ([]byte, error) {
+			return fs.ReadFile(root, name)
+		}
+		if names, err = fs.Glob(root, pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	} else {
+		files := make(map[string][]byte, len(loadedChart.Files))
+		for _, f := range loadedChart.Files {
+			files[f.Name] = f.Data
+			if ok, _ := path.Match(pattern, f.Name); ok {
+				names = append(names, f.Name)
+			}
+		}
+		readFile = func(name string) ([]byte, error) {
+			data, ok := files[name]
+			if !ok {
+				return nil, fmt.Errorf("file %s not found in chart", name)
+			}
+			return data, nil
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("chart %s has no test suites matching %s", loadedChart.Name(), pattern)
+	}
+
+	suites := make([]helm_parser.UnitTestFile, 0, len(names))
+	for _, name := range names {
+		data, err := readFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read test suite %s: %v", name, err)
+		}
+		suites = append(suites, helm_parser.UnitTestFile{Path: name, Data: data})
+	}
+	return helm_parser.RunUnitTests(loadedChart, suites, readFile), nil
+}
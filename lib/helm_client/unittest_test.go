@@ -0,0 +1,56 @@
+package helm_client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+func TestRunChartUnitTests(t *testing.T) {
+	chartDir := writeLocalChart(t)
+	files := map[string]string{
+		".helmignore": "tests/\n",
+		"tests/configmap_test.yaml": "suite: configmap\ntemplates: [configmap.yaml]\ntests:\n" +
+			"  - it: uses the message\n    asserts:\n      - equal: {path: data.message, value: " + localMarker + "}\n" +
+			"  - it: uses the override\n    values: [values/override.yaml]\n    asserts:\n      - equal: {path: data.message, value: overridden}\n",
+		"tests/values/override.yaml": "message: overridden\n",
+		"tests/escape_test.yaml":     "tests:\n  - it: reads outside\n    values: [../../secret.yaml]\n    asserts:\n      - isKind: {of: ConfigMap}\n",
+	}
+	for name, body := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(chartDir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(chartDir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithLocalCharts(true))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	report, err := client.RunChartUnitTests(context.Background(), "file://"+chartDir, localChart, localVersion, "")
+	if err != nil {
+		t.Fatalf("RunChartUnitTests() error = %v", err)
+	}
+	if len(report.Suites) != 2 || report.Suites[0].File != "tests/configmap_test.yaml" || report.Suites[1].File != "tests/escape_test.yaml" {
+		t.Fatalf("Suites = %+v, want the configmap and escape suites", report.Suites)
+	}
+	for _, test := range report.Suites[0].Tests {
+		if test.Status != helm_parser.UnitTestPassed {
+			t.Errorf("test %q: status %s, want passed (%+v)", test.Name, test.Status, test)
+		}
+	}
+	if test := report.Suites[1].Tests[0]; test.Status != helm_parser.UnitTestFailed || test.Error == "" {
+		t.Errorf("values file outside of the chart: %+v, want a failure", test)
+	}
+
+	if _, err := client.RunChartUnitTests(context.Background(), "file://"+chartDir, localChart, localVersion, "ci/*_test.yaml"); err == nil {
+		t.Error("expected an error for a pattern without suites")
+	}
+}
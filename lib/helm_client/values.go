@@ -0,0 +1,99 @@
+package helm_client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"helm.sh/helm/v4/pkg/chart/common"
+	"helm.sh/helm/v4/pkg/chart/common/util"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+
+	"github.com/zekker6/mcp-helm/lib/helm_parser"
+)
+
+// FetchValues downloads a values file over HTTP(S), like helm --values with a
+// URL. The URL is subject to the allowed and denied repository patterns, and
+// chart repository credentials are only sent with -pass-credentials-all. The
+// file may not be larger than the maximum chart size.
+func (c *HelmClient) FetchValues(ctx context.Context, valuesURL string) (map[string]any, error) {
+	u, err := url.Parse(valuesURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid values URL %q: expected an http or https URL", valuesURL)
+	}
+	if err := c.checkRepoAllowed(valuesURL); err != nil {
+		return nil, err
+	}
+
+	opCtx, cancel := withTimeout(ctx, c.options.repoTimeout)
+	defer cancel()
+
+	// No repository URL, so credentials do not match the values host.
+	g, err := c.httpGetters(opCtx, "", c.options.maxChartSize).ByScheme(u.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.Get(valuesURL)
+	if err = timeoutErr(ctx, opCtx, c.options.repoTimeout, err); err != nil {
+		return nil, fmt.Errorf("failed to download values from %s: %v", valuesURL, err)
+	}
+
+	values, err := common.ReadValues(resp.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse values from %s: %v", valuesURL, err)
+	}
+	return values, nil
+}
+
+// MergeValues deep-merges values sources in order, later sources taking
+// precedence, like several helm --values files. Nested maps are merged, any
+// other value, including lists, is replaced.
+func MergeValues(sources ...map[string]any) map[string]any {
+	var merged map[string]any
+	for _, values := range sources {
+		if values == nil {
+			continue
+		}
+		if merged == nil {
+			merged = make(map[string]any)
+		}
+		merged = loader.MergeMaps(merged, values)
+	}
+	return merged
+}
+
+// GetEffectiveValues returns the values a chart is rendered with when
+// installed with values: the chart defaults and those of its subcharts
+// coalesced with values, including global values. A null value removes the
+// default.
+func (c *HelmClient) GetEffectiveValues(ctx context.Context, repoURL, chartName, version string, values map[string]any) (map[string]any, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+	}
+
+	if loadedChart == nil {
+		return nil, fmt.Errorf("chart %s version %s not found", chartName, version)
+	}
+
+	effective, err := util.CoalesceValues(loadedChart, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge values: %v", err)
+	}
+	return effective, nil
+}
+
+// ValidateValues checks values against the schema and default values of a
+// chart, see helm_parser.ValidateValues.
+func (c *HelmClient) ValidateValues(ctx context.Context, repoURL, chartName, version string, values map[string]any) (helm_parser.ValuesValidation, error) {
+	loadedChart, err := c.loadChart(ctx, repoURL, chartName, version)
+	if err != nil {
+		return helm_parser.ValuesValidation{}, fmt.Errorf("failed to load chart %s version %s: %v", chartName, version, err)
+	}
+
+	if loadedChart == nil {
+		return helm_parser.ValuesValidation{}, fmt.Errorf("chart %s version %s not found", chartName, version)
+	}
+
+	return helm_parser.ValidateValues(loadedChart, values)
+}
@@ -0,0 +1,78 @@
+package helm_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFetchValues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("repository credentials sent to the values URL")
+		}
+		_, _ = w.Write([]byte("image:\n  tag: v1\n"))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(WithBasicAuth("user", "pass"))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	values, err := client.FetchValues(context.Background(), srv.URL+"/values.yaml")
+	if err != nil {
+		t.Fatalf("FetchValues() error = %v", err)
+	}
+	if want := map[string]any{"image": map[string]any{"tag": "v1"}}; !reflect.DeepEqual(values, want) {
+		t.Errorf("FetchValues() = %v, want %v", values, want)
+	}
+
+	denied, err := NewClient(WithDeniedRepos(srv.URL + "/*"))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if _, err := denied.FetchValues(context.Background(), srv.URL+"/values.yaml"); err == nil {
+		t.Error("expected denied values URL to be rejected")
+	}
+	if _, err := client.FetchValues(context.Background(), "/etc/passwd"); err == nil {
+		t.Error("expected a local path to be rejected")
+	}
+}
+
+func TestMergeValues(t *testing.T) {
+	got := MergeValues(
+		map[string]any{"image": map[string]any{"repository": "app", "tag": "v1"}, "args": []any{"a", "b"}},
+		nil,
+		map[string]any{"image": map[string]any{"tag": "v2"}, "args": []any{"c"}},
+	)
+	want := map[string]any{"image": map[string]any{"repository": "app", "tag": "v2"}, "args": []any{"c"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeValues() = %v, want %v", got, want)
+	}
+	if got := MergeValues(nil, nil); got != nil {
+		t.Errorf("MergeValues() = %v, want nil", got)
+	}
+}
+
+func TestGetEffectiveValues(t *testing.T) {
+	chartDir := writeLocalChart(t)
+	client := newTestClient(t)
+
+	values, err := client.GetEffectiveValues(context.Background(), chartDir, localChart, localVersion, map[string]any{"replicaCount": 2})
+	if err != nil {
+		t.Fatalf("GetEffectiveValues() error = %v", err)
+	}
+	if values["message"] != localMarker || values["replicaCount"] != 2 {
+		t.Errorf("GetEffectiveValues() = %v", values)
+	}
+
+	values, err = client.GetEffectiveValues(context.Background(), chartDir, localChart, localVersion, map[string]any{"message": nil})
+	if err != nil {
+		t.Fatalf("GetEffectiveValues() error = %v", err)
+	}
+	if _, ok := values["message"]; ok {
+		t.Errorf("expected null to remove the default, got %v", values)
+	}
+}
@@ -0,0 +1,85 @@
+package helm_client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofrs/flock"
+	"go.uber.org/zap"
+
+	"github.com/zekker6/mcp-helm/lib/logger"
+)
+
+// workspacesDir is the directory below the cache directory holding the
+// workspaces of all clients sharing that cache directory.
+const workspacesDir = "workspaces"
+
+// workspace is a directory private to a single HelmClient, holding Helm's
+// repository configuration, downloaded repository indexes and temporary chart
+// downloads. Several mcp-helm instances can share a cache directory without
+// overwriting each other's files; only the digest-keyed chart archive cache is
+// shared, and it is written atomically.
+//
+// A workspace is protected by a lock file held for the lifetime of the client.
+// Workspaces whose lock is not held, left behind by instances that exited
+// without Close, are removed when the next client is created.
+type workspace struct {
+	dir  string
+	lock *flock.Flock
+}
+
+func newWorkspace(cacheDir string) (*workspace, error) {
+	root := filepath.Join(cacheDir, workspacesDir)
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create workspaces directory: %w", err)
+	}
+	removeStaleWorkspaces(root)
+
+	dir, err := os.MkdirTemp(root, "client-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	lock := flock.New(dir + ".lock")
+	locked, err := lock.TryLock()
+	if err != nil || !locked {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to lock workspace %s: %v", dir, err)
+	}
+
+	return &workspace{dir: dir, lock: lock}, nil
+}
+
+// removeStaleWorkspaces removes the workspaces in root whose lock is not held
+// by a running client.
+func removeStaleWorkspaces(root string) {
+	locks, err := filepath.Glob(filepath.Join(root, "*.lock"))
+	if err != nil {
+		return
+	}
+
+	for _, path := range locks {
+		lock := flock.New(path)
+		locked, err := lock.TryLock()
+		if err != nil || !locked {
+			continue
+		}
+		if err := os.RemoveAll(strings.TrimSuffix(path, ".lock")); err != nil {
+			logger.Warn("failed to remove stale workspace", zap.String("path", path), zap.Error(err))
+		}
+		_ = lock.Unlock()
+		_ = os.Remove(path)
+	}
+}
+
+// close releases the workspace lock and removes the workspace.
+func (w *workspace) close() error {
+	err := os.RemoveAll(w.dir)
+	if unlockErr := w.lock.Unlock(); err == nil {
+		err = unlockErr
+	}
+	_ = os.Remove(w.lock.Path())
+	return err
+}
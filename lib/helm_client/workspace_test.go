@@ -0,0 +1,70 @@
+package helm_client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkspaceIsolation(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	first, err := NewClient(WithCacheDir(cacheDir))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	second, err := NewClient(WithCacheDir(cacheDir))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if first.ws.dir == second.ws.dir {
+		t.Fatalf("clients share workspace %s", first.ws.dir)
+	}
+	if first.settings.RepositoryConfig == second.settings.RepositoryConfig {
+		t.Errorf("clients share repository config %s", first.settings.RepositoryConfig)
+	}
+	if first.settings.ContentCache != second.settings.ContentCache {
+		t.Errorf("clients should share the chart archive cache, got %s and %s", first.settings.ContentCache, second.settings.ContentCache)
+	}
+
+	if err := second.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(second.ws.dir); !os.IsNotExist(err) {
+		t.Errorf("expected closed workspace to be removed, stat error = %v", err)
+	}
+	if _, err := os.Stat(first.ws.dir); err != nil {
+		t.Errorf("expected workspace of open client to be kept: %v", err)
+	}
+
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func TestStaleWorkspaceRemoved(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	// A workspace left behind by an instance that exited without Close.
+	stale := filepath.Join(cacheDir, workspacesDir, "client-stale")
+	if err := os.MkdirAll(filepath.Join(stale, "helm-cache"), 0o755); err != nil {
+		t.Fatalf("mkdir stale workspace: %v", err)
+	}
+	if err := os.WriteFile(stale+".lock", nil, 0o644); err != nil {
+		t.Fatalf("write stale lock: %v", err)
+	}
+
+	client, err := NewClient(WithCacheDir(cacheDir))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("expected stale workspace to be removed, stat error = %v", err)
+	}
+	if _, err := os.Stat(stale + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("expected stale lock to be removed, stat error = %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package helm_parser
+
+import (
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Autoscaler describes a HorizontalPodAutoscaler scaling a workload.
+type Autoscaler struct {
+	Name        string `json:"name"`
+	MinReplicas int    `json:"minReplicas"`
+	MaxReplicas int    `json:"maxReplicas"`
+}
+
+// DisruptionBudget describes a PodDisruptionBudget selecting the pods of a
+// workload.
+type DisruptionBudget struct {
+	Name           string `json:"name"`
+	MinAvailable   string `json:"minAvailable,omitempty"`
+	MaxUnavailable string `json:"maxUnavailable,omitempty"`
+}
+
+// TopologySpread is a topology spread constraint of a workload.
+type TopologySpread struct {
+	TopologyKey       string `json:"topologyKey"`
+	MaxSkew           int    `json:"maxSkew"`
+	WhenUnsatisfiable string `json:"whenUnsatisfiable"`
+}
+
+// UpdateStrategy is the strategy a workload replaces its pods with, with
+// the defaults of Kubernetes for unset fields.
+type UpdateStrategy struct {
+	// Type is RollingUpdate, Recreate (Deployments) or OnDelete
+	// (StatefulSets and DaemonSets).
+	Type           string `json:"type"`
+	MaxSurge       string `json:"maxSurge,omitempty"`
+	MaxUnavailable string `json:"maxUnavailable,omitempty"`
+	// Partition is the ordinal from which a StatefulSet updates its pods.
+	Partition *int `json:"partition,omitempty"`
+}
+
+// WorkloadAvailability summarizes how a workload stays available.
+type WorkloadAvailability struct {
+	Resource
+	// Replicas is the replica count, unset for DaemonSets, which run a pod on
+	// every node.
+	Replicas         *int              `json:"replicas,omitempty"`
+	Autoscaler       *Autoscaler       `json:"autoscaler,omitempty"`
+	DisruptionBudget *DisruptionBudget `json:"disruptionBudget,omitempty"`
+	TopologySpread   []TopologySpread  `json:"topologySpread,omitempty"`
+	// PodAntiAffinity reports whether the pods have an anti-affinity, which
+	// usually keeps replicas off the same node.
+	PodAntiAffinity bool           `json:"podAntiAffinity"`
+	UpdateStrategy  UpdateStrategy `json:"updateStrategy"`
+	MinReadySeconds int            `json:"minReadySeconds,omitempty"`
+}
+
+// AvailabilityReport is the result of SummarizeAvailability.
+type AvailabilityReport struct {
+	Workloads []WorkloadAvailability `json:"workloads"`
+}
+
+type availabilityStrategy struct {
+	Type          string `json:"type"`
+	RollingUpdate struct {
+		MaxSurge       *intstr.IntOrString `json:"maxSurge"`
+		MaxUnavailable *intstr.IntOrString `json:"maxUnavailable"`
+		Partition      *int                `json:"partition"`
+	} `json:"rollingUpdate"`
+}
+
+// availabilityResource holds the fields of the workloads,
+// HorizontalPodAutoscalers and PodDisruptionBudgets SummarizeAvailability
+// looks at.
+type availabilityResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		Replicas        *int                 `json:"replicas"`
+		MinReadySeconds int                  `json:"minReadySeconds"`
+		Strategy        availabilityStrategy `json:"strategy"`
+		UpdateStrategy  availabilityStrategy `json:"updateStrategy"`
+		Template        struct {
+			Metadata struct {
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+			Spec struct {
+				Affinity struct {
+					PodAntiAffinity map[string]any `json:"podAntiAffinity"`
+				} `json:"affinity"`
+				TopologySpreadConstraints []struct {
+					TopologyKey       string `json:"topologyKey"`
+					MaxSkew           int    `json:"maxSkew"`
+					WhenUnsatisfiable string `json:"whenUnsatisfiable"`
+				} `json:"topologySpreadConstraints"`
+			} `json:"spec"`
+		} `json:"template"`
+
+		// HorizontalPodAutoscaler.
+		ScaleTargetRef struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"scaleTargetRef"`
+		MinReplicas *int `json:"minReplicas"`
+		MaxReplicas int  `json:"maxReplicas"`
+
+		// PodDisruptionBudget.
+		Selector *struct {
+			MatchLabels map[string]string `json:"matchLabels"`
+		} `json:"selector"`
+		MinAvailable   *intstr.IntOrString `json:"minAvailable"`
+		MaxUnavailable *intstr.IntOrString `json:"maxUnavailable"`
+	} `json:"spec"`
+}
+
+// SummarizeAvailability reports for the Deployments, StatefulSets,
+// DaemonSets and ReplicaSets of a multi-document YAML manifest, such as a
+// rendered chart, what keeps them available: replica counts, the
+// HorizontalPodAutoscalers and PodDisruptionBudgets in the manifest targeting
+// them, topology spread constraints, pod anti-affinity and update
+// strategies. Workloads are reported in manifest order.
+func SummarizeAvailability(manifest string) (*AvailabilityReport, error) {
+	resources, err := decodeManifest[availabilityResource](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AvailabilityReport{Workloads: []WorkloadAvailability{}}
+	for _, r := range resources {
+		w := WorkloadAvailability{
+			Resource:        Resource{APIVersion: r.APIVersion, Kind: r.Kind, Name: r.Metadata.Name, Namespace: r.Metadata.Namespace},
+			PodAntiAffinity: r.Spec.Template.Spec.Affinity.PodAntiAffinity != nil,
+			MinReadySeconds: r.Spec.MinReadySeconds,
+		}
+		switch r.Kind {
+		case "Deployment":
+			w.UpdateStrategy = updateStrategy(r.Spec.Strategy, "25%", "25%")
+		case "StatefulSet":
+			w.UpdateStrategy = updateStrategy(r.Spec.UpdateStrategy, "", "1")
+		case "DaemonSet":
+			w.UpdateStrategy = updateStrategy(r.Spec.UpdateStrategy, "0", "1")
+		case "ReplicaSet":
+			// ReplicaSets do not replace their pods.
+		default:
+			continue
+		}
+		if r.Kind != "DaemonSet" {
+			replicas := 1
+			if r.Spec.Replicas != nil {
+				replicas = *r.Spec.Replicas
+			}
+			w.Replicas = &replicas
+		}
+		for _, c := range r.Spec.Template.Spec.TopologySpreadConstraints {
+			w.TopologySpread = append(w.TopologySpread, TopologySpread(c))
+		}
+
+		for _, other := range resources {
+			if other.Metadata.Namespace != r.Metadata.Namespace {
+				continue
+			}
+			switch {
+			case other.Kind == "HorizontalPodAutoscaler" && other.Spec.ScaleTargetRef.Kind == r.Kind && other.Spec.ScaleTargetRef.Name == r.Metadata.Name:
+				w.Autoscaler = &Autoscaler{Name: other.Metadata.Name, MinReplicas: 1, MaxReplicas: other.Spec.MaxReplicas}
+				if other.Spec.MinReplicas != nil {
+					w.Autoscaler.MinReplicas = *other.Spec.MinReplicas
+				}
+			case other.Kind == "PodDisruptionBudget" && other.Spec.Selector != nil && labelsMatch(other.Spec.Selector.MatchLabels, r.Spec.Template.Metadata.Labels):
+				w.DisruptionBudget = &DisruptionBudget{
+					Name:           other.Metadata.Name,
+					MinAvailable:   intOrString(other.Spec.MinAvailable),
+					MaxUnavailable: intOrString(other.Spec.MaxUnavailable),
+				}
+			}
+		}
+		report.Workloads = append(report.Workloads, w)
+	}
+	return report, nil
+}
+
+// updateStrategy returns the strategy s with the defaults for a rolling
+// update of the workload kind.
+func updateStrategy(s availabilityStrategy, maxSurge, maxUnavailable string) UpdateStrategy {
+	strategy := UpdateStrategy{Type: s.Type, Partition: s.RollingUpdate.Partition}
+	if strategy.Type == "" {
+		strategy.Type = "RollingUpdate"
+	}
+	if strategy.Type != "RollingUpdate" {
+		return UpdateStrategy{Type: strategy.Type}
+	}
+	strategy.MaxSurge = intOrString(s.RollingUpdate.MaxSurge)
+	if strategy.MaxSurge == "" {
+		strategy.MaxSurge = maxSurge
+	}
+	strategy.MaxUnavailable = intOrString(s.RollingUpdate.MaxUnavailable)
+	if strategy.MaxUnavailable == "" {
+		strategy.MaxUnavailable = maxUnavailable
+	}
+	return strategy
+}
+
+func intOrString(v *intstr.IntOrString) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
@@ -0,0 +1,111 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarizeAvailability(t *testing.T) {
+	manifest := `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+  minReadySeconds: 10
+  strategy:
+    rollingUpdate:
+      maxUnavailable: 0
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      affinity:
+        podAntiAffinity:
+          preferredDuringSchedulingIgnoredDuringExecution: []
+      topologySpreadConstraints:
+        - maxSkew: 1
+          topologyKey: topology.kubernetes.io/zone
+          whenUnsatisfiable: ScheduleAnyway
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+spec:
+  maxUnavailable: 50%
+  selector:
+    matchLabels:
+      app: web
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+  maxReplicas: 10
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  updateStrategy:
+    type: OnDelete
+  template:
+    metadata:
+      labels:
+        app: db
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    metadata:
+      labels:
+        app: agent
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+`
+
+	report, err := SummarizeAvailability(manifest)
+	if err != nil {
+		t.Fatalf("SummarizeAvailability() error = %v", err)
+	}
+	two, one := 2, 1
+	want := []WorkloadAvailability{
+		{
+			Resource:         Resource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+			Replicas:         &two,
+			Autoscaler:       &Autoscaler{Name: "web", MinReplicas: 1, MaxReplicas: 10},
+			DisruptionBudget: &DisruptionBudget{Name: "web", MaxUnavailable: "50%"},
+			TopologySpread:   []TopologySpread{{TopologyKey: "topology.kubernetes.io/zone", MaxSkew: 1, WhenUnsatisfiable: "ScheduleAnyway"}},
+			PodAntiAffinity:  true,
+			UpdateStrategy:   UpdateStrategy{Type: "RollingUpdate", MaxSurge: "25%", MaxUnavailable: "0"},
+			MinReadySeconds:  10,
+		},
+		{
+			Resource:       Resource{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "db"},
+			Replicas:       &one,
+			UpdateStrategy: UpdateStrategy{Type: "OnDelete"},
+		},
+		{
+			Resource:       Resource{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "agent"},
+			UpdateStrategy: UpdateStrategy{Type: "RollingUpdate", MaxSurge: "0", MaxUnavailable: "1"},
+		},
+	}
+	if !reflect.DeepEqual(report.Workloads, want) {
+		t.Errorf("got %+v, want %+v", report.Workloads, want)
+	}
+}
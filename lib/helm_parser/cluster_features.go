@@ -0,0 +1,142 @@
+package helm_parser
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// Template features reported by FindClusterFeatures.
+const (
+	FeatureLookup      = "lookup"
+	FeatureAPIVersions = "capabilities_api_versions"
+	FeatureKubeVersion = "capabilities_kube_version"
+	FeatureRequired    = "required"
+)
+
+// clusterFeatures lists the template constructs that render differently
+// offline than against a live cluster, with the difference.
+var clusterFeatures = []struct {
+	name   string
+	re     *regexp.Regexp
+	impact string
+}{
+	{
+		FeatureLookup,
+		regexp.MustCompile(`(^|[\s(|])lookup\s`),
+		"lookup queries the cluster for existing resources; offline it returns an empty map, so branches depending on existing resources (e.g. reusing generated secrets) take the not-found path",
+	},
+	{
+		FeatureAPIVersions,
+		regexp.MustCompile(`\.Capabilities\.APIVersions`),
+		"API version checks see only the built-in API versions offline; resources gated on CRDs or optional APIs (e.g. monitoring.coreos.com/v1) are omitted",
+	},
+	{
+		FeatureKubeVersion,
+		regexp.MustCompile(`\.Capabilities\.KubeVersion`),
+		"Kubernetes version checks see Helm's default version offline instead of the version of the target cluster, which may select other API versions or fields",
+	},
+	{
+		FeatureRequired,
+		regexp.MustCompile(`(^|[\s(|])required\s`),
+		"required fails rendering when the value is not set; offline rendering with default values fails or the value must be provided",
+	},
+}
+
+// ClusterFeature is a use of a template construct that behaves differently
+// offline than against a live cluster.
+type ClusterFeature struct {
+	// Template is the path of the template, below charts/<subchart name>/ for
+	// templates of subcharts.
+	Template string `json:"template"`
+	Line     int    `json:"line"`
+	Feature  string `json:"feature"`
+	Snippet  string `json:"snippet"`
+}
+
+// ClusterFeatureReport lists the cluster-dependent template constructs of a
+// chart, with an explanation of every feature found.
+type ClusterFeatureReport struct {
+	Findings []ClusterFeature `json:"findings"`
+	// Impact explains how every feature found behaves offline.
+	Impact map[string]string `json:"impact"`
+}
+
+// FindClusterFeatures scans the templates of chart, and of its subcharts if
+// recursive is set, for lookup calls, .Capabilities checks and required
+// values, which make offline rendering differ from installing into a cluster.
+func FindClusterFeatures(chart *chartv2.Chart, recursive bool) ClusterFeatureReport {
+	report := ClusterFeatureReport{
+		Findings: findClusterFeatures(chart, "", recursive),
+		Impact:   make(map[string]string),
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Template != b.Template {
+			return a.Template < b.Template
+		}
+		return a.Line < b.Line
+	})
+	for _, f := range report.Findings {
+		for _, feature := range clusterFeatures {
+			if feature.name == f.Feature {
+				report.Impact[f.Feature] = feature.impact
+			}
+		}
+	}
+	return report
+}
+
+func findClusterFeatures(chart *chartv2.Chart, prefix string, recursive bool) []ClusterFeature {
+	findings := []ClusterFeature{}
+	for _, tmpl := range chart.Templates {
+		for i, line := range strings.Split(string(tmpl.Data), "\n") {
+			for _, action := range templateActions(line) {
+				for _, feature := range clusterFeatures {
+					if feature.re.MatchString(action) {
+						findings = append(findings, ClusterFeature{
+							Template: prefix + tmpl.Name,
+							Line:     i + 1,
+							Feature:  feature.name,
+							Snippet:  strings.TrimSpace(line),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	if recursive {
+		for _, dep := range chart.Dependencies() {
+			findings = append(findings, findClusterFeatures(dep, prefix+"charts/"+dep.Name()+"/", recursive)...)
+		}
+	}
+	return findings
+}
+
+// templateActions returns the contents of the template actions on a line,
+// skipping comments. An action continuing on the next line is returned up
+// to the end of the line.
+func templateActions(line string) []string {
+	var actions []string
+	for {
+		start := strings.Index(line, "{{")
+		if start == -1 {
+			return actions
+		}
+		line = line[start+2:]
+		end := strings.Index(line, "}}")
+		action := line
+		if end != -1 {
+			action = line[:end]
+			line = line[end+2:]
+		} else {
+			line = ""
+		}
+		if trimmed := strings.TrimLeft(action, "- "); !strings.HasPrefix(trimmed, "/*") {
+			actions = append(actions, action)
+		}
+	}
+}
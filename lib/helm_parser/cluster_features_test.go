@@ -0,0 +1,50 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/chart/common"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestFindClusterFeatures(t *testing.T) {
+	chart := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "app", Version: "1.0.0"},
+		Templates: []*common.File{
+			{Name: "templates/secret.yaml", Data: []byte(`{{- $existing := (lookup "v1" "Secret" .Release.Namespace "app") }}
+{{/* required is not called here: required "x" */}}
+password: {{ required "password is required" .Values.password | b64enc }}
+`)},
+			{Name: "templates/monitor.yaml", Data: []byte(`{{- if .Capabilities.APIVersions.Has "monitoring.coreos.com/v1" }}
+apiVersion: monitoring.coreos.com/v1
+{{- end }}
+`)},
+		},
+	}
+	sub := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "db", Version: "1.0.0"},
+		Templates: []*common.File{
+			{Name: "templates/pdb.yaml", Data: []byte(`apiVersion: {{ if semverCompare ">=1.21-0" .Capabilities.KubeVersion.Version }}policy/v1{{ end }}`)},
+		},
+	}
+	chart.SetDependencies(sub)
+
+	report := FindClusterFeatures(chart, false)
+	want := []ClusterFeature{
+		{Template: "templates/monitor.yaml", Line: 1, Feature: FeatureAPIVersions, Snippet: `{{- if .Capabilities.APIVersions.Has "monitoring.coreos.com/v1" }}`},
+		{Template: "templates/secret.yaml", Line: 1, Feature: FeatureLookup, Snippet: `{{- $existing := (lookup "v1" "Secret" .Release.Namespace "app") }}`},
+		{Template: "templates/secret.yaml", Line: 3, Feature: FeatureRequired, Snippet: `password: {{ required "password is required" .Values.password | b64enc }}`},
+	}
+	if !reflect.DeepEqual(report.Findings, want) {
+		t.Fatalf("FindClusterFeatures() = %+v, want %+v", report.Findings, want)
+	}
+	if len(report.Impact) != 3 || report.Impact[FeatureKubeVersion] != "" {
+		t.Errorf("unexpected impact %v", report.Impact)
+	}
+
+	report = FindClusterFeatures(chart, true)
+	if len(report.Findings) != 4 || report.Findings[0].Template != "charts/db/templates/pdb.yaml" || report.Findings[0].Feature != FeatureKubeVersion {
+		t.Errorf("expected the subchart template to be scanned, got %+v", report.Findings)
+	}
+}
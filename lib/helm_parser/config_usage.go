@@ -0,0 +1,321 @@
+package helm_parser
+
+import (
+	"slices"
+	"strings"
+)
+
+// Ways a workload uses a ConfigMap or Secret, reported by MapConfigUsage.
+const (
+	ConfigUsageEnv             = "env"
+	ConfigUsageEnvFrom         = "envFrom"
+	ConfigUsageVolume          = "volume"
+	ConfigUsageImagePullSecret = "imagePullSecret"
+)
+
+// ConfigReference is a reference of a workload to a ConfigMap or Secret.
+type ConfigReference struct {
+	// Kind is ConfigMap or Secret.
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Usage is how the workload uses the object, e.g. envFrom.
+	Usage string `json:"usage"`
+	// Container is the container referencing the object in its environment.
+	Container string `json:"container,omitempty"`
+	// Key is the key of an environment variable taken from the object.
+	Key string `json:"key,omitempty"`
+	// Optional reports that the pods start without the object.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// WorkloadConfigUsage lists the ConfigMaps and Secrets a workload references.
+type WorkloadConfigUsage struct {
+	Resource
+	References []ConfigReference `json:"references"`
+}
+
+// ConfigObject is a ConfigMap or Secret created or referenced by the
+// manifest.
+type ConfigObject struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// CreatedBy is the kind of the resource in the manifest creating the
+	// object: ConfigMap or Secret, or for Secrets ExternalSecret, SealedSecret
+	// or a cert-manager Certificate. It is empty for objects expected to
+	// exist.
+	CreatedBy string `json:"createdBy,omitempty"`
+	// UsedBy are the workloads referencing the object, as kind/name.
+	UsedBy []string `json:"usedBy"`
+	// Optional reports that all references to the object are optional.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// ConfigUsageReport is the result of MapConfigUsage.
+type ConfigUsageReport struct {
+	Workloads  []WorkloadConfigUsage `json:"workloads"`
+	ConfigMaps []ConfigObject        `json:"configMaps"`
+	Secrets    []ConfigObject        `json:"secrets"`
+	// ExpectedConfigMaps and ExpectedSecrets are the names of the objects
+	// workloads require but the manifest does not create, which must exist
+	// before installing it.
+	ExpectedConfigMaps []string `json:"expectedConfigMaps"`
+	ExpectedSecrets    []string `json:"expectedSecrets"`
+}
+
+type configKeyRef struct {
+	Name     string `json:"name"`
+	Key      string `json:"key"`
+	Optional bool   `json:"optional"`
+}
+
+type configNameRef struct {
+	Name     string `json:"name"`
+	Optional bool   `json:"optional"`
+}
+
+type configUsageContainer struct {
+	Name string `json:"name"`
+	Env  []struct {
+		ValueFrom *struct {
+			ConfigMapKeyRef *configKeyRef `json:"configMapKeyRef"`
+			SecretKeyRef    *configKeyRef `json:"secretKeyRef"`
+		} `json:"valueFrom"`
+	} `json:"env"`
+	EnvFrom []struct {
+		ConfigMapRef *configNameRef `json:"configMapRef"`
+		SecretRef    *configNameRef `json:"secretRef"`
+	} `json:"envFrom"`
+}
+
+type configUsagePodSpec struct {
+	Containers       []configUsageContainer `json:"containers"`
+	InitContainers   []configUsageContainer `json:"initContainers"`
+	ImagePullSecrets []struct {
+		Name string `json:"name"`
+	} `json:"imagePullSecrets"`
+	Volumes []struct {
+		ConfigMap *configNameRef `json:"configMap"`
+		Secret    *struct {
+			SecretName string `json:"secretName"`
+			Optional   bool   `json:"optional"`
+		} `json:"secret"`
+		Projected *struct {
+			Sources []struct {
+				ConfigMap *configNameRef `json:"configMap"`
+				Secret    *configNameRef `json:"secret"`
+			} `json:"sources"`
+		} `json:"projected"`
+	} `json:"volumes"`
+}
+
+// configUsageResource holds the fields of the workloads and of the resources
+// creating ConfigMaps and Secrets MapConfigUsage looks at. The pod spec is
+// embedded for Pods.
+type configUsageResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		configUsagePodSpec
+		Template struct {
+			Spec configUsagePodSpec `json:"spec"`
+
+			// SealedSecret.
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		} `json:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template struct {
+					Spec configUsagePodSpec `json:"spec"`
+				} `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+
+		// ExternalSecret.
+		Target struct {
+			Name string `json:"name"`
+		} `json:"target"`
+
+		// Certificate.
+		SecretName string `json:"secretName"`
+	} `json:"spec"`
+}
+
+// podSpec returns the pod spec of a workload, false for other resources.
+func (r configUsageResource) podSpec() (configUsagePodSpec, bool) {
+	switch r.Kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		return r.Spec.Template.Spec, true
+	case "CronJob":
+		return r.Spec.JobTemplate.Spec.Template.Spec, true
+	case "Pod":
+		return r.Spec.configUsagePodSpec, true
+	}
+	return configUsagePodSpec{}, false
+}
+
+// createdSecret returns the name of the Secret a resource creates, if any.
+func (r configUsageResource) createdSecret() string {
+	switch {
+	case r.Kind == "Secret":
+		return r.Metadata.Name
+	case r.Kind == "ExternalSecret" && r.Spec.Target.Name != "":
+		return r.Spec.Target.Name
+	case r.Kind == "ExternalSecret":
+		// The target defaults to the name of the ExternalSecret.
+		return r.Metadata.Name
+	case r.Kind == "SealedSecret" && r.Spec.Template.Metadata.Name != "":
+		return r.Spec.Template.Metadata.Name
+	case r.Kind == "SealedSecret":
+		return r.Metadata.Name
+	case r.Kind == "Certificate" && strings.HasPrefix(r.APIVersion, "cert-manager.io/"):
+		return r.Spec.SecretName
+	}
+	return ""
+}
+
+// MapConfigUsage maps which workloads of a multi-document YAML manifest, such
+// as a rendered chart, reference which ConfigMaps and Secrets through
+// environment variables, envFrom, volumes and image pull secrets, and which
+// of them the manifest creates itself or expects to exist. Workloads are
+// reported in manifest order, objects sorted by namespace and name.
+func MapConfigUsage(manifest string) (*ConfigUsageReport, error) {
+	resources, err := decodeManifest[configUsageResource](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ConfigUsageReport{
+		Workloads:          []WorkloadConfigUsage{},
+		ConfigMaps:         []ConfigObject{},
+		Secrets:            []ConfigObject{},
+		ExpectedConfigMaps: []string{},
+		ExpectedSecrets:    []string{},
+	}
+	objects := map[string]map[[2]string]*ConfigObject{"ConfigMap": {}, "Secret": {}}
+	object := func(kind, namespace, name string) *ConfigObject {
+		key := [2]string{namespace, name}
+		if objects[kind][key] == nil {
+			objects[kind][key] = &ConfigObject{Name: name, Namespace: namespace, UsedBy: []string{}, Optional: true}
+		}
+		return objects[kind][key]
+	}
+
+	for _, r := range resources {
+		if r.Kind == "ConfigMap" {
+			object("ConfigMap", r.Metadata.Namespace, r.Metadata.Name).CreatedBy = r.Kind
+		} else if name := r.createdSecret(); name != "" {
+			object("Secret", r.Metadata.Namespace, name).CreatedBy = r.Kind
+		}
+	}
+
+	for _, r := range resources {
+		spec, ok := r.podSpec()
+		if !ok {
+			continue
+		}
+		w := WorkloadConfigUsage{
+			Resource:   Resource{APIVersion: r.APIVersion, Kind: r.Kind, Name: r.Metadata.Name, Namespace: r.Metadata.Namespace},
+			References: podConfigReferences(spec),
+		}
+		workload := r.Kind + "/" + r.Metadata.Name
+		for _, ref := range w.References {
+			o := object(ref.Kind, r.Metadata.Namespace, ref.Name)
+			if !slices.Contains(o.UsedBy, workload) {
+				o.UsedBy = append(o.UsedBy, workload)
+			}
+			o.Optional = o.Optional && ref.Optional
+		}
+		report.Workloads = append(report.Workloads, w)
+	}
+
+	for _, kind := range []string{"ConfigMap", "Secret"} {
+		var list []ConfigObject
+		var expected []string
+		for _, o := range objects[kind] {
+			if len(o.UsedBy) == 0 {
+				o.Optional = false
+			}
+			if o.CreatedBy == "" && !o.Optional {
+				expected = append(expected, o.Name)
+			}
+			list = append(list, *o)
+		}
+		slices.SortFunc(list, func(a, b ConfigObject) int {
+			if c := strings.Compare(a.Namespace, b.Namespace); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+		slices.Sort(expected)
+		expected = slices.Compact(expected)
+		if kind == "ConfigMap" {
+			report.ConfigMaps = append(report.ConfigMaps, list...)
+			report.ExpectedConfigMaps = append(report.ExpectedConfigMaps, expected...)
+		} else {
+			report.Secrets = append(report.Secrets, list...)
+			report.ExpectedSecrets = append(report.ExpectedSecrets, expected...)
+		}
+	}
+	return report, nil
+}
+
+// podConfigReferences returns the ConfigMaps and Secrets a pod spec
+// references, without duplicates.
+func podConfigReferences(spec configUsagePodSpec) []ConfigReference {
+	refs := []ConfigReference{}
+	add := func(ref ConfigReference) {
+		if ref.Name != "" && !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+
+	for _, s := range spec.ImagePullSecrets {
+		add(ConfigReference{Kind: "Secret", Name: s.Name, Usage: ConfigUsageImagePullSecret})
+	}
+	for _, v := range spec.Volumes {
+		if v.ConfigMap != nil {
+			add(ConfigReference{Kind: "ConfigMap", Name: v.ConfigMap.Name, Usage: ConfigUsageVolume, Optional: v.ConfigMap.Optional})
+		}
+		if v.Secret != nil {
+			add(ConfigReference{Kind: "Secret", Name: v.Secret.SecretName, Usage: ConfigUsageVolume, Optional: v.Secret.Optional})
+		}
+		if v.Projected != nil {
+			for _, s := range v.Projected.Sources {
+				if s.ConfigMap != nil {
+					add(ConfigReference{Kind: "ConfigMap", Name: s.ConfigMap.Name, Usage: ConfigUsageVolume, Optional: s.ConfigMap.Optional})
+				}
+				if s.Secret != nil {
+					add(ConfigReference{Kind: "Secret", Name: s.Secret.Name, Usage: ConfigUsageVolume, Optional: s.Secret.Optional})
+				}
+			}
+		}
+	}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		for _, e := range c.EnvFrom {
+			if e.ConfigMapRef != nil {
+				add(ConfigReference{Kind: "ConfigMap", Name: e.ConfigMapRef.Name, Usage: ConfigUsageEnvFrom, Container: c.Name, Optional: e.ConfigMapRef.Optional})
+			}
+			if e.SecretRef != nil {
+				add(ConfigReference{Kind: "Secret", Name: e.SecretRef.Name, Usage: ConfigUsageEnvFrom, Container: c.Name, Optional: e.SecretRef.Optional})
+			}
+		}
+		for _, e := range c.Env {
+			if e.ValueFrom == nil {
+				continue
+			}
+			if ref := e.ValueFrom.ConfigMapKeyRef; ref != nil {
+				add(ConfigReference{Kind: "ConfigMap", Name: ref.Name, Usage: ConfigUsageEnv, Container: c.Name, Key: ref.Key, Optional: ref.Optional})
+			}
+			if ref := e.ValueFrom.SecretKeyRef; ref != nil {
+				add(ConfigReference{Kind: "Secret", Name: ref.Name, Usage: ConfigUsageEnv, Container: c.Name, Key: ref.Key, Optional: ref.Optional})
+			}
+		}
+	}
+	return refs
+}
@@ -0,0 +1,150 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapConfigUsage(t *testing.T) {
+	manifest := `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-secret
+---
+apiVersion: external-secrets.io/v1beta1
+kind: ExternalSecret
+metadata:
+  name: db
+spec:
+  target:
+    name: db-credentials
+---
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: tls
+spec:
+  secretName: app-tls
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      imagePullSecrets:
+        - name: registry
+      volumes:
+        - name: config
+          configMap:
+            name: app-config
+        - name: tls
+          secret:
+            secretName: app-tls
+        - name: extra
+          projected:
+            sources:
+              - configMap:
+                  name: extra
+                  optional: true
+      initContainers:
+        - name: migrate
+          envFrom:
+            - secretRef:
+                name: db-credentials
+      containers:
+        - name: app
+          envFrom:
+            - configMapRef:
+                name: app-config
+            - secretRef:
+                name: db-credentials
+          env:
+            - name: PLAIN
+              value: x
+            - name: API_KEY
+              valueFrom:
+                secretKeyRef:
+                  name: api
+                  key: key
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: backup
+              env:
+                - name: API_KEY
+                  valueFrom:
+                    secretKeyRef:
+                      name: api
+                      key: key
+`
+
+	report, err := MapConfigUsage(manifest)
+	if err != nil {
+		t.Fatalf("MapConfigUsage() error = %v", err)
+	}
+
+	wantWorkloads := []WorkloadConfigUsage{
+		{
+			Resource: Resource{APIVersion: "apps/v1", Kind: "Deployment", Name: "app"},
+			References: []ConfigReference{
+				{Kind: "Secret", Name: "registry", Usage: ConfigUsageImagePullSecret},
+				{Kind: "ConfigMap", Name: "app-config", Usage: ConfigUsageVolume},
+				{Kind: "Secret", Name: "app-tls", Usage: ConfigUsageVolume},
+				{Kind: "ConfigMap", Name: "extra", Usage: ConfigUsageVolume, Optional: true},
+				{Kind: "Secret", Name: "db-credentials", Usage: ConfigUsageEnvFrom, Container: "migrate"},
+				{Kind: "ConfigMap", Name: "app-config", Usage: ConfigUsageEnvFrom, Container: "app"},
+				{Kind: "Secret", Name: "db-credentials", Usage: ConfigUsageEnvFrom, Container: "app"},
+				{Kind: "Secret", Name: "api", Usage: ConfigUsageEnv, Container: "app", Key: "key"},
+			},
+		},
+		{
+			Resource:   Resource{APIVersion: "batch/v1", Kind: "CronJob", Name: "backup"},
+			References: []ConfigReference{{Kind: "Secret", Name: "api", Usage: ConfigUsageEnv, Container: "backup", Key: "key"}},
+		},
+	}
+	if !reflect.DeepEqual(report.Workloads, wantWorkloads) {
+		t.Errorf("workloads = %+v\nwant %+v", report.Workloads, wantWorkloads)
+	}
+
+	wantConfigMaps := []ConfigObject{
+		{Name: "app-config", CreatedBy: "ConfigMap", UsedBy: []string{"Deployment/app"}},
+		{Name: "extra", UsedBy: []string{"Deployment/app"}, Optional: true},
+	}
+	if !reflect.DeepEqual(report.ConfigMaps, wantConfigMaps) {
+		t.Errorf("config maps = %+v\nwant %+v", report.ConfigMaps, wantConfigMaps)
+	}
+
+	wantSecrets := []ConfigObject{
+		{Name: "api", UsedBy: []string{"Deployment/app", "CronJob/backup"}},
+		{Name: "app-secret", CreatedBy: "Secret", UsedBy: []string{}},
+		{Name: "app-tls", CreatedBy: "Certificate", UsedBy: []string{"Deployment/app"}},
+		{Name: "db-credentials", CreatedBy: "ExternalSecret", UsedBy: []string{"Deployment/app"}},
+		{Name: "registry", UsedBy: []string{"Deployment/app"}},
+	}
+	if !reflect.DeepEqual(report.Secrets, wantSecrets) {
+		t.Errorf("secrets = %+v\nwant %+v", report.Secrets, wantSecrets)
+	}
+
+	if want := []string{}; !reflect.DeepEqual(report.ExpectedConfigMaps, want) {
+		t.Errorf("expected config maps = %v, want %v", report.ExpectedConfigMaps, want)
+	}
+	if want := []string{"api", "registry"}; !reflect.DeepEqual(report.ExpectedSecrets, want) {
+		t.Errorf("expected secrets = %v, want %v", report.ExpectedSecrets, want)
+	}
+}
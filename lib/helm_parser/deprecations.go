@@ -0,0 +1,127 @@
+package helm_parser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Deprecation states reported by FindDeprecatedAPIs.
+const (
+	APIDeprecated = "deprecated"
+	APIRemoved    = "removed"
+)
+
+// apiDeprecation describes a Kubernetes API version of a kind that was
+// deprecated and removed in the given Kubernetes minor versions.
+type apiDeprecation struct {
+	apiVersion   string
+	kinds        []string
+	deprecatedIn KubeVersion
+	removedIn    KubeVersion
+	replacement  string
+}
+
+// apiDeprecations lists the deprecated APIs of built-in Kubernetes kinds,
+// following the Kubernetes deprecated API migration guide.
+var apiDeprecations = []apiDeprecation{
+	{"extensions/v1beta1", []string{"Deployment", "DaemonSet", "ReplicaSet"}, KubeVersion{1, 9}, KubeVersion{1, 16}, "apps/v1"},
+	{"apps/v1beta1", []string{"Deployment", "StatefulSet", "ReplicaSet"}, KubeVersion{1, 9}, KubeVersion{1, 16}, "apps/v1"},
+	{"apps/v1beta2", []string{"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet"}, KubeVersion{1, 9}, KubeVersion{1, 16}, "apps/v1"},
+	{"extensions/v1beta1", []string{"NetworkPolicy"}, KubeVersion{1, 9}, KubeVersion{1, 16}, "networking.k8s.io/v1"},
+	{"extensions/v1beta1", []string{"PodSecurityPolicy"}, KubeVersion{1, 11}, KubeVersion{1, 16}, "policy/v1beta1"},
+	{"extensions/v1beta1", []string{"Ingress"}, KubeVersion{1, 14}, KubeVersion{1, 22}, "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", []string{"Ingress", "IngressClass"}, KubeVersion{1, 19}, KubeVersion{1, 22}, "networking.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", []string{"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"}, KubeVersion{1, 17}, KubeVersion{1, 22}, "rbac.authorization.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", []string{"CustomResourceDefinition"}, KubeVersion{1, 16}, KubeVersion{1, 22}, "apiextensions.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}, KubeVersion{1, 16}, KubeVersion{1, 22}, "admissionregistration.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", []string{"APIService"}, KubeVersion{1, 19}, KubeVersion{1, 22}, "apiregistration.k8s.io/v1"},
+	{"authentication.k8s.io/v1beta1", []string{"TokenReview"}, KubeVersion{1, 19}, KubeVersion{1, 22}, "authentication.k8s.io/v1"},
+	{"authorization.k8s.io/v1beta1", []string{"LocalSubjectAccessReview", "SelfSubjectAccessReview", "SubjectAccessReview"}, KubeVersion{1, 19}, KubeVersion{1, 22}, "authorization.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", []string{"CertificateSigningRequest"}, KubeVersion{1, 19}, KubeVersion{1, 22}, "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", []string{"Lease"}, KubeVersion{1, 19}, KubeVersion{1, 22}, "coordination.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", []string{"PriorityClass"}, KubeVersion{1, 14}, KubeVersion{1, 22}, "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, KubeVersion{1, 19}, KubeVersion{1, 22}, "storage.k8s.io/v1"},
+	{"batch/v1beta1", []string{"CronJob"}, KubeVersion{1, 21}, KubeVersion{1, 25}, "batch/v1"},
+	{"discovery.k8s.io/v1beta1", []string{"EndpointSlice"}, KubeVersion{1, 21}, KubeVersion{1, 25}, "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", []string{"Event"}, KubeVersion{1, 19}, KubeVersion{1, 25}, "events.k8s.io/v1"},
+	{"autoscaling/v2beta1", []string{"HorizontalPodAutoscaler"}, KubeVersion{1, 22}, KubeVersion{1, 25}, "autoscaling/v2"},
+	{"policy/v1beta1", []string{"PodDisruptionBudget"}, KubeVersion{1, 21}, KubeVersion{1, 25}, "policy/v1"},
+	{"policy/v1beta1", []string{"PodSecurityPolicy"}, KubeVersion{1, 21}, KubeVersion{1, 25}, ""},
+	{"node.k8s.io/v1beta1", []string{"RuntimeClass"}, KubeVersion{1, 20}, KubeVersion{1, 25}, "node.k8s.io/v1"},
+	{"autoscaling/v2beta2", []string{"HorizontalPodAutoscaler"}, KubeVersion{1, 23}, KubeVersion{1, 26}, "autoscaling/v2"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", []string{"FlowSchema", "PriorityLevelConfiguration"}, KubeVersion{1, 23}, KubeVersion{1, 26}, "flowcontrol.apiserver.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", []string{"CSIStorageCapacity"}, KubeVersion{1, 24}, KubeVersion{1, 27}, "storage.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", []string{"FlowSchema", "PriorityLevelConfiguration"}, KubeVersion{1, 26}, KubeVersion{1, 29}, "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", []string{"FlowSchema", "PriorityLevelConfiguration"}, KubeVersion{1, 29}, KubeVersion{1, 32}, "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// DeprecatedAPI reports a resource using a deprecated or removed API.
+type DeprecatedAPI struct {
+	Resource
+	// State is APIDeprecated if the API still works in the target version
+	// and APIRemoved if it is no longer served.
+	State        string `json:"state"`
+	DeprecatedIn string `json:"deprecatedIn"`
+	RemovedIn    string `json:"removedIn"`
+	// Replacement is the API version to migrate to, if there is one.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// FindDeprecatedAPIs returns the resources using APIs that are deprecated or
+// removed in the Kubernetes version target.
+func FindDeprecatedAPIs(resources []Resource, target KubeVersion) []DeprecatedAPI {
+	found := []DeprecatedAPI{}
+	for _, r := range resources {
+		for _, d := range apiDeprecations {
+			if d.apiVersion != r.APIVersion || !slices.Contains(d.kinds, r.Kind) || target.less(d.deprecatedIn) {
+				continue
+			}
+			state := APIDeprecated
+			if !target.less(d.removedIn) {
+				state = APIRemoved
+			}
+			found = append(found, DeprecatedAPI{
+				Resource:     r,
+				State:        state,
+				DeprecatedIn: d.deprecatedIn.String(),
+				RemovedIn:    d.removedIn.String(),
+				Replacement:  d.replacement,
+			})
+			break
+		}
+	}
+	return found
+}
+
+// KubeVersion is a Kubernetes minor version.
+type KubeVersion struct {
+	major, minor int
+}
+
+// ParseKubeVersion parses versions such as "1.25", "v1.25.3" or "v1.27.4-eks-2d98532".
+func ParseKubeVersion(s string) (KubeVersion, error) {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".", 3)
+	if len(parts) < 2 {
+		return KubeVersion{}, fmt.Errorf("invalid Kubernetes version %q: expected a version such as 1.25", s)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return KubeVersion{}, fmt.Errorf("invalid Kubernetes version %q: expected a version such as 1.25", s)
+	}
+	// Minor versions of managed clusters may carry a suffix, e.g. "27+".
+	minor, err := strconv.Atoi(strings.TrimRight(parts[1], "+"))
+	if err != nil {
+		return KubeVersion{}, fmt.Errorf("invalid Kubernetes version %q: expected a version such as 1.25", s)
+	}
+	return KubeVersion{major, minor}, nil
+}
+
+func (v KubeVersion) less(o KubeVersion) bool {
+	return v.major < o.major || v.major == o.major && v.minor < o.minor
+}
+
+func (v KubeVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
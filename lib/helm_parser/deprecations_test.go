@@ -0,0 +1,63 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindDeprecatedAPIs(t *testing.T) {
+	resources := []Resource{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+		{APIVersion: "batch/v1beta1", Kind: "CronJob", Name: "backup"},
+		{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", Name: "web", Namespace: "apps"},
+		{APIVersion: "autoscaling/v2beta2", Kind: "HorizontalPodAutoscaler", Name: "web"},
+	}
+
+	tests := []struct {
+		target string
+		want   []DeprecatedAPI
+	}{
+		{"1.20", []DeprecatedAPI{}},
+		{"v1.24.3", []DeprecatedAPI{
+			{Resource: resources[1], State: APIDeprecated, DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "batch/v1"},
+			{Resource: resources[2], State: APIDeprecated, DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "policy/v1"},
+			{Resource: resources[3], State: APIDeprecated, DeprecatedIn: "1.23", RemovedIn: "1.26", Replacement: "autoscaling/v2"},
+		}},
+		{"1.25", []DeprecatedAPI{
+			{Resource: resources[1], State: APIRemoved, DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "batch/v1"},
+			{Resource: resources[2], State: APIRemoved, DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "policy/v1"},
+			{Resource: resources[3], State: APIDeprecated, DeprecatedIn: "1.23", RemovedIn: "1.26", Replacement: "autoscaling/v2"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			target, err := ParseKubeVersion(tt.target)
+			if err != nil {
+				t.Fatalf("ParseKubeVersion: %v", err)
+			}
+			if got := FindDeprecatedAPIs(resources, target); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKubeVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    KubeVersion
+		wantErr bool
+	}{
+		{"1.25", KubeVersion{1, 25}, false},
+		{"v1.27.4-eks-2d98532", KubeVersion{1, 27}, false},
+		{"1.28+", KubeVersion{1, 28}, false},
+		{"latest", KubeVersion{}, true},
+		{"1", KubeVersion{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseKubeVersion(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseKubeVersion(%q) = %v, %v; want %v, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package helm_parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Kinds of change reported by DiffManifests.
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// diffContext is the number of unchanged lines shown around changes.
+const diffContext = 3
+
+// maxDiffCells bounds the work of the line diff of a single resource. Larger
+// resources are shown as entirely replaced.
+const maxDiffCells = 4_000_000
+
+// ResourceChange describes how a single Kubernetes resource differs between
+// two manifests.
+type ResourceChange struct {
+	// Resource identifies the resource as kind/namespace/name, or kind/name
+	// for resources without a namespace in the manifest.
+	Resource string `json:"resource"`
+	Change   string `json:"change"`
+	// Diff is a unified diff of the resource from the old to the new manifest.
+	Diff string `json:"diff"`
+}
+
+// ManifestDiff is the result of comparing two multi-document manifests.
+type ManifestDiff struct {
+	Changes   []ResourceChange `json:"changes"`
+	Unchanged int              `json:"unchanged"`
+}
+
+// DiffManifests compares two multi-document YAML manifests, such as the
+// manifest of a deployed release and a new rendering of its chart, resource
+// by resource. Resources are matched by kind, namespace and name.
+func DiffManifests(oldManifest, newManifest string) (*ManifestDiff, error) {
+	oldResources, err := splitResources(oldManifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse old manifest: %v", err)
+	}
+	newResources, err := splitResources(newManifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse new manifest: %v", err)
+	}
+
+	keys := make(map[string]struct{}, len(oldResources)+len(newResources))
+	for k := range oldResources {
+		keys[k] = struct{}{}
+	}
+	for k := range newResources {
+		keys[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	result := &ManifestDiff{Changes: []ResourceChange{}}
+	for _, k := range sorted {
+		before, inOld := oldResources[k]
+		after, inNew := newResources[k]
+
+		change := ChangeModified
+		switch {
+		case !inOld:
+			change = ChangeAdded
+		case !inNew:
+			change = ChangeRemoved
+		case before == after:
+			result.Unchanged++
+			continue
+		}
+		result.Changes = append(result.Changes, ResourceChange{
+			Resource: k,
+			Change:   change,
+			Diff:     unifiedDiff(k, before, after),
+		})
+	}
+	return result, nil
+}
+
+// splitResources splits a manifest into its documents, keyed by resource.
+// Comments such as Helm's "# Source:" lines are dropped from the documents.
+func splitResources(manifest string) (map[string]string, error) {
+	resources := make(map[string]string)
+	for _, doc := range strings.Split(manifest, "\n---") {
+		doc = strings.TrimPrefix(doc, "---")
+		doc = stripComments(doc)
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+
+		var meta struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name      string `yaml:"name"`
+				Namespace string `yaml:"namespace"`
+			} `yaml:"metadata"`
+		}
+		if err := yaml.Unmarshal([]byte(doc), &meta); err != nil {
+			return nil, err
+		}
+
+		key := meta.Kind + "/" + meta.Metadata.Name
+		if meta.Metadata.Namespace != "" {
+			key = meta.Kind + "/" + meta.Metadata.Namespace + "/" + meta.Metadata.Name
+		}
+		resources[key] = strings.Trim(doc, "\n") + "\n"
+	}
+	return resources, nil
+}
+
+func stripComments(doc string) string {
+	lines := strings.Split(doc, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// unifiedDiff returns a unified diff between two texts, with hunks of
+// diffContext unchanged lines around every change.
+func unifiedDiff(name, before, after string) string {
+	a := splitLines(before)
+	b := splitLines(after)
+	ops := diffLines(a, b)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", name, name)
+
+	for start := 0; start < len(ops); {
+		// Find the next change.
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*diffContext lines.
+		end := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+
+		from := max(first-diffContext, start)
+		to := min(end+diffContext, len(ops))
+
+		aStart, bStart := ops[from].aLine, ops[from].bLine
+		var aCount, bCount int
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		for _, op := range ops[from:to] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+		}
+		start = to
+	}
+	return sb.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprint(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffOp is a line of a diff: ' ' for unchanged, '-' for removed and '+' for
+// added lines. aLine and bLine are the 0-based positions in the old and new
+// text at which the line occurs or would be inserted.
+type diffOp struct {
+	kind  byte
+	text  string
+	aLine int
+	bLine int
+}
+
+// diffLines computes a line diff from the longest common subsequence of a and
+// b.
+func diffLines(a, b []string) []diffOp {
+	if len(a)*len(b) > maxDiffCells {
+		ops := make([]diffOp, 0, len(a)+len(b))
+		for i, line := range a {
+			ops = append(ops, diffOp{kind: '-', text: line, aLine: i})
+		}
+		for j, line := range b {
+			ops = append(ops, diffOp{kind: '+', text: line, aLine: len(a), bLine: j})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', text: a[i], aLine: i, bLine: j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: '-', text: a[i], aLine: i, bLine: j})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', text: b[j], aLine: i, bLine: j})
+			j++
+		}
+	}
+	return ops
+}
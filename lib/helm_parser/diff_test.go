@@ -0,0 +1,154 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffManifests(t *testing.T) {
+	oldManifest := `---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: apps
+spec:
+  ports:
+  - port: 80
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: apps
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.25
+---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: legacy
+`
+	newManifest := `---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: apps
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.27
+---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: apps
+spec:
+  ports:
+  - port: 80
+---
+# Source: app/templates/serviceaccount.yaml
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: web
+`
+
+	got, err := DiffManifests(oldManifest, newManifest)
+	if err != nil {
+		t.Fatalf("DiffManifests() error = %v", err)
+	}
+	if got.Unchanged != 1 {
+		t.Errorf("Unchanged = %d, want 1", got.Unchanged)
+	}
+
+	want := []ResourceChange{
+		{
+			Resource: "ConfigMap/legacy",
+			Change:   ChangeRemoved,
+			Diff: `--- ConfigMap/legacy
++++ ConfigMap/legacy
+@@ -1,4 +0,0 @@
+-apiVersion: v1
+-kind: ConfigMap
+-metadata:
+-  name: legacy
+`,
+		},
+		{
+			Resource: "Deployment/apps/web",
+			Change:   ChangeModified,
+			Diff: `--- Deployment/apps/web
++++ Deployment/apps/web
+@@ -9,4 +9,4 @@
+     spec:
+       containers:
+       - name: web
+-        image: nginx:1.25
++        image: nginx:1.27
+`,
+		},
+		{
+			Resource: "ServiceAccount/web",
+			Change:   ChangeAdded,
+			Diff: `--- ServiceAccount/web
++++ ServiceAccount/web
+@@ -0,0 +1,4 @@
++apiVersion: v1
++kind: ServiceAccount
++metadata:
++  name: web
+`,
+		},
+	}
+	if !reflect.DeepEqual(got.Changes, want) {
+		t.Fatalf("Changes = %#v, want %#v", got.Changes, want)
+	}
+}
+
+func TestDiffManifestsInvalid(t *testing.T) {
+	if _, err := DiffManifests("kind: [", ""); err == nil {
+		t.Fatal("expected an error for an invalid manifest")
+	}
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n"
+	after := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nM\nn\n"
+
+	want := `--- x
++++ x
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -10,5 +10,5 @@
+ j
+ k
+ l
+-m
++M
+ n
+`
+	if got := unifiedDiff("x", before, after); got != want {
+		t.Fatalf("unifiedDiff() =\n%s\nwant\n%s", got, want)
+	}
+}
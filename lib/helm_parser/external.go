@@ -0,0 +1,371 @@
+package helm_parser
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Categories of the external services FindExternalDependencies reports.
+const (
+	DependencyDatabase      = "database"
+	DependencyCache         = "cache"
+	DependencyMessageQueue  = "message-queue"
+	DependencyObjectStorage = "object-storage"
+	DependencySMTP          = "smtp"
+	DependencyDNS           = "dns"
+	// DependencyService is an external service of no known category, e.g. the
+	// target of an ExternalName Service.
+	DependencyService = "service"
+)
+
+// dependencyKeywords are the words in values paths and variable names
+// identifying the category of an endpoint, checked in order.
+var dependencyKeywords = []struct {
+	category string
+	words    []string
+}{
+	{DependencySMTP, []string{"smtp", "mail"}},
+	{DependencyObjectStorage, []string{"bucket", "s3", "minio", "gcs", "blob"}},
+	{DependencyCache, []string{"redis", "memcache", "valkey"}},
+	{DependencyMessageQueue, []string{"kafka", "rabbitmq", "amqp", "nats", "broker"}},
+	{DependencyDatabase, []string{"database", "postgres", "mysql", "mariadb", "mongo", "db", "sql"}},
+}
+
+// dependencySchemes are the URL schemes identifying the category of an
+// endpoint.
+var dependencySchemes = map[string]string{
+	"postgres":    DependencyDatabase,
+	"postgresql":  DependencyDatabase,
+	"mysql":       DependencyDatabase,
+	"mariadb":     DependencyDatabase,
+	"mongodb":     DependencyDatabase,
+	"mongodb+srv": DependencyDatabase,
+	"sqlserver":   DependencyDatabase,
+	"redis":       DependencyCache,
+	"rediss":      DependencyCache,
+	"memcached":   DependencyCache,
+	"amqp":        DependencyMessageQueue,
+	"amqps":       DependencyMessageQueue,
+	"kafka":       DependencyMessageQueue,
+	"nats":        DependencyMessageQueue,
+	"s3":          DependencyObjectStorage,
+	"gs":          DependencyObjectStorage,
+	"smtp":        DependencySMTP,
+	"smtps":       DependencySMTP,
+}
+
+// endpointKeys are the words in the last element of a value path or variable
+// name marking it as an endpoint.
+var endpointKeys = []string{"host", "server", "address", "endpoint", "url", "uri", "dsn", "brokers", "bucket"}
+
+// externalDNSAnnotations are the annotations of external-dns holding host
+// names it creates records for.
+var externalDNSAnnotations = []string{
+	"external-dns.alpha.kubernetes.io/hostname",
+	"external-dns.alpha.kubernetes.io/internal-hostname",
+	"external-dns.alpha.kubernetes.io/target",
+}
+
+// ExternalDependency is an external service a chart expects.
+type ExternalDependency struct {
+	Category string `json:"category"`
+	// Endpoint is the host, URL or bucket name, with passwords redacted.
+	Endpoint string `json:"endpoint"`
+	// Location is where the endpoint was found: a values path, or a
+	// resource as kind/name with the variable, annotation or key.
+	Location string `json:"location"`
+}
+
+// DependencyReport is the result of FindExternalDependencies.
+type DependencyReport struct {
+	// Categories are the categories of the dependencies, sorted.
+	Categories   []string             `json:"categories"`
+	Dependencies []ExternalDependency `json:"dependencies"`
+}
+
+type externalContainer struct {
+	Env []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"env"`
+}
+
+type externalPodSpec struct {
+	Containers     []externalContainer `json:"containers"`
+	InitContainers []externalContainer `json:"initContainers"`
+}
+
+// externalResource holds the fields FindExternalDependencies looks at. The
+// pod spec is embedded for Pods.
+type externalResource struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name        string            `json:"name"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		externalPodSpec
+		Type         string `json:"type"`
+		ExternalName string `json:"externalName"`
+		Template     struct {
+			Spec externalPodSpec `json:"spec"`
+		} `json:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template struct {
+					Spec externalPodSpec `json:"spec"`
+				} `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+	} `json:"spec"`
+	// ConfigMaps.
+	Data map[string]string `json:"data"`
+}
+
+// podSpec returns the pod spec of a workload, false for other resources.
+func (r externalResource) podSpec() (externalPodSpec, bool) {
+	switch r.Kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		return r.Spec.Template.Spec, true
+	case "CronJob":
+		return r.Spec.JobTemplate.Spec.Template.Spec, true
+	case "Pod":
+		return r.Spec.externalPodSpec, true
+	}
+	return externalPodSpec{}, false
+}
+
+// FindExternalDependencies reports the services outside the cluster a chart
+// expects, found in its values and in a multi-document YAML manifest of the
+// chart rendered with them: database, cache, message queue, object storage
+// and SMTP endpoints in values, container environment variables and
+// ConfigMaps, targets of ExternalName Services and host names managed by
+// external-dns. Endpoints are recognized from URL schemes, or from names
+// like externalDatabase.host or SMTP_SERVER; endpoints inside the cluster
+// and template expressions are ignored. Secrets are not inspected.
+func FindExternalDependencies(manifest string, values map[string]any) (*DependencyReport, error) {
+	resources, err := decodeManifest[externalResource](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DependencyReport{Categories: []string{}, Dependencies: []ExternalDependency{}}
+	add := func(category, endpoint, location string) {
+		dep := ExternalDependency{Category: category, Endpoint: endpoint, Location: location}
+		if !slices.Contains(report.Dependencies, dep) {
+			report.Dependencies = append(report.Dependencies, dep)
+		}
+	}
+	check := func(name, value, location string) {
+		if category, endpoint, ok := classifyEndpoint(name, value); ok {
+			add(category, endpoint, location)
+		}
+	}
+
+	walkValues(values, "", func(path, value string) {
+		check(path, value, "values: "+path)
+	})
+
+	for _, r := range resources {
+		resource := r.Kind + "/" + r.Metadata.Name
+		for _, annotation := range externalDNSAnnotations {
+			for _, host := range strings.Split(r.Metadata.Annotations[annotation], ",") {
+				if host = strings.TrimSpace(host); host != "" {
+					add(DependencyDNS, host, resource+" annotation "+annotation)
+				}
+			}
+		}
+
+		switch r.Kind {
+		case "Service":
+			if r.Spec.Type == "ExternalName" && r.Spec.ExternalName != "" && !inCluster(r.Spec.ExternalName) {
+				category := categoryOf(r.Metadata.Name)
+				if category == "" {
+					category = DependencyService
+				}
+				add(category, r.Spec.ExternalName, resource+" externalName")
+			}
+		case "ConfigMap":
+			for _, k := range slices.Sorted(maps.Keys(r.Data)) {
+				check(k, r.Data[k], resource+" key "+k)
+			}
+		}
+
+		if spec, ok := r.podSpec(); ok {
+			for _, c := range append(spec.InitContainers, spec.Containers...) {
+				for _, e := range c.Env {
+					check(e.Name, e.Value, resource+" env "+e.Name)
+				}
+			}
+		}
+	}
+
+	for _, dep := range report.Dependencies {
+		report.Categories = append(report.Categories, dep.Category)
+	}
+	slices.Sort(report.Categories)
+	report.Categories = slices.Compact(report.Categories)
+	return report, nil
+}
+
+// walkValues calls fn with the path and value of every string in values, in
+// the order of the sorted keys.
+func walkValues(values map[string]any, prefix string, fn func(path, value string)) {
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		walkValue(values[k], path, fn)
+	}
+}
+
+func walkValue(value any, path string, fn func(path, value string)) {
+	switch v := value.(type) {
+	case map[string]any:
+		walkValues(v, path, fn)
+	case []any:
+		for i, item := range v {
+			walkValue(item, fmt.Sprintf("%s[%d]", path, i), fn)
+		}
+	case string:
+		fn(path, v)
+	}
+}
+
+// classifyEndpoint returns the category and endpoint of a value named name,
+// a values path or variable name, and false if it is not an external
+// endpoint.
+func classifyEndpoint(name, value string) (string, string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.ContainsAny(value, " \n{}$") {
+		return "", "", false
+	}
+
+	if u, err := url.Parse(value); err == nil && u.Host != "" {
+		if inCluster(u.Hostname()) {
+			return "", "", false
+		}
+		if category, ok := dependencySchemes[strings.ToLower(u.Scheme)]; ok {
+			return category, u.Redacted(), true
+		}
+		if !isEndpointName(name) {
+			return "", "", false
+		}
+		if category := categoryOf(name); category != "" {
+			return category, u.Redacted(), true
+		}
+		return "", "", false
+	}
+
+	if !isEndpointName(name) {
+		return "", "", false
+	}
+	category := categoryOf(name)
+	if category == "" {
+		return "", "", false
+	}
+	if strings.Contains(lastElement(name), "bucket") {
+		return DependencyObjectStorage, value, true
+	}
+	// Hosts are host names or addresses, possibly with a port or a list of
+	// them like Kafka brokers.
+	for _, host := range strings.Split(value, ",") {
+		h := strings.TrimSpace(host)
+		if hostname, _, err := net.SplitHostPort(h); err == nil {
+			h = hostname
+		}
+		if !strings.Contains(h, ".") && net.ParseIP(h) == nil || inCluster(h) {
+			return "", "", false
+		}
+	}
+	return category, value, true
+}
+
+// categoryOf returns the category of the first keyword starting or ending
+// a word of name, or an empty string.
+func categoryOf(name string) string {
+	words := splitWords(name)
+	for _, k := range dependencyKeywords {
+		for _, keyword := range k.words {
+			for _, word := range words {
+				if strings.HasPrefix(word, keyword) || strings.HasSuffix(word, keyword) {
+					return k.category
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// splitWords splits a values path or variable name into lower-case words at
+// punctuation and camel case, e.g. external, database and host for
+// externalDatabase.host.
+func splitWords(name string) []string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, strings.ToLower(word.String()))
+			word.Reset()
+		}
+	}
+	prevLower := false
+	for _, r := range name {
+		switch {
+		case unicode.IsUpper(r):
+			if prevLower {
+				flush()
+			}
+			word.WriteRune(r)
+			prevLower = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+			prevLower = true
+		default:
+			flush()
+			prevLower = false
+		}
+	}
+	flush()
+	return words
+}
+
+func isEndpointName(name string) bool {
+	last := lastElement(name)
+	for _, key := range endpointKeys {
+		if strings.Contains(last, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastElement returns the last element of a values path or variable name,
+// lower-cased, e.g. host for externalDatabase.host and DB_HOST.
+func lastElement(name string) string {
+	name = strings.ToLower(name)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, "_"); i >= 0 && !strings.HasPrefix(name, "_") {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// inCluster reports whether host is served from within the cluster or the
+// pod.
+func inCluster(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".svc") || strings.HasSuffix(host, ".cluster.local") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
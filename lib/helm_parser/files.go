@@ -0,0 +1,104 @@
+package helm_parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v4/pkg/chart/common"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// ChartFile describes a file of a chart without its contents.
+type ChartFile struct {
+	// Path is relative to the chart root. Files of subcharts are listed below
+	// charts/<subchart name>/.
+	Path string `json:"path"`
+	Size int    `json:"size"`
+	// Subchart is the name of the subchart the file belongs to, empty for
+	// files of the chart itself.
+	Subchart string `json:"subchart,omitempty"`
+}
+
+// chartFiles returns all files of a chart, excluding its charts/ directory.
+// Raw holds every file of a loaded chart; Templates and Files are merged in for
+// charts that were built in memory.
+func chartFiles(c *chartv2.Chart) []*common.File {
+	seen := make(map[string]bool)
+	var files []*common.File
+	for _, group := range [][]*common.File{c.Raw, c.Templates, c.Files} {
+		for _, f := range group {
+			if seen[f.Name] || strings.HasPrefix(f.Name, "charts/") {
+				continue
+			}
+			seen[f.Name] = true
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// ListChartFiles returns the file tree of a chart, including the files of its
+// subcharts, sorted by path.
+func ListChartFiles(c *chartv2.Chart) []ChartFile {
+	files := listChartFiles(c, "", "")
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+func listChartFiles(c *chartv2.Chart, prefix, subchart string) []ChartFile {
+	var files []ChartFile
+	for _, f := range chartFiles(c) {
+		files = append(files, ChartFile{Path: prefix + f.Name, Size: len(f.Data), Subchart: subchart})
+	}
+	for _, dep := range c.Dependencies() {
+		files = append(files, listChartFiles(dep, prefix+"charts/"+dep.Name()+"/", dep.Name())...)
+	}
+	return files
+}
+
+// FilterChartFiles returns the files selected by filter, in the way
+// GetChartContents selects them. Files of subcharts are only returned if
+// recursive is set.
+func FilterChartFiles(files []ChartFile, recursive bool, filter ContentFilter) []ChartFile {
+	var selected []ChartFile
+	for _, f := range files {
+		name := f.Path
+		if f.Subchart != "" {
+			if !recursive {
+				continue
+			}
+			// The filter applies to the path relative to the subchart root.
+			prefix := "charts/" + f.Subchart + "/"
+			name = name[strings.LastIndex(name, prefix)+len(prefix):]
+		}
+		if filter.match(name) {
+			selected = append(selected, f)
+		}
+	}
+	return selected
+}
+
+// GetChartFile returns the content of a single chart file by its path as
+// reported by ListChartFiles.
+func GetChartFile(c *chartv2.Chart, path string) ([]byte, error) {
+	path = strings.TrimPrefix(path, "/")
+
+	for _, f := range chartFiles(c) {
+		if f.Name == path {
+			return f.Data, nil
+		}
+	}
+
+	if rest, ok := strings.CutPrefix(path, "charts/"); ok {
+		if name, subPath, ok := strings.Cut(rest, "/"); ok {
+			for _, dep := range c.Dependencies() {
+				if dep.Name() == name {
+					return GetChartFile(dep, subPath)
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("file %s not found in chart %s", path, c.Name())
+}
@@ -0,0 +1,112 @@
+package helm_parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListChartFiles(t *testing.T) {
+	mockChart := createMockChart()
+	mockChart.AddDependency(createMockSubchart())
+
+	files := ListChartFiles(mockChart)
+
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	wantPaths := []string{
+		"Chart.yaml",
+		"charts/subchart/Chart.yaml",
+		"charts/subchart/values.yaml",
+		"templates/deployment.yaml",
+		"values.yaml",
+	}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Fatalf("ListChartFiles() paths = %v, want %v", paths, wantPaths)
+	}
+
+	for _, f := range files {
+		if f.Size == 0 {
+			t.Errorf("file %s has zero size", f.Path)
+		}
+		wantSubchart := ""
+		if f.Path == "charts/subchart/Chart.yaml" || f.Path == "charts/subchart/values.yaml" {
+			wantSubchart = "subchart"
+		}
+		if f.Subchart != wantSubchart {
+			t.Errorf("file %s subchart = %q, want %q", f.Path, f.Subchart, wantSubchart)
+		}
+	}
+}
+
+func TestFilterChartFiles(t *testing.T) {
+	files := []ChartFile{
+		{Path: "Chart.yaml"},
+		{Path: "charts/subchart/Chart.yaml", Subchart: "subchart"},
+		{Path: "charts/subchart/templates/service.yaml", Subchart: "subchart"},
+		{Path: "templates/deployment.yaml"},
+		{Path: "values.yaml"},
+	}
+
+	tests := []struct {
+		name      string
+		recursive bool
+		filter    ContentFilter
+		want      []string
+	}{
+		{name: "all", filter: ContentAll, want: []string{"Chart.yaml", "templates/deployment.yaml", "values.yaml"}},
+		{name: "templates", filter: ContentTemplates, want: []string{"templates/deployment.yaml"}},
+		{name: "recursive templates", recursive: true, filter: ContentTemplates, want: []string{"charts/subchart/templates/service.yaml", "templates/deployment.yaml"}},
+		{name: "recursive non-templates", recursive: true, filter: ContentNonTemplates, want: []string{"Chart.yaml", "charts/subchart/Chart.yaml", "values.yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			for _, f := range FilterChartFiles(files, tt.recursive, tt.filter) {
+				paths = append(paths, f.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("FilterChartFiles() = %v, want %v", paths, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetChartFile(t *testing.T) {
+	mockChart := createMockChart()
+	mockChart.AddDependency(createMockSubchart())
+
+	tests := []struct {
+		name         string
+		path         string
+		wantContains string
+		wantError    bool
+	}{
+		{name: "chart file", path: "templates/deployment.yaml", wantContains: "kind: Deployment"},
+		{name: "leading slash", path: "/values.yaml", wantContains: "replicaCount"},
+		{name: "subchart file", path: "charts/subchart/values.yaml", wantContains: "subchartValue"},
+		{name: "missing file", path: "templates/missing.yaml", wantError: true},
+		{name: "missing subchart", path: "charts/other/values.yaml", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := GetChartFile(mockChart, tt.path)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetChartFile() error = %v", err)
+			}
+			if !strings.Contains(string(data), tt.wantContains) {
+				t.Errorf("GetChartFile() = %q, want it to contain %q", data, tt.wantContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package helm_parser
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// HoursPerMonth is the average number of hours in a month used to turn
+// hourly prices into monthly costs.
+const HoursPerMonth = 730
+
+// PriceTable holds the prices EstimateFootprint charges for requested
+// resources. The currency is that of the prices.
+type PriceTable struct {
+	// CPUHour is the price of one vCPU for an hour.
+	CPUHour float64 `json:"cpuHour"`
+	// MemoryGBHour is the price of one GiB of memory for an hour.
+	MemoryGBHour float64 `json:"memoryGBHour"`
+}
+
+// FootprintOptions configures EstimateFootprint.
+type FootprintOptions struct {
+	Prices PriceTable
+	// Replicas overrides the replica counts of workloads, keyed by kind/name
+	// or by name, e.g. for counts set by an autoscaler at runtime.
+	Replicas map[string]int
+	// DaemonSetNodes is the number of nodes DaemonSets run on, 1 if unset.
+	DaemonSetNodes int
+}
+
+// WorkloadFootprint is the resource footprint of a workload.
+type WorkloadFootprint struct {
+	Resource
+	Replicas int `json:"replicas"`
+	// MaxReplicas is the maximum replica count of a
+	// HorizontalPodAutoscaler scaling the workload, which then runs with its
+	// minimum replica count.
+	MaxReplicas int `json:"maxReplicas,omitempty"`
+	// PodCPU and PodMemoryGB are the vCPUs and GiB of memory requested by a
+	// pod, including init containers like the scheduler does.
+	PodCPU      float64 `json:"podCPU"`
+	PodMemoryGB float64 `json:"podMemoryGB"`
+	CPU         float64 `json:"cpu"`
+	MemoryGB    float64 `json:"memoryGB"`
+	MonthlyCost float64 `json:"monthlyCost"`
+	// MissingRequests lists the resources containers request neither
+	// explicitly nor through a limit, as container/resource. They are not
+	// counted, so the estimate is a lower bound.
+	MissingRequests []string `json:"missingRequests,omitempty"`
+	// Intermittent marks Jobs and CronJobs, whose pods only run for a while.
+	// They are not counted in the totals.
+	Intermittent bool `json:"intermittent,omitempty"`
+}
+
+// Footprint is the result of EstimateFootprint.
+type Footprint struct {
+	Prices      PriceTable `json:"prices"`
+	CPU         float64    `json:"cpu"`
+	MemoryGB    float64    `json:"memoryGB"`
+	MonthlyCost float64    `json:"monthlyCost"`
+	// MaxMonthlyCost is the monthly cost with autoscaled workloads at their
+	// maximum replica count, set if there are any.
+	MaxMonthlyCost float64             `json:"maxMonthlyCost,omitempty"`
+	Workloads      []WorkloadFootprint `json:"workloads"`
+}
+
+type footprintContainer struct {
+	Name          string `json:"name"`
+	RestartPolicy string `json:"restartPolicy"`
+	Resources     struct {
+		Limits   map[string]resource.Quantity `json:"limits"`
+		Requests map[string]resource.Quantity `json:"requests"`
+	} `json:"resources"`
+}
+
+type footprintPodSpec struct {
+	Containers     []footprintContainer `json:"containers"`
+	InitContainers []footprintContainer `json:"initContainers"`
+}
+
+// footprintResource holds the fields of the workloads and
+// HorizontalPodAutoscalers EstimateFootprint looks at. The pod spec is
+// embedded for Pods.
+type footprintResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		footprintPodSpec
+		Replicas    *int `json:"replicas"`
+		Parallelism *int `json:"parallelism"`
+		Template    struct {
+			Spec footprintPodSpec `json:"spec"`
+		} `json:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template struct {
+					Spec footprintPodSpec `json:"spec"`
+				} `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+		ScaleTargetRef struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"scaleTargetRef"`
+		MinReplicas *int `json:"minReplicas"`
+		MaxReplicas int  `json:"maxReplicas"`
+	} `json:"spec"`
+}
+
+// EstimateFootprint sums the CPU and memory requested by the workloads of a
+// multi-document YAML manifest, such as a rendered chart, and estimates the
+// monthly cost of running them with the prices of opts. Workloads run their
+// replica count, the minimum of a HorizontalPodAutoscaler in the manifest
+// targeting them, or the count overridden in opts.
+func EstimateFootprint(manifest string, opts FootprintOptions) (*Footprint, error) {
+	resources, err := decodeManifest[footprintResource](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := opts.DaemonSetNodes
+	if nodes <= 0 {
+		nodes = 1
+	}
+	perMonth := func(cpu, memory float64) float64 {
+		return (cpu*opts.Prices.CPUHour + memory*opts.Prices.MemoryGBHour) * HoursPerMonth
+	}
+
+	footprint := &Footprint{Prices: opts.Prices, Workloads: []WorkloadFootprint{}}
+	autoscaled := false
+	for _, r := range resources {
+		var spec footprintPodSpec
+		replicas := 1
+		switch r.Kind {
+		case "Deployment", "StatefulSet", "ReplicaSet":
+			spec = r.Spec.Template.Spec
+			if r.Spec.Replicas != nil {
+				replicas = *r.Spec.Replicas
+			}
+		case "DaemonSet":
+			spec = r.Spec.Template.Spec
+			replicas = nodes
+		case "Job":
+			spec = r.Spec.Template.Spec
+			if r.Spec.Parallelism != nil {
+				replicas = *r.Spec.Parallelism
+			}
+		case "CronJob":
+			spec = r.Spec.JobTemplate.Spec.Template.Spec
+		case "Pod":
+			spec = r.Spec.footprintPodSpec
+		default:
+			continue
+		}
+
+		w := WorkloadFootprint{
+			Resource:     Resource{APIVersion: r.APIVersion, Kind: r.Kind, Name: r.Metadata.Name, Namespace: r.Metadata.Namespace},
+			Replicas:     replicas,
+			Intermittent: r.Kind == "Job" || r.Kind == "CronJob",
+		}
+		if hpa, ok := findAutoscaler(resources, r); ok {
+			w.Replicas = 1
+			if hpa.Spec.MinReplicas != nil {
+				w.Replicas = *hpa.Spec.MinReplicas
+			}
+			w.MaxReplicas = hpa.Spec.MaxReplicas
+		}
+		if n, ok := opts.Replicas[r.Kind+"/"+r.Metadata.Name]; ok {
+			w.Replicas, w.MaxReplicas = n, 0
+		} else if n, ok := opts.Replicas[r.Metadata.Name]; ok {
+			w.Replicas, w.MaxReplicas = n, 0
+		}
+
+		var cpu, memory resource.Quantity
+		cpu, memory, w.MissingRequests = podRequests(spec)
+		w.PodCPU = cpu.AsApproximateFloat64()
+		w.PodMemoryGB = memory.AsApproximateFloat64() / (1 << 30)
+		w.CPU = w.PodCPU * float64(w.Replicas)
+		w.MemoryGB = w.PodMemoryGB * float64(w.Replicas)
+		w.MonthlyCost = perMonth(w.CPU, w.MemoryGB)
+
+		if !w.Intermittent {
+			footprint.CPU += w.CPU
+			footprint.MemoryGB += w.MemoryGB
+			footprint.MonthlyCost += w.MonthlyCost
+			if w.MaxReplicas > w.Replicas {
+				autoscaled = true
+				footprint.MaxMonthlyCost += perMonth(w.PodCPU, w.PodMemoryGB) * float64(w.MaxReplicas)
+			} else {
+				footprint.MaxMonthlyCost += w.MonthlyCost
+			}
+		}
+		footprint.Workloads = append(footprint.Workloads, w)
+	}
+	if !autoscaled {
+		footprint.MaxMonthlyCost = 0
+	}
+	return footprint, nil
+}
+
+// findAutoscaler returns the HorizontalPodAutoscaler in resources scaling the
+// workload r.
+func findAutoscaler(resources []footprintResource, r footprintResource) (footprintResource, bool) {
+	for _, hpa := range resources {
+		if hpa.Kind == "HorizontalPodAutoscaler" && hpa.Metadata.Namespace == r.Metadata.Namespace &&
+			hpa.Spec.ScaleTargetRef.Kind == r.Kind && hpa.Spec.ScaleTargetRef.Name == r.Metadata.Name {
+			return hpa, true
+		}
+	}
+	return footprintResource{}, false
+}
+
+// podRequests returns the CPU and memory requested by a pod like the
+// scheduler computes them: the sum of the containers and sidecars, or the
+// largest init container if that is more. Containers without a request
+// request their limit.
+func podRequests(spec footprintPodSpec) (cpu, memory resource.Quantity, missing []string) {
+	for _, name := range []string{"cpu", "memory"} {
+		var sum, maxInit resource.Quantity
+		for _, c := range spec.InitContainers {
+			q, ok := containerRequest(c, name)
+			if !ok {
+				missing = append(missing, c.Name+"/"+name)
+				continue
+			}
+			if c.RestartPolicy == "Always" {
+				// Sidecars keep running next to the containers.
+				sum.Add(q)
+			} else if q.Cmp(maxInit) > 0 {
+				maxInit = q
+			}
+		}
+		for _, c := range spec.Containers {
+			q, ok := containerRequest(c, name)
+			if !ok {
+				missing = append(missing, c.Name+"/"+name)
+				continue
+			}
+			sum.Add(q)
+		}
+		if maxInit.Cmp(sum) > 0 {
+			sum = maxInit
+		}
+		if name == "cpu" {
+			cpu = sum
+		} else {
+			memory = sum
+		}
+	}
+	return cpu, memory, missing
+}
+
+func containerRequest(c footprintContainer, name string) (resource.Quantity, bool) {
+	if q, ok := c.Resources.Requests[name]; ok {
+		return q, true
+	}
+	q, ok := c.Resources.Limits[name]
+	return q, ok
+}
@@ -0,0 +1,159 @@
+package helm_parser
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestEstimateFootprint(t *testing.T) {
+	manifest := `---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      initContainers:
+        - name: migrate
+          resources:
+            requests:
+              cpu: 2
+              memory: 256Mi
+        - name: proxy
+          restartPolicy: Always
+          resources:
+            requests:
+              cpu: 100m
+              memory: 64Mi
+      containers:
+        - name: web
+          resources:
+            requests:
+              cpu: 400m
+              memory: 448Mi
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    spec:
+      containers:
+        - name: agent
+          resources:
+            limits:
+              cpu: 500m
+        - name: logs
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+        - name: db
+          resources:
+            requests:
+              cpu: "1"
+              memory: 2Gi
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: db
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: StatefulSet
+    name: db
+  minReplicas: 2
+  maxReplicas: 4
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: backup
+              resources:
+                requests:
+                  cpu: "1"
+                  memory: 1Gi
+`
+
+	footprint, err := EstimateFootprint(manifest, FootprintOptions{
+		Prices:         PriceTable{CPUHour: 0.04, MemoryGBHour: 0.005},
+		Replicas:       map[string]int{"DaemonSet/agent": 5},
+		DaemonSetNodes: 2,
+	})
+	if err != nil {
+		t.Fatalf("EstimateFootprint() error = %v", err)
+	}
+	if len(footprint.Workloads) != 4 {
+		t.Fatalf("got %d workloads, want 4: %+v", len(footprint.Workloads), footprint.Workloads)
+	}
+
+	approx := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-6 {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+
+	// The init container requests more CPU than the containers and the
+	// sidecar, the containers and the sidecar more memory.
+	web := footprint.Workloads[0]
+	approx("web pod cpu", web.PodCPU, 2)
+	approx("web pod memory", web.PodMemoryGB, 0.5)
+	approx("web cpu", web.CPU, 6)
+	approx("web memory", web.MemoryGB, 1.5)
+	approx("web cost", web.MonthlyCost, (6*0.04+1.5*0.005)*HoursPerMonth)
+
+	agent := footprint.Workloads[1]
+	if agent.Replicas != 5 {
+		t.Errorf("agent replicas = %d, want the override 5", agent.Replicas)
+	}
+	approx("agent pod cpu", agent.PodCPU, 0.5)
+	if want := []string{"logs/cpu", "agent/memory", "logs/memory"}; !reflect.DeepEqual(agent.MissingRequests, want) {
+		t.Errorf("agent missing requests = %v, want %v", agent.MissingRequests, want)
+	}
+
+	db := footprint.Workloads[2]
+	if db.Replicas != 2 || db.MaxReplicas != 4 {
+		t.Errorf("db replicas = %d..%d, want the autoscaler range 2..4", db.Replicas, db.MaxReplicas)
+	}
+
+	backup := footprint.Workloads[3]
+	if !backup.Intermittent || backup.Replicas != 1 {
+		t.Errorf("backup = %+v, want an intermittent workload", backup)
+	}
+
+	approx("total cpu", footprint.CPU, 6+2.5+2)
+	approx("total memory", footprint.MemoryGB, 1.5+4)
+	approx("total cost", footprint.MonthlyCost, ((6+2.5+2)*0.04+(1.5+4)*0.005)*HoursPerMonth)
+	approx("max cost", footprint.MaxMonthlyCost, ((6+2.5+4)*0.04+(1.5+8)*0.005)*HoursPerMonth)
+}
+
+func TestEstimateFootprintWithoutAutoscaler(t *testing.T) {
+	manifest := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: app\nspec:\n  containers:\n    - name: app\n      resources:\n        requests:\n          cpu: 250m\n          memory: 1Gi\n"
+	footprint, err := EstimateFootprint(manifest, FootprintOptions{Prices: PriceTable{CPUHour: 1, MemoryGBHour: 1}})
+	if err != nil {
+		t.Fatalf("EstimateFootprint() error = %v", err)
+	}
+	if footprint.MaxMonthlyCost != 0 || footprint.CPU != 0.25 || footprint.MemoryGB != 1 {
+		t.Errorf("footprint = %+v, want 0.25 vCPU and 1 GiB without a maximum cost", footprint)
+	}
+}
@@ -0,0 +1,310 @@
+package helm_parser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Checks run by AuditHA on every long-running workload. They are graded
+// like the checks of ScoreManifest.
+const (
+	CheckHAReplicas         = "ha-replicas"
+	CheckHASpread           = "ha-replica-spread"
+	CheckHADisruptionBudget = "ha-disruption-budget"
+	CheckHAUpdateStrategy   = "ha-update-strategy"
+	CheckHALeaderElection   = "ha-leader-election"
+)
+
+var haRecommendations = map[string]string{
+	CheckHAReplicas:         "Run at least 2 replicas, or set the minimum of the HorizontalPodAutoscaler to 2, so the component survives the loss of a pod or node",
+	CheckHASpread:           "Add a podAntiAffinity or topologySpreadConstraints on kubernetes.io/hostname or a zone so the replicas do not share a failure domain",
+	CheckHADisruptionBudget: "Add a PodDisruptionBudget allowing at least one disruption, so node drains keep enough replicas running without blocking",
+	CheckHAUpdateStrategy:   "Use a RollingUpdate strategy so upgrades replace the pods gradually instead of stopping them all",
+	CheckHALeaderElection:   "Keep leader election enabled for replicated controllers, and run at least 2 replicas so a standby takes over the lease",
+}
+
+// WorkloadHA is the HA readiness of a workload.
+type WorkloadHA struct {
+	Resource
+	// Replicas is the replica count, or the minimum of a
+	// HorizontalPodAutoscaler scaling the workload.
+	Replicas int `json:"replicas"`
+	// LeaderElection is enabled or disabled if the workload uses leader
+	// election, detected from its arguments, environment or its permission
+	// to update Leases, and empty otherwise.
+	LeaderElection string `json:"leaderElection,omitempty"`
+	// LeaderElectionSource tells what the leader election was detected from.
+	LeaderElectionSource string       `json:"leaderElectionSource,omitempty"`
+	Checks               []ScoreCheck `json:"checks"`
+}
+
+// HAReport is the result of AuditHA.
+type HAReport struct {
+	// Ready reports that no check is critical.
+	Ready     bool         `json:"ready"`
+	Critical  int          `json:"critical"`
+	Warnings  int          `json:"warnings"`
+	Workloads []WorkloadHA `json:"workloads"`
+}
+
+type haContainer struct {
+	Command []string `json:"command"`
+	Args    []string `json:"args"`
+	Env     []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"env"`
+}
+
+// haResource holds the fields of the workloads and RBAC resources AuditHA
+// looks at besides those of SummarizeAvailability.
+type haResource struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		Template struct {
+			Spec struct {
+				ServiceAccountName string        `json:"serviceAccountName"`
+				Containers         []haContainer `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+
+	// Roles and ClusterRoles.
+	Rules []struct {
+		APIGroups []string `json:"apiGroups"`
+		Resources []string `json:"resources"`
+		Verbs     []string `json:"verbs"`
+	} `json:"rules"`
+
+	// RoleBindings and ClusterRoleBindings.
+	RoleRef struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	} `json:"roleRef"`
+	Subjects []struct {
+		Kind      string `json:"kind"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"subjects"`
+}
+
+// AuditHA checks whether the Deployments, StatefulSets and ReplicaSets of a
+// multi-document YAML manifest, such as a rendered chart, are ready for
+// production: that they run several replicas spread over nodes, have a
+// PodDisruptionBudget that does not block drains, update without downtime,
+// and that replicated controllers elect a leader. Workloads are reported in
+// manifest order.
+func AuditHA(manifest string) (*HAReport, error) {
+	availability, err := SummarizeAvailability(manifest)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := decodeManifest[haResource](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &HAReport{Ready: true, Workloads: []WorkloadHA{}}
+	for _, a := range availability.Workloads {
+		if a.Kind == "DaemonSet" {
+			// DaemonSets run a pod on every node.
+			continue
+		}
+		w := WorkloadHA{Resource: a.Resource, Replicas: *a.Replicas}
+		if a.Autoscaler != nil {
+			w.Replicas = a.Autoscaler.MinReplicas
+		}
+		for _, r := range resources {
+			if r.Kind == a.Kind && r.Metadata.Name == a.Name && r.Metadata.Namespace == a.Namespace {
+				w.LeaderElection, w.LeaderElectionSource = leaderElection(r, resources)
+				break
+			}
+		}
+
+		w.Checks = []ScoreCheck{
+			checkHAReplicas(w),
+			checkHASpread(w, a),
+			checkHADisruptionBudget(w, a),
+			checkHAUpdateStrategy(w, a),
+			checkHALeaderElection(w),
+		}
+		for _, c := range w.Checks {
+			switch c.Grade {
+			case GradeCritical:
+				report.Critical++
+				report.Ready = false
+			case GradeWarning:
+				report.Warnings++
+			}
+		}
+		report.Workloads = append(report.Workloads, w)
+	}
+	return report, nil
+}
+
+func haCheck(id, grade, message string) ScoreCheck {
+	check := ScoreCheck{ID: id, Grade: grade, Message: message}
+	if grade == GradeWarning || grade == GradeCritical {
+		check.Recommendation = haRecommendations[id]
+	}
+	return check
+}
+
+func checkHAReplicas(w WorkloadHA) ScoreCheck {
+	switch w.Replicas {
+	case 0:
+		return haCheck(CheckHAReplicas, GradeCritical, "runs no replicas")
+	case 1:
+		return haCheck(CheckHAReplicas, GradeCritical, "runs a single replica, a single point of failure")
+	}
+	return haCheck(CheckHAReplicas, GradeOK, fmt.Sprintf("runs %d replicas", w.Replicas))
+}
+
+func checkHASpread(w WorkloadHA, a WorkloadAvailability) ScoreCheck {
+	switch {
+	case w.Replicas < 2:
+		return haCheck(CheckHASpread, GradeSkipped, "the workload does not run several replicas")
+	case a.PodAntiAffinity || len(a.TopologySpread) > 0:
+		return haCheck(CheckHASpread, GradeOK, "")
+	}
+	return haCheck(CheckHASpread, GradeWarning, "no pod anti-affinity or topology spread constraint, the replicas may all run on the same node")
+}
+
+func checkHADisruptionBudget(w WorkloadHA, a WorkloadAvailability) ScoreCheck {
+	pdb := a.DisruptionBudget
+	switch {
+	case w.Replicas < 2:
+		return haCheck(CheckHADisruptionBudget, GradeSkipped, "the workload does not run several replicas")
+	case pdb == nil:
+		return haCheck(CheckHADisruptionBudget, GradeWarning, "no PodDisruptionBudget selects the pods, a node drain may evict all replicas at once")
+	case blocksEvictions(pdb, w.Replicas):
+		return haCheck(CheckHADisruptionBudget, GradeWarning, fmt.Sprintf("PodDisruptionBudget %s allows no disruption with %d replicas and blocks node drains", pdb.Name, w.Replicas))
+	}
+	return haCheck(CheckHADisruptionBudget, GradeOK, "selected by PodDisruptionBudget "+pdb.Name)
+}
+
+// blocksEvictions reports whether a PodDisruptionBudget allows no pod of a
+// workload with replicas to be evicted.
+func blocksEvictions(pdb *DisruptionBudget, replicas int) bool {
+	if pdb.MaxUnavailable != "" {
+		return pdb.MaxUnavailable == "0" || pdb.MaxUnavailable == "0%"
+	}
+	if pdb.MinAvailable == "100%" {
+		return true
+	}
+	n, err := strconv.Atoi(pdb.MinAvailable)
+	return err == nil && n >= replicas
+}
+
+func checkHAUpdateStrategy(w WorkloadHA, a WorkloadAvailability) ScoreCheck {
+	switch {
+	case a.Kind == "ReplicaSet":
+		return haCheck(CheckHAUpdateStrategy, GradeSkipped, "ReplicaSets do not replace their pods")
+	case a.UpdateStrategy.Type == "Recreate":
+		return haCheck(CheckHAUpdateStrategy, GradeWarning, "the Recreate strategy stops all pods before starting the new ones")
+	case a.UpdateStrategy.Type == "OnDelete":
+		return haCheck(CheckHAUpdateStrategy, GradeWarning, "the OnDelete strategy only updates pods deleted by hand")
+	case w.Replicas < 2 && a.Kind == "StatefulSet":
+		return haCheck(CheckHAUpdateStrategy, GradeWarning, "a StatefulSet with one replica is unavailable while its pod is replaced")
+	case w.Replicas < 2 && a.UpdateStrategy.MaxSurge == "0":
+		return haCheck(CheckHAUpdateStrategy, GradeWarning, "with one replica and maxSurge 0 the pod is stopped before its replacement starts")
+	}
+	return haCheck(CheckHAUpdateStrategy, GradeOK, "")
+}
+
+func checkHALeaderElection(w WorkloadHA) ScoreCheck {
+	switch {
+	case w.LeaderElection == "":
+		return haCheck(CheckHALeaderElection, GradeSkipped, "no leader election detected")
+	case w.LeaderElection == "disabled" && w.Replicas > 1:
+		return haCheck(CheckHALeaderElection, GradeCritical, fmt.Sprintf("leader election is disabled by %s, but %d replicas run concurrently", w.LeaderElectionSource, w.Replicas))
+	case w.LeaderElection == "disabled":
+		return haCheck(CheckHALeaderElection, GradeWarning, fmt.Sprintf("leader election is disabled by %s, so the workload cannot run several replicas", w.LeaderElectionSource))
+	case w.Replicas < 2:
+		return haCheck(CheckHALeaderElection, GradeWarning, fmt.Sprintf("uses leader election (%s) without a standby replica to take over", w.LeaderElectionSource))
+	}
+	return haCheck(CheckHALeaderElection, GradeOK, fmt.Sprintf("uses leader election (%s): one replica is active, the others take over when its lease expires", w.LeaderElectionSource))
+}
+
+// leaderElection detects whether a workload elects a leader: from
+// leader-elect flags of its containers, LEADER_ELECT environment variables,
+// or a Role or ClusterRole in resources allowing its service account to
+// update Leases. It returns enabled or disabled and the source, or empty
+// strings.
+func leaderElection(w haResource, resources []haResource) (string, string) {
+	for _, c := range w.Spec.Template.Spec.Containers {
+		for _, arg := range append(slices.Clone(c.Command), c.Args...) {
+			flag, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			if !strings.Contains(strings.ToLower(flag), "leader-elect") || !strings.HasPrefix(arg, "-") {
+				continue
+			}
+			if hasValue && isFalse(value) {
+				return "disabled", "flag " + arg
+			}
+			return "enabled", "flag " + arg
+		}
+		for _, e := range c.Env {
+			if !strings.Contains(strings.ToUpper(e.Name), "LEADER_ELECT") {
+				continue
+			}
+			if isFalse(e.Value) {
+				return "disabled", "env " + e.Name
+			}
+			return "enabled", "env " + e.Name
+		}
+	}
+
+	serviceAccount := w.Spec.Template.Spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	for _, b := range resources {
+		if b.Kind != "RoleBinding" && b.Kind != "ClusterRoleBinding" {
+			continue
+		}
+		bound := false
+		for _, s := range b.Subjects {
+			namespace := s.Namespace
+			if namespace == "" {
+				namespace = b.Metadata.Namespace
+			}
+			if s.Kind == "ServiceAccount" && s.Name == serviceAccount && namespace == w.Metadata.Namespace {
+				bound = true
+			}
+		}
+		if !bound {
+			continue
+		}
+		for _, role := range resources {
+			if role.Kind != b.RoleRef.Kind || role.Metadata.Name != b.RoleRef.Name || (role.Kind == "Role" && role.Metadata.Namespace != b.Metadata.Namespace) {
+				continue
+			}
+			if updatesLeases(role) {
+				return "enabled", fmt.Sprintf("%s %s updating leases", role.Kind, role.Metadata.Name)
+			}
+		}
+	}
+	return "", ""
+}
+
+func updatesLeases(role haResource) bool {
+	for _, rule := range role.Rules {
+		if (slices.Contains(rule.APIGroups, "coordination.k8s.io") || slices.Contains(rule.APIGroups, "*")) &&
+			(slices.Contains(rule.Resources, "leases") || slices.Contains(rule.Resources, "*")) &&
+			(slices.Contains(rule.Verbs, "update") || slices.Contains(rule.Verbs, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func isFalse(value string) bool {
+	b, err := strconv.ParseBool(value)
+	return err == nil && !b
+}
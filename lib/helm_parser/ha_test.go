@@ -0,0 +1,155 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAuditHA(t *testing.T) {
+	manifest := `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      topologySpreadConstraints:
+        - topologyKey: kubernetes.io/hostname
+          maxSkew: 1
+          whenUnsatisfiable: DoNotSchedule
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+spec:
+  minAvailable: 3
+  selector:
+    matchLabels:
+      app: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller
+spec:
+  template:
+    spec:
+      serviceAccountName: controller
+      containers:
+        - name: controller
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: leases
+rules:
+  - apiGroups: [coordination.k8s.io]
+    resources: [leases]
+    verbs: [get, create, update]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: controller
+roleRef:
+  kind: Role
+  name: leases
+subjects:
+  - kind: ServiceAccount
+    name: controller
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: operator
+spec:
+  replicas: 2
+  updateStrategy:
+    type: OnDelete
+  template:
+    spec:
+      affinity:
+        podAntiAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution: []
+      containers:
+        - name: operator
+          args: [--leader-elect=false]
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+`
+
+	report, err := AuditHA(manifest)
+	if err != nil {
+		t.Fatalf("AuditHA() error = %v", err)
+	}
+
+	grades := func(w WorkloadHA) map[string]string {
+		got := map[string]string{}
+		for _, c := range w.Checks {
+			got[c.ID] = c.Grade
+		}
+		return got
+	}
+	tests := []struct {
+		name           string
+		replicas       int
+		leaderElection string
+		grades         map[string]string
+	}{
+		{
+			name:     "web",
+			replicas: 3,
+			grades: map[string]string{
+				CheckHAReplicas: GradeOK, CheckHASpread: GradeOK, CheckHADisruptionBudget: GradeWarning,
+				CheckHAUpdateStrategy: GradeOK, CheckHALeaderElection: GradeSkipped,
+			},
+		},
+		{
+			name:           "controller",
+			replicas:       1,
+			leaderElection: "enabled",
+			grades: map[string]string{
+				CheckHAReplicas: GradeCritical, CheckHASpread: GradeSkipped, CheckHADisruptionBudget: GradeSkipped,
+				CheckHAUpdateStrategy: GradeOK, CheckHALeaderElection: GradeWarning,
+			},
+		},
+		{
+			name:           "operator",
+			replicas:       2,
+			leaderElection: "disabled",
+			grades: map[string]string{
+				CheckHAReplicas: GradeOK, CheckHASpread: GradeOK, CheckHADisruptionBudget: GradeWarning,
+				CheckHAUpdateStrategy: GradeWarning, CheckHALeaderElection: GradeCritical,
+			},
+		},
+	}
+	if len(report.Workloads) != len(tests) {
+		t.Fatalf("got %d workloads, want %d", len(report.Workloads), len(tests))
+	}
+	for i, tt := range tests {
+		w := report.Workloads[i]
+		if w.Name != tt.name || w.Replicas != tt.replicas || w.LeaderElection != tt.leaderElection {
+			t.Errorf("workload %d = %s with %d replicas and leader election %q, want %s with %d and %q",
+				i, w.Name, w.Replicas, w.LeaderElection, tt.name, tt.replicas, tt.leaderElection)
+		}
+		if got := grades(w); !reflect.DeepEqual(got, tt.grades) {
+			t.Errorf("%s: grades = %v, want %v", tt.name, got, tt.grades)
+		}
+	}
+
+	if report.Ready || report.Critical != 2 || report.Warnings != 4 {
+		t.Errorf("ready = %v, critical = %d, warnings = %d; want false, 2, 4", report.Ready, report.Critical, report.Warnings)
+	}
+	if got := report.Workloads[1].LeaderElectionSource; got != "Role leases updating leases" {
+		t.Errorf("leader election source = %q", got)
+	}
+}
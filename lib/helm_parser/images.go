@@ -1,7 +1,9 @@
 package helm_parser
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -17,8 +19,11 @@ type ImageReference struct {
 	Repository string `json:"repository"`
 	Tag        string `json:"tag"`
 	Digest     string `json:"digest,omitempty"`
-	FullImage  string `json:"fullImage"`
-	Source     string `json:"source"`
+	// ResolvedDigest is the digest the tag points to in the registry, set
+	// only if it was looked up.
+	ResolvedDigest string `json:"resolvedDigest,omitempty"`
+	FullImage      string `json:"fullImage"`
+	Source         string `json:"source"`
 }
 
 func parseImage(image string) ImageReference {
@@ -75,22 +80,50 @@ func parseImage(image string) ImageReference {
 	return ref
 }
 
-func GetChartImages(chart *chartv2.Chart, customValues map[string]interface{}, recursive bool) ([]ImageReference, error) {
-	manifests, err := renderChart(chart, customValues)
-	if err != nil {
-		return nil, err
-	}
+// ImageSource selects where GetChartImages looks for images.
+type ImageSource string
+
+const (
+	// ImagesRendered extracts images from the manifests rendered with the
+	// given values.
+	ImagesRendered ImageSource = "rendered"
+	// ImagesValues scans the values for image references, finding images of
+	// optional features that are disabled by default.
+	ImagesValues ImageSource = "values"
+	// ImagesAll combines ImagesRendered and ImagesValues.
+	ImagesAll ImageSource = "all"
+)
 
-	images := extractImagesFromManifests(manifests)
+// ParseImageSource validates an image source; an empty value selects
+// ImagesRendered.
+func ParseImageSource(s string) (ImageSource, error) {
+	switch src := ImageSource(s); src {
+	case "":
+		return ImagesRendered, nil
+	case ImagesRendered, ImagesValues, ImagesAll:
+		return src, nil
+	default:
+		return "", fmt.Errorf("unknown image source %q, expected one of %q, %q or %q", s, ImagesRendered, ImagesValues, ImagesAll)
+	}
+}
 
-	if recursive {
-		for _, subChart := range chart.Dependencies() {
-			subImages, err := GetChartImages(subChart, customValues, recursive)
-			if err != nil {
-				return nil, fmt.Errorf("failed to render subchart %s: %v", subChart.Name(), err)
-			}
-			images = append(images, subImages...)
+// GetChartImages returns the images of chart from source. Templates are
+// rendered as configured by opts.
+func GetChartImages(chart *chartv2.Chart, customValues map[string]interface{}, recursive bool, source ImageSource, opts RenderOptions) ([]ImageReference, error) {
+	var images []ImageReference
+	if source != ImagesValues {
+		rendered, err := renderedImages(chart, customValues, recursive, opts)
+		if err != nil {
+			return nil, err
 		}
+		images = append(images, rendered...)
+	}
+	if source != ImagesRendered {
+		values, err := util.CoalesceValues(chart, customValues)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge values: %v", err)
+		}
+		images = append(images, valuesImages(chart, values, "", recursive)...)
 	}
 
 	images = deduplicateImages(images)
@@ -101,23 +134,81 @@ func GetChartImages(chart *chartv2.Chart, customValues map[string]interface{}, r
 	return images, nil
 }
 
-func renderChart(chart *chartv2.Chart, customValues map[string]interface{}) ([]string, error) {
-	options := common.ReleaseOptions{
-		Name:      "release-name",
-		Namespace: "default",
-		Revision:  1,
-		IsUpgrade: false,
-		IsInstall: true,
+func renderedImages(chart *chartv2.Chart, customValues map[string]interface{}, recursive bool, opts RenderOptions) ([]ImageReference, error) {
+	manifests, err := renderedManifests(chart, customValues, recursive, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.Patches) > 0 {
+		if manifests, err = applyPatches(manifests, opts.Patches); err != nil {
+			return nil, err
+		}
 	}
+	return extractImagesFromManifests(manifests), nil
+}
 
-	caps := common.DefaultCapabilities
-	valuesToRender, err := util.ToRenderValues(chart, customValues, options, caps)
+func renderedManifests(chart *chartv2.Chart, customValues map[string]interface{}, recursive bool, opts RenderOptions) ([]string, error) {
+	manifests, err := renderChart(chart, customValues, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	e := engine.Engine{Strict: false, LintMode: true}
-	rendered, err := e.Render(chart, valuesToRender)
+	if recursive {
+		for _, subChart := range chart.Dependencies() {
+			subManifests, err := renderedManifests(subChart, customValues, recursive, opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render subchart %s: %v", subChart.Name(), err)
+			}
+			manifests = append(manifests, subManifests...)
+		}
+	}
+	return manifests, nil
+}
+
+// Release name and namespace templates are rendered for, unless set in
+// RenderOptions.
+const (
+	DefaultReleaseName = "release-name"
+	DefaultNamespace   = "default"
+)
+
+// RenderOptions configure offline rendering of chart templates. The zero
+// value renders with the defaults.
+type RenderOptions struct {
+	// ReleaseName and Namespace are the .Release.Name and .Release.Namespace
+	// of the rendered templates, which usually end up in resource names,
+	// labels and cross-references.
+	ReleaseName string
+	Namespace   string
+	// Capabilities are the cluster capabilities seen by the templates,
+	// common.DefaultCapabilities if nil; see NewCapabilities.
+	Capabilities *common.Capabilities
+	// Patches are applied to the rendered manifests before they are
+	// analyzed, like a kustomize post-renderer.
+	Patches []Patch
+}
+
+// NewCapabilities returns the capabilities of a cluster running kubeVersion
+// and serving apiVersions in addition to the built-in API versions, like
+// helm template --kube-version --api-versions. An empty kubeVersion keeps
+// Helm's default version.
+func NewCapabilities(kubeVersion string, apiVersions []string) (*common.Capabilities, error) {
+	caps := common.DefaultCapabilities.Copy()
+	if kubeVersion != "" {
+		kv, err := common.ParseKubeVersion(kubeVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kube version %q: %v", kubeVersion, err)
+		}
+		caps.KubeVersion = *kv
+	}
+	if len(apiVersions) > 0 {
+		caps.APIVersions = append(slices.Clone(caps.APIVersions), apiVersions...)
+	}
+	return caps, nil
+}
+
+func renderChart(chart *chartv2.Chart, customValues map[string]interface{}, opts RenderOptions) ([]string, error) {
+	rendered, err := renderTemplates(chart, customValues, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -132,6 +223,30 @@ func renderChart(chart *chartv2.Chart, customValues map[string]interface{}) ([]s
 	return manifests, nil
 }
 
+// renderTemplates renders the templates of chart and its subcharts in lint
+// mode, keyed by their path, e.g. mychart/templates/deployment.yaml.
+func renderTemplates(chart *chartv2.Chart, customValues map[string]interface{}, opts RenderOptions) (map[string]string, error) {
+	options := common.ReleaseOptions{
+		Name:      cmp.Or(opts.ReleaseName, DefaultReleaseName),
+		Namespace: cmp.Or(opts.Namespace, DefaultNamespace),
+		Revision:  1,
+		IsUpgrade: false,
+		IsInstall: true,
+	}
+
+	caps := opts.Capabilities
+	if caps == nil {
+		caps = common.DefaultCapabilities
+	}
+	valuesToRender, err := util.ToRenderValues(chart, customValues, options, caps)
+	if err != nil {
+		return nil, err
+	}
+
+	e := engine.Engine{Strict: false, LintMode: true}
+	return e.Render(chart, valuesToRender)
+}
+
 func extractImagesFromManifests(manifests []string) []ImageReference {
 	var images []ImageReference
 
@@ -239,6 +354,122 @@ func navigateToPath(obj map[string]interface{}, path []string) map[interface{}]i
 	return nil
 }
 
+// valuesImages finds image references in the values of chart: strings under
+// keys named image or ending in Image, e.g. "nginx:1.25", and maps with a
+// repository, optionally with registry, tag and digest keys. Images without a
+// tag use the appVersion of the chart, as most charts do. The values of
+// subcharts are scanned if recursive is set.
+func valuesImages(chart *chartv2.Chart, values map[string]any, prefix string, recursive bool) []ImageReference {
+	subCharts := make(map[string]*chartv2.Chart)
+	for _, sub := range chart.Dependencies() {
+		subCharts[sub.Name()] = sub
+	}
+	appVersion := ""
+	if chart.Metadata != nil {
+		appVersion = chart.Metadata.AppVersion
+	}
+
+	var images []ImageReference
+	for key, value := range values {
+		if sub, ok := subCharts[key]; ok {
+			if subValues, ok := asValuesMap(value); ok && recursive {
+				images = append(images, valuesImages(sub, subValues, prefix+key+".", recursive)...)
+			}
+			continue
+		}
+		images = append(images, findValuesImages(key, value, prefix+key, appVersion)...)
+	}
+	return images
+}
+
+func findValuesImages(key string, value any, path, appVersion string) []ImageReference {
+	if s, ok := value.(string); ok {
+		if isImageKey(key) && s != "" && !strings.ContainsAny(s, " \t\n{}") {
+			ref := parseImage(s)
+			ref.Source = "values: " + path
+			return []ImageReference{ref}
+		}
+		return nil
+	}
+
+	if list, ok := value.([]any); ok {
+		var images []ImageReference
+		for i, item := range list {
+			images = append(images, findValuesImages("", item, fmt.Sprintf("%s[%d]", path, i), appVersion)...)
+		}
+		return images
+	}
+
+	m, ok := asValuesMap(value)
+	if !ok {
+		return nil
+	}
+	if image, ok := imageFromValues(key, m, appVersion); ok {
+		ref := parseImage(image)
+		ref.Source = "values: " + path
+		return []ImageReference{ref}
+	}
+	var images []ImageReference
+	for k, v := range m {
+		images = append(images, findValuesImages(k, v, path+"."+k, appVersion)...)
+	}
+	return images
+}
+
+// imageFromValues builds the image reference of a map such as
+// {registry: docker.io, repository: bitnami/redis, tag: 7.2}.
+func imageFromValues(key string, m map[string]any, appVersion string) (string, bool) {
+	scalar := func(k string) string {
+		switch v := m[k].(type) {
+		case string:
+			return strings.TrimSpace(v)
+		case int, int64, float64:
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+
+	repository := scalar("repository")
+	if repository == "" && isImageKey(key) {
+		repository = scalar("name")
+	}
+	if repository == "" || strings.ContainsAny(repository, " {}") {
+		return "", false
+	}
+
+	image := repository
+	if registry := scalar("registry"); registry != "" {
+		image = registry + "/" + repository
+	}
+	// The repository may already include the tag, e.g. "nginx:1.25".
+	hasTag := strings.Contains(image[strings.LastIndex(image, "/")+1:], ":")
+	tag, digest := scalar("tag"), scalar("digest")
+	if tag == "" && digest == "" {
+		tag = appVersion
+	}
+	if tag != "" && !hasTag {
+		image += ":" + tag
+	}
+	if digest != "" {
+		image += "@" + digest
+	}
+	return image, true
+}
+
+func isImageKey(key string) bool {
+	return strings.EqualFold(key, "image") || strings.HasSuffix(key, "Image")
+}
+
+func asValuesMap(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, true
+	case common.Values:
+		return m, true
+	}
+	return nil, false
+}
+
 func deduplicateImages(images []ImageReference) []ImageReference {
 	seen := make(map[string]ImageReference)
 
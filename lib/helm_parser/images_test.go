@@ -2,6 +2,9 @@ package helm_parser
 
 import (
 	"testing"
+
+	"helm.sh/helm/v4/pkg/chart/common"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
 )
 
 func TestParseImageString(t *testing.T) {
@@ -398,3 +401,186 @@ func TestExtractFromPodSpec(t *testing.T) {
 		t.Error("init container image not found")
 	}
 }
+
+func TestGetChartImagesFromValues(t *testing.T) {
+	chart := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "app", Version: "1.0.0", AppVersion: "2.3.0"},
+		Templates: []*common.File{
+			{Name: "templates/deployment.yaml", Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: "{{ .Values.image.repository }}:{{ .Values.image.tag | default .Chart.AppVersion }}"
+`)},
+		},
+		Values: map[string]any{
+			"image": map[string]any{"repository": "example/app", "tag": ""},
+			"metrics": map[string]any{
+				"enabled": false,
+				"image":   map[string]any{"registry": "quay.io", "repository": "prometheus/exporter", "tag": 1.5},
+			},
+			"initImage":    "busybox:1.36",
+			"sidecars":     []any{map[string]any{"name": "proxy", "image": "envoyproxy/envoy:v1.30@sha256:abc"}},
+			"nameOverride": "",
+			"podLabels":    map[string]any{"image": "{{ .Release.Name }}"},
+		},
+	}
+	sub := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "cache", Version: "1.0.0", AppVersion: "7.2"},
+		Values:   map[string]any{"image": map[string]any{"repository": "redis"}},
+	}
+	chart.SetDependencies(sub)
+
+	full := func(images []ImageReference) map[string]string {
+		got := make(map[string]string)
+		for _, img := range images {
+			got[img.FullImage] = img.Source
+		}
+		return got
+	}
+
+	images, err := GetChartImages(chart, nil, false, ImagesRendered, RenderOptions{})
+	if err != nil {
+		t.Fatalf("GetChartImages() error = %v", err)
+	}
+	if got := full(images); len(got) != 1 || got["example/app:2.3.0"] == "" {
+		t.Errorf("rendered images = %v", got)
+	}
+
+	images, err = GetChartImages(chart, map[string]any{"metrics": map[string]any{"image": map[string]any{"tag": "1.6"}}}, false, ImagesValues, RenderOptions{})
+	if err != nil {
+		t.Fatalf("GetChartImages() error = %v", err)
+	}
+	want := map[string]string{
+		"example/app:2.3.0":                 "values: image",
+		"quay.io/prometheus/exporter:1.6":   "values: metrics.image",
+		"busybox:1.36":                      "values: initImage",
+		"envoyproxy/envoy:v1.30@sha256:abc": "values: sidecars[0].image",
+	}
+	if got := full(images); len(got) != len(want) {
+		t.Errorf("values images = %v, want %v", got, want)
+	} else {
+		for image, source := range want {
+			if got[image] != source {
+				t.Errorf("image %s: source = %q, want %q", image, got[image], source)
+			}
+		}
+	}
+
+	images, err = GetChartImages(chart, nil, true, ImagesAll, RenderOptions{})
+	if err != nil {
+		t.Fatalf("GetChartImages() error = %v", err)
+	}
+	got := full(images)
+	if got["redis:7.2"] != "values: cache.image" {
+		t.Errorf("subchart image missing: %v", got)
+	}
+	if got["example/app:2.3.0"] != "Deployment/app, values: image" {
+		t.Errorf("expected rendered and values sources to be combined, got %q", got["example/app:2.3.0"])
+	}
+}
+
+func TestGetChartImagesCapabilities(t *testing.T) {
+	chart := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "app", Version: "1.0.0"},
+		Templates: []*common.File{
+			{Name: "templates/pods.yaml", Data: []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+    - name: app
+      image: "{{ if semverCompare ">=1.29-0" .Capabilities.KubeVersion.Version }}app:new{{ else }}app:old{{ end }}"
+{{- if .Capabilities.APIVersions.Has "monitoring.coreos.com/v1" }}
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: exporter
+spec:
+  containers:
+    - name: exporter
+      image: exporter:1.0
+{{- end }}
+`)},
+		},
+	}
+
+	images, err := GetChartImages(chart, nil, false, ImagesRendered, RenderOptions{})
+	if err != nil {
+		t.Fatalf("GetChartImages() error = %v", err)
+	}
+	if len(images) != 1 || images[0].FullImage != "app:old" {
+		t.Errorf("images with default capabilities = %+v", images)
+	}
+
+	caps, err := NewCapabilities("v1.30.2", []string{"monitoring.coreos.com/v1"})
+	if err != nil {
+		t.Fatalf("NewCapabilities() error = %v", err)
+	}
+	images, err = GetChartImages(chart, nil, false, ImagesRendered, RenderOptions{Capabilities: caps})
+	if err != nil {
+		t.Fatalf("GetChartImages() error = %v", err)
+	}
+	if len(images) != 2 || images[0].FullImage != "app:new" || images[1].FullImage != "exporter:1.0" {
+		t.Errorf("images with overridden capabilities = %+v", images)
+	}
+	if common.DefaultCapabilities.APIVersions.Has("monitoring.coreos.com/v1") {
+		t.Error("NewCapabilities() modified the default capabilities")
+	}
+
+	if _, err := NewCapabilities("latest", nil); err == nil {
+		t.Error("expected an error for an invalid kube version")
+	}
+}
+
+func TestGetChartImagesRelease(t *testing.T) {
+	chart := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "app", Version: "1.0.0"},
+		Templates: []*common.File{
+			{Name: "templates/pod.yaml", Data: []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ .Release.Name }}-app
+spec:
+  containers:
+    - name: app
+      image: registry.{{ .Release.Namespace }}.svc/app:1.0
+`)},
+		},
+	}
+
+	for _, tc := range []struct {
+		opts       RenderOptions
+		wantImage  string
+		wantSource string
+	}{
+		{RenderOptions{}, "registry.default.svc/app:1.0", "Pod/release-name-app"},
+		{RenderOptions{ReleaseName: "prod", Namespace: "apps"}, "registry.apps.svc/app:1.0", "Pod/prod-app"},
+	} {
+		images, err := GetChartImages(chart, nil, false, ImagesRendered, tc.opts)
+		if err != nil {
+			t.Fatalf("GetChartImages() error = %v", err)
+		}
+		if len(images) != 1 || images[0].FullImage != tc.wantImage || images[0].Source != tc.wantSource {
+			t.Errorf("GetChartImages(%+v) = %+v, want %s from %s", tc.opts, images, tc.wantImage, tc.wantSource)
+		}
+	}
+}
+
+func TestParseImageSource(t *testing.T) {
+	for in, want := range map[string]ImageSource{"": ImagesRendered, "values": ImagesValues, "all": ImagesAll} {
+		if got, err := ParseImageSource(in); err != nil || got != want {
+			t.Errorf("ParseImageSource(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseImageSource("manifests"); err == nil {
+		t.Error("expected an error for an unknown source")
+	}
+}
@@ -0,0 +1,301 @@
+package helm_parser
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// IngressRoute is a host and path routed to a backend.
+type IngressRoute struct {
+	// Host is empty for routes matching any host.
+	Host     string `json:"host,omitempty"`
+	Path     string `json:"path,omitempty"`
+	PathType string `json:"pathType,omitempty"`
+	// Backend is the service requests are sent to, as name:port.
+	Backend string `json:"backend,omitempty"`
+}
+
+// IngressTLS is a certificate an Ingress serves for hosts.
+type IngressTLS struct {
+	Hosts      []string `json:"hosts,omitempty"`
+	SecretName string   `json:"secretName,omitempty"`
+}
+
+// GatewayListener is a listener of a Gateway.
+type GatewayListener struct {
+	Name     string `json:"name"`
+	Hostname string `json:"hostname,omitempty"`
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+	// TLSSecrets are the certificates of HTTPS and TLS listeners.
+	TLSSecrets []string `json:"tlsSecrets,omitempty"`
+}
+
+// IngressResource is an Ingress, Gateway or HTTPRoute.
+type IngressResource struct {
+	Resource
+	// Class is the ingress class of an Ingress or the gateway class of a
+	// Gateway.
+	Class string `json:"class,omitempty"`
+	// Hosts are the host names the resource accepts requests for.
+	Hosts  []string       `json:"hosts"`
+	Routes []IngressRoute `json:"routes,omitempty"`
+	TLS    []IngressTLS   `json:"tls,omitempty"`
+	// Listeners are the listeners of a Gateway.
+	Listeners []GatewayListener `json:"listeners,omitempty"`
+	// ParentRefs are the Gateways an HTTPRoute attaches to, as
+	// name/section.
+	ParentRefs []string `json:"parentRefs,omitempty"`
+	// CertIssuer is the cert-manager issuer requesting the certificates,
+	// from the cert-manager.io annotations.
+	CertIssuer string `json:"certIssuer,omitempty"`
+}
+
+// IngressReport is the result of ExtractIngresses.
+type IngressReport struct {
+	// Hosts are the host names of all resources, sorted.
+	Hosts []string `json:"hosts"`
+	// TLSSecrets are the Secrets holding the certificates of all resources,
+	// sorted.
+	TLSSecrets []string          `json:"tlsSecrets"`
+	Resources  []IngressResource `json:"resources"`
+}
+
+type ingressBackend struct {
+	Service struct {
+		Name string `json:"name"`
+		Port struct {
+			Number int    `json:"number"`
+			Name   string `json:"name"`
+		} `json:"port"`
+	} `json:"service"`
+	// networking.k8s.io/v1beta1 and extensions/v1beta1.
+	ServiceName string             `json:"serviceName"`
+	ServicePort intstr.IntOrString `json:"servicePort"`
+}
+
+func (b *ingressBackend) String() string {
+	if b == nil {
+		return ""
+	}
+	if b.ServiceName != "" {
+		return b.ServiceName + ":" + b.ServicePort.String()
+	}
+	if b.Service.Name == "" {
+		return ""
+	}
+	if b.Service.Port.Name != "" {
+		return b.Service.Name + ":" + b.Service.Port.Name
+	}
+	return fmt.Sprintf("%s:%d", b.Service.Name, b.Service.Port.Number)
+}
+
+// ingressManifestResource holds the fields of Ingresses, Gateways and
+// HTTPRoutes ExtractIngresses looks at.
+type ingressManifestResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		// Ingress.
+		IngressClassName string          `json:"ingressClassName"`
+		DefaultBackend   *ingressBackend `json:"defaultBackend"`
+		Backend          *ingressBackend `json:"backend"`
+		Rules            []struct {
+			Host string `json:"host"`
+			HTTP struct {
+				Paths []struct {
+					Path     string          `json:"path"`
+					PathType string          `json:"pathType"`
+					Backend  *ingressBackend `json:"backend"`
+				} `json:"paths"`
+			} `json:"http"`
+
+			// HTTPRoute.
+			Matches []struct {
+				Path struct {
+					Type  string `json:"type"`
+					Value string `json:"value"`
+				} `json:"path"`
+			} `json:"matches"`
+			BackendRefs []struct {
+				Name string `json:"name"`
+				Port int    `json:"port"`
+			} `json:"backendRefs"`
+		} `json:"rules"`
+		TLS []struct {
+			Hosts      []string `json:"hosts"`
+			SecretName string   `json:"secretName"`
+		} `json:"tls"`
+
+		// Gateway.
+		GatewayClassName string `json:"gatewayClassName"`
+		Listeners        []struct {
+			Name     string `json:"name"`
+			Hostname string `json:"hostname"`
+			Port     int    `json:"port"`
+			Protocol string `json:"protocol"`
+			TLS      struct {
+				CertificateRefs []struct {
+					Name string `json:"name"`
+				} `json:"certificateRefs"`
+			} `json:"tls"`
+		} `json:"listeners"`
+
+		// HTTPRoute.
+		Hostnames  []string `json:"hostnames"`
+		ParentRefs []struct {
+			Name        string `json:"name"`
+			SectionName string `json:"sectionName"`
+		} `json:"parentRefs"`
+	} `json:"spec"`
+}
+
+// ExtractIngresses returns the Ingresses, Gateway API Gateways and
+// HTTPRoutes of a multi-document YAML manifest, such as a rendered chart,
+// with their hosts, paths, backends, TLS secrets and classes, in manifest
+// order.
+func ExtractIngresses(manifest string) (*IngressReport, error) {
+	resources, err := decodeManifest[ingressManifestResource](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &IngressReport{Hosts: []string{}, TLSSecrets: []string{}, Resources: []IngressResource{}}
+	for _, r := range resources {
+		var ing IngressResource
+		switch r.Kind {
+		case "Ingress":
+			ing = extractIngress(r)
+		case "Gateway":
+			ing = extractGateway(r)
+		case "HTTPRoute":
+			ing = extractHTTPRoute(r)
+		default:
+			continue
+		}
+		ing.Resource = Resource{APIVersion: r.APIVersion, Kind: r.Kind, Name: r.Metadata.Name, Namespace: r.Metadata.Namespace}
+		ing.CertIssuer = r.Metadata.Annotations["cert-manager.io/cluster-issuer"]
+		if ing.CertIssuer == "" {
+			ing.CertIssuer = r.Metadata.Annotations["cert-manager.io/issuer"]
+		}
+		if ing.Hosts == nil {
+			ing.Hosts = []string{}
+		}
+
+		report.Hosts = append(report.Hosts, ing.Hosts...)
+		for _, tls := range ing.TLS {
+			if tls.SecretName != "" {
+				report.TLSSecrets = append(report.TLSSecrets, tls.SecretName)
+			}
+		}
+		for _, l := range ing.Listeners {
+			report.TLSSecrets = append(report.TLSSecrets, l.TLSSecrets...)
+		}
+		report.Resources = append(report.Resources, ing)
+	}
+	slices.Sort(report.Hosts)
+	report.Hosts = slices.Compact(report.Hosts)
+	slices.Sort(report.TLSSecrets)
+	report.TLSSecrets = slices.Compact(report.TLSSecrets)
+	return report, nil
+}
+
+func extractIngress(r ingressManifestResource) IngressResource {
+	ing := IngressResource{Class: r.Spec.IngressClassName}
+	if ing.Class == "" {
+		ing.Class = r.Metadata.Annotations["kubernetes.io/ingress.class"]
+	}
+
+	defaultBackend := r.Spec.DefaultBackend
+	if defaultBackend == nil {
+		defaultBackend = r.Spec.Backend
+	}
+	if backend := defaultBackend.String(); backend != "" {
+		ing.Routes = append(ing.Routes, IngressRoute{Backend: backend})
+	}
+	for _, rule := range r.Spec.Rules {
+		if rule.Host != "" && !slices.Contains(ing.Hosts, rule.Host) {
+			ing.Hosts = append(ing.Hosts, rule.Host)
+		}
+		for _, p := range rule.HTTP.Paths {
+			ing.Routes = append(ing.Routes, IngressRoute{Host: rule.Host, Path: p.Path, PathType: p.PathType, Backend: p.Backend.String()})
+		}
+	}
+	for _, tls := range r.Spec.TLS {
+		ing.TLS = append(ing.TLS, IngressTLS(tls))
+		for _, host := range tls.Hosts {
+			if !slices.Contains(ing.Hosts, host) {
+				ing.Hosts = append(ing.Hosts, host)
+			}
+		}
+	}
+	return ing
+}
+
+func extractGateway(r ingressManifestResource) IngressResource {
+	ing := IngressResource{Class: r.Spec.GatewayClassName}
+	for _, l := range r.Spec.Listeners {
+		listener := GatewayListener{Name: l.Name, Hostname: l.Hostname, Port: l.Port, Protocol: l.Protocol}
+		for _, ref := range l.TLS.CertificateRefs {
+			listener.TLSSecrets = append(listener.TLSSecrets, ref.Name)
+		}
+		if l.Hostname != "" && !slices.Contains(ing.Hosts, l.Hostname) {
+			ing.Hosts = append(ing.Hosts, l.Hostname)
+		}
+		ing.Listeners = append(ing.Listeners, listener)
+	}
+	return ing
+}
+
+func extractHTTPRoute(r ingressManifestResource) IngressResource {
+	ing := IngressResource{Hosts: r.Spec.Hostnames}
+	for _, ref := range r.Spec.ParentRefs {
+		parent := ref.Name
+		if ref.SectionName != "" {
+			parent += "/" + ref.SectionName
+		}
+		ing.ParentRefs = append(ing.ParentRefs, parent)
+	}
+
+	hosts := r.Spec.Hostnames
+	if len(hosts) == 0 {
+		// The route matches any host of its Gateways.
+		hosts = []string{""}
+	}
+	for _, rule := range r.Spec.Rules {
+		backends := make([]string, 0, len(rule.BackendRefs))
+		for _, ref := range rule.BackendRefs {
+			backends = append(backends, fmt.Sprintf("%s:%d", ref.Name, ref.Port))
+		}
+		if len(backends) == 0 {
+			backends = []string{""}
+		}
+		// Rules without a path match all paths.
+		paths := [][2]string{{"PathPrefix", "/"}}
+		if len(rule.Matches) > 0 {
+			paths = paths[:0]
+			for _, m := range rule.Matches {
+				if m.Path.Value == "" {
+					paths = append(paths, [2]string{"PathPrefix", "/"})
+				} else {
+					paths = append(paths, [2]string{m.Path.Type, m.Path.Value})
+				}
+			}
+		}
+		for _, host := range hosts {
+			for _, path := range paths {
+				for _, backend := range backends {
+					ing.Routes = append(ing.Routes, IngressRoute{Host: host, Path: path[1], PathType: path[0], Backend: backend})
+				}
+			}
+		}
+	}
+	return ing
+}
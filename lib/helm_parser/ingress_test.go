@@ -0,0 +1,132 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractIngresses(t *testing.T) {
+	manifest := `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+  annotations:
+    cert-manager.io/cluster-issuer: letsencrypt
+spec:
+  ingressClassName: nginx
+  tls:
+    - hosts: [app.example.com]
+      secretName: web-tls
+  rules:
+    - host: app.example.com
+      http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend:
+              service:
+                name: web
+                port:
+                  number: 80
+          - path: /api
+            pathType: Prefix
+            backend:
+              service:
+                name: api
+                port:
+                  name: http
+---
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: legacy
+  annotations:
+    kubernetes.io/ingress.class: traefik
+spec:
+  backend:
+    serviceName: web
+    servicePort: 8080
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: gw
+spec:
+  gatewayClassName: istio
+  listeners:
+    - name: https
+      hostname: "*.example.com"
+      port: 443
+      protocol: HTTPS
+      tls:
+        certificateRefs:
+          - name: wildcard-tls
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: route
+spec:
+  parentRefs:
+    - name: gw
+      sectionName: https
+  hostnames: [docs.example.com]
+  rules:
+    - backendRefs:
+        - name: docs
+          port: 8080
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+	report, err := ExtractIngresses(manifest)
+	if err != nil {
+		t.Fatalf("ExtractIngresses() error = %v", err)
+	}
+
+	if want := []string{"*.example.com", "app.example.com", "docs.example.com"}; !reflect.DeepEqual(report.Hosts, want) {
+		t.Errorf("hosts = %v, want %v", report.Hosts, want)
+	}
+	if want := []string{"web-tls", "wildcard-tls"}; !reflect.DeepEqual(report.TLSSecrets, want) {
+		t.Errorf("TLS secrets = %v, want %v", report.TLSSecrets, want)
+	}
+
+	want := []IngressResource{
+		{
+			Resource:   Resource{APIVersion: "networking.k8s.io/v1", Kind: "Ingress", Name: "web"},
+			Class:      "nginx",
+			Hosts:      []string{"app.example.com"},
+			CertIssuer: "letsencrypt",
+			Routes: []IngressRoute{
+				{Host: "app.example.com", Path: "/", PathType: "Prefix", Backend: "web:80"},
+				{Host: "app.example.com", Path: "/api", PathType: "Prefix", Backend: "api:http"},
+			},
+			TLS: []IngressTLS{{Hosts: []string{"app.example.com"}, SecretName: "web-tls"}},
+		},
+		{
+			Resource: Resource{APIVersion: "extensions/v1beta1", Kind: "Ingress", Name: "legacy"},
+			Class:    "traefik",
+			Hosts:    []string{},
+			Routes:   []IngressRoute{{Backend: "web:8080"}},
+		},
+		{
+			Resource:  Resource{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway", Name: "gw"},
+			Class:     "istio",
+			Hosts:     []string{"*.example.com"},
+			Listeners: []GatewayListener{{Name: "https", Hostname: "*.example.com", Port: 443, Protocol: "HTTPS", TLSSecrets: []string{"wildcard-tls"}}},
+		},
+		{
+			Resource:   Resource{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute", Name: "route"},
+			Hosts:      []string{"docs.example.com"},
+			ParentRefs: []string{"gw/https"},
+			Routes:     []IngressRoute{{Host: "docs.example.com", Path: "/", PathType: "PathPrefix", Backend: "docs:8080"}},
+		},
+	}
+	if !reflect.DeepEqual(report.Resources, want) {
+		t.Errorf("got %+v\nwant %+v", report.Resources, want)
+	}
+}
@@ -0,0 +1,176 @@
+package helm_parser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// RecommendedLabels are the labels Kubernetes recommends on all resources,
+// see https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/.
+var RecommendedLabels = []string{
+	"app.kubernetes.io/name",
+	"app.kubernetes.io/instance",
+	"app.kubernetes.io/version",
+	"app.kubernetes.io/component",
+	"app.kubernetes.io/part-of",
+	"app.kubernetes.io/managed-by",
+}
+
+// LabelUsage is a label or annotation key used in a manifest.
+type LabelUsage struct {
+	Key string `json:"key"`
+	// Values are the values of the key, sorted. They are omitted for
+	// annotations.
+	Values []string `json:"values,omitempty"`
+	// Count is the number of resources with the key.
+	Count int `json:"count"`
+}
+
+// ResourceLabels is the label conformance of a resource.
+type ResourceLabels struct {
+	Resource
+	// Missing are the required labels the resource lacks.
+	Missing []string `json:"missing,omitempty"`
+	// PodTemplateMissing are the required labels the pod template of a
+	// workload lacks.
+	PodTemplateMissing []string `json:"podTemplateMissing,omitempty"`
+	// Invalid are the labels Kubernetes rejects, with the reason.
+	Invalid []string `json:"invalid,omitempty"`
+}
+
+// LabelReport is the result of CheckLabels.
+type LabelReport struct {
+	Required []string `json:"required"`
+	// Conformance is the percentage of resources with all required labels
+	// and no invalid ones, 100 if there are none.
+	Conformance int `json:"conformance"`
+	// Resources are the resources of the manifest in manifest order.
+	Resources   []ResourceLabels `json:"resources"`
+	Labels      []LabelUsage     `json:"labels"`
+	Annotations []LabelUsage     `json:"annotations"`
+}
+
+type labelsMetadata struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// labelsResource holds the fields CheckLabels looks at.
+type labelsResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace"`
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		Template struct {
+			Metadata labelsMetadata `json:"metadata"`
+		} `json:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template struct {
+					Metadata labelsMetadata `json:"metadata"`
+				} `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+	} `json:"spec"`
+}
+
+// CheckLabels aggregates the labels and annotations of the resources of a
+// multi-document YAML manifest, such as a rendered chart, and checks that
+// the resources and the pod templates of workloads carry the required
+// labels, RecommendedLabels if required is empty, with valid values.
+func CheckLabels(manifest string, required []string) (*LabelReport, error) {
+	resources, err := decodeManifest[labelsResource](manifest)
+	if err != nil {
+		return nil, err
+	}
+	if len(required) == 0 {
+		required = RecommendedLabels
+	}
+
+	report := &LabelReport{Required: required, Conformance: 100, Resources: []ResourceLabels{}}
+	labels := map[string]*LabelUsage{}
+	annotations := map[string]*LabelUsage{}
+	conforming := 0
+	for _, r := range resources {
+		if r.Kind == "" {
+			continue
+		}
+		res := ResourceLabels{
+			Resource: Resource{APIVersion: r.APIVersion, Kind: r.Kind, Name: r.Metadata.Name, Namespace: r.Metadata.Namespace},
+			Missing:  missingLabels(r.Metadata.Labels, required),
+			Invalid:  invalidLabels(r.Metadata.Labels),
+		}
+		switch r.Kind {
+		case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+			res.PodTemplateMissing = missingLabels(r.Spec.Template.Metadata.Labels, required)
+		case "CronJob":
+			res.PodTemplateMissing = missingLabels(r.Spec.JobTemplate.Spec.Template.Metadata.Labels, required)
+		}
+		if len(res.Missing) == 0 && len(res.PodTemplateMissing) == 0 && len(res.Invalid) == 0 {
+			conforming++
+		}
+		report.Resources = append(report.Resources, res)
+
+		for k, v := range r.Metadata.Labels {
+			if labels[k] == nil {
+				labels[k] = &LabelUsage{Key: k}
+			}
+			labels[k].Count++
+			if !slices.Contains(labels[k].Values, v) {
+				labels[k].Values = append(labels[k].Values, v)
+			}
+		}
+		for k := range r.Metadata.Annotations {
+			if annotations[k] == nil {
+				annotations[k] = &LabelUsage{Key: k}
+			}
+			annotations[k].Count++
+		}
+	}
+	if len(report.Resources) > 0 {
+		report.Conformance = conforming * 100 / len(report.Resources)
+	}
+	report.Labels = sortedLabelUsage(labels)
+	report.Annotations = sortedLabelUsage(annotations)
+	return report, nil
+}
+
+func missingLabels(labels map[string]string, required []string) []string {
+	var missing []string
+	for _, key := range required {
+		if labels[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+func invalidLabels(labels map[string]string) []string {
+	var invalid []string
+	for k, v := range labels {
+		errs := validation.IsQualifiedName(k)
+		errs = append(errs, validation.IsValidLabelValue(v)...)
+		if len(errs) > 0 {
+			invalid = append(invalid, fmt.Sprintf("%s=%s: %s", k, v, strings.Join(errs, "; ")))
+		}
+	}
+	slices.Sort(invalid)
+	return invalid
+}
+
+func sortedLabelUsage(usage map[string]*LabelUsage) []LabelUsage {
+	list := make([]LabelUsage, 0, len(usage))
+	for _, u := range usage {
+		slices.Sort(u.Values)
+		list = append(list, *u)
+	}
+	slices.SortFunc(list, func(a, b LabelUsage) int { return strings.Compare(a.Key, b.Key) })
+	return list
+}
@@ -0,0 +1,78 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckLabels(t *testing.T) {
+	manifest := `---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  labels:
+    app.kubernetes.io/name: web
+    app.kubernetes.io/instance: prod
+  annotations:
+    prometheus.io/scrape: "true"
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app.kubernetes.io/name: web
+    app.kubernetes.io/instance: prod
+    app.kubernetes.io/version: 1.0.0+build.1
+spec:
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  labels:
+    app.kubernetes.io/name: web
+    app.kubernetes.io/instance: staging
+`
+
+	report, err := CheckLabels(manifest, []string{"app.kubernetes.io/name", "app.kubernetes.io/instance"})
+	if err != nil {
+		t.Fatalf("CheckLabels() error = %v", err)
+	}
+
+	if report.Conformance != 66 {
+		t.Errorf("conformance = %d, want 66", report.Conformance)
+	}
+	deployment := report.Resources[1]
+	if deployment.Missing != nil || !reflect.DeepEqual(deployment.PodTemplateMissing, []string{"app.kubernetes.io/instance"}) {
+		t.Errorf("deployment missing = %v, pod template missing = %v", deployment.Missing, deployment.PodTemplateMissing)
+	}
+	if len(deployment.Invalid) != 1 {
+		t.Errorf("deployment invalid = %v, want the version label", deployment.Invalid)
+	}
+
+	wantLabels := []LabelUsage{
+		{Key: "app.kubernetes.io/instance", Values: []string{"prod", "staging"}, Count: 3},
+		{Key: "app.kubernetes.io/name", Values: []string{"web"}, Count: 3},
+		{Key: "app.kubernetes.io/version", Values: []string{"1.0.0+build.1"}, Count: 1},
+	}
+	if !reflect.DeepEqual(report.Labels, wantLabels) {
+		t.Errorf("labels = %+v\nwant %+v", report.Labels, wantLabels)
+	}
+	if want := []LabelUsage{{Key: "prometheus.io/scrape", Count: 1}}; !reflect.DeepEqual(report.Annotations, want) {
+		t.Errorf("annotations = %+v, want %+v", report.Annotations, want)
+	}
+
+	report, err = CheckLabels(manifest, nil)
+	if err != nil {
+		t.Fatalf("CheckLabels() error = %v", err)
+	}
+	if !reflect.DeepEqual(report.Required, RecommendedLabels) || report.Conformance != 0 {
+		t.Errorf("required = %v, conformance = %d; want the recommended labels and 0", report.Required, report.Conformance)
+	}
+}
@@ -67,9 +67,97 @@ func GetChartDependencies(chart *chartv2.Chart) ([]string, error) {
 	return dependencies, nil
 }
 
-func GetChartContents(c *chartv2.Chart, recursive bool) (string, error) {
+// DependencyNode is a dependency of a chart in a dependency tree.
+type DependencyNode struct {
+	Name string `json:"name"`
+	// Version is the version constraint declared in Chart.yaml.
+	Version    string   `json:"version"`
+	Repository string   `json:"repository,omitempty"`
+	Condition  string   `json:"condition,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Alias      string   `json:"alias,omitempty"`
+	// Bundled reports whether the dependency is included in the chart
+	// archive. Children and ResolvedVersion are only known for bundled
+	// dependencies.
+	Bundled         bool             `json:"bundled"`
+	ResolvedVersion string           `json:"resolvedVersion,omitempty"`
+	Children        []DependencyNode `json:"children,omitempty"`
+}
+
+// GetChartDependencyTree returns the dependencies declared by chart with the
+// dependencies of its bundled subcharts nested under them.
+func GetChartDependencyTree(chart *chartv2.Chart) []DependencyNode {
+	if chart.Metadata == nil {
+		return []DependencyNode{}
+	}
+
+	subCharts := make(map[string]*chartv2.Chart)
+	for _, sub := range chart.Dependencies() {
+		subCharts[sub.Name()] = sub
+	}
+
+	nodes := make([]DependencyNode, 0, len(chart.Metadata.Dependencies))
+	for _, dep := range chart.Metadata.Dependencies {
+		node := DependencyNode{
+			Name:       dep.Name,
+			Version:    dep.Version,
+			Repository: dep.Repository,
+			Condition:  dep.Condition,
+			Tags:       dep.Tags,
+			Alias:      dep.Alias,
+		}
+		if sub, ok := subCharts[dep.Name]; ok {
+			node.Bundled = true
+			node.ResolvedVersion = sub.Metadata.Version
+			node.Children = GetChartDependencyTree(sub)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// ContentFilter selects the files returned by GetChartContents.
+type ContentFilter string
+
+const (
+	// ContentAll returns all files of the chart.
+	ContentAll ContentFilter = "all"
+	// ContentTemplates returns only files in templates/.
+	ContentTemplates ContentFilter = "templates"
+	// ContentNonTemplates returns every file except those in templates/.
+	ContentNonTemplates ContentFilter = "non_templates"
+)
+
+// ParseContentFilter validates a content filter; an empty value selects all files.
+func ParseContentFilter(s string) (ContentFilter, error) {
+	switch f := ContentFilter(s); f {
+	case "":
+		return ContentAll, nil
+	case ContentAll, ContentTemplates, ContentNonTemplates:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown content filter %q, expected one of %q, %q or %q", s, ContentAll, ContentTemplates, ContentNonTemplates)
+	}
+}
+
+func (f ContentFilter) match(name string) bool {
+	isTemplate := strings.HasPrefix(name, "templates/")
+	switch f {
+	case ContentTemplates:
+		return isTemplate
+	case ContentNonTemplates:
+		return !isTemplate
+	default:
+		return true
+	}
+}
+
+func GetChartContents(c *chartv2.Chart, recursive bool, filter ContentFilter) (string, error) {
 	sb := strings.Builder{}
-	for _, file := range c.Files {
+	for _, file := range chartFiles(c) {
+		if !filter.match(file.Name) {
+			continue
+		}
 		fmt.Fprintf(&sb, "# file: %s/%s\n", c.Name(), file.Name)
 		sb.Write(file.Data)
 		sb.WriteString("\n\n")
@@ -77,7 +165,7 @@ func GetChartContents(c *chartv2.Chart, recursive bool) (string, error) {
 	if recursive {
 		for _, subChart := range c.Dependencies() {
 			fmt.Fprintf(&sb, "# Subchart: %s\n", subChart.Name())
-			subContent, err := GetChartContents(subChart, recursive)
+			subContent, err := GetChartContents(subChart, recursive, filter)
 			if err != nil {
 				return "", fmt.Errorf("failed to get contents for subchart %s: %v", subChart.Name(), err)
 			}
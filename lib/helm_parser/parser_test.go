@@ -2,6 +2,7 @@ package helm_parser
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -94,7 +95,7 @@ func TestGetChartContents(t *testing.T) {
 	mockChart := createMockChart()
 
 	// Test without recursion
-	contents, err := GetChartContents(mockChart, false)
+	contents, err := GetChartContents(mockChart, false, ContentAll)
 	if err != nil {
 		t.Fatalf("GetChartContents(recursive=false) error = %v", err)
 	}
@@ -112,7 +113,7 @@ func TestGetChartContents(t *testing.T) {
 	mockChart.AddDependency(mockSubchart)
 
 	// Test with recursion
-	contentsRecursive, err := GetChartContents(mockChart, true)
+	contentsRecursive, err := GetChartContents(mockChart, true, ContentAll)
 	if err != nil {
 		t.Fatalf("GetChartContents(recursive=true) error = %v", err)
 	}
@@ -183,3 +184,81 @@ func TestGetChartDependencies(t *testing.T) {
 		t.Fatalf("Expected second dependency name to be 'dependency2', got '%s'", secondDep.Name)
 	}
 }
+
+func TestGetChartDependencyTree(t *testing.T) {
+	leaf := &chartv2.Chart{Metadata: &chartv2.Metadata{Name: "common", Version: "2.1.0"}}
+	database := &chartv2.Chart{Metadata: &chartv2.Metadata{
+		Name:    "postgresql",
+		Version: "15.2.3",
+		Dependencies: []*chartv2.Dependency{
+			{Name: "common", Version: "2.x.x", Repository: "oci://registry-1.docker.io/bitnamicharts", Tags: []string{"bitnami-common"}},
+		},
+	}}
+	database.SetDependencies(leaf)
+	umbrella := &chartv2.Chart{Metadata: &chartv2.Metadata{
+		Name:    "app",
+		Version: "1.0.0",
+		Dependencies: []*chartv2.Dependency{
+			{Name: "postgresql", Version: "~15.2.0", Repository: "https://charts.bitnami.com/bitnami", Condition: "postgresql.enabled", Alias: "db"},
+			{Name: "redis", Version: "18.x", Repository: "https://charts.bitnami.com/bitnami"},
+		},
+	}}
+	umbrella.SetDependencies(database)
+
+	want := []DependencyNode{
+		{
+			Name: "postgresql", Version: "~15.2.0", Repository: "https://charts.bitnami.com/bitnami",
+			Condition: "postgresql.enabled", Alias: "db", Bundled: true, ResolvedVersion: "15.2.3",
+			Children: []DependencyNode{
+				{Name: "common", Version: "2.x.x", Repository: "oci://registry-1.docker.io/bitnamicharts", Tags: []string{"bitnami-common"}, Bundled: true, ResolvedVersion: "2.1.0", Children: []DependencyNode{}},
+			},
+		},
+		{Name: "redis", Version: "18.x", Repository: "https://charts.bitnami.com/bitnami"},
+	}
+	if got := GetChartDependencyTree(umbrella); !reflect.DeepEqual(got, want) {
+		t.Fatalf("GetChartDependencyTree() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetChartContentsFilter(t *testing.T) {
+	tests := []struct {
+		filter      ContentFilter
+		wantFiles   []string
+		unwantFiles []string
+	}{
+		{filter: ContentAll, wantFiles: []string{"Chart.yaml", "values.yaml", "templates/deployment.yaml"}},
+		{filter: ContentTemplates, wantFiles: []string{"templates/deployment.yaml"}, unwantFiles: []string{"Chart.yaml", "values.yaml"}},
+		{filter: ContentNonTemplates, wantFiles: []string{"Chart.yaml", "values.yaml"}, unwantFiles: []string{"templates/deployment.yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.filter), func(t *testing.T) {
+			contents, err := GetChartContents(createMockChart(), false, tt.filter)
+			if err != nil {
+				t.Fatalf("GetChartContents() error = %v", err)
+			}
+			for _, name := range tt.wantFiles {
+				if !strings.Contains(contents, "# file: test-chart/"+name+"\n") {
+					t.Errorf("expected contents to include %s", name)
+				}
+			}
+			for _, name := range tt.unwantFiles {
+				if strings.Contains(contents, "# file: test-chart/"+name+"\n") {
+					t.Errorf("expected contents to exclude %s", name)
+				}
+			}
+		})
+	}
+}
+
+func TestParseContentFilter(t *testing.T) {
+	if f, err := ParseContentFilter(""); err != nil || f != ContentAll {
+		t.Errorf("ParseContentFilter(\"\") = %q, %v, want %q", f, err, ContentAll)
+	}
+	if f, err := ParseContentFilter("templates"); err != nil || f != ContentTemplates {
+		t.Errorf("ParseContentFilter(\"templates\") = %q, %v, want %q", f, err, ContentTemplates)
+	}
+	if _, err := ParseContentFilter("bogus"); err == nil {
+		t.Error("expected error for unknown filter")
+	}
+}
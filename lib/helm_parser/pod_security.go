@@ -0,0 +1,312 @@
+package helm_parser
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Pod Security Standards levels, from the least to the most restrictive.
+const (
+	PodSecurityPrivileged = "privileged"
+	PodSecurityBaseline   = "baseline"
+	PodSecurityRestricted = "restricted"
+)
+
+// baselineCapabilities are the capabilities the baseline level allows
+// containers to add.
+var baselineCapabilities = []string{
+	"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD", "NET_BIND_SERVICE",
+	"SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+}
+
+// safeSysctls are the sysctls the baseline level allows.
+var safeSysctls = []string{
+	"kernel.shm_rmid_forced", "net.ipv4.ip_local_port_range", "net.ipv4.ip_unprivileged_port_start",
+	"net.ipv4.tcp_syncookies", "net.ipv4.ping_group_range", "net.ipv4.ip_local_reserved_ports",
+	"net.ipv4.tcp_keepalive_time", "net.ipv4.tcp_fin_timeout", "net.ipv4.tcp_keepalive_intvl",
+	"net.ipv4.tcp_keepalive_probes",
+}
+
+// restrictedVolumeTypes are the volume types the restricted level allows.
+var restrictedVolumeTypes = []string{
+	"configMap", "csi", "downwardAPI", "emptyDir", "ephemeral", "persistentVolumeClaim", "projected", "secret",
+}
+
+// PodSecurityViolation is a field of a workload that violates a policy of
+// the Pod Security Standards.
+type PodSecurityViolation struct {
+	// Level is the level whose policy is violated: baseline or restricted.
+	Level string `json:"level"`
+	// Policy is the name of the policy in the Pod Security Standards.
+	Policy string `json:"policy"`
+	// Field is the path of the field in the workload, e.g.
+	// spec.template.spec.containers[0].securityContext.privileged.
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// WorkloadPodSecurity is the Pod Security Standards level a workload
+// satisfies.
+type WorkloadPodSecurity struct {
+	Resource
+	// Level is the most restrictive level the workload satisfies.
+	Level      string                 `json:"level"`
+	Violations []PodSecurityViolation `json:"violations"`
+}
+
+// PodSecurityReport is the result of CheckPodSecurity.
+type PodSecurityReport struct {
+	// Level is the most restrictive level all workloads satisfy, e.g. the
+	// strictest pod-security.kubernetes.io/enforce label a namespace running
+	// them may have.
+	Level     string                `json:"level"`
+	Workloads []WorkloadPodSecurity `json:"workloads"`
+}
+
+// CheckPodSecurity evaluates the workloads of a multi-document YAML manifest,
+// such as a rendered chart, against the baseline and restricted levels of the
+// Kubernetes Pod Security Standards, like the PodSecurity admission
+// controller does. Workloads are reported in manifest order with the most
+// restrictive level they satisfy and the fields violating the others.
+func CheckPodSecurity(manifest string) (*PodSecurityReport, error) {
+	objects, err := decodeManifest[map[string]any](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PodSecurityReport{Level: PodSecurityRestricted, Workloads: []WorkloadPodSecurity{}}
+	for _, obj := range objects {
+		kind, _ := obj["kind"].(string)
+		var path []string
+		switch kind {
+		case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+			path = []string{"spec", "template"}
+		case "CronJob":
+			path = []string{"spec", "jobTemplate", "spec", "template"}
+		case "Pod":
+			// The pod is its own template.
+		default:
+			continue
+		}
+		template := obj
+		if path != nil {
+			template = nestedMap(obj, path...)
+		}
+
+		apiVersion, _ := obj["apiVersion"].(string)
+		name, _ := nestedMap(obj, "metadata")["name"].(string)
+		namespace, _ := nestedMap(obj, "metadata")["namespace"].(string)
+
+		violations := checkPodTemplate(strings.Join(path, "."), template)
+		w := WorkloadPodSecurity{
+			Resource:   Resource{APIVersion: apiVersion, Kind: kind, Name: name, Namespace: namespace},
+			Level:      PodSecurityRestricted,
+			Violations: violations,
+		}
+		for _, v := range violations {
+			if v.Level == PodSecurityBaseline {
+				w.Level = PodSecurityPrivileged
+				break
+			}
+			w.Level = PodSecurityBaseline
+		}
+		report.Level = leastRestrictive(report.Level, w.Level)
+		report.Workloads = append(report.Workloads, w)
+	}
+	return report, nil
+}
+
+func leastRestrictive(a, b string) string {
+	levels := []string{PodSecurityPrivileged, PodSecurityBaseline, PodSecurityRestricted}
+	if slices.Index(levels, a) < slices.Index(levels, b) {
+		return a
+	}
+	return b
+}
+
+// podContainer is a container of a pod spec with the path of its field.
+type podContainer struct {
+	field string
+	name  string
+	spec  map[string]any
+}
+
+// checkPodTemplate returns the violations of a pod template, or a pod, at the
+// field path templateField, empty for a pod.
+func checkPodTemplate(templateField string, template map[string]any) []PodSecurityViolation {
+	metadata, spec := nestedMap(template, "metadata"), nestedMap(template, "spec")
+	prefix := fieldPath(templateField, "spec")
+	violations := []PodSecurityViolation{}
+	violate := func(level, policy, field, format string, args ...any) {
+		violations = append(violations, PodSecurityViolation{Level: level, Policy: policy, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var containers []podContainer
+	for _, key := range []string{"initContainers", "containers", "ephemeralContainers"} {
+		list, _ := spec[key].([]any)
+		for i, c := range list {
+			m, _ := c.(map[string]any)
+			name, _ := m["name"].(string)
+			containers = append(containers, podContainer{field: fmt.Sprintf("%s.%s[%d]", prefix, key, i), name: name, spec: m})
+		}
+	}
+	podSC := nestedMap(spec, "securityContext")
+	podSCField := prefix + ".securityContext"
+
+	// Baseline.
+	if nestedMap(podSC, "windowsOptions")["hostProcess"] == true {
+		violate(PodSecurityBaseline, "HostProcess", podSCField+".windowsOptions.hostProcess", "pod runs as a Windows HostProcess")
+	}
+	for _, key := range []string{"hostNetwork", "hostPID", "hostIPC"} {
+		if spec[key] == true {
+			violate(PodSecurityBaseline, "Host Namespaces", prefix+"."+key, "pod shares the %s namespace of the node", strings.TrimPrefix(key, "host"))
+		}
+	}
+	volumes, _ := spec["volumes"].([]any)
+	for i, v := range volumes {
+		m, _ := v.(map[string]any)
+		field := fmt.Sprintf("%s.volumes[%d]", prefix, i)
+		name, _ := m["name"].(string)
+		for _, key := range slices.Sorted(maps.Keys(m)) {
+			if key == "name" {
+				continue
+			}
+			if key == "hostPath" {
+				violate(PodSecurityBaseline, "HostPath Volumes", field+".hostPath", "volume %q mounts a path of the node", name)
+			} else if !slices.Contains(restrictedVolumeTypes, key) {
+				violate(PodSecurityRestricted, "Volume Types", field+"."+key, "volume %q has type %s", name, key)
+			}
+		}
+	}
+	checkSELinux(podSCField, nestedMap(podSC, "seLinuxOptions"), violate)
+	podSeccomp, _ := nestedMap(podSC, "seccompProfile")["type"].(string)
+	if podSeccomp == "Unconfined" {
+		violate(PodSecurityBaseline, "Seccomp", podSCField+".seccompProfile.type", "pod disables seccomp")
+	}
+	if nestedMap(podSC, "appArmorProfile")["type"] == "Unconfined" {
+		violate(PodSecurityBaseline, "AppArmor", podSCField+".appArmorProfile.type", "pod disables AppArmor")
+	}
+	annotations := nestedMap(metadata, "annotations")
+	for _, key := range slices.Sorted(maps.Keys(annotations)) {
+		s, _ := annotations[key].(string)
+		if strings.HasPrefix(key, "container.apparmor.security.beta.kubernetes.io/") && s != "" && s != "runtime/default" && !strings.HasPrefix(s, "localhost/") {
+			violate(PodSecurityBaseline, "AppArmor", fieldPath(templateField, "metadata.annotations."+key), "AppArmor profile %s is not allowed", s)
+		}
+	}
+	sysctls, _ := podSC["sysctls"].([]any)
+	for i, s := range sysctls {
+		m, _ := s.(map[string]any)
+		name, _ := m["name"].(string)
+		if !slices.Contains(safeSysctls, name) {
+			violate(PodSecurityBaseline, "Sysctls", fmt.Sprintf("%s.sysctls[%d].name", podSCField, i), "sysctl %s is not safe", name)
+		}
+	}
+
+	// Restricted, at the pod level.
+	podRunAsNonRoot := podSC["runAsNonRoot"] == true
+	if isZero(podSC["runAsUser"]) {
+		violate(PodSecurityRestricted, "Running as Non-root user", podSCField+".runAsUser", "pod runs as user 0 (root)")
+	}
+
+	for _, c := range containers {
+		sc := nestedMap(c.spec, "securityContext")
+		scField := c.field + ".securityContext"
+
+		// Baseline.
+		if nestedMap(sc, "windowsOptions")["hostProcess"] == true {
+			violate(PodSecurityBaseline, "HostProcess", scField+".windowsOptions.hostProcess", "container %q runs as a Windows HostProcess", c.name)
+		}
+		if sc["privileged"] == true {
+			violate(PodSecurityBaseline, "Privileged Containers", scField+".privileged", "container %q is privileged", c.name)
+		}
+		added, _ := nestedMap(sc, "capabilities")["add"].([]any)
+		for i, capability := range added {
+			name := strings.TrimPrefix(fmt.Sprint(capability), "CAP_")
+			field := fmt.Sprintf("%s.capabilities.add[%d]", scField, i)
+			switch {
+			case !slices.Contains(baselineCapabilities, name):
+				violate(PodSecurityBaseline, "Capabilities", field, "container %q adds capability %s", c.name, name)
+			case name != "NET_BIND_SERVICE":
+				violate(PodSecurityRestricted, "Capabilities", field, "container %q adds capability %s, only NET_BIND_SERVICE is allowed", c.name, name)
+			}
+		}
+		ports, _ := c.spec["ports"].([]any)
+		for i, p := range ports {
+			m, _ := p.(map[string]any)
+			if port := m["hostPort"]; port != nil && !isZero(port) {
+				violate(PodSecurityBaseline, "Host Ports", fmt.Sprintf("%s.ports[%d].hostPort", c.field, i), "container %q uses host port %v", c.name, port)
+			}
+		}
+		checkSELinux(scField, nestedMap(sc, "seLinuxOptions"), violate)
+		if procMount, ok := sc["procMount"].(string); ok && procMount != "Default" {
+			violate(PodSecurityBaseline, "/proc Mount Type", scField+".procMount", "container %q uses /proc mount type %s", c.name, procMount)
+		}
+		seccomp, _ := nestedMap(sc, "seccompProfile")["type"].(string)
+		if seccomp == "Unconfined" {
+			violate(PodSecurityBaseline, "Seccomp", scField+".seccompProfile.type", "container %q disables seccomp", c.name)
+		}
+		if nestedMap(sc, "appArmorProfile")["type"] == "Unconfined" {
+			violate(PodSecurityBaseline, "AppArmor", scField+".appArmorProfile.type", "container %q disables AppArmor", c.name)
+		}
+
+		// Restricted.
+		if sc["allowPrivilegeEscalation"] != false {
+			violate(PodSecurityRestricted, "Privilege Escalation", scField+".allowPrivilegeEscalation", "container %q does not set allowPrivilegeEscalation to false", c.name)
+		}
+		switch runAsNonRoot, set := sc["runAsNonRoot"]; {
+		case set && runAsNonRoot != true:
+			violate(PodSecurityRestricted, "Running as Non-root", scField+".runAsNonRoot", "container %q allows running as root", c.name)
+		case !set && !podRunAsNonRoot:
+			violate(PodSecurityRestricted, "Running as Non-root", scField+".runAsNonRoot", "neither container %q nor the pod set runAsNonRoot to true", c.name)
+		}
+		if isZero(sc["runAsUser"]) {
+			violate(PodSecurityRestricted, "Running as Non-root user", scField+".runAsUser", "container %q runs as user 0 (root)", c.name)
+		}
+		if seccomp == "" && podSeccomp != "RuntimeDefault" && podSeccomp != "Localhost" {
+			violate(PodSecurityRestricted, "Seccomp", scField+".seccompProfile.type", "neither container %q nor the pod set a RuntimeDefault or Localhost seccomp profile", c.name)
+		}
+		dropped, _ := nestedMap(sc, "capabilities")["drop"].([]any)
+		if !slices.ContainsFunc(dropped, func(capability any) bool { return capability == "ALL" }) {
+			violate(PodSecurityRestricted, "Capabilities", scField+".capabilities.drop", "container %q does not drop ALL capabilities", c.name)
+		}
+	}
+	return violations
+}
+
+// checkSELinux checks the SELinux options at field against the baseline
+// level, which allows only container types and no custom user or role.
+func checkSELinux(field string, options map[string]any, violate func(level, policy, field, format string, args ...any)) {
+	if t, _ := options["type"].(string); t != "" && !slices.Contains([]string{"container_t", "container_init_t", "container_kvm_t", "container_engine_t"}, t) {
+		violate(PodSecurityBaseline, "SELinux", field+".seLinuxOptions.type", "SELinux type %s is not allowed", t)
+	}
+	for _, key := range []string{"user", "role"} {
+		if v, _ := options[key].(string); v != "" {
+			violate(PodSecurityBaseline, "SELinux", field+".seLinuxOptions."+key, "custom SELinux %s %s is not allowed", key, v)
+		}
+	}
+}
+
+// fieldPath joins the path of a field to that of its parent, which is empty
+// for the root of a resource.
+func fieldPath(parent, field string) string {
+	if parent == "" {
+		return field
+	}
+	return parent + "." + field
+}
+
+// nestedMap returns the map at path in m, nil if there is none.
+func nestedMap(m map[string]any, path ...string) map[string]any {
+	for _, key := range path {
+		m, _ = m[key].(map[string]any)
+	}
+	return m
+}
+
+// isZero reports whether v is the number 0, as decoded from JSON.
+func isZero(v any) bool {
+	f, ok := v.(float64)
+	return ok && f == 0
+}
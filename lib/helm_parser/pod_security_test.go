@@ -0,0 +1,157 @@
+package helm_parser
+
+import (
+	"testing"
+)
+
+func TestCheckPodSecurity(t *testing.T) {
+	manifest := `---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: restricted
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: true
+        seccompProfile:
+          type: RuntimeDefault
+      containers:
+        - name: app
+          securityContext:
+            allowPrivilegeEscalation: false
+            capabilities:
+              drop: [ALL]
+              add: [NET_BIND_SERVICE]
+      volumes:
+        - name: config
+          configMap:
+            name: app
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: baseline
+spec:
+  template:
+    spec:
+      containers:
+        - name: db
+          securityContext:
+            capabilities:
+              add: [CHOWN]
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: privileged
+spec:
+  template:
+    metadata:
+      annotations:
+        container.apparmor.security.beta.kubernetes.io/agent: unconfined
+    spec:
+      hostNetwork: true
+      containers:
+        - name: agent
+          ports:
+            - containerPort: 9100
+              hostPort: 9100
+          securityContext:
+            privileged: true
+            capabilities:
+              add: [SYS_ADMIN]
+      volumes:
+        - name: root
+          hostPath:
+            path: /
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test
+spec:
+  securityContext:
+    runAsUser: 0
+    sysctls:
+      - name: kernel.msgmax
+        value: "65536"
+  containers:
+    - name: test
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+	report, err := CheckPodSecurity(manifest)
+	if err != nil {
+		t.Fatalf("CheckPodSecurity() error = %v", err)
+	}
+	if report.Level != PodSecurityPrivileged {
+		t.Errorf("report level = %s, want privileged", report.Level)
+	}
+	if len(report.Workloads) != 4 {
+		t.Fatalf("got %d workloads, want 4: %+v", len(report.Workloads), report.Workloads)
+	}
+
+	fields := func(w WorkloadPodSecurity) map[string]string {
+		m := map[string]string{}
+		for _, v := range w.Violations {
+			m[v.Field] = v.Level
+		}
+		return m
+	}
+
+	if w := report.Workloads[0]; w.Level != PodSecurityRestricted || len(w.Violations) != 0 {
+		t.Errorf("restricted workload = %+v, want no violations", w)
+	}
+
+	baseline := report.Workloads[1]
+	if baseline.Level != PodSecurityBaseline {
+		t.Errorf("baseline workload level = %s", baseline.Level)
+	}
+	wantBaseline := map[string]string{
+		"spec.template.spec.containers[0].securityContext.capabilities.add[0]":      PodSecurityRestricted,
+		"spec.template.spec.containers[0].securityContext.allowPrivilegeEscalation": PodSecurityRestricted,
+		"spec.template.spec.containers[0].securityContext.runAsNonRoot":             PodSecurityRestricted,
+		"spec.template.spec.containers[0].securityContext.seccompProfile.type":      PodSecurityRestricted,
+		"spec.template.spec.containers[0].securityContext.capabilities.drop":        PodSecurityRestricted,
+	}
+	if got := fields(baseline); len(got) != len(wantBaseline) {
+		t.Errorf("baseline violations = %v, want %v", got, wantBaseline)
+	} else {
+		for field, level := range wantBaseline {
+			if got[field] != level {
+				t.Errorf("baseline %s = %q, want %s", field, got[field], level)
+			}
+		}
+	}
+
+	privileged := report.Workloads[2]
+	if privileged.Level != PodSecurityPrivileged {
+		t.Errorf("privileged workload level = %s", privileged.Level)
+	}
+	got := fields(privileged)
+	for _, field := range []string{
+		"spec.template.spec.hostNetwork",
+		"spec.template.spec.volumes[0].hostPath",
+		"spec.template.spec.containers[0].securityContext.privileged",
+		"spec.template.spec.containers[0].securityContext.capabilities.add[0]",
+		"spec.template.spec.containers[0].ports[0].hostPort",
+		"spec.template.metadata.annotations.container.apparmor.security.beta.kubernetes.io/agent",
+	} {
+		if got[field] != PodSecurityBaseline {
+			t.Errorf("privileged %s = %q, want a baseline violation", field, got[field])
+		}
+	}
+
+	pod := report.Workloads[3]
+	got = fields(pod)
+	if pod.Level != PodSecurityPrivileged || got["spec.securityContext.sysctls[0].name"] != PodSecurityBaseline || got["spec.securityContext.runAsUser"] != PodSecurityRestricted {
+		t.Errorf("pod = %+v, want the unsafe sysctl and the root user reported", pod)
+	}
+}
@@ -0,0 +1,107 @@
+package helm_parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+)
+
+// Patch is a kustomize patch applied to the rendered manifests, like a
+// kustomize post-renderer. Patch is either a strategic merge patch, which
+// selects the resource to patch by its apiVersion, kind and name unless
+// Target is set, or a JSON 6902 patch, which requires Target.
+type Patch struct {
+	Patch  string       `json:"patch"`
+	Target *PatchTarget `json:"target,omitempty"`
+}
+
+// PatchTarget selects the resources a Patch applies to. Empty fields match
+// any resource; Name and Namespace may be regular expressions.
+type PatchTarget struct {
+	Group              string `json:"group,omitempty"`
+	Version            string `json:"version,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+	Name               string `json:"name,omitempty"`
+	Namespace          string `json:"namespace,omitempty"`
+	LabelSelector      string `json:"labelSelector,omitempty"`
+	AnnotationSelector string `json:"annotationSelector,omitempty"`
+}
+
+// applyPatches applies patches to the resources of manifests with kustomize
+// and returns the patched resources as a single manifest. Documents that are
+// not Kubernetes resources are dropped, and resources rendered more than
+// once, e.g. by a chart and again by its subchart, are kept once.
+func applyPatches(manifests []string, patches []Patch) ([]string, error) {
+	var resources []string
+	seen := make(map[string]bool)
+	for _, manifest := range manifests {
+		for _, doc := range strings.Split("\n"+manifest, "\n---") {
+			var obj struct {
+				APIVersion string `yaml:"apiVersion"`
+				Kind       string `yaml:"kind"`
+				Metadata   struct {
+					Name      string `yaml:"name"`
+					Namespace string `yaml:"namespace"`
+				} `yaml:"metadata"`
+			}
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj.APIVersion == "" || obj.Kind == "" {
+				continue
+			}
+			id := strings.Join([]string{obj.APIVersion, obj.Kind, obj.Metadata.Namespace, obj.Metadata.Name}, "/")
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			resources = append(resources, doc)
+		}
+	}
+
+	if len(resources) == 0 {
+		return nil, nil
+	}
+
+	kustomization := types.Kustomization{Resources: []string{"manifests.yaml"}}
+	for _, p := range patches {
+		patch := types.Patch{Patch: p.Patch}
+		if t := p.Target; t != nil {
+			patch.Target = &types.Selector{
+				ResId: resid.ResId{
+					Gvk:       resid.Gvk{Group: t.Group, Version: t.Version, Kind: t.Kind},
+					Name:      t.Name,
+					Namespace: t.Namespace,
+				},
+				LabelSelector:      t.LabelSelector,
+				AnnotationSelector: t.AnnotationSelector,
+			}
+		}
+		kustomization.Patches = append(kustomization.Patches, patch)
+	}
+	// JSON is valid YAML.
+	encoded, err := json.Marshal(kustomization)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := filesys.MakeFsInMemory()
+	if err := fs.WriteFile("/render/manifests.yaml", []byte(strings.Join(resources, "\n---"))); err != nil {
+		return nil, err
+	}
+	if err := fs.WriteFile("/render/kustomization.yaml", encoded); err != nil {
+		return nil, err
+	}
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fs, "/render")
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply patches: %v", err)
+	}
+	patched, err := resMap.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply patches: %v", err)
+	}
+	return []string{string(patched)}, nil
+}
@@ -0,0 +1,76 @@
+package helm_parser
+
+import (
+	"testing"
+
+	"helm.sh/helm/v4/pkg/chart/common"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestGetChartImagesPatches(t *testing.T) {
+	chart := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "app", Version: "1.0.0"},
+		Templates: []*common.File{
+			{Name: "templates/deployment.yaml", Data: []byte(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: app:1.0
+        - name: proxy
+          image: envoy:1.30
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+spec:
+  template:
+    spec:
+      containers:
+        - name: migrate
+          image: app:1.0
+`)},
+			{Name: "templates/NOTES.txt", Data: []byte("Thank you for installing {{ .Chart.Name }}.")},
+		},
+	}
+
+	opts := RenderOptions{Patches: []Patch{
+		{Patch: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: mirror.example.com/app:1.0
+`},
+		{
+			Patch:  `[{"op": "replace", "path": "/spec/template/spec/containers/0/image", "value": "mirror.example.com/app:1.0"}]`,
+			Target: &PatchTarget{Kind: "Job"},
+		},
+		{
+			Patch:  `[{"op": "remove", "path": "/spec/template/spec/containers/1"}]`,
+			Target: &PatchTarget{Group: "apps", Kind: "Deployment", Name: "app"},
+		},
+	}}
+	images, err := GetChartImages(chart, nil, true, ImagesRendered, opts)
+	if err != nil {
+		t.Fatalf("GetChartImages() error = %v", err)
+	}
+	if len(images) != 1 || images[0].FullImage != "mirror.example.com/app:1.0" || images[0].Source != "Deployment/app, Job/migrate" {
+		t.Errorf("GetChartImages() = %+v, want only the patched image", images)
+	}
+
+	opts.Patches = []Patch{{Patch: `[{"op": "remove", "path": "/spec"}]`}}
+	if _, err := GetChartImages(chart, nil, false, ImagesRendered, opts); err == nil {
+		t.Error("expected an error for a JSON 6902 patch without target")
+	}
+}
@@ -0,0 +1,209 @@
+package helm_parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v4/pkg/chart/common/util"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+)
+
+// ImageRelocation describes how to pull the images of a chart from a private
+// registry, see RelocateImages.
+type ImageRelocation struct {
+	// Values are the values overrides repointing the images, to be passed
+	// to helm install like a values file.
+	Values map[string]any `json:"values"`
+	// Overrides are Values as dotted paths, e.g. for helm --set.
+	Overrides []ValueOverride `json:"overrides"`
+	// Mappings are the images to copy to the registry.
+	Mappings []ImageMapping `json:"mappings"`
+	// Unrelocated are the images still pulled from their original registry
+	// with Values, e.g. images hardcoded in templates, which need manual
+	// overrides.
+	Unrelocated []ImageReference `json:"unrelocated"`
+}
+
+// ValueOverride is a single value of an ImageRelocation.
+type ValueOverride struct {
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+// ImageMapping maps an image of a chart to its copy in a private registry.
+type ImageMapping struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+// RelocateImages computes the values overrides that make chart pull its
+// images from below prefix, a registry host optionally followed by a path,
+// e.g. "registry.internal/mirror". Images keep their repository and tag, e.g.
+// docker.io/bitnami/redis:7.2 becomes registry.internal/mirror/bitnami/redis:7.2.
+//
+// global.imageRegistry is set if the chart supports it, as Bitnami charts
+// do. Image maps in the values get prefix as registry if they have a
+// registry key, or a repository including prefix otherwise; image strings
+// are replaced. The chart is rendered with the overrides to report images
+// that are not relocated. customValues, recursive and opts are used as by
+// GetChartImages.
+func RelocateImages(chart *chartv2.Chart, customValues map[string]any, prefix string, recursive bool, opts RenderOptions) (*ImageRelocation, error) {
+	prefix, err := ParseRegistryPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := GetChartImages(chart, customValues, recursive, ImagesAll, opts)
+	if err != nil {
+		return nil, err
+	}
+	values, err := util.CoalesceValues(chart, customValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge values: %v", err)
+	}
+
+	overrides := make(map[string]string)
+	if global, ok := asValuesMap(values["global"]); ok {
+		if _, ok := global["imageRegistry"]; ok {
+			overrides["global.imageRegistry"] = prefix
+		}
+	}
+	relocateValuesImages(chart, values, "", recursive, prefix, overrides)
+
+	relocation := &ImageRelocation{
+		Values:      map[string]any{},
+		Overrides:   make([]ValueOverride, 0, len(overrides)),
+		Mappings:    make([]ImageMapping, 0, len(images)),
+		Unrelocated: []ImageReference{},
+	}
+	for path, value := range overrides {
+		relocation.Overrides = append(relocation.Overrides, ValueOverride{Path: path, Value: value})
+		setValue(relocation.Values, strings.Split(path, "."), value)
+	}
+	sort.Slice(relocation.Overrides, func(i, j int) bool { return relocation.Overrides[i].Path < relocation.Overrides[j].Path })
+	for _, image := range images {
+		relocation.Mappings = append(relocation.Mappings, ImageMapping{Source: image.FullImage, Destination: RelocatedImage(image, prefix)})
+	}
+
+	relocated, err := GetChartImages(chart, loader.MergeMaps(copyValuesMap(customValues), relocation.Values), recursive, ImagesAll, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the chart with the relocated images: %v", err)
+	}
+	for _, image := range relocated {
+		if !strings.HasPrefix(image.FullImage, prefix+"/") {
+			relocation.Unrelocated = append(relocation.Unrelocated, image)
+		}
+	}
+	return relocation, nil
+}
+
+// relocateValuesImages adds the overrides relocating the image references in
+// values to overrides, keyed by dotted path. It walks the values like
+// valuesImages; images in lists are skipped, as lists cannot be overridden
+// partially.
+func relocateValuesImages(chart *chartv2.Chart, values map[string]any, prefix string, recursive bool, registry string, overrides map[string]string) {
+	subCharts := make(map[string]*chartv2.Chart)
+	for _, sub := range chart.Dependencies() {
+		subCharts[sub.Name()] = sub
+	}
+	appVersion := ""
+	if chart.Metadata != nil {
+		appVersion = chart.Metadata.AppVersion
+	}
+
+	for key, value := range values {
+		if sub, ok := subCharts[key]; ok {
+			if subValues, ok := asValuesMap(value); ok && recursive {
+				relocateValuesImages(sub, subValues, prefix+key+".", recursive, registry, overrides)
+			}
+			continue
+		}
+		relocateValuesImage(key, value, prefix+key, appVersion, registry, overrides)
+	}
+}
+
+func relocateValuesImage(key string, value any, path, appVersion, registry string, overrides map[string]string) {
+	if s, ok := value.(string); ok {
+		if isImageKey(key) && s != "" && !strings.ContainsAny(s, " \t\n{}") {
+			overrides[path] = RelocatedImage(parseImage(s), registry)
+		}
+		return
+	}
+
+	m, ok := asValuesMap(value)
+	if !ok {
+		return
+	}
+	if image, ok := imageFromValues(key, m, appVersion); ok {
+		ref := parseImage(image)
+		repositoryKey := "repository"
+		if _, ok := m["repository"]; !ok {
+			repositoryKey = "name"
+		}
+		if _, ok := m["registry"]; ok {
+			overrides[path+".registry"] = registry
+			// The repository may include a registry, e.g. quay.io/org/app.
+			if repository, _ := m[repositoryKey].(string); repository != ref.Repository && !strings.Contains(repository, ":") {
+				overrides[path+"."+repositoryKey] = ref.Repository
+			}
+		} else {
+			overrides[path+"."+repositoryKey] = registry + "/" + ref.Repository
+		}
+		return
+	}
+	for k, v := range m {
+		relocateValuesImage(k, v, path+"."+k, appVersion, registry, overrides)
+	}
+}
+
+// ParseRegistryPrefix validates a registry prefix, a registry host optionally
+// followed by a path, e.g. "registry.internal/mirror", and trims trailing
+// slashes.
+func ParseRegistryPrefix(prefix string) (string, error) {
+	prefix = strings.TrimRight(strings.TrimSpace(prefix), "/")
+	if prefix == "" || strings.Contains(prefix, "://") {
+		return "", fmt.Errorf("invalid registry prefix %q: expected a registry host with an optional path, e.g. registry.internal/mirror", prefix)
+	}
+	return prefix, nil
+}
+
+// RelocatedImage returns image below registry, keeping its repository, tag
+// and digest.
+func RelocatedImage(image ImageReference, registry string) string {
+	relocated := registry + "/" + image.Repository
+	if image.Tag != "" {
+		relocated += ":" + image.Tag
+	}
+	if image.Digest != "" {
+		relocated += "@" + image.Digest
+	}
+	return relocated
+}
+
+// setValue sets the nested value at path in values.
+func setValue(values map[string]any, path []string, value any) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := values[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			values[key] = next
+		}
+		values = next
+	}
+	values[path[len(path)-1]] = value
+}
+
+// copyValuesMap returns a deep copy of the nested maps of values, so merging
+// into it does not modify values.
+func copyValuesMap(values map[string]any) map[string]any {
+	cp := make(map[string]any, len(values))
+	for k, v := range values {
+		if m, ok := asValuesMap(v); ok {
+			v = copyValuesMap(m)
+		}
+		cp[k] = v
+	}
+	return cp
+}
@@ -0,0 +1,96 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/chart/common"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestRelocateImages(t *testing.T) {
+	chart := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "app", Version: "1.0.0", AppVersion: "2.3.0"},
+		Templates: []*common.File{
+			{Name: "templates/deployment.yaml", Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          image: {{ .Values.initImage }}
+        - name: wait
+          image: busybox:1.36
+      containers:
+        - name: app
+          image: "{{ .Values.global.imageRegistry | default .Values.image.registry }}/{{ .Values.image.repository }}:{{ .Values.image.tag }}"
+        - name: exporter
+          image: "{{ .Values.exporter.image.repository }}:{{ .Values.exporter.image.tag }}"
+        - name: proxy
+          image: "{{ .Values.proxy.image.registry }}/{{ .Values.proxy.image.repository }}:{{ .Values.proxy.image.tag }}"
+`)},
+		},
+		Values: map[string]any{
+			"global":    map[string]any{"imageRegistry": ""},
+			"image":     map[string]any{"registry": "docker.io", "repository": "bitnami/app", "tag": "2.3.0"},
+			"exporter":  map[string]any{"image": map[string]any{"repository": "quay.io/prometheus/exporter", "tag": "1.5"}},
+			"proxy":     map[string]any{"image": map[string]any{"registry": "", "repository": "ghcr.io/org/proxy", "tag": "0.9"}},
+			"initImage": "alpine:3.20",
+			"sidecars":  []any{map[string]any{"image": "envoyproxy/envoy:v1.30"}},
+		},
+	}
+
+	relocation, err := RelocateImages(chart, nil, "registry.internal/mirror/", false, RenderOptions{})
+	if err != nil {
+		t.Fatalf("RelocateImages() error = %v", err)
+	}
+
+	wantOverrides := []ValueOverride{
+		{Path: "exporter.image.repository", Value: "registry.internal/mirror/prometheus/exporter"},
+		{Path: "global.imageRegistry", Value: "registry.internal/mirror"},
+		{Path: "image.registry", Value: "registry.internal/mirror"},
+		{Path: "initImage", Value: "registry.internal/mirror/library/alpine:3.20"},
+		{Path: "proxy.image.registry", Value: "registry.internal/mirror"},
+		{Path: "proxy.image.repository", Value: "org/proxy"},
+	}
+	if !reflect.DeepEqual(relocation.Overrides, wantOverrides) {
+		t.Errorf("Overrides = %+v, want %+v", relocation.Overrides, wantOverrides)
+	}
+	if got := relocation.Values["proxy"]; !reflect.DeepEqual(got, map[string]any{"image": map[string]any{"registry": "registry.internal/mirror", "repository": "org/proxy"}}) {
+		t.Errorf("Values[proxy] = %v", got)
+	}
+
+	mappings := make(map[string]string)
+	for _, m := range relocation.Mappings {
+		mappings[m.Source] = m.Destination
+	}
+	for source, destination := range map[string]string{
+		"docker.io/bitnami/app:2.3.0":     "registry.internal/mirror/bitnami/app:2.3.0",
+		"quay.io/prometheus/exporter:1.5": "registry.internal/mirror/prometheus/exporter:1.5",
+		"alpine:3.20":                     "registry.internal/mirror/library/alpine:3.20",
+		"busybox:1.36":                    "registry.internal/mirror/library/busybox:1.36",
+		"envoyproxy/envoy:v1.30":          "registry.internal/mirror/envoyproxy/envoy:v1.30",
+	} {
+		if mappings[source] != destination {
+			t.Errorf("mapping of %s = %q, want %q", source, mappings[source], destination)
+		}
+	}
+
+	var unrelocated []string
+	for _, image := range relocation.Unrelocated {
+		unrelocated = append(unrelocated, image.FullImage)
+	}
+	// The hardcoded image and the image in a list need manual overrides.
+	if want := []string{"busybox:1.36", "envoyproxy/envoy:v1.30"}; !reflect.DeepEqual(unrelocated, want) {
+		t.Errorf("Unrelocated = %v, want %v", unrelocated, want)
+	}
+
+	for _, prefix := range []string{"", "https://registry.internal"} {
+		if _, err := RelocateImages(chart, nil, prefix, false, RenderOptions{}); err == nil {
+			t.Errorf("RelocateImages(%q) expected an error", prefix)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package helm_parser
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// RenderManifest renders chart with customValues like helm template and
+// returns the manifest: the rendered templates of the chart and its
+// subcharts ordered by path, each preceded by a "# Source:" comment, without
+// NOTES.txt. Patches of opts are applied to the rendered resources.
+func RenderManifest(chart *chartv2.Chart, customValues map[string]any, opts RenderOptions) (string, error) {
+	rendered, err := renderTemplates(chart, customValues, opts)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(rendered))
+	for name, content := range rendered {
+		if path.Base(name) == "NOTES.txt" || strings.TrimSpace(content) == "" {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifests := make([]string, 0, len(names))
+	for _, name := range names {
+		manifests = append(manifests, "# Source: "+name+"\n"+strings.Trim(rendered[name], "\n")+"\n")
+	}
+	if len(opts.Patches) > 0 {
+		if manifests, err = applyPatches(manifests, opts.Patches); err != nil {
+			return "", err
+		}
+	}
+
+	var sb strings.Builder
+	for _, manifest := range manifests {
+		sb.WriteString("---\n")
+		sb.WriteString(strings.TrimPrefix(manifest, "---\n"))
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,30 @@
+package helm_parser
+
+import (
+	"testing"
+
+	"helm.sh/helm/v4/pkg/chart/common"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestRenderManifest(t *testing.T) {
+	chart := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "app", Version: "1.0.0"},
+		Templates: []*common.File{
+			{Name: "templates/service.yaml", Data: []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}\n")},
+			{Name: "templates/configmap.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\n")},
+			{Name: "templates/empty.yaml", Data: []byte("{{- if false }}\nkind: Secret\n{{- end }}\n")},
+			{Name: "templates/NOTES.txt", Data: []byte("Installed {{ .Release.Name }}")},
+		},
+	}
+
+	manifest, err := RenderManifest(chart, nil, RenderOptions{ReleaseName: "demo"})
+	if err != nil {
+		t.Fatalf("RenderManifest() error = %v", err)
+	}
+	want := "---\n# Source: app/templates/configmap.yaml\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: demo\n" +
+		"---\n# Source: app/templates/service.yaml\napiVersion: v1\nkind: Service\nmetadata:\n  name: demo\n"
+	if manifest != want {
+		t.Errorf("RenderManifest() = %q, want %q", manifest, want)
+	}
+}
@@ -0,0 +1,71 @@
+package helm_parser
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// Resource identifies a Kubernetes resource in a manifest.
+type Resource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	// Namespace is empty for resources without a namespace in the manifest.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ParseManifestResources returns the resources of a multi-document YAML
+// manifest, such as the manifest of a deployed release, in manifest order.
+// Empty documents are skipped.
+func ParseManifestResources(manifest string) ([]Resource, error) {
+	var resources []Resource
+	for _, doc := range strings.Split(manifest, "\n---") {
+		doc = stripComments(strings.TrimPrefix(doc, "---"))
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+
+		var meta struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+			Metadata   struct {
+				Name      string `yaml:"name"`
+				Namespace string `yaml:"namespace"`
+			} `yaml:"metadata"`
+		}
+		if err := yaml.Unmarshal([]byte(doc), &meta); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %v", err)
+		}
+		if meta.Kind == "" {
+			continue
+		}
+		resources = append(resources, Resource{
+			APIVersion: meta.APIVersion,
+			Kind:       meta.Kind,
+			Name:       meta.Metadata.Name,
+			Namespace:  meta.Metadata.Namespace,
+		})
+	}
+	return resources, nil
+}
+
+// decodeManifest decodes the documents of a multi-document YAML manifest into
+// values of T, in manifest order. Empty documents are skipped.
+func decodeManifest[T any](manifest string) ([]T, error) {
+	var values []T
+	for _, doc := range strings.Split(manifest, "\n---") {
+		doc = stripComments(strings.TrimPrefix(doc, "---"))
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		var v T
+		if err := k8syaml.Unmarshal([]byte(doc), &v); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %v", err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
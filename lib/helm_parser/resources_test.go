@@ -0,0 +1,40 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseManifestResources(t *testing.T) {
+	manifest := `---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+# Source: app/templates/empty.yaml
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: apps
+`
+	got, err := ParseManifestResources(manifest)
+	if err != nil {
+		t.Fatalf("ParseManifestResources: %v", err)
+	}
+	want := []Resource{
+		{APIVersion: "v1", Kind: "Service", Name: "web"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "apps"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := ParseManifestResources("kind: [unclosed"); err == nil {
+		t.Fatal("expected an error for invalid YAML")
+	}
+}
@@ -0,0 +1,272 @@
+package helm_parser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WorkloadScheduling summarizes where a workload may be scheduled.
+type WorkloadScheduling struct {
+	Resource
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// NodeAffinity, PodAffinity and PodAntiAffinity are the affinity terms,
+	// prefixed by required or preferred with their weight.
+	NodeAffinity    []string `json:"nodeAffinity,omitempty"`
+	PodAffinity     []string `json:"podAffinity,omitempty"`
+	PodAntiAffinity []string `json:"podAntiAffinity,omitempty"`
+	// Tolerations are the taints the pods tolerate, as key=value:effect.
+	Tolerations       []string `json:"tolerations,omitempty"`
+	PriorityClassName string   `json:"priorityClassName,omitempty"`
+	RuntimeClassName  string   `json:"runtimeClassName,omitempty"`
+	SchedulerName     string   `json:"schedulerName,omitempty"`
+	// Pinned reports that the pods only run on some nodes, selected by a node
+	// selector or a required node affinity.
+	Pinned bool `json:"pinned"`
+}
+
+// SchedulingReport is the result of SummarizeScheduling.
+type SchedulingReport struct {
+	Workloads []WorkloadScheduling `json:"workloads"`
+	// PinnedWorkloads are the workloads only running on some nodes, as
+	// kind/name.
+	PinnedWorkloads []string `json:"pinnedWorkloads"`
+	// NodeLabels are the node labels and expressions the workloads require,
+	// sorted.
+	NodeLabels []string `json:"nodeLabels"`
+	// Tolerations, PriorityClasses and RuntimeClasses are those of all
+	// workloads, sorted.
+	Tolerations     []string `json:"tolerations"`
+	PriorityClasses []string `json:"priorityClasses"`
+	RuntimeClasses  []string `json:"runtimeClasses"`
+}
+
+type schedulingSelectorRequirement struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+type schedulingNodeSelectorTerm struct {
+	MatchExpressions []schedulingSelectorRequirement `json:"matchExpressions"`
+	MatchFields      []schedulingSelectorRequirement `json:"matchFields"`
+}
+
+type schedulingPodAffinityTerm struct {
+	LabelSelector *struct {
+		MatchLabels      map[string]string               `json:"matchLabels"`
+		MatchExpressions []schedulingSelectorRequirement `json:"matchExpressions"`
+	} `json:"labelSelector"`
+	TopologyKey string `json:"topologyKey"`
+}
+
+type schedulingPodAffinity struct {
+	Required  []schedulingPodAffinityTerm `json:"requiredDuringSchedulingIgnoredDuringExecution"`
+	Preferred []struct {
+		Weight          int                       `json:"weight"`
+		PodAffinityTerm schedulingPodAffinityTerm `json:"podAffinityTerm"`
+	} `json:"preferredDuringSchedulingIgnoredDuringExecution"`
+}
+
+type schedulingPodSpec struct {
+	NodeSelector map[string]string `json:"nodeSelector"`
+	Affinity     struct {
+		NodeAffinity struct {
+			Required *struct {
+				NodeSelectorTerms []schedulingNodeSelectorTerm `json:"nodeSelectorTerms"`
+			} `json:"requiredDuringSchedulingIgnoredDuringExecution"`
+			Preferred []struct {
+				Weight     int                        `json:"weight"`
+				Preference schedulingNodeSelectorTerm `json:"preference"`
+			} `json:"preferredDuringSchedulingIgnoredDuringExecution"`
+		} `json:"nodeAffinity"`
+		PodAffinity     schedulingPodAffinity `json:"podAffinity"`
+		PodAntiAffinity schedulingPodAffinity `json:"podAntiAffinity"`
+	} `json:"affinity"`
+	Tolerations []struct {
+		Key               string `json:"key"`
+		Operator          string `json:"operator"`
+		Value             string `json:"value"`
+		Effect            string `json:"effect"`
+		TolerationSeconds *int   `json:"tolerationSeconds"`
+	} `json:"tolerations"`
+	PriorityClassName string `json:"priorityClassName"`
+	RuntimeClassName  string `json:"runtimeClassName"`
+	SchedulerName     string `json:"schedulerName"`
+}
+
+// schedulingResource holds the fields of the workloads SummarizeScheduling
+// looks at. The pod spec is embedded for Pods.
+type schedulingResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		schedulingPodSpec
+		Template struct {
+			Spec schedulingPodSpec `json:"spec"`
+		} `json:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template struct {
+					Spec schedulingPodSpec `json:"spec"`
+				} `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+	} `json:"spec"`
+}
+
+// podSpec returns the pod spec of a workload, false for other resources.
+func (r schedulingResource) podSpec() (schedulingPodSpec, bool) {
+	switch r.Kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		return r.Spec.Template.Spec, true
+	case "CronJob":
+		return r.Spec.JobTemplate.Spec.Template.Spec, true
+	case "Pod":
+		return r.Spec.schedulingPodSpec, true
+	}
+	return schedulingPodSpec{}, false
+}
+
+// SummarizeScheduling reports for the workloads of a multi-document YAML
+// manifest, such as a rendered chart, their node selectors, affinities,
+// tolerations, priority classes, runtime classes and schedulers, in manifest
+// order, and which workloads only run on nodes with specific labels.
+func SummarizeScheduling(manifest string) (*SchedulingReport, error) {
+	resources, err := decodeManifest[schedulingResource](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SchedulingReport{
+		Workloads:       []WorkloadScheduling{},
+		PinnedWorkloads: []string{},
+		NodeLabels:      []string{},
+		Tolerations:     []string{},
+		PriorityClasses: []string{},
+		RuntimeClasses:  []string{},
+	}
+	for _, r := range resources {
+		spec, ok := r.podSpec()
+		if !ok {
+			continue
+		}
+		w := WorkloadScheduling{
+			Resource:          Resource{APIVersion: r.APIVersion, Kind: r.Kind, Name: r.Metadata.Name, Namespace: r.Metadata.Namespace},
+			NodeSelector:      spec.NodeSelector,
+			PriorityClassName: spec.PriorityClassName,
+			RuntimeClassName:  spec.RuntimeClassName,
+			SchedulerName:     spec.SchedulerName,
+			Pinned:            len(spec.NodeSelector) > 0,
+		}
+		for k, v := range spec.NodeSelector {
+			report.NodeLabels = append(report.NodeLabels, k+"="+v)
+		}
+
+		nodeAffinity := spec.Affinity.NodeAffinity
+		if nodeAffinity.Required != nil {
+			for _, term := range nodeAffinity.Required.NodeSelectorTerms {
+				expr := nodeSelectorTerm(term)
+				w.NodeAffinity = append(w.NodeAffinity, "required: "+expr)
+				report.NodeLabels = append(report.NodeLabels, expr)
+				w.Pinned = true
+			}
+		}
+		for _, p := range nodeAffinity.Preferred {
+			w.NodeAffinity = append(w.NodeAffinity, fmt.Sprintf("preferred (weight %d): %s", p.Weight, nodeSelectorTerm(p.Preference)))
+		}
+		w.PodAffinity = podAffinityTerms(spec.Affinity.PodAffinity)
+		w.PodAntiAffinity = podAffinityTerms(spec.Affinity.PodAntiAffinity)
+
+		for _, t := range spec.Tolerations {
+			toleration := t.Key
+			switch {
+			case t.Key == "" && t.Operator == "Exists":
+				toleration = "*"
+			case t.Operator != "Exists":
+				toleration += "=" + t.Value
+			}
+			if t.Effect != "" {
+				toleration += ":" + t.Effect
+			}
+			if t.TolerationSeconds != nil {
+				toleration += fmt.Sprintf(" for %ds", *t.TolerationSeconds)
+			}
+			w.Tolerations = append(w.Tolerations, toleration)
+		}
+		report.Tolerations = append(report.Tolerations, w.Tolerations...)
+
+		if w.Pinned {
+			report.PinnedWorkloads = append(report.PinnedWorkloads, r.Kind+"/"+r.Metadata.Name)
+		}
+		if w.PriorityClassName != "" {
+			report.PriorityClasses = append(report.PriorityClasses, w.PriorityClassName)
+		}
+		if w.RuntimeClassName != "" {
+			report.RuntimeClasses = append(report.RuntimeClasses, w.RuntimeClassName)
+		}
+		report.Workloads = append(report.Workloads, w)
+	}
+
+	for _, list := range []*[]string{&report.NodeLabels, &report.Tolerations, &report.PriorityClasses, &report.RuntimeClasses} {
+		slices.Sort(*list)
+		*list = slices.Compact(*list)
+	}
+	return report, nil
+}
+
+// nodeSelectorTerm formats the requirements of a node selector term, which
+// must all be met, e.g. "kubernetes.io/arch In amd64,arm64".
+func nodeSelectorTerm(term schedulingNodeSelectorTerm) string {
+	var requirements []string
+	for _, r := range append(term.MatchExpressions, term.MatchFields...) {
+		requirements = append(requirements, selectorRequirement(r))
+	}
+	return strings.Join(requirements, " && ")
+}
+
+func selectorRequirement(r schedulingSelectorRequirement) string {
+	s := r.Key + " " + r.Operator
+	if len(r.Values) > 0 {
+		s += " " + strings.Join(r.Values, ",")
+	}
+	return s
+}
+
+// podAffinityTerms formats the terms of a pod affinity or anti-affinity as
+// the selected pods and the topology key, e.g.
+// "required: app=web per kubernetes.io/hostname".
+func podAffinityTerms(affinity schedulingPodAffinity) []string {
+	var terms []string
+	for _, t := range affinity.Required {
+		terms = append(terms, "required: "+podAffinityTerm(t))
+	}
+	for _, p := range affinity.Preferred {
+		terms = append(terms, fmt.Sprintf("preferred (weight %d): %s", p.Weight, podAffinityTerm(p.PodAffinityTerm)))
+	}
+	return terms
+}
+
+func podAffinityTerm(t schedulingPodAffinityTerm) string {
+	// A missing selector matches no pods, an empty one all pods.
+	if t.LabelSelector == nil {
+		return "no pods per " + t.TopologyKey
+	}
+	var selector []string
+	for k, v := range t.LabelSelector.MatchLabels {
+		selector = append(selector, k+"="+v)
+	}
+	slices.Sort(selector)
+	for _, r := range t.LabelSelector.MatchExpressions {
+		selector = append(selector, selectorRequirement(r))
+	}
+	pods := "all pods"
+	if len(selector) > 0 {
+		pods = strings.Join(selector, " && ")
+	}
+	return pods + " per " + t.TopologyKey
+}
@@ -0,0 +1,109 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarizeScheduling(t *testing.T) {
+	manifest := `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: gpu-worker
+spec:
+  template:
+    spec:
+      nodeSelector:
+        pool: gpu
+      priorityClassName: high
+      runtimeClassName: nvidia
+      tolerations:
+        - key: nvidia.com/gpu
+          operator: Exists
+          effect: NoSchedule
+        - key: node.kubernetes.io/unreachable
+          operator: Equal
+          value: "true"
+          effect: NoExecute
+          tolerationSeconds: 30
+      affinity:
+        nodeAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution:
+            nodeSelectorTerms:
+              - matchExpressions:
+                  - key: kubernetes.io/arch
+                    operator: In
+                    values: [amd64]
+                  - key: spot
+                    operator: DoesNotExist
+          preferredDuringSchedulingIgnoredDuringExecution:
+            - weight: 10
+              preference:
+                matchExpressions:
+                  - key: zone
+                    operator: In
+                    values: [a, b]
+        podAntiAffinity:
+          preferredDuringSchedulingIgnoredDuringExecution:
+            - weight: 100
+              podAffinityTerm:
+                topologyKey: kubernetes.io/hostname
+                labelSelector:
+                  matchLabels:
+                    app: gpu-worker
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    spec:
+      tolerations:
+        - operator: Exists
+      affinity:
+        podAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution:
+            - topologyKey: zone
+              labelSelector: {}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+
+	report, err := SummarizeScheduling(manifest)
+	if err != nil {
+		t.Fatalf("SummarizeScheduling() error = %v", err)
+	}
+
+	want := &SchedulingReport{
+		Workloads: []WorkloadScheduling{
+			{
+				Resource:          Resource{APIVersion: "apps/v1", Kind: "Deployment", Name: "gpu-worker"},
+				NodeSelector:      map[string]string{"pool": "gpu"},
+				NodeAffinity:      []string{"required: kubernetes.io/arch In amd64 && spot DoesNotExist", "preferred (weight 10): zone In a,b"},
+				PodAntiAffinity:   []string{"preferred (weight 100): app=gpu-worker per kubernetes.io/hostname"},
+				Tolerations:       []string{"nvidia.com/gpu:NoSchedule", "node.kubernetes.io/unreachable=true:NoExecute for 30s"},
+				PriorityClassName: "high",
+				RuntimeClassName:  "nvidia",
+				Pinned:            true,
+			},
+			{
+				Resource:    Resource{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "agent"},
+				PodAffinity: []string{"required: all pods per zone"},
+				Tolerations: []string{"*"},
+			},
+		},
+		PinnedWorkloads: []string{"Deployment/gpu-worker"},
+		NodeLabels:      []string{"kubernetes.io/arch In amd64 && spot DoesNotExist", "pool=gpu"},
+		Tolerations:     []string{"*", "node.kubernetes.io/unreachable=true:NoExecute for 30s", "nvidia.com/gpu:NoSchedule"},
+		PriorityClasses: []string{"high"},
+		RuntimeClasses:  []string{"nvidia"},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("got %+v\nwant %+v", report, want)
+	}
+}
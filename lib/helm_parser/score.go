@@ -0,0 +1,306 @@
+package helm_parser
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Grades of the checks reported by ScoreManifest.
+const (
+	GradeOK       = "ok"
+	GradeWarning  = "warning"
+	GradeCritical = "critical"
+	// GradeSkipped marks checks that do not apply to the workload. They do
+	// not count towards its score.
+	GradeSkipped = "skipped"
+)
+
+// Checks run by ScoreManifest on every workload.
+const (
+	CheckProbes           = "container-probes"
+	CheckResources        = "container-resources"
+	CheckImagePullPolicy  = "container-image-pull-policy"
+	CheckPodAntiAffinity  = "pod-anti-affinity"
+	CheckDisruptionBudget = "pod-disruption-budget"
+)
+
+var scoreRecommendations = map[string]string{
+	CheckProbes:           "Define a readinessProbe so traffic is only sent to ready pods, and a livenessProbe so hung containers are restarted",
+	CheckResources:        "Set CPU and memory requests so pods are scheduled on nodes with enough capacity, and limits so a container cannot starve its neighbours",
+	CheckImagePullPolicy:  "Pin images to a version tag or digest, or set imagePullPolicy to Always for mutable tags, so all replicas run the same image",
+	CheckPodAntiAffinity:  "Add a podAntiAffinity or topologySpreadConstraints so the replicas are spread over nodes and survive the loss of one",
+	CheckDisruptionBudget: "Add a PodDisruptionBudget selecting the pods so node drains and cluster upgrades keep enough replicas running",
+}
+
+// ScoreCheck is the result of one check of a workload.
+type ScoreCheck struct {
+	ID    string `json:"id"`
+	Grade string `json:"grade"`
+	// Message describes the problems found, or why the check was skipped.
+	Message string `json:"message,omitempty"`
+	// Recommendation tells how to fix the problems, set for warnings and
+	// critical findings.
+	Recommendation string `json:"recommendation,omitempty"`
+}
+
+// WorkloadScore is the score of a workload in a manifest.
+type WorkloadScore struct {
+	Resource
+	// Score is the percentage of points the workload achieved in the checks
+	// that apply to it: 2 for ok, 1 for warning and 0 for critical.
+	Score  int          `json:"score"`
+	Checks []ScoreCheck `json:"checks"`
+}
+
+// ScoreReport is the result of ScoreManifest.
+type ScoreReport struct {
+	// Score is the average score of the workloads, 100 if there are none.
+	Score     int             `json:"score"`
+	Workloads []WorkloadScore `json:"workloads"`
+}
+
+type scoreContainer struct {
+	Name            string         `json:"name"`
+	Image           string         `json:"image"`
+	ImagePullPolicy string         `json:"imagePullPolicy"`
+	ReadinessProbe  map[string]any `json:"readinessProbe"`
+	LivenessProbe   map[string]any `json:"livenessProbe"`
+	Resources       struct {
+		Limits   map[string]any `json:"limits"`
+		Requests map[string]any `json:"requests"`
+	} `json:"resources"`
+}
+
+type scorePodSpec struct {
+	Containers []scoreContainer `json:"containers"`
+	Affinity   struct {
+		PodAntiAffinity map[string]any `json:"podAntiAffinity"`
+	} `json:"affinity"`
+	TopologySpreadConstraints []any `json:"topologySpreadConstraints"`
+}
+
+type scorePodTemplate struct {
+	Metadata struct {
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Spec scorePodSpec `json:"spec"`
+}
+
+// scoreResource holds the fields of the workloads and PodDisruptionBudgets
+// the checks look at. The pod spec is embedded for Pods.
+type scoreResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string            `json:"name"`
+		Namespace string            `json:"namespace"`
+		Labels    map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Spec struct {
+		scorePodSpec
+		Replicas *int `json:"replicas"`
+		Selector *struct {
+			MatchLabels map[string]string `json:"matchLabels"`
+		} `json:"selector"`
+		Template    scorePodTemplate `json:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template scorePodTemplate `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+	} `json:"spec"`
+}
+
+// ScoreManifest checks the workloads of a multi-document YAML manifest, such
+// as a rendered chart, against common best practices: probes, resource
+// requests and limits, image pull policies, anti-affinity and
+// PodDisruptionBudgets for replicated workloads. Workloads are reported in
+// manifest order with a score and recommendations for the failed checks.
+func ScoreManifest(manifest string) (*ScoreReport, error) {
+	resources, err := decodeManifest[scoreResource](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ScoreReport{Score: 100, Workloads: []WorkloadScore{}}
+	total := 0
+	for _, r := range resources {
+		template, ok := r.podTemplate()
+		if !ok {
+			continue
+		}
+		w := WorkloadScore{
+			Resource: Resource{APIVersion: r.APIVersion, Kind: r.Kind, Name: r.Metadata.Name, Namespace: r.Metadata.Namespace},
+			Checks: []ScoreCheck{
+				checkProbes(r.Kind, template.Spec.Containers),
+				checkResources(template.Spec.Containers),
+				checkImagePullPolicy(template.Spec.Containers),
+				checkPodAntiAffinity(r, template.Spec),
+				checkDisruptionBudget(r, template, resources),
+			},
+		}
+		w.Score = checksScore(w.Checks)
+		total += w.Score
+		report.Workloads = append(report.Workloads, w)
+	}
+	if len(report.Workloads) > 0 {
+		report.Score = int(math.Round(float64(total) / float64(len(report.Workloads))))
+	}
+	return report, nil
+}
+
+// podTemplate returns the pod template of a workload, false for other
+// resources.
+func (r scoreResource) podTemplate() (scorePodTemplate, bool) {
+	switch r.Kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		return r.Spec.Template, true
+	case "CronJob":
+		return r.Spec.JobTemplate.Spec.Template, true
+	case "Pod":
+		var t scorePodTemplate
+		t.Metadata.Labels = r.Metadata.Labels
+		t.Spec = r.Spec.scorePodSpec
+		return t, true
+	}
+	return scorePodTemplate{}, false
+}
+
+// replicated reports whether the workload runs several replicas of a pod
+// that should not all be disrupted at once.
+func (r scoreResource) replicated() bool {
+	switch r.Kind {
+	case "Deployment", "StatefulSet", "ReplicaSet":
+		// Replicas defaults to 1.
+		return r.Spec.Replicas != nil && *r.Spec.Replicas > 1
+	}
+	return false
+}
+
+func checksScore(checks []ScoreCheck) int {
+	points, scored := 0, 0
+	for _, c := range checks {
+		switch c.Grade {
+		case GradeOK:
+			points += 2
+		case GradeWarning:
+			points++
+		case GradeSkipped:
+			continue
+		}
+		scored++
+	}
+	if scored == 0 {
+		return 100
+	}
+	return int(math.Round(float64(points) * 100 / float64(2*scored)))
+}
+
+// newCheck grades a check from its problems: the worst grade of the
+// problems, or ok if there are none.
+func newCheck(id string, problems []string, grades []string) ScoreCheck {
+	check := ScoreCheck{ID: id, Grade: GradeOK}
+	for _, grade := range grades {
+		if grade == GradeCritical || check.Grade == GradeOK {
+			check.Grade = grade
+		}
+	}
+	if len(problems) > 0 {
+		check.Message = strings.Join(problems, "; ")
+		check.Recommendation = scoreRecommendations[id]
+	}
+	return check
+}
+
+func checkProbes(kind string, containers []scoreContainer) ScoreCheck {
+	if kind == "Job" || kind == "CronJob" {
+		return ScoreCheck{ID: CheckProbes, Grade: GradeSkipped, Message: "probes do not apply to jobs"}
+	}
+	var problems, grades []string
+	for _, c := range containers {
+		if c.ReadinessProbe == nil {
+			problems = append(problems, fmt.Sprintf("container %q has no readinessProbe", c.Name))
+			grades = append(grades, GradeCritical)
+		}
+		if c.LivenessProbe == nil {
+			problems = append(problems, fmt.Sprintf("container %q has no livenessProbe", c.Name))
+			grades = append(grades, GradeWarning)
+		}
+	}
+	return newCheck(CheckProbes, problems, grades)
+}
+
+func checkResources(containers []scoreContainer) ScoreCheck {
+	var problems, grades []string
+	for _, c := range containers {
+		for _, resource := range []string{"cpu", "memory"} {
+			if _, ok := c.Resources.Limits[resource]; !ok {
+				problems = append(problems, fmt.Sprintf("container %q has no %s limit", c.Name, resource))
+				grades = append(grades, GradeCritical)
+			}
+			if _, ok := c.Resources.Requests[resource]; !ok {
+				problems = append(problems, fmt.Sprintf("container %q has no %s request", c.Name, resource))
+				grades = append(grades, GradeWarning)
+			}
+		}
+	}
+	return newCheck(CheckResources, problems, grades)
+}
+
+// checkImagePullPolicy checks that nodes pull mutable image tags again, so
+// all replicas run the same image. Without an explicit policy Kubernetes
+// pulls images tagged latest or without a tag always.
+func checkImagePullPolicy(containers []scoreContainer) ScoreCheck {
+	var problems, grades []string
+	for _, c := range containers {
+		ref := parseImage(c.Image)
+		mutable := ref.Digest == "" && ref.Tag == "latest"
+		switch {
+		case c.ImagePullPolicy == "Never":
+			problems = append(problems, fmt.Sprintf("container %q never pulls image %s, which must be present on every node", c.Name, c.Image))
+			grades = append(grades, GradeWarning)
+		case mutable && c.ImagePullPolicy != "" && c.ImagePullPolicy != "Always":
+			problems = append(problems, fmt.Sprintf("container %q uses the mutable image %s with imagePullPolicy %s", c.Name, c.Image, c.ImagePullPolicy))
+			grades = append(grades, GradeCritical)
+		}
+	}
+	return newCheck(CheckImagePullPolicy, problems, grades)
+}
+
+func checkPodAntiAffinity(r scoreResource, spec scorePodSpec) ScoreCheck {
+	if !r.replicated() {
+		return ScoreCheck{ID: CheckPodAntiAffinity, Grade: GradeSkipped, Message: "the workload does not run several replicas"}
+	}
+	if spec.Affinity.PodAntiAffinity != nil || len(spec.TopologySpreadConstraints) > 0 {
+		return ScoreCheck{ID: CheckPodAntiAffinity, Grade: GradeOK}
+	}
+	return newCheck(CheckPodAntiAffinity, []string{"the replicas may all be scheduled on the same node"}, []string{GradeWarning})
+}
+
+// checkDisruptionBudget looks for a PodDisruptionBudget in the manifest whose
+// selector matches the pod labels of a replicated workload. Only matchLabels
+// of the selectors are compared.
+func checkDisruptionBudget(r scoreResource, template scorePodTemplate, resources []scoreResource) ScoreCheck {
+	if !r.replicated() {
+		return ScoreCheck{ID: CheckDisruptionBudget, Grade: GradeSkipped, Message: "the workload does not run several replicas"}
+	}
+	for _, pdb := range resources {
+		if pdb.Kind != "PodDisruptionBudget" || pdb.Metadata.Namespace != r.Metadata.Namespace || pdb.Spec.Selector == nil {
+			continue
+		}
+		if labelsMatch(pdb.Spec.Selector.MatchLabels, template.Metadata.Labels) {
+			return ScoreCheck{ID: CheckDisruptionBudget, Grade: GradeOK, Message: "selected by PodDisruptionBudget " + pdb.Metadata.Name}
+		}
+	}
+	return newCheck(CheckDisruptionBudget, []string{"no PodDisruptionBudget selects the pods"}, []string{GradeCritical})
+}
+
+func labelsMatch(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,180 @@
+package helm_parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScoreManifest(t *testing.T) {
+	manifest := `---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: apps
+spec:
+  replicas: 3
+  template:
+    metadata:
+      labels:
+        app: web
+        tier: frontend
+    spec:
+      affinity:
+        podAntiAffinity:
+          preferredDuringSchedulingIgnoredDuringExecution: []
+      containers:
+        - name: web
+          image: nginx:1.27
+          readinessProbe:
+            httpGet:
+              path: /
+          livenessProbe:
+            httpGet:
+              path: /
+          resources:
+            limits:
+              cpu: 500m
+              memory: 128Mi
+            requests:
+              cpu: 100m
+              memory: 64Mi
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+  namespace: apps
+spec:
+  minAvailable: 1
+  selector:
+    matchLabels:
+      app: web
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 2
+  template:
+    metadata:
+      labels:
+        app: db
+    spec:
+      containers:
+        - name: db
+          image: postgres
+          imagePullPolicy: IfNotPresent
+          livenessProbe:
+            exec:
+              command: [pg_isready]
+          resources:
+            requests:
+              memory: 1Gi
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: backup
+              image: backup:1.0
+              imagePullPolicy: Never
+              resources:
+                limits:
+                  cpu: 100m
+                  memory: 64Mi
+                requests:
+                  cpu: 100m
+                  memory: 64Mi
+`
+
+	report, err := ScoreManifest(manifest)
+	if err != nil {
+		t.Fatalf("ScoreManifest() error = %v", err)
+	}
+	if len(report.Workloads) != 3 {
+		t.Fatalf("got %d workloads, want 3: %+v", len(report.Workloads), report.Workloads)
+	}
+
+	grades := func(w WorkloadScore) map[string]string {
+		m := map[string]string{}
+		for _, c := range w.Checks {
+			m[c.ID] = c.Grade
+		}
+		return m
+	}
+
+	web := report.Workloads[0]
+	if web.Name != "web" || web.Namespace != "apps" || web.Score != 100 {
+		t.Errorf("web = %+v, want all checks to pass", web)
+	}
+
+	db := report.Workloads[1]
+	wantDB := map[string]string{
+		CheckProbes:           GradeCritical,
+		CheckResources:        GradeCritical,
+		CheckImagePullPolicy:  GradeCritical,
+		CheckPodAntiAffinity:  GradeWarning,
+		CheckDisruptionBudget: GradeCritical,
+	}
+	for id, want := range wantDB {
+		if got := grades(db)[id]; got != want {
+			t.Errorf("db %s = %s, want %s", id, got, want)
+		}
+	}
+	if db.Score != 10 {
+		t.Errorf("db score = %d, want 10", db.Score)
+	}
+	for _, c := range db.Checks {
+		if c.Recommendation == "" {
+			t.Errorf("db %s: missing recommendation", c.ID)
+		}
+	}
+	if msg := db.Checks[1].Message; !strings.Contains(msg, `container "db" has no cpu limit`) || strings.Contains(msg, "memory request") {
+		t.Errorf("db resources message = %q", msg)
+	}
+
+	backup := report.Workloads[2]
+	wantBackup := map[string]string{
+		CheckProbes:           GradeSkipped,
+		CheckResources:        GradeOK,
+		CheckImagePullPolicy:  GradeWarning,
+		CheckPodAntiAffinity:  GradeSkipped,
+		CheckDisruptionBudget: GradeSkipped,
+	}
+	for id, want := range wantBackup {
+		if got := grades(backup)[id]; got != want {
+			t.Errorf("backup %s = %s, want %s", id, got, want)
+		}
+	}
+	if backup.Score != 75 {
+		t.Errorf("backup score = %d, want 75", backup.Score)
+	}
+
+	if report.Score != 62 {
+		t.Errorf("report score = %d, want 62", report.Score)
+	}
+}
+
+func TestScoreManifestWithoutWorkloads(t *testing.T) {
+	report, err := ScoreManifest("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n")
+	if err != nil {
+		t.Fatalf("ScoreManifest() error = %v", err)
+	}
+	if report.Score != 100 || len(report.Workloads) != 0 {
+		t.Errorf("report = %+v, want an empty report scoring 100", report)
+	}
+}
@@ -0,0 +1,242 @@
+package helm_parser
+
+import (
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Ways ExtractServices reports ports to be reachable from outside the
+// cluster.
+const (
+	ExposureLoadBalancer = "LoadBalancer"
+	ExposureNodePort     = "NodePort"
+	ExposureExternalIP   = "ExternalIP"
+	ExposureHostPort     = "HostPort"
+)
+
+// ServicePort is a port of a Service.
+type ServicePort struct {
+	Name     string `json:"name,omitempty"`
+	Protocol string `json:"protocol"`
+	Port     int    `json:"port"`
+	// TargetPort is the port number or name on the pods, the port if unset.
+	TargetPort string `json:"targetPort"`
+	// NodePort is set for NodePort and LoadBalancer services that choose it.
+	NodePort    int    `json:"nodePort,omitempty"`
+	AppProtocol string `json:"appProtocol,omitempty"`
+}
+
+// ServiceInfo describes a Service.
+type ServiceInfo struct {
+	Resource
+	Type string `json:"type"`
+	// Headless reports that the Service has no cluster IP and resolves to
+	// the pod addresses.
+	Headless     bool              `json:"headless,omitempty"`
+	Ports        []ServicePort     `json:"ports"`
+	Selector     map[string]string `json:"selector,omitempty"`
+	ExternalName string            `json:"externalName,omitempty"`
+	ExternalIPs  []string          `json:"externalIPs,omitempty"`
+	// LoadBalancerSourceRanges are the client CIDRs a LoadBalancer accepts.
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	ExternalTrafficPolicy    string   `json:"externalTrafficPolicy,omitempty"`
+	// LoadBalancerAnnotations are the annotations configuring the cloud load
+	// balancer, e.g. to make it internal.
+	LoadBalancerAnnotations map[string]string `json:"loadBalancerAnnotations,omitempty"`
+	// Workloads are the workloads in the manifest whose pods the selector
+	// matches, as kind/name.
+	Workloads []string `json:"workloads,omitempty"`
+}
+
+// ExposedPort is a port reachable from outside the cluster.
+type ExposedPort struct {
+	// Resource is the Service or workload exposing the port, as kind/name.
+	Resource string `json:"resource"`
+	// Exposure is how the port is exposed, e.g. LoadBalancer.
+	Exposure string `json:"exposure"`
+	Protocol string `json:"protocol"`
+	Port     int    `json:"port"`
+	// NodePort is the port on the nodes of NodePort and LoadBalancer
+	// services, if set in the manifest.
+	NodePort int `json:"nodePort,omitempty"`
+}
+
+// ServiceReport is the result of ExtractServices.
+type ServiceReport struct {
+	Services []ServiceInfo `json:"services"`
+	// Exposed are the ports reachable from outside the cluster.
+	Exposed []ExposedPort `json:"exposed"`
+}
+
+type servicesContainer struct {
+	Ports []struct {
+		ContainerPort int    `json:"containerPort"`
+		HostPort      int    `json:"hostPort"`
+		Protocol      string `json:"protocol"`
+	} `json:"ports"`
+}
+
+type servicesPodTemplate struct {
+	Metadata struct {
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Spec struct {
+		Containers     []servicesContainer `json:"containers"`
+		InitContainers []servicesContainer `json:"initContainers"`
+	} `json:"spec"`
+}
+
+// servicesResource holds the fields of Services and workloads
+// ExtractServices looks at.
+type servicesResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace"`
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		// Service.
+		Type      string `json:"type"`
+		ClusterIP string `json:"clusterIP"`
+		Ports     []struct {
+			Name        string              `json:"name"`
+			Protocol    string              `json:"protocol"`
+			Port        int                 `json:"port"`
+			TargetPort  *intstr.IntOrString `json:"targetPort"`
+			NodePort    int                 `json:"nodePort"`
+			AppProtocol string              `json:"appProtocol"`
+		} `json:"ports"`
+		Selector                 map[string]string `json:"selector"`
+		ExternalName             string            `json:"externalName"`
+		ExternalIPs              []string          `json:"externalIPs"`
+		LoadBalancerSourceRanges []string          `json:"loadBalancerSourceRanges"`
+		ExternalTrafficPolicy    string            `json:"externalTrafficPolicy"`
+
+		// Workloads; the pod spec of Pods is decoded into Template.Spec.
+		Template    servicesPodTemplate `json:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template servicesPodTemplate `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+		Containers     []servicesContainer `json:"containers"`
+		InitContainers []servicesContainer `json:"initContainers"`
+	} `json:"spec"`
+}
+
+// podTemplate returns the pod template of a workload, false for other
+// resources.
+func (r servicesResource) podTemplate() (servicesPodTemplate, bool) {
+	switch r.Kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		return r.Spec.Template, true
+	case "CronJob":
+		return r.Spec.JobTemplate.Spec.Template, true
+	case "Pod":
+		var t servicesPodTemplate
+		t.Metadata.Labels = r.Metadata.Labels
+		t.Spec.Containers = r.Spec.Containers
+		t.Spec.InitContainers = r.Spec.InitContainers
+		return t, true
+	}
+	return servicesPodTemplate{}, false
+}
+
+// ExtractServices returns the Services of a multi-document YAML manifest,
+// such as a rendered chart, with their types, ports and selectors and the
+// workloads they select, in manifest order, and the ports reachable from
+// outside the cluster through LoadBalancer and NodePort Services, external
+// IPs and host ports of containers.
+func ExtractServices(manifest string) (*ServiceReport, error) {
+	resources, err := decodeManifest[servicesResource](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ServiceReport{Services: []ServiceInfo{}, Exposed: []ExposedPort{}}
+	for _, r := range resources {
+		if r.Kind != "Service" {
+			continue
+		}
+		svc := ServiceInfo{
+			Resource:                 Resource{APIVersion: r.APIVersion, Kind: r.Kind, Name: r.Metadata.Name, Namespace: r.Metadata.Namespace},
+			Type:                     r.Spec.Type,
+			Headless:                 r.Spec.ClusterIP == "None",
+			Ports:                    []ServicePort{},
+			Selector:                 r.Spec.Selector,
+			ExternalName:             r.Spec.ExternalName,
+			ExternalIPs:              r.Spec.ExternalIPs,
+			LoadBalancerSourceRanges: r.Spec.LoadBalancerSourceRanges,
+			ExternalTrafficPolicy:    r.Spec.ExternalTrafficPolicy,
+		}
+		if svc.Type == "" {
+			svc.Type = "ClusterIP"
+		}
+		for k, v := range r.Metadata.Annotations {
+			if strings.Contains(k, "load-balancer") || strings.Contains(k, "loadbalancer") {
+				if svc.LoadBalancerAnnotations == nil {
+					svc.LoadBalancerAnnotations = map[string]string{}
+				}
+				svc.LoadBalancerAnnotations[k] = v
+			}
+		}
+
+		for _, p := range r.Spec.Ports {
+			port := ServicePort{Name: p.Name, Protocol: p.Protocol, Port: p.Port, NodePort: p.NodePort, AppProtocol: p.AppProtocol}
+			if port.Protocol == "" {
+				port.Protocol = "TCP"
+			}
+			port.TargetPort = intOrString(p.TargetPort)
+			if port.TargetPort == "" {
+				port.TargetPort = strconv.Itoa(p.Port)
+			}
+			svc.Ports = append(svc.Ports, port)
+
+			name := "Service/" + r.Metadata.Name
+			switch svc.Type {
+			case ExposureLoadBalancer, ExposureNodePort:
+				report.Exposed = append(report.Exposed, ExposedPort{Resource: name, Exposure: svc.Type, Protocol: port.Protocol, Port: port.Port, NodePort: port.NodePort})
+			}
+			if len(svc.ExternalIPs) > 0 {
+				report.Exposed = append(report.Exposed, ExposedPort{Resource: name, Exposure: ExposureExternalIP, Protocol: port.Protocol, Port: port.Port})
+			}
+		}
+
+		// Services without a selector have their endpoints managed
+		// elsewhere, and select no pods.
+		if len(svc.Selector) > 0 {
+			for _, w := range resources {
+				template, ok := w.podTemplate()
+				if ok && w.Metadata.Namespace == r.Metadata.Namespace && len(template.Metadata.Labels) > 0 && labelsMatch(svc.Selector, template.Metadata.Labels) {
+					svc.Workloads = append(svc.Workloads, w.Kind+"/"+w.Metadata.Name)
+				}
+			}
+		}
+		report.Services = append(report.Services, svc)
+	}
+
+	for _, w := range resources {
+		template, ok := w.podTemplate()
+		if !ok {
+			continue
+		}
+		for _, c := range append(template.Spec.InitContainers, template.Spec.Containers...) {
+			for _, p := range c.Ports {
+				if p.HostPort == 0 {
+					continue
+				}
+				protocol := p.Protocol
+				if protocol == "" {
+					protocol = "TCP"
+				}
+				report.Exposed = append(report.Exposed, ExposedPort{Resource: w.Kind + "/" + w.Metadata.Name, Exposure: ExposureHostPort, Protocol: protocol, Port: p.HostPort})
+			}
+		}
+	}
+	return report, nil
+}
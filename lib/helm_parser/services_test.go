@@ -0,0 +1,132 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractServices(t *testing.T) {
+	manifest := `---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  annotations:
+    service.beta.kubernetes.io/aws-load-balancer-internal: "true"
+    prometheus.io/scrape: "true"
+spec:
+  type: LoadBalancer
+  loadBalancerSourceRanges: [10.0.0.0/8]
+  externalTrafficPolicy: Local
+  selector:
+    app: web
+  ports:
+    - name: http
+      port: 80
+      targetPort: http
+    - name: metrics
+      port: 9090
+      nodePort: 30090
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-headless
+spec:
+  clusterIP: None
+  selector:
+    app: web
+  ports:
+    - port: 5000
+      protocol: UDP
+      targetPort: 5001
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+spec:
+  type: ExternalName
+  externalName: db.example.com
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+        tier: frontend
+    spec:
+      containers:
+        - name: web
+          ports:
+            - containerPort: 8080
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    metadata:
+      labels:
+        app: agent
+    spec:
+      containers:
+        - name: agent
+          ports:
+            - containerPort: 8125
+              hostPort: 8125
+              protocol: UDP
+`
+
+	report, err := ExtractServices(manifest)
+	if err != nil {
+		t.Fatalf("ExtractServices() error = %v", err)
+	}
+
+	wantServices := []ServiceInfo{
+		{
+			Resource: Resource{APIVersion: "v1", Kind: "Service", Name: "web"},
+			Type:     "LoadBalancer",
+			Ports: []ServicePort{
+				{Name: "http", Protocol: "TCP", Port: 80, TargetPort: "http"},
+				{Name: "metrics", Protocol: "TCP", Port: 9090, TargetPort: "9090", NodePort: 30090},
+			},
+			Selector:                 map[string]string{"app": "web"},
+			LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+			ExternalTrafficPolicy:    "Local",
+			LoadBalancerAnnotations:  map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+			Workloads:                []string{"Deployment/web"},
+		},
+		{
+			Resource:  Resource{APIVersion: "v1", Kind: "Service", Name: "web-headless"},
+			Type:      "ClusterIP",
+			Headless:  true,
+			Ports:     []ServicePort{{Protocol: "UDP", Port: 5000, TargetPort: "5001"}},
+			Selector:  map[string]string{"app": "web"},
+			Workloads: []string{"Deployment/web"},
+		},
+		{
+			Resource:     Resource{APIVersion: "v1", Kind: "Service", Name: "db"},
+			Type:         "ExternalName",
+			Ports:        []ServicePort{},
+			ExternalName: "db.example.com",
+		},
+	}
+	if !reflect.DeepEqual(report.Services, wantServices) {
+		t.Errorf("services = %+v\nwant %+v", report.Services, wantServices)
+	}
+
+	wantExposed := []ExposedPort{
+		{Resource: "Service/web", Exposure: ExposureLoadBalancer, Protocol: "TCP", Port: 80},
+		{Resource: "Service/web", Exposure: ExposureLoadBalancer, Protocol: "TCP", Port: 9090, NodePort: 30090},
+		{Resource: "DaemonSet/agent", Exposure: ExposureHostPort, Protocol: "UDP", Port: 8125},
+	}
+	if !reflect.DeepEqual(report.Exposed, wantExposed) {
+		t.Errorf("exposed = %+v\nwant %+v", report.Exposed, wantExposed)
+	}
+}
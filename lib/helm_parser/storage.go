@@ -0,0 +1,212 @@
+package helm_parser
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// StorageClaim is a PersistentVolumeClaim, a volumeClaimTemplate of a
+// StatefulSet or an ephemeral volume of a workload.
+type StorageClaim struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Owner is the workload creating the claims from a template, as
+	// kind/name, empty for PersistentVolumeClaims.
+	Owner string `json:"owner,omitempty"`
+	// Ephemeral marks ephemeral volumes, deleted with their pods.
+	Ephemeral bool `json:"ephemeral,omitempty"`
+	// Count is the number of claims: the replica count of the owner, or 1.
+	// DaemonSets create one claim per node.
+	Count int `json:"count"`
+	// Size is the requested storage of a claim as written in the manifest.
+	Size   string  `json:"size,omitempty"`
+	SizeGB float64 `json:"sizeGB"`
+	// StorageClass is empty for claims provisioned with the default storage
+	// class of the cluster.
+	StorageClass string `json:"storageClass,omitempty"`
+	// Static marks claims with an empty storage class, which bind
+	// pre-provisioned PersistentVolumes.
+	Static      bool     `json:"static,omitempty"`
+	AccessModes []string `json:"accessModes,omitempty"`
+	VolumeMode  string   `json:"volumeMode,omitempty"`
+	// UsedBy are the workloads mounting a PersistentVolumeClaim, as
+	// kind/name.
+	UsedBy []string `json:"usedBy,omitempty"`
+}
+
+// StorageReport is the result of ExtractStorage.
+type StorageReport struct {
+	Claims []StorageClaim `json:"claims"`
+	// TotalGB is the storage requested by all claims in GiB, counting the
+	// claims of DaemonSets once.
+	TotalGB float64 `json:"totalGB"`
+	// StorageClasses are the storage classes the claims use, sorted.
+	StorageClasses []string `json:"storageClasses"`
+	// UsesDefaultClass reports that some claims use the default storage
+	// class of the cluster.
+	UsesDefaultClass bool `json:"usesDefaultClass"`
+	// ExpectedClaims are the PersistentVolumeClaims workloads mount that the
+	// manifest does not create, which must exist before installing it.
+	ExpectedClaims []string `json:"expectedClaims"`
+}
+
+type storageClaimSpec struct {
+	StorageClassName *string  `json:"storageClassName"`
+	AccessModes      []string `json:"accessModes"`
+	VolumeMode       string   `json:"volumeMode"`
+	Resources        struct {
+		Requests map[string]resource.Quantity `json:"requests"`
+	} `json:"resources"`
+}
+
+type storagePodSpec struct {
+	Volumes []struct {
+		PersistentVolumeClaim *struct {
+			ClaimName string `json:"claimName"`
+		} `json:"persistentVolumeClaim"`
+		Ephemeral *struct {
+			VolumeClaimTemplate struct {
+				Spec storageClaimSpec `json:"spec"`
+			} `json:"volumeClaimTemplate"`
+		} `json:"ephemeral"`
+		Name string `json:"name"`
+	} `json:"volumes"`
+}
+
+// storageResource holds the fields of the PersistentVolumeClaims and
+// workloads ExtractStorage looks at. The claim spec is embedded for
+// PersistentVolumeClaims and the pod spec for Pods.
+type storageResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		storageClaimSpec
+		storagePodSpec
+		Replicas *int `json:"replicas"`
+		Template struct {
+			Spec storagePodSpec `json:"spec"`
+		} `json:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template struct {
+					Spec storagePodSpec `json:"spec"`
+				} `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+		VolumeClaimTemplates []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Spec storageClaimSpec `json:"spec"`
+		} `json:"volumeClaimTemplates"`
+	} `json:"spec"`
+}
+
+// podSpec returns the pod spec and replica count of a workload, false for
+// other resources.
+func (r storageResource) podSpec() (storagePodSpec, int, bool) {
+	replicas := 1
+	if r.Spec.Replicas != nil {
+		replicas = *r.Spec.Replicas
+	}
+	switch r.Kind {
+	case "Deployment", "StatefulSet", "ReplicaSet":
+		return r.Spec.Template.Spec, replicas, true
+	case "DaemonSet", "Job":
+		return r.Spec.Template.Spec, 1, true
+	case "CronJob":
+		return r.Spec.JobTemplate.Spec.Template.Spec, 1, true
+	case "Pod":
+		return r.Spec.storagePodSpec, 1, true
+	}
+	return storagePodSpec{}, 0, false
+}
+
+// ExtractStorage returns the storage requested by a multi-document YAML
+// manifest, such as a rendered chart: its PersistentVolumeClaims, the
+// volumeClaimTemplates of StatefulSets and the ephemeral volumes of workloads
+// with their sizes, storage classes and access modes, in manifest order, and
+// the claims workloads mount without the manifest creating them.
+func ExtractStorage(manifest string) (*StorageReport, error) {
+	resources, err := decodeManifest[storageResource](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StorageReport{Claims: []StorageClaim{}, StorageClasses: []string{}, ExpectedClaims: []string{}}
+	claims := map[[2]string]int{}
+	for _, r := range resources {
+		if r.Kind == "PersistentVolumeClaim" {
+			claims[[2]string{r.Metadata.Namespace, r.Metadata.Name}] = len(report.Claims)
+			report.Claims = append(report.Claims, newStorageClaim(r.Metadata.Name, r.Metadata.Namespace, "", 1, r.Spec.storageClaimSpec))
+		}
+	}
+
+	for _, r := range resources {
+		spec, replicas, ok := r.podSpec()
+		if !ok {
+			continue
+		}
+		owner := r.Kind + "/" + r.Metadata.Name
+		if r.Kind == "StatefulSet" {
+			for _, t := range r.Spec.VolumeClaimTemplates {
+				report.Claims = append(report.Claims, newStorageClaim(t.Metadata.Name, r.Metadata.Namespace, owner, replicas, t.Spec))
+			}
+		}
+		for _, v := range spec.Volumes {
+			switch {
+			case v.PersistentVolumeClaim != nil:
+				i, ok := claims[[2]string{r.Metadata.Namespace, v.PersistentVolumeClaim.ClaimName}]
+				if !ok {
+					report.ExpectedClaims = append(report.ExpectedClaims, v.PersistentVolumeClaim.ClaimName)
+				} else if !slices.Contains(report.Claims[i].UsedBy, owner) {
+					report.Claims[i].UsedBy = append(report.Claims[i].UsedBy, owner)
+				}
+			case v.Ephemeral != nil:
+				claim := newStorageClaim(v.Name, r.Metadata.Namespace, owner, replicas, v.Ephemeral.VolumeClaimTemplate.Spec)
+				claim.Ephemeral = true
+				report.Claims = append(report.Claims, claim)
+			}
+		}
+	}
+
+	for _, c := range report.Claims {
+		report.TotalGB += c.SizeGB * float64(c.Count)
+		switch {
+		case c.StorageClass != "":
+			report.StorageClasses = append(report.StorageClasses, c.StorageClass)
+		case !c.Static:
+			report.UsesDefaultClass = true
+		}
+	}
+	slices.Sort(report.StorageClasses)
+	report.StorageClasses = slices.Compact(report.StorageClasses)
+	slices.Sort(report.ExpectedClaims)
+	report.ExpectedClaims = slices.Compact(report.ExpectedClaims)
+	return report, nil
+}
+
+func newStorageClaim(name, namespace, owner string, count int, spec storageClaimSpec) StorageClaim {
+	claim := StorageClaim{
+		Name:        name,
+		Namespace:   namespace,
+		Owner:       owner,
+		Count:       count,
+		AccessModes: spec.AccessModes,
+		VolumeMode:  spec.VolumeMode,
+	}
+	if spec.StorageClassName != nil {
+		claim.StorageClass = *spec.StorageClassName
+		claim.Static = claim.StorageClass == ""
+	}
+	if size, ok := spec.Resources.Requests["storage"]; ok {
+		claim.Size = size.String()
+		claim.SizeGB = size.AsApproximateFloat64() / (1 << 30)
+	}
+	return claim
+}
@@ -0,0 +1,104 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractStorage(t *testing.T) {
+	manifest := `---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: uploads
+spec:
+  accessModes: [ReadWriteMany]
+  storageClassName: efs
+  resources:
+    requests:
+      storage: 10Gi
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: archive
+spec:
+  accessModes: [ReadOnlyMany]
+  storageClassName: ""
+  resources:
+    requests:
+      storage: 512Mi
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 3
+  volumeClaimTemplates:
+    - metadata:
+        name: data
+      spec:
+        accessModes: [ReadWriteOnce]
+        resources:
+          requests:
+            storage: 8Gi
+  template:
+    spec:
+      containers:
+        - name: db
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      volumes:
+        - name: uploads
+          persistentVolumeClaim:
+            claimName: uploads
+        - name: shared
+          persistentVolumeClaim:
+            claimName: shared
+        - name: scratch
+          ephemeral:
+            volumeClaimTemplate:
+              spec:
+                accessModes: [ReadWriteOnce]
+                storageClassName: fast
+                volumeMode: Block
+                resources:
+                  requests:
+                    storage: 1Gi
+`
+
+	report, err := ExtractStorage(manifest)
+	if err != nil {
+		t.Fatalf("ExtractStorage() error = %v", err)
+	}
+
+	want := []StorageClaim{
+		{Name: "uploads", Count: 1, Size: "10Gi", SizeGB: 10, StorageClass: "efs", AccessModes: []string{"ReadWriteMany"}, UsedBy: []string{"Deployment/web"}},
+		{Name: "archive", Count: 1, Size: "512Mi", SizeGB: 0.5, Static: true, AccessModes: []string{"ReadOnlyMany"}},
+		{Name: "data", Owner: "StatefulSet/db", Count: 3, Size: "8Gi", SizeGB: 8, AccessModes: []string{"ReadWriteOnce"}},
+		{Name: "scratch", Owner: "Deployment/web", Ephemeral: true, Count: 2, Size: "1Gi", SizeGB: 1, StorageClass: "fast", AccessModes: []string{"ReadWriteOnce"}, VolumeMode: "Block"},
+	}
+	if !reflect.DeepEqual(report.Claims, want) {
+		t.Errorf("claims = %+v\nwant %+v", report.Claims, want)
+	}
+	if report.TotalGB != 36.5 {
+		t.Errorf("total = %v GiB, want 36.5", report.TotalGB)
+	}
+	if want := []string{"efs", "fast"}; !reflect.DeepEqual(report.StorageClasses, want) {
+		t.Errorf("storage classes = %v, want %v", report.StorageClasses, want)
+	}
+	if !report.UsesDefaultClass {
+		t.Error("UsesDefaultClass = false, want true")
+	}
+	if want := []string{"shared"}; !reflect.DeepEqual(report.ExpectedClaims, want) {
+		t.Errorf("expected claims = %v, want %v", report.ExpectedClaims, want)
+	}
+}
@@ -0,0 +1,762 @@
+package helm_parser
+
+import (
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"helm.sh/helm/v4/pkg/chart/common"
+	"helm.sh/helm/v4/pkg/chart/common/util"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	"helm.sh/helm/v4/pkg/engine"
+	"sigs.k8s.io/yaml"
+)
+
+// Statuses of unit tests and their assertions, see UnitTestReport.
+const (
+	UnitTestPassed  = "passed"
+	UnitTestFailed  = "failed"
+	UnitTestSkipped = "skipped"
+)
+
+// DefaultUnitTestPattern matches the helm-unittest suites of a chart, like
+// the default of helm unittest --file.
+const DefaultUnitTestPattern = "tests/*_test.yaml"
+
+// UnitTestFile is a helm-unittest suite file of a chart, see RunUnitTests.
+type UnitTestFile struct {
+	// Path is relative to the chart directory, e.g. tests/deployment_test.yaml.
+	Path string
+	Data []byte
+}
+
+// UnitTestReport is the result of running the helm-unittest suites of a
+// chart.
+type UnitTestReport struct {
+	Passed bool `json:"passed"`
+	// Tests counts the tests of all suites, Failed those that failed and
+	// Skipped those that were skipped or only have unsupported assertions.
+	Tests   int             `json:"tests"`
+	Failed  int             `json:"failed"`
+	Skipped int             `json:"skipped"`
+	Suites  []UnitTestSuite `json:"suites"`
+}
+
+// UnitTestSuite is the result of a suite file.
+type UnitTestSuite struct {
+	File string `json:"file"`
+	Name string `json:"name"`
+	// Error is set if the suite could not be parsed, in which case it has no
+	// tests and counts as failed.
+	Error string           `json:"error,omitempty"`
+	Tests []UnitTestResult `json:"tests"`
+}
+
+// UnitTestResult is the result of a single test of a suite.
+type UnitTestResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Error is set if the test could not run, e.g. because a values file is
+	// missing.
+	Error      string              `json:"error,omitempty"`
+	Assertions []UnitTestAssertion `json:"assertions"`
+}
+
+// UnitTestAssertion is the result of a single assertion of a test.
+type UnitTestAssertion struct {
+	// Type is the assertion, e.g. equal or notMatchRegex for negated
+	// assertions.
+	Type   string `json:"type"`
+	Path   string `json:"path,omitempty"`
+	Status string `json:"status"`
+	// Message explains why the assertion failed or was skipped.
+	Message string `json:"message,omitempty"`
+}
+
+type unitTestSuite struct {
+	Suite        string               `json:"suite"`
+	Templates    []string             `json:"templates"`
+	Release      unitTestRelease      `json:"release"`
+	Capabilities unitTestCapabilities `json:"capabilities"`
+	Chart        unitTestChart        `json:"chart"`
+	Set          map[string]any       `json:"set"`
+	Values       []string             `json:"values"`
+	Tests        []unitTestCase       `json:"tests"`
+}
+
+type unitTestCase struct {
+	It               string               `json:"it"`
+	Template         string               `json:"template"`
+	Templates        []string             `json:"templates"`
+	DocumentIndex    *int                 `json:"documentIndex"`
+	DocumentSelector *documentSelector    `json:"documentSelector"`
+	Release          unitTestRelease      `json:"release"`
+	Capabilities     unitTestCapabilities `json:"capabilities"`
+	Chart            unitTestChart        `json:"chart"`
+	Set              map[string]any       `json:"set"`
+	Values           []string             `json:"values"`
+	Skip             *struct {
+		Reason string `json:"reason"`
+	} `json:"skip"`
+	Asserts []map[string]any `json:"asserts"`
+}
+
+type unitTestRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Revision  int    `json:"revision"`
+	Upgrade   bool   `json:"upgrade"`
+}
+
+type unitTestCapabilities struct {
+	// The versions are numbers or strings in suites.
+	MajorVersion any      `json:"majorVersion"`
+	MinorVersion any      `json:"minorVersion"`
+	APIVersions  []string `json:"apiVersions"`
+}
+
+type unitTestChart struct {
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion"`
+}
+
+type documentSelector struct {
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// RunUnitTests runs helm-unittest suites against chart, reporting the result
+// of every assertion. Values files referenced by suites are read with
+// readFile, relative to the chart directory.
+//
+// Suites follow the helm-unittest format: templates, release, capabilities,
+// chart, set and values at the suite and test level, documentIndex and
+// documentSelector, and the equal, notEqual, equalRaw, matchRegex,
+// matchRegexRaw, contains, isNull, isEmpty, exists, isKind, isAPIVersion,
+// hasDocuments, lengthEqual, isSubset and failedTemplate assertions with
+// their not variants. Snapshot assertions are reported as skipped.
+func RunUnitTests(chart *chartv2.Chart, suites []UnitTestFile, readFile func(path string) ([]byte, error)) *UnitTestReport {
+	report := &UnitTestReport{Passed: true, Suites: make([]UnitTestSuite, 0, len(suites))}
+	for _, file := range suites {
+		result := UnitTestSuite{File: file.Path, Tests: []UnitTestResult{}}
+
+		var suite unitTestSuite
+		if err := yaml.Unmarshal(file.Data, &suite); err != nil {
+			result.Error = fmt.Sprintf("invalid test suite: %v", err)
+			report.Passed = false
+			report.Suites = append(report.Suites, result)
+			continue
+		}
+		result.Name = suite.Suite
+		for _, tc := range suite.Tests {
+			test := runUnitTest(chart, file.Path, &suite, tc, readFile)
+			report.Tests++
+			switch test.Status {
+			case UnitTestFailed:
+				report.Failed++
+				report.Passed = false
+			case UnitTestSkipped:
+				report.Skipped++
+			}
+			result.Tests = append(result.Tests, test)
+		}
+		report.Suites = append(report.Suites, result)
+	}
+	return report
+}
+
+func runUnitTest(chart *chartv2.Chart, suitePath string, suite *unitTestSuite, tc unitTestCase, readFile func(string) ([]byte, error)) UnitTestResult {
+	result := UnitTestResult{Name: tc.It, Status: UnitTestPassed, Assertions: []UnitTestAssertion{}}
+	if tc.Skip != nil {
+		result.Status = UnitTestSkipped
+		result.Error = tc.Skip.Reason
+		return result
+	}
+
+	values := make(map[string]any)
+	for _, layer := range []struct {
+		files []string
+		set   map[string]any
+	}{{suite.Values, suite.Set}, {tc.Values, tc.Set}} {
+		for _, name := range layer.files {
+			file := path.Join(path.Dir(suitePath), name)
+			data, err := readFile(file)
+			if err != nil {
+				return failedUnitTest(result, fmt.Sprintf("failed to read values file %s: %v", file, err))
+			}
+			fileValues, err := common.ReadValues(data)
+			if err != nil {
+				return failedUnitTest(result, fmt.Sprintf("failed to parse values file %s: %v", file, err))
+			}
+			values = loader.MergeMaps(values, fileValues)
+		}
+		for key, value := range layer.set {
+			set := make(map[string]any)
+			setValue(set, strings.Split(key, "."), value)
+			values = loader.MergeMaps(values, set)
+		}
+	}
+
+	rendered, renderErr := renderUnitTest(chart, values, suite, tc)
+
+	templates := suite.Templates
+	if tc.Templates != nil {
+		templates = tc.Templates
+	}
+	if tc.Template != "" {
+		templates = []string{tc.Template}
+	}
+
+	for _, assert := range tc.Asserts {
+		a := evaluateUnitTestAssertion(chart, assert, templates, tc, rendered, renderErr)
+		if a.Status == UnitTestFailed {
+			result.Status = UnitTestFailed
+		}
+		result.Assertions = append(result.Assertions, a)
+	}
+	if result.Status == UnitTestPassed && len(result.Assertions) > 0 {
+		skipped := true
+		for _, a := range result.Assertions {
+			skipped = skipped && a.Status == UnitTestSkipped
+		}
+		if skipped {
+			result.Status = UnitTestSkipped
+		}
+	}
+	return result
+}
+
+func failedUnitTest(result UnitTestResult, msg string) UnitTestResult {
+	result.Status = UnitTestFailed
+	result.Error = msg
+	return result
+}
+
+// renderUnitTest renders the templates of chart for a test, keyed by their
+// path, e.g. mychart/templates/deployment.yaml. Unlike the rendering of the
+// other tools it fails on required values, like helm install.
+func renderUnitTest(chart *chartv2.Chart, values map[string]any, suite *unitTestSuite, tc unitTestCase) (map[string]string, error) {
+	if tc.Chart.Version != "" || tc.Chart.AppVersion != "" || suite.Chart.Version != "" || suite.Chart.AppVersion != "" {
+		// Copy the chart, which may be shared through the chart cache.
+		copied := *chart
+		metadata := *chart.Metadata
+		metadata.Version = cmp.Or(tc.Chart.Version, suite.Chart.Version, metadata.Version)
+		metadata.AppVersion = cmp.Or(tc.Chart.AppVersion, suite.Chart.AppVersion, metadata.AppVersion)
+		copied.Metadata = &metadata
+		chart = &copied
+	}
+
+	options := common.ReleaseOptions{
+		Name:      cmp.Or(tc.Release.Name, suite.Release.Name, DefaultReleaseName),
+		Namespace: cmp.Or(tc.Release.Namespace, suite.Release.Namespace, DefaultNamespace),
+		Revision:  cmp.Or(tc.Release.Revision, suite.Release.Revision, 1),
+		IsUpgrade: tc.Release.Upgrade || suite.Release.Upgrade,
+	}
+	options.IsInstall = !options.IsUpgrade
+
+	caps, err := unitTestCapabilitiesOf(suite.Capabilities, tc.Capabilities)
+	if err != nil {
+		return nil, err
+	}
+	valuesToRender, err := util.ToRenderValues(chart, values, options, caps)
+	if err != nil {
+		return nil, err
+	}
+	return engine.Engine{}.Render(chart, valuesToRender)
+}
+
+func unitTestCapabilitiesOf(suite, tc unitTestCapabilities) (*common.Capabilities, error) {
+	major := cmp.Or(versionString(tc.MajorVersion), versionString(suite.MajorVersion))
+	minor := cmp.Or(versionString(tc.MinorVersion), versionString(suite.MinorVersion))
+	kubeVersion := ""
+	if major != "" || minor != "" {
+		kubeVersion = fmt.Sprintf("v%s.%s.0", cmp.Or(major, common.DefaultCapabilities.KubeVersion.Major), cmp.Or(minor, common.DefaultCapabilities.KubeVersion.Minor))
+	}
+	return NewCapabilities(kubeVersion, append(suite.APIVersions, tc.APIVersions...))
+}
+
+func versionString(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// unitTestNegations maps the not variants of assertions to the assertion
+// they negate.
+var unitTestNegations = map[string]string{
+	"notEqual":            "equal",
+	"notEqualRaw":         "equalRaw",
+	"notMatchRegex":       "matchRegex",
+	"notMatchRegexRaw":    "matchRegexRaw",
+	"notContains":         "contains",
+	"isNotNull":           "isNull",
+	"isNotEmpty":          "isEmpty",
+	"notExists":           "exists",
+	"isNotSubset":         "isSubset",
+	"notFailedTemplate":   "failedTemplate",
+	"isNotKind":           "isKind",
+	"isNotAPIVersion":     "isAPIVersion",
+	"notLengthEqual":      "lengthEqual",
+	"notHasDocuments":     "hasDocuments",
+	"notMatchSnapshot":    "matchSnapshot",
+	"notMatchSnapshotRaw": "matchSnapshotRaw",
+}
+
+// unitTestAssertionKeys are the keys of an assertion that are not its type.
+var unitTestAssertionKeys = map[string]bool{"not": true, "template": true, "documentIndex": true, "documentSelector": true}
+
+func evaluateUnitTestAssertion(chart *chartv2.Chart, assert map[string]any, templates []string, tc unitTestCase, rendered map[string]string, renderErr error) UnitTestAssertion {
+	var kind string
+	var params map[string]any
+	for key, value := range assert {
+		if unitTestAssertionKeys[key] {
+			continue
+		}
+		kind = key
+		params, _ = value.(map[string]any)
+	}
+	result := UnitTestAssertion{Type: kind, Status: UnitTestPassed}
+	if kind == "" {
+		result.Status, result.Message = UnitTestFailed, "assertion has no type"
+		return result
+	}
+	if params == nil {
+		params = map[string]any{}
+	}
+	result.Path, _ = params["path"].(string)
+
+	negate, _ := assert["not"].(bool)
+	base := kind
+	if negated, ok := unitTestNegations[kind]; ok {
+		base = negated
+		negate = !negate
+	}
+
+	switch base {
+	case "matchSnapshot", "matchSnapshotRaw":
+		result.Status, result.Message = UnitTestSkipped, "snapshot assertions are not supported"
+		return result
+	case "failedTemplate":
+		ok, msg := assertFailedTemplate(params, renderErr)
+		return unitTestOutcome(result, ok, negate, msg, "expected the templates not to fail to render")
+	}
+	if renderErr != nil {
+		result.Status, result.Message = UnitTestFailed, fmt.Sprintf("failed to render templates: %v", renderErr)
+		return result
+	}
+
+	if template, ok := assert["template"].(string); ok && template != "" {
+		templates = []string{template}
+	}
+	names, err := unitTestTemplates(chart, templates, rendered)
+	if err != nil {
+		result.Status, result.Message = UnitTestFailed, err.Error()
+		return result
+	}
+
+	if base == "equalRaw" || base == "matchRegexRaw" {
+		var raw strings.Builder
+		for _, name := range names {
+			raw.WriteString(rendered[name])
+		}
+		ok, msg := assertRaw(base, params, raw.String())
+		return unitTestOutcome(result, ok, negate, msg, fmt.Sprintf("expected %s not to hold for the raw output", base))
+	}
+
+	var docs []map[string]any
+	for _, name := range names {
+		templateDocs, err := unitTestDocuments(rendered[name])
+		if err != nil {
+			result.Status, result.Message = UnitTestFailed, fmt.Sprintf("failed to parse the output of %s: %v", name, err)
+			return result
+		}
+		docs = append(docs, templateDocs...)
+	}
+	docs, err = selectUnitTestDocuments(docs, assert, tc)
+	if err != nil {
+		result.Status, result.Message = UnitTestFailed, err.Error()
+		return result
+	}
+
+	if base == "hasDocuments" {
+		count := intParam(params, "count")
+		ok := len(docs) == count
+		return unitTestOutcome(result, ok, negate, fmt.Sprintf("expected %d documents, got %d", count, len(docs)), fmt.Sprintf("expected a document count other than %d", count))
+	}
+	if len(docs) == 0 {
+		result.Status, result.Message = UnitTestFailed, "no documents to assert on"
+		return result
+	}
+	for i, doc := range docs {
+		ok, msg, err := assertDocument(base, params, doc)
+		if err != nil {
+			result.Status, result.Message = UnitTestFailed, err.Error()
+			return result
+		}
+		if ok == negate {
+			if negate {
+				msg = fmt.Sprintf("expected %s not to hold", base)
+			}
+			result.Status, result.Message = UnitTestFailed, fmt.Sprintf("document %d: %s", i, msg)
+			return result
+		}
+	}
+	return result
+}
+
+func unitTestOutcome(result UnitTestAssertion, ok, negate bool, msg, negatedMsg string) UnitTestAssertion {
+	if ok == negate {
+		result.Status = UnitTestFailed
+		result.Message = msg
+		if negate {
+			result.Message = negatedMsg
+		}
+	}
+	return result
+}
+
+// unitTestTemplates returns the rendered templates matched by templates,
+// relative to the templates directory of chart, or all templates if
+// templates is empty.
+func unitTestTemplates(chart *chartv2.Chart, templates []string, rendered map[string]string) ([]string, error) {
+	var names []string
+	if len(templates) == 0 {
+		for name := range rendered {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+	for _, template := range templates {
+		if !strings.HasPrefix(template, "templates/") && !strings.HasPrefix(template, "charts/") {
+			template = "templates/" + template
+		}
+		pattern := chart.Name() + "/" + template
+		var matched []string
+		for name := range rendered {
+			if ok, _ := path.Match(pattern, name); ok {
+				matched = append(matched, name)
+			}
+		}
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("template %s not found", template)
+		}
+		sort.Strings(matched)
+		names = append(names, matched...)
+	}
+	return names, nil
+}
+
+// unitTestDocuments parses the YAML documents of a rendered template.
+func unitTestDocuments(content string) ([]map[string]any, error) {
+	var docs []map[string]any
+	for _, doc := range strings.Split("\n"+content, "\n---") {
+		if strings.TrimSpace(stripComments(doc)) == "" {
+			continue
+		}
+		var m map[string]any
+		if err := yaml.Unmarshal([]byte(doc), &m); err != nil {
+			return nil, err
+		}
+		if m != nil {
+			docs = append(docs, m)
+		}
+	}
+	return docs, nil
+}
+
+// selectUnitTestDocuments applies the documentIndex and documentSelector of
+// an assertion, or else those of its test.
+func selectUnitTestDocuments(docs []map[string]any, assert map[string]any, tc unitTestCase) ([]map[string]any, error) {
+	index := tc.DocumentIndex
+	if v, ok := assert["documentIndex"].(float64); ok {
+		i := int(v)
+		index = &i
+	}
+	selector := tc.DocumentSelector
+	if v, ok := assert["documentSelector"].(map[string]any); ok {
+		p, _ := v["path"].(string)
+		selector = &documentSelector{Path: p, Value: v["value"]}
+	}
+
+	if selector != nil {
+		var selected []map[string]any
+		for _, doc := range docs {
+			value, found, err := lookupUnitTestPath(doc, selector.Path)
+			if err != nil {
+				return nil, err
+			}
+			if found && reflect.DeepEqual(value, selector.Value) {
+				selected = append(selected, doc)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("no document where %s is %v", selector.Path, selector.Value)
+		}
+		docs = selected
+	}
+	if index != nil {
+		if *index < 0 || *index >= len(docs) {
+			return nil, fmt.Errorf("document index %d out of range, the templates have %d documents", *index, len(docs))
+		}
+		docs = docs[*index : *index+1]
+	}
+	return docs, nil
+}
+
+func assertFailedTemplate(params map[string]any, renderErr error) (bool, string) {
+	if renderErr == nil {
+		return false, "expected the templates to fail to render"
+	}
+	if msg, ok := params["errorMessage"].(string); ok && !strings.Contains(renderErr.Error(), msg) {
+		return false, fmt.Sprintf("expected error %q, got %q", msg, renderErr.Error())
+	}
+	if pattern, ok := params["errorPattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, fmt.Sprintf("invalid errorPattern: %v", err)
+		}
+		if !re.MatchString(renderErr.Error()) {
+			return false, fmt.Sprintf("expected error matching %q, got %q", pattern, renderErr.Error())
+		}
+	}
+	return true, ""
+}
+
+func assertRaw(kind string, params map[string]any, raw string) (bool, string) {
+	if kind == "equalRaw" {
+		want := fmt.Sprint(params["value"])
+		return raw == want, fmt.Sprintf("expected %q, got %q", want, raw)
+	}
+	pattern, _ := params["pattern"].(string)
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, fmt.Sprintf("invalid pattern: %v", err)
+	}
+	return re.MatchString(raw), fmt.Sprintf("expected %q to match %q", raw, pattern)
+}
+
+// assertDocument evaluates an assertion against a single document, returning
+// whether it holds and why not. An error is returned for invalid assertions.
+func assertDocument(kind string, params map[string]any, doc map[string]any) (bool, string, error) {
+	p, _ := params["path"].(string)
+	value, found, err := lookupUnitTestPath(doc, p)
+	if err != nil {
+		return false, "", err
+	}
+
+	switch kind {
+	case "equal":
+		if decode, _ := params["decodeBase64"].(bool); decode {
+			if value, err = decodeBase64Value(value); err != nil {
+				return false, err.Error(), nil
+			}
+		}
+		return reflect.DeepEqual(value, params["value"]), fmt.Sprintf("expected %s to be %s, got %s", p, yamlString(params["value"]), yamlString(value)), nil
+	case "matchRegex":
+		if decode, _ := params["decodeBase64"].(bool); decode {
+			if value, err = decodeBase64Value(value); err != nil {
+				return false, err.Error(), nil
+			}
+		}
+		pattern, _ := params["pattern"].(string)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, "", fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+		s, ok := value.(string)
+		if !ok {
+			return false, fmt.Sprintf("expected %s to be a string, got %s", p, yamlString(value)), nil
+		}
+		return re.MatchString(s), fmt.Sprintf("expected %s to match %q, got %q", p, pattern, s), nil
+	case "contains":
+		list, ok := value.([]any)
+		if !ok {
+			return false, fmt.Sprintf("expected %s to be a list, got %s", p, yamlString(value)), nil
+		}
+		content := params["content"]
+		anyMatch, _ := params["any"].(bool)
+		matches := 0
+		for _, item := range list {
+			if reflect.DeepEqual(item, content) || (anyMatch && isSubset(item, content)) {
+				matches++
+			}
+		}
+		if _, ok := params["count"]; ok {
+			count := intParam(params, "count")
+			return matches == count, fmt.Sprintf("expected %s to contain %s %d times, found %d", p, yamlString(content), count, matches), nil
+		}
+		return matches > 0, fmt.Sprintf("expected %s to contain %s", p, yamlString(content)), nil
+	case "isNull":
+		return value == nil, fmt.Sprintf("expected %s to be null, got %s", p, yamlString(value)), nil
+	case "isEmpty":
+		return isEmptyValue(value), fmt.Sprintf("expected %s to be empty, got %s", p, yamlString(value)), nil
+	case "exists":
+		return found, fmt.Sprintf("expected %s to exist", p), nil
+	case "isKind":
+		return doc["kind"] == params["of"], fmt.Sprintf("expected kind %v, got %v", params["of"], doc["kind"]), nil
+	case "isAPIVersion":
+		return doc["apiVersion"] == params["of"], fmt.Sprintf("expected apiVersion %v, got %v", params["of"], doc["apiVersion"]), nil
+	case "lengthEqual":
+		count := intParam(params, "count")
+		n := -1
+		switch v := value.(type) {
+		case []any:
+			n = len(v)
+		case map[string]any:
+			n = len(v)
+		}
+		return n == count, fmt.Sprintf("expected %s to have %d items, got %s", p, count, yamlString(value)), nil
+	case "isSubset":
+		return isSubset(value, params["content"]), fmt.Sprintf("expected %s to contain %s", p, yamlString(params["content"])), nil
+	default:
+		return false, "", fmt.Errorf("unsupported assertion %s", kind)
+	}
+}
+
+// isSubset reports whether value contains everything of content: the keys of
+// maps recursively, any other value exactly.
+func isSubset(value, content any) bool {
+	contentMap, ok := content.(map[string]any)
+	if !ok {
+		return reflect.DeepEqual(value, content)
+	}
+	valueMap, ok := value.(map[string]any)
+	if !ok {
+		return false
+	}
+	for key, c := range contentMap {
+		v, ok := valueMap[key]
+		if !ok || !isSubset(v, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	}
+	return false
+}
+
+func decodeBase64Value(value any) (any, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a base64 string, got %s", yamlString(value))
+	}
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64: %v", err)
+	}
+	return string(decoded), nil
+}
+
+func intParam(params map[string]any, key string) int {
+	v, _ := params[key].(float64)
+	return int(v)
+}
+
+// yamlString formats a value for assertion messages.
+func yamlString(v any) string {
+	if v == nil {
+		return "null"
+	}
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// lookupUnitTestPath returns the value at a helm-unittest path such as
+// spec.template.spec.containers[0].image or
+// metadata.labels["app.kubernetes.io/name"], and whether it exists. The
+// empty path is the document itself.
+func lookupUnitTestPath(doc any, p string) (any, bool, error) {
+	segments, err := parseUnitTestPath(p)
+	if err != nil {
+		return nil, false, err
+	}
+	current := doc
+	for _, segment := range segments {
+		switch v := current.(type) {
+		case map[string]any:
+			next, ok := v[segment]
+			if !ok {
+				return nil, false, nil
+			}
+			current = next
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false, nil
+			}
+			current = v[i]
+		default:
+			return nil, false, nil
+		}
+	}
+	return current, true, nil
+}
+
+// parseUnitTestPath splits a path into its keys and list indexes.
+func parseUnitTestPath(p string) ([]string, error) {
+	var segments []string
+	var key strings.Builder
+	flush := func() {
+		if key.Len() > 0 {
+			segments = append(segments, key.String())
+			key.Reset()
+		}
+	}
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '.':
+			flush()
+		case '\\':
+			if i+1 < len(p) {
+				i++
+				key.WriteByte(p[i])
+			}
+		case '[':
+			flush()
+			end := strings.IndexByte(p[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed [", p)
+			}
+			inner := p[i+1 : i+end]
+			if len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0] {
+				inner = inner[1 : len(inner)-1]
+			} else if _, err := strconv.Atoi(inner); err != nil {
+				return nil, fmt.Errorf("invalid path %q: [%s] is neither an index nor a quoted key", p, inner)
+			}
+			segments = append(segments, inner)
+			i += end
+		default:
+			key.WriteByte(c)
+		}
+	}
+	flush()
+	return segments, nil
+}
@@ -0,0 +1,195 @@
+package helm_parser
+
+import (
+	"fmt"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/chart/common"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestRunUnitTests(t *testing.T) {
+	chart := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "app", Version: "1.0.0", AppVersion: "2.3.0"},
+		Templates: []*common.File{
+			{Name: "templates/deployment.yaml", Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}-app
+  labels:
+    app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
+spec:
+  replicas: {{ .Values.replicas }}
+  template:
+    spec:
+      containers:
+        - name: app
+          image: "{{ required "image.repository is required" .Values.image.repository }}:{{ .Values.image.tag }}"
+          ports:
+            - containerPort: 8080
+              name: http
+`)},
+			{Name: "templates/service.yaml", Data: []byte(`{{- if .Values.service.enabled }}
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Release.Name }}-app
+{{- end }}
+`)},
+			{Name: "templates/NOTES.txt", Data: []byte(`Installed {{ .Release.Name }}`)},
+		},
+		Values: map[string]any{
+			"replicas": 1,
+			"image":    map[string]any{"repository": "ghcr.io/org/app", "tag": "2.3.0"},
+			"service":  map[string]any{"enabled": true},
+		},
+	}
+
+	suite := `suite: deployment
+templates:
+  - deployment.yaml
+release:
+  name: demo
+tests:
+  - it: renders the defaults
+    asserts:
+      - isKind:
+          of: Deployment
+      - equal:
+          path: metadata.name
+          value: demo-app
+      - equal:
+          path: spec.replicas
+          value: 1
+      - equal:
+          path: metadata.labels["app.kubernetes.io/version"]
+          value: "2.3.0"
+      - matchRegex:
+          path: spec.template.spec.containers[0].image
+          pattern: "^ghcr.io/org/app:"
+      - contains:
+          path: spec.template.spec.containers[0].ports
+          content:
+            name: http
+          any: true
+      - notExists:
+          path: spec.strategy
+      - hasDocuments:
+          count: 1
+  - it: applies set and values files
+    values:
+      - values/prod.yaml
+    set:
+      image.tag: "3.0.0"
+    chart:
+      appVersion: "3.0.0"
+    asserts:
+      - equal:
+          path: spec.replicas
+          value: 3
+      - equal:
+          path: spec.template.spec.containers[0].image
+          value: ghcr.io/org/app:3.0.0
+      - isSubset:
+          path: metadata.labels
+          content:
+            app.kubernetes.io/version: "3.0.0"
+  - it: fails without a repository
+    set:
+      image.repository: null
+    asserts:
+      - failedTemplate:
+          errorMessage: image.repository is required
+  - it: renders no service when disabled
+    template: service.yaml
+    set:
+      service.enabled: false
+    asserts:
+      - hasDocuments:
+          count: 0
+  - it: renders the notes
+    template: NOTES.txt
+    asserts:
+      - equalRaw:
+          value: Installed demo
+      - matchSnapshot: {}
+  - it: has a failing assertion
+    asserts:
+      - equal:
+          path: spec.replicas
+          value: 5
+      - isNull:
+          path: spec.replicas
+        not: true
+  - it: is skipped
+    skip:
+      reason: not ready
+    asserts:
+      - isKind:
+          of: Service
+`
+	files := map[string]string{"tests/values/prod.yaml": "replicas: 3\n"}
+	readFile := func(path string) ([]byte, error) {
+		if data, ok := files[path]; ok {
+			return []byte(data), nil
+		}
+		return nil, fmt.Errorf("%s not found", path)
+	}
+
+	report := RunUnitTests(chart, []UnitTestFile{
+		{Path: "tests/deployment_test.yaml", Data: []byte(suite)},
+		{Path: "tests/broken_test.yaml", Data: []byte("tests: {")},
+	}, readFile)
+
+	if report.Passed {
+		t.Error("Passed = true, want false")
+	}
+	if report.Tests != 7 || report.Failed != 1 || report.Skipped != 1 {
+		t.Errorf("Tests, Failed, Skipped = %d, %d, %d, want 7, 1, 1", report.Tests, report.Failed, report.Skipped)
+	}
+	if len(report.Suites) != 2 || report.Suites[0].Name != "deployment" || report.Suites[1].Error == "" {
+		t.Fatalf("Suites = %+v, want the deployment suite and a broken suite", report.Suites)
+	}
+
+	want := map[string]string{
+		"renders the defaults":             UnitTestPassed,
+		"applies set and values files":     UnitTestPassed,
+		"fails without a repository":       UnitTestPassed,
+		"renders no service when disabled": UnitTestPassed,
+		"renders the notes":                UnitTestPassed,
+		"has a failing assertion":          UnitTestFailed,
+		"is skipped":                       UnitTestSkipped,
+	}
+	for _, test := range report.Suites[0].Tests {
+		if test.Status != want[test.Name] {
+			t.Errorf("test %q: status %s, want %s (%+v)", test.Name, test.Status, want[test.Name], test)
+		}
+	}
+
+	failing := report.Suites[0].Tests[5].Assertions
+	if len(failing) != 2 || failing[0].Status != UnitTestFailed || failing[1].Status != UnitTestPassed {
+		t.Errorf("failing test assertions = %+v, want equal to fail and not isNull to pass", failing)
+	}
+	if notes := report.Suites[0].Tests[4].Assertions; notes[1].Status != UnitTestSkipped {
+		t.Errorf("matchSnapshot status = %s, want skipped", notes[1].Status)
+	}
+}
+
+func TestParseUnitTestPath(t *testing.T) {
+	for path, want := range map[string][]string{
+		"":                              nil,
+		"spec.replicas":                 {"spec", "replicas"},
+		"spec.containers[0].image":      {"spec", "containers", "0", "image"},
+		`metadata.labels["app/name"]`:   {"metadata", "labels", "app/name"},
+		`metadata.annotations['a.b/c']`: {"metadata", "annotations", "a.b/c"},
+		`data.config\.yaml`:             {"data", "config.yaml"},
+	} {
+		got, err := parseUnitTestPath(path)
+		if err != nil || fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("parseUnitTestPath(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	if _, err := parseUnitTestPath("spec.containers[name]"); err == nil {
+		t.Error("expected an error for an unquoted key in brackets")
+	}
+}
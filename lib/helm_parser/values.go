@@ -0,0 +1,63 @@
+package helm_parser
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ValueChange describes a value that differs between two sets of values.
+// Old or New is omitted if the value is only set on one side.
+type ValueChange struct {
+	// Path is the dotted path of the value, e.g. "image.tag".
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// FlattenValues returns the leaf values of nested Helm values keyed by their
+// dotted path. Lists and empty maps are leaves.
+func FlattenValues(values map[string]any) map[string]any {
+	flat := make(map[string]any)
+	flattenValues(values, "", flat)
+	return flat
+}
+
+func flattenValues(values map[string]any, prefix string, flat map[string]any) {
+	for k, v := range values {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if m, ok := v.(map[string]any); ok && len(m) > 0 {
+			flattenValues(m, path, flat)
+			continue
+		}
+		flat[path] = v
+	}
+}
+
+// DiffValues compares two sets of nested Helm values leaf by leaf and returns
+// the changes sorted by path.
+func DiffValues(oldValues, newValues map[string]any) []ValueChange {
+	oldFlat := FlattenValues(oldValues)
+	newFlat := FlattenValues(newValues)
+
+	changes := []ValueChange{}
+	for path, oldValue := range oldFlat {
+		newValue, ok := newFlat[path]
+		if !ok {
+			changes = append(changes, ValueChange{Path: path, Old: oldValue})
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, ValueChange{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+	for path, newValue := range newFlat {
+		if _, ok := oldFlat[path]; !ok {
+			changes = append(changes, ValueChange{Path: path, New: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
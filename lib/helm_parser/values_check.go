@@ -0,0 +1,304 @@
+package helm_parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v4/pkg/chart/common/util"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+)
+
+// Severities of a ValuesIssue.
+const (
+	// SeverityError marks values the chart rejects or cannot use.
+	SeverityError = "error"
+	// SeverityWarning marks values that are likely mistakes.
+	SeverityWarning = "warning"
+)
+
+// ValuesIssue is a problem found in user supplied values.
+type ValuesIssue struct {
+	// Path is the dotted path of the value, e.g. ingress.hosts[0].host.
+	Path     string `json:"path"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// Suggestion is the path of a default value with a similar name, for
+	// keys that are likely misspelled.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// ValuesValidation is the result of ValidateValues.
+type ValuesValidation struct {
+	// Valid is false if any issue is an error.
+	Valid bool `json:"valid"`
+	// Schema reports whether the chart or any subchart has a
+	// values.schema.json.
+	Schema bool          `json:"schema"`
+	Issues []ValuesIssue `json:"issues"`
+}
+
+// schemaErrorRe matches a violation reported by Helm's schema validation.
+var schemaErrorRe = regexp.MustCompile(`^- at '([^']*)': (.*)$`)
+
+// ValidateValues checks values overriding the defaults of chart. The values
+// coalesced with the defaults are validated against the values.schema.json
+// of the chart and its subcharts, like helm install does. Independently of a
+// schema, keys missing from the defaults and values whose type differs from
+// the default are reported. Keys missing from the defaults are only reported
+// if no template of the chart refers to them either, see templateRefs.
+func ValidateValues(chart *chartv2.Chart, values map[string]any) (ValuesValidation, error) {
+	result := ValuesValidation{Schema: hasSchema(chart), Issues: []ValuesIssue{}}
+
+	coalesced, err := util.CoalesceValues(chart, values)
+	if err != nil {
+		return result, fmt.Errorf("failed to merge values: %v", err)
+	}
+	if err := util.ValidateAgainstSchema(chart, coalesced); err != nil {
+		result.Issues = append(result.Issues, schemaIssues(chart.Name(), err)...)
+	}
+
+	// The schema violation is more precise than a type mismatch of the same
+	// value.
+	schemaPaths := make(map[string]bool)
+	for _, issue := range result.Issues {
+		schemaPaths[issue.Path] = true
+	}
+	refs := templateRefs(chart)
+	for _, issue := range compareWithDefaults(values, defaultValues(chart), refs, "") {
+		if !schemaPaths[issue.Path] {
+			result.Issues = append(result.Issues, issue)
+		}
+	}
+
+	sort.SliceStable(result.Issues, func(i, j int) bool { return result.Issues[i].Path < result.Issues[j].Path })
+	result.Valid = true
+	for _, issue := range result.Issues {
+		if issue.Severity == SeverityError {
+			result.Valid = false
+		}
+	}
+	return result, nil
+}
+
+// defaultValues returns the default values of chart with those of its
+// subcharts below their names. Unlike util.CoalesceValues, null defaults are
+// kept.
+func defaultValues(chart *chartv2.Chart) map[string]any {
+	defaults := loader.MergeMaps(nil, chart.Values)
+	for _, dep := range chart.Dependencies() {
+		sub := defaultValues(dep)
+		if parent, ok := defaults[dep.Name()].(map[string]any); ok {
+			sub = loader.MergeMaps(sub, parent)
+		}
+		defaults[dep.Name()] = sub
+	}
+	return defaults
+}
+
+func hasSchema(chart *chartv2.Chart) bool {
+	if len(chart.Schema) > 0 {
+		return true
+	}
+	for _, dep := range chart.Dependencies() {
+		if hasSchema(dep) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaIssues splits the error of util.ValidateAgainstSchema, which lists
+// the violations of every chart below a "<chart name>:" line, into issues.
+// Paths of subchart violations are prefixed with the subchart name.
+func schemaIssues(chartName string, err error) []ValuesIssue {
+	var issues []ValuesIssue
+	prefix := ""
+	for _, line := range strings.Split(err.Error(), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+		case !strings.HasPrefix(line, "-") && !strings.HasPrefix(line, " ") && strings.HasSuffix(line, ":"):
+			prefix = ""
+			if name := strings.TrimSuffix(line, ":"); name != chartName {
+				prefix = name
+			}
+		case schemaErrorRe.MatchString(line):
+			m := schemaErrorRe.FindStringSubmatch(line)
+			issues = append(issues, ValuesIssue{
+				Path:     joinPath(prefix, pointerToPath(m[1])),
+				Severity: SeverityError,
+				Message:  "values.schema.json: " + m[2],
+			})
+		case len(issues) > 0 && strings.HasPrefix(line, " "):
+			// Nested details of the previous violation.
+			issues[len(issues)-1].Message += "; " + strings.TrimPrefix(trimmed, "- ")
+		default:
+			issues = append(issues, ValuesIssue{Path: prefix, Severity: SeverityError, Message: "values.schema.json: " + trimmed})
+		}
+	}
+	return issues
+}
+
+// pointerToPath converts a JSON pointer such as /hosts/0/name to a values
+// path such as hosts[0].name.
+func pointerToPath(pointer string) string {
+	var path string
+	for _, elem := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if elem == "" {
+			continue
+		}
+		elem = strings.NewReplacer("~1", "/", "~0", "~").Replace(elem)
+		if strings.Trim(elem, "0123456789") == "" {
+			path += "[" + elem + "]"
+		} else {
+			path = joinPath(path, elem)
+		}
+	}
+	return path
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	if key == "" {
+		return prefix
+	}
+	return prefix + "." + key
+}
+
+// compareWithDefaults reports keys of values that are not in defaults or
+// refs, and values whose type differs from the default. Maps without
+// defaults, such as podAnnotations: {}, and null defaults accept any content.
+func compareWithDefaults(values, defaults map[string]any, refs templateFields, prefix string) []ValuesIssue {
+	var issues []ValuesIssue
+	for key, value := range values {
+		path := joinPath(prefix, key)
+		def, ok := defaults[key]
+		switch {
+		case !ok:
+			if len(defaults) > 0 && key != "global" && !refs[key] {
+				issue := ValuesIssue{
+					Path:     path,
+					Severity: SeverityWarning,
+					Message:  "key is not in the chart's default values and no template refers to it, so it is likely ignored",
+				}
+				if similar := similarKey(key, defaults); similar != "" {
+					issue.Suggestion = joinPath(prefix, similar)
+					issue.Message += fmt.Sprintf("; did you mean %s?", issue.Suggestion)
+				}
+				issues = append(issues, issue)
+			}
+		case value == nil || def == nil:
+			// null removes the default; a null default accepts any value.
+		default:
+			valueKind, defKind := valueKind(value), valueKind(def)
+			switch {
+			case valueKind == "map" && defKind == "map":
+				issues = append(issues, compareWithDefaults(value.(map[string]any), def.(map[string]any), refs, path)...)
+			case valueKind == defKind:
+			case valueKind == "map" || defKind == "map":
+				issues = append(issues, ValuesIssue{
+					Path:     path,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("expected %s like the default value, got %s; Helm ignores the override", defKind, valueKind),
+				})
+			default:
+				issues = append(issues, ValuesIssue{
+					Path:     path,
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("expected %s like the default value, got %s", defKind, valueKind),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// templateFields are the field names and string literals used in the
+// templates of a chart.
+type templateFields map[string]bool
+
+// templateFieldRe matches field accesses such as .Values.ingress.enabled or
+// .enabled inside a with block, and quoted keys such as those of index calls.
+var templateFieldRe = regexp.MustCompile(`\.([A-Za-z_][A-Za-z0-9_]*)|"([^"\\]*)"`)
+
+// templateRefs collects the fields referred to by the templates of chart and
+// its subcharts. It is a heuristic: a key that is used anywhere, also through
+// with, range or index, counts as referenced, whatever its parent.
+func templateRefs(chart *chartv2.Chart) templateFields {
+	refs := make(templateFields)
+	for _, tmpl := range chart.Templates {
+		for _, action := range templateActions(string(tmpl.Data)) {
+			for _, m := range templateFieldRe.FindAllStringSubmatch(action, -1) {
+				refs[m[1]+m[2]] = true
+			}
+		}
+	}
+	for _, dep := range chart.Dependencies() {
+		for field := range templateRefs(dep) {
+			refs[field] = true
+		}
+	}
+	return refs
+}
+
+// similarKey returns the key of defaults closest to key, if it differs only
+// in case or by at most two edits, e.g. "enabled" for "enable".
+func similarKey(key string, defaults map[string]any) string {
+	best, bestDistance := "", 3
+	for candidate := range defaults {
+		distance := editDistance(strings.ToLower(key), strings.ToLower(candidate))
+		if distance < bestDistance || (distance == bestDistance && best != "" && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	// Very short keys are within two edits of almost anything.
+	if bestDistance > 0 && len(key) <= bestDistance+1 {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+func valueKind(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "map"
+	case []any:
+		return "list"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
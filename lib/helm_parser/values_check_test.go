@@ -0,0 +1,133 @@
+package helm_parser
+
+import (
+	"testing"
+
+	"helm.sh/helm/v4/pkg/chart/common"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestValidateValues(t *testing.T) {
+	chart := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "app", Version: "1.0.0"},
+		Values: map[string]any{
+			"replicaCount":   float64(1),
+			"image":          map[string]any{"repository": "app", "tag": ""},
+			"ingress":        map[string]any{"enabled": false, "hosts": []any{}},
+			"podAnnotations": map[string]any{},
+			"resources":      nil,
+		},
+		Schema: []byte(`{"type": "object", "properties": {"replicaCount": {"type": "integer", "minimum": 1}}}`),
+		Templates: []*common.File{
+			{Name: "templates/deployment.yaml", Data: []byte(`{{- with .Values.extraEnv }}
+env: {{ toYaml . | nindent 2 }}
+{{- end }}
+{{- if .Values.ingress.enabled }}
+host: {{ index .Values.ingress "host-name" }}
+{{- end }}`)},
+		},
+	}
+	sub := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "db", Version: "1.0.0"},
+		Values:   map[string]any{"port": float64(5432)},
+		Schema:   []byte(`{"type": "object", "properties": {"port": {"type": "integer"}}}`),
+	}
+	chart.SetDependencies(sub)
+
+	result, err := ValidateValues(chart, map[string]any{
+		"replicaCount":   float64(2),
+		"image":          map[string]any{"tag": "v2"},
+		"podAnnotations": map[string]any{"a": "b"},
+		"resources":      map[string]any{"limits": map[string]any{"cpu": "1"}},
+		"global":         map[string]any{"imageRegistry": "example.com"},
+		"extraEnv":       []any{map[string]any{"name": "A", "value": "b"}},
+		"ingress":        map[string]any{"host-name": "example.com"},
+	})
+	if err != nil {
+		t.Fatalf("ValidateValues() error = %v", err)
+	}
+	if !result.Valid || !result.Schema || len(result.Issues) != 0 {
+		t.Errorf("expected valid values, got %+v", result)
+	}
+
+	result, err = ValidateValues(chart, map[string]any{
+		"replicaCount": float64(0),
+		"image":        "app:v2",
+		"ingress":      map[string]any{"enable": true, "hosts": "example.com"},
+		"db":           map[string]any{"port": "x"},
+		"extraEnvs":    []any{},
+	})
+	if err != nil {
+		t.Fatalf("ValidateValues() error = %v", err)
+	}
+	if result.Valid {
+		t.Error("expected invalid values")
+	}
+	want := map[string]string{
+		"db.port":        SeverityError,
+		"image":          SeverityError,
+		"ingress.enable": SeverityWarning,
+		"ingress.hosts":  SeverityWarning,
+		"extraEnvs":      SeverityWarning,
+		"replicaCount":   SeverityError,
+	}
+	got := make(map[string]string)
+	for _, issue := range result.Issues {
+		got[issue.Path] = issue.Severity
+	}
+	if len(got) != len(want) {
+		t.Errorf("issues = %+v, want paths %v", result.Issues, want)
+	}
+	for path, severity := range want {
+		if got[path] != severity {
+			t.Errorf("issue %s: severity = %q, want %q (issues %+v)", path, got[path], severity, result.Issues)
+		}
+	}
+}
+
+func TestValidateValuesSuggestion(t *testing.T) {
+	chart := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{Name: "app", Version: "1.0.0"},
+		Values: map[string]any{
+			"ingress": map[string]any{"enabled": false, "className": ""},
+		},
+	}
+
+	result, err := ValidateValues(chart, map[string]any{
+		"ingress":  map[string]any{"enable": true, "classname": "nginx"},
+		"replicas": float64(2),
+	})
+	if err != nil {
+		t.Fatalf("ValidateValues() error = %v", err)
+	}
+	suggestions := make(map[string]string)
+	for _, issue := range result.Issues {
+		suggestions[issue.Path] = issue.Suggestion
+	}
+	want := map[string]string{
+		"ingress.enable":    "ingress.enabled",
+		"ingress.classname": "ingress.className",
+		"replicas":          "",
+	}
+	if len(suggestions) != len(want) {
+		t.Errorf("issues = %+v", result.Issues)
+	}
+	for path, suggestion := range want {
+		if got, ok := suggestions[path]; !ok || got != suggestion {
+			t.Errorf("issue %s: suggestion = %q, want %q (issues %+v)", path, got, suggestion, result.Issues)
+		}
+	}
+}
+
+func TestPointerToPath(t *testing.T) {
+	for pointer, want := range map[string]string{
+		"":                      "",
+		"/replicaCount":         "replicaCount",
+		"/ingress/hosts/0/host": "ingress.hosts[0].host",
+		"/podAnnotations/a~1b":  "podAnnotations.a/b",
+	} {
+		if got := pointerToPath(pointer); got != want {
+			t.Errorf("pointerToPath(%q) = %q, want %q", pointer, got, want)
+		}
+	}
+}
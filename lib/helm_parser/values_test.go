@@ -0,0 +1,50 @@
+package helm_parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlattenValues(t *testing.T) {
+	values := map[string]any{
+		"replicas": 1,
+		"image":    map[string]any{"repository": "nginx", "tag": "1.25"},
+		"env":      []any{"A", "B"},
+		"extra":    map[string]any{},
+	}
+	want := map[string]any{
+		"replicas":         1,
+		"image.repository": "nginx",
+		"image.tag":        "1.25",
+		"env":              []any{"A", "B"},
+		"extra":            map[string]any{},
+	}
+	if got := FlattenValues(values); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestDiffValues(t *testing.T) {
+	oldValues := map[string]any{
+		"replicas": 1,
+		"image":    map[string]any{"repository": "nginx", "tag": "1.25"},
+		"legacy":   true,
+	}
+	newValues := map[string]any{
+		"replicas": 1,
+		"image":    map[string]any{"repository": "nginx", "tag": "1.27"},
+		"probes":   map[string]any{"enabled": false},
+	}
+	want := []ValueChange{
+		{Path: "image.tag", Old: "1.25", New: "1.27"},
+		{Path: "legacy", Old: true},
+		{Path: "probes.enabled", New: false},
+	}
+	if got := DiffValues(oldValues, newValues); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if got := DiffValues(oldValues, oldValues); len(got) != 0 {
+		t.Fatalf("expected no changes, got %+v", got)
+	}
+}
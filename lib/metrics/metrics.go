@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Cache names and lookup results used as labels of CacheRequests.
+const (
+	CacheChart   = "chart"
+	CacheArchive = "archive"
+	CacheIndex   = "index"
+
+	Hit  = "hit"
+	Miss = "miss"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	toolCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_helm_tool_calls_total",
+		Help: "Number of tool invocations.",
+	}, []string{"tool"})
+	toolErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_helm_tool_errors_total",
+		Help: "Number of tool invocations that returned an error.",
+	}, []string{"tool"})
+	toolDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mcp_helm_tool_duration_seconds",
+		Help:    "Duration of tool invocations.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+	}, []string{"tool"})
+
+	// ChartDownloadDuration tracks chart archive downloads by repository type
+	// ("http" or "oci"). Archives served from the cache are not observed.
+	ChartDownloadDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mcp_helm_chart_download_duration_seconds",
+		Help:    "Duration of chart archive downloads.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+	}, []string{"type"})
+	// IndexDownloadDuration tracks repository index downloads.
+	IndexDownloadDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "mcp_helm_index_download_duration_seconds",
+		Help:    "Duration of repository index downloads.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+	})
+	// CacheRequests counts cache lookups by cache and result.
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_helm_cache_requests_total",
+		Help: "Number of cache lookups.",
+	}, []string{"cache", "result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		toolCalls, toolErrors, toolDuration,
+		ChartDownloadDuration, IndexDownloadDuration, CacheRequests,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// CacheLookup records the result of a cache lookup.
+func CacheLookup(cache string, hit bool) {
+	result := Miss
+	if hit {
+		result = Hit
+	}
+	CacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// ToolMiddleware records invocations, errors and durations of tool calls.
+func ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool := request.Params.Name
+		start := time.Now()
+
+		result, err := next(ctx, request)
+
+		toolCalls.WithLabelValues(tool).Inc()
+		toolDuration.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+		if err != nil || (result != nil && result.IsError) {
+			toolErrors.WithLabelValues(tool).Inc()
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestToolMiddleware(t *testing.T) {
+	handler := ToolMiddleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.GetBool("fail", false) {
+			return mcp.NewToolResultError("failed"), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	for _, fail := range []bool{false, true, true} {
+		request := mcp.CallToolRequest{Params: mcp.CallToolParams{
+			Name:      "test_tool",
+			Arguments: map[string]any{"fail": fail},
+		}}
+		if _, err := handler(context.Background(), request); err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+	}
+	CacheLookup(CacheChart, true)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	for _, want := range []string{
+		`mcp_helm_tool_calls_total{tool="test_tool"} 3`,
+		`mcp_helm_tool_errors_total{tool="test_tool"} 2`,
+		`mcp_helm_tool_duration_seconds_count{tool="test_tool"} 3`,
+		`mcp_helm_cache_requests_total{cache="chart",result="hit"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected metrics to contain %q", want)
+		}
+	}
+}